@@ -15,6 +15,245 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/audit": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Changes made through the api, newest first. Before and after hold only the changed fields",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "Get audit log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit, 10 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "table of the changed record, e.g. doctor, drug, queue",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id of the changed record",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id of the user who made the change",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "create, update, delete, restore or purge",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or after the date, YYYY-MM-DD",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or before the date, YYYY-MM-DD",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, desc by default",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page, used instead of page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AuditLogsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Get a new access and refresh token pair by refresh token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Refresh tokens",
+                "parameters": [
+                    {
+                        "description": "refresh token",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/{role}/login": {
+            "post": {
+                "description": "Login as author, clinic_admin, customer, doctor, pharmacist or super_admin",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Login",
+                "parameters": [
+                    {
+                        "enum": [
+                            "author",
+                            "clinic_admin",
+                            "customer",
+                            "doctor",
+                            "pharmacist",
+                            "super_admin"
+                        ],
+                        "type": "string",
+                        "description": "role",
+                        "name": "role",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "login and password",
+                        "name": "login",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
         "/author": {
             "get": {
                 "description": "Get authors list",
@@ -37,7 +276,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "limit",
+                        "description": "limit, 10 by default and at most 100",
                         "name": "limit",
                         "in": "query"
                     },
@@ -46,6 +285,36 @@ const docTemplate = `{
                         "description": "search",
                         "name": "search",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or after the date, YYYY-MM-DD",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or before the date, YYYY-MM-DD",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at (default), first_name, last_name, birth_date",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, desc for the default sorting and asc otherwise",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "list soft deleted records too, super admin only",
+                        "name": "include_deleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -61,12 +330,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -76,6 +357,11 @@ const docTemplate = `{
                 }
             },
             "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "description": "Create a new author",
                 "consumes": [
                     "application/json"
@@ -117,6 +403,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -153,6 +445,12 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/models.Author"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -167,6 +465,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -176,7 +480,12 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update author by id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update author by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
                 "consumes": [
                     "application/json"
                 ],
@@ -203,6 +512,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.UpdateAuthor"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -210,6 +525,12 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/models.Author"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -224,6 +545,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -233,6 +566,11 @@ const docTemplate = `{
                 }
             },
             "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "description": "Delete Author",
                 "consumes": [
                     "application/json"
@@ -272,6 +610,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -281,7 +625,12 @@ const docTemplate = `{
                 }
             },
             "patch": {
-                "description": "update author password",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update author by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
                 "consumes": [
                     "application/json"
                 ],
@@ -291,11 +640,11 @@ const docTemplate = `{
                 "tags": [
                     "author"
                 ],
-                "summary": "Update author password",
+                "summary": "Update author by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "author_id",
+                        "description": "author id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -306,15 +655,27 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateAuthorPassword"
+                            "$ref": "#/definitions/models.UpdateAuthor"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.Author"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -329,12 +690,161 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "412": {
+                        "description": "Precondition Failed",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
-                    }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/author/{id}/password": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update author password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "author"
+                ],
+                "summary": "Update author password",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "author_id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "author",
+                        "name": "author",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateAuthorPassword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/author/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restore a soft deleted author",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "author"
+                ],
+                "summary": "Restore author",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "author id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Author"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
                 }
             }
         },
@@ -360,7 +870,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "limit",
+                        "description": "limit, 10 by default and at most 100",
                         "name": "limit",
                         "in": "query"
                     },
@@ -369,6 +879,36 @@ const docTemplate = `{
                         "description": "search",
                         "name": "search",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or after the date, YYYY-MM-DD",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or before the date, YYYY-MM-DD",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at (default), name",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, desc for the default sorting and asc otherwise",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "list soft deleted records too, super admin only",
+                        "name": "include_deleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -384,12 +924,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -399,6 +951,11 @@ const docTemplate = `{
                 }
             },
             "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "description": "Create a new clinic",
                 "consumes": [
                     "application/json"
@@ -440,6 +997,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -476,6 +1039,12 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/models.Clinic"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -490,6 +1059,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -499,7 +1074,12 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update clinic by id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update clinic by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
                 "consumes": [
                     "application/json"
                 ],
@@ -526,6 +1106,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.UpdateClinic"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -533,6 +1119,12 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/models.Clinic"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -547,6 +1139,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -556,6 +1160,11 @@ const docTemplate = `{
                 }
             },
             "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "description": "Delete clinic",
                 "consumes": [
                     "application/json"
@@ -595,6 +1204,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -602,11 +1217,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/clinic_admin": {
-            "get": {
-                "description": "Get clinic admins list",
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update clinic by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
                 "consumes": [
                     "application/json"
                 ],
@@ -614,34 +1232,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "clinic_admin"
+                    "clinic"
                 ],
-                "summary": "Get clinic admins list",
+                "summary": "Update clinic by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query"
+                        "description": "clinic id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "clinic",
+                        "name": "clinic",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateClinic"
+                        }
                     },
                     {
                         "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ClinicAdminsResponse"
+                            "$ref": "#/definitions/models.Clinic"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -656,6 +1284,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -663,9 +1303,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/clinic/{id}/restore": {
             "post": {
-                "description": "Create a new clinic admin",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restore a soft deleted clinic together with the branches, doctor types and doctors its delete took with it",
                 "consumes": [
                     "application/json"
                 ],
@@ -673,25 +1320,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "clinic_admin"
+                    "clinic"
                 ],
-                "summary": "Create a new clinic admin",
+                "summary": "Restore clinic",
                 "parameters": [
                     {
-                        "description": "clinic admin data",
-                        "name": "clinic_admin",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateClinicAdmin"
-                        }
-                    }
-                ],
+                        "type": "string",
+                        "description": "clinic id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ClinicAdmin"
+                            "$ref": "#/definitions/models.Clinic"
                         }
                     },
                     "400": {
@@ -700,12 +1345,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -715,9 +1372,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/clinic_admin/{id}": {
+        "/clinic_admin": {
             "get": {
-                "description": "Get clinic admin by id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get clinic admins list",
                 "consumes": [
                     "application/json"
                 ],
@@ -727,21 +1389,74 @@ const docTemplate = `{
                 "tags": [
                     "clinic_admin"
                 ],
-                "summary": "Get clinic admin by id",
+                "summary": "Get clinic admins list",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "clinic_admin",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit, 10 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or after the date, YYYY-MM-DD",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or before the date, YYYY-MM-DD",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at (default), first_name, last_name",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, desc for the default sorting and asc otherwise",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "clinic branch id",
+                        "name": "clinic_branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "doctor type id",
+                        "name": "doctor_type_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "list soft deleted records too, super admin only",
+                        "name": "include_deleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ClinicAdmin"
+                            "$ref": "#/definitions/models.ClinicAdminsResponse"
                         }
                     },
                     "400": {
@@ -750,12 +1465,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -764,8 +1491,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Update clinic admin by id",
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new clinic admin",
                 "consumes": [
                     "application/json"
                 ],
@@ -775,28 +1507,21 @@ const docTemplate = `{
                 "tags": [
                     "clinic_admin"
                 ],
-                "summary": "Update clinic admin by id",
+                "summary": "Create a new clinic admin",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "clinic admin id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "clinic_admin",
+                        "description": "clinic admin data",
                         "name": "clinic_admin",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateClinicAdmin"
+                            "$ref": "#/definitions/models.CreateClinicAdmin"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "$ref": "#/definitions/models.ClinicAdmin"
                         }
@@ -813,6 +1538,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -820,9 +1551,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete clinic admin",
+            }
+        },
+        "/clinic_admin/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get clinic admin by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -832,11 +1570,11 @@ const docTemplate = `{
                 "tags": [
                     "clinic_admin"
                 ],
-                "summary": "Delete clinic admin",
+                "summary": "Get clinic admin by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "clinic_admin id",
+                        "description": "clinic_admin",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -846,7 +1584,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.ClinicAdmin"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -861,6 +1605,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -869,8 +1619,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
-                "description": "update clinic admin password",
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update clinic admin by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
                 "consumes": [
                     "application/json"
                 ],
@@ -880,11 +1635,11 @@ const docTemplate = `{
                 "tags": [
                     "clinic_admin"
                 ],
-                "summary": "Update clinic admin password",
+                "summary": "Update clinic admin by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "clinic admin",
+                        "description": "clinic admin id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -895,15 +1650,27 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateClinicAdminPassword"
+                            "$ref": "#/definitions/models.UpdateClinicAdmin"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.ClinicAdmin"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -918,6 +1685,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -925,11 +1704,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/clinic_branch": {
-            "get": {
-                "description": "Get clinic branchs list",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete clinic admin",
                 "consumes": [
                     "application/json"
                 ],
@@ -937,34 +1719,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "clinic_branch"
+                    "clinic_admin"
                 ],
-                "summary": "Get clinic branchs list",
+                "summary": "Delete clinic admin",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "clinic_admin id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ClinicBranchsResponse"
+                            "$ref": "#/definitions/models.Response"
                         }
                     },
                     "400": {
@@ -979,6 +1750,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -987,8 +1764,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "description": "Create a new clinic branch",
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update clinic admin by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
                 "consumes": [
                     "application/json"
                 ],
@@ -996,25 +1778,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "clinic_branch"
+                    "clinic_admin"
                 ],
-                "summary": "Create a new clinic branch",
+                "summary": "Update clinic admin by id",
                 "parameters": [
                     {
-                        "description": "clinic branch data",
-                        "name": "clinic_branch",
+                        "type": "string",
+                        "description": "clinic admin id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "clinic_admin",
+                        "name": "clinic_admin",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateClinicBranch"
+                            "$ref": "#/definitions/models.UpdateClinicAdmin"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ClinicBranch"
+                            "$ref": "#/definitions/models.ClinicAdmin"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -1029,6 +1830,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1038,9 +1851,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/clinic_branch/{id}": {
-            "get": {
-                "description": "Get clinic branch by id",
+        "/clinic_admin/{id}/password": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update clinic admin password",
                 "consumes": [
                     "application/json"
                 ],
@@ -1048,72 +1866,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "clinic_branch"
+                    "clinic_admin"
                 ],
-                "summary": "Get clinic branch by id",
+                "summary": "Update clinic admin password",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "clinic_branch",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.ClinicBranch"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/models.Response"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/models.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/models.Response"
-                        }
-                    }
-                }
-            },
-            "put": {
-                "description": "Update clinic branch by id",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "clinic_branch"
-                ],
-                "summary": "Update clinic branch by id",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "clinic branch id",
+                        "description": "clinic admin",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "clinic_branch",
-                        "name": "clinic_branch",
+                        "description": "clinic_admin",
+                        "name": "clinic_admin",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateClinicBranch"
+                            "$ref": "#/definitions/models.UpdateClinicAdminPassword"
                         }
                     }
                 ],
@@ -1121,7 +1891,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ClinicBranch"
+                            "$ref": "#/definitions/models.Response"
                         }
                     },
                     "400": {
@@ -1136,6 +1906,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1143,9 +1919,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete clinic branch",
+            }
+        },
+        "/clinic_admin/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restore a soft deleted clinic admin",
                 "consumes": [
                     "application/json"
                 ],
@@ -1153,13 +1936,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "clinic_branch"
+                    "clinic_admin"
                 ],
-                "summary": "Delete clinic branch",
+                "summary": "Restore clinic admin",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "clinic_branch id",
+                        "description": "clinic admin id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1169,7 +1952,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.ClinicAdmin"
                         }
                     },
                     "400": {
@@ -1178,12 +1961,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1193,9 +1988,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/customer": {
+        "/clinic_branch": {
             "get": {
-                "description": "Get customers list",
+                "description": "Get clinic branchs list",
                 "consumes": [
                     "application/json"
                 ],
@@ -1203,9 +1998,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "customer"
+                    "clinic_branch"
                 ],
-                "summary": "Get customers list",
+                "summary": "Get clinic branchs list",
                 "parameters": [
                     {
                         "type": "string",
@@ -1215,7 +2010,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "limit",
+                        "description": "limit, 10 by default and at most 100",
                         "name": "limit",
                         "in": "query"
                     },
@@ -1224,13 +2019,49 @@ const docTemplate = `{
                         "description": "search",
                         "name": "search",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or after the date, YYYY-MM-DD",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or before the date, YYYY-MM-DD",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at (default), address",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, desc for the default sorting and asc otherwise",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "clinic id",
+                        "name": "clinic_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "list soft deleted records too, super admin only",
+                        "name": "include_deleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.CustomersResponse"
+                            "$ref": "#/definitions/models.ClinicBranchsResponse"
                         }
                     },
                     "400": {
@@ -1239,12 +2070,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1254,7 +2097,12 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new customer",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new clinic branch",
                 "consumes": [
                     "application/json"
                 ],
@@ -1262,17 +2110,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "customer"
+                    "clinic_branch"
                 ],
-                "summary": "Create a new customer",
+                "summary": "Create a new clinic branch",
                 "parameters": [
                     {
-                        "description": "customer data",
-                        "name": "customer",
+                        "description": "clinic branch data",
+                        "name": "clinic_branch",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateCustomer"
+                            "$ref": "#/definitions/models.CreateClinicBranch"
                         }
                     }
                 ],
@@ -1280,7 +2128,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Customer"
+                            "$ref": "#/definitions/models.ClinicBranch"
                         }
                     },
                     "400": {
@@ -1295,6 +2143,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1304,9 +2158,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/customer/{id}": {
+        "/clinic_branch/{id}": {
             "get": {
-                "description": "Get customer by id",
+                "description": "Get clinic branch by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1314,13 +2168,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "customer"
+                    "clinic_branch"
                 ],
-                "summary": "Get customer by id",
+                "summary": "Get clinic branch by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "customer",
+                        "description": "clinic_branch",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1330,7 +2184,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Customer"
+                            "$ref": "#/definitions/models.ClinicBranch"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -1345,6 +2205,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1354,7 +2220,12 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update customer by id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update clinic branch by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
                 "consumes": [
                     "application/json"
                 ],
@@ -1362,32 +2233,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "customer"
+                    "clinic_branch"
                 ],
-                "summary": "Update customer by id",
+                "summary": "Update clinic branch by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "customer id",
+                        "description": "clinic branch id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "customer",
-                        "name": "customer",
+                        "description": "clinic_branch",
+                        "name": "clinic_branch",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateCustomer"
+                            "$ref": "#/definitions/models.UpdateClinicBranch"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Customer"
+                            "$ref": "#/definitions/models.ClinicBranch"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -1402,6 +2285,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1411,7 +2306,12 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete customer",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete clinic branch",
                 "consumes": [
                     "application/json"
                 ],
@@ -1419,13 +2319,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "customer"
+                    "clinic_branch"
                 ],
-                "summary": "Delete customer",
+                "summary": "Delete clinic branch",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "customer id",
+                        "description": "clinic_branch id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1450,6 +2350,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1459,7 +2365,12 @@ const docTemplate = `{
                 }
             },
             "patch": {
-                "description": "update customer password",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update clinic branch by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
                 "consumes": [
                     "application/json"
                 ],
@@ -1467,32 +2378,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "customer"
+                    "clinic_branch"
                 ],
-                "summary": "Update customer password",
+                "summary": "Update clinic branch by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "customer",
+                        "description": "clinic branch id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "customer",
-                        "name": "customer",
+                        "description": "clinic_branch",
+                        "name": "clinic_branch",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateCustomerPassword"
+                            "$ref": "#/definitions/models.UpdateClinicBranch"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.ClinicBranch"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -1507,6 +2430,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1516,36 +2451,28 @@ const docTemplate = `{
                 }
             }
         },
-        "/doctor": {
+        "/clinic_branch/{id}/queue_board": {
             "get": {
-                "description": "Get doctors list",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Server-Sent Events stream for waiting room screens. The first \"queue\" event is a snapshot of the day, next ones are created/updated/deleted deltas keyed by queue_id. A reconnecting client gets a fresh snapshot",
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
-                    "doctor"
+                    "queue"
                 ],
-                "summary": "Get doctors list",
+                "summary": "Live queue board of a clinic branch",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "clinic branch id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "search",
-                        "name": "search",
+                        "description": "date in YYYY-MM-DD format, today by default",
+                        "name": "date",
                         "in": "query"
                     }
                 ],
@@ -1553,7 +2480,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.DoctorsResponse"
+                            "$ref": "#/definitions/models.QueueBoardEvent"
                         }
                     },
                     "400": {
@@ -1562,8 +2489,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
@@ -1575,9 +2502,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/clinic_branch/{id}/restore": {
             "post": {
-                "description": "Create a new doctor",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restore a soft deleted clinic branch together with the doctor types and doctors its delete took with it",
                 "consumes": [
                     "application/json"
                 ],
@@ -1585,25 +2519,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "doctor"
+                    "clinic_branch"
                 ],
-                "summary": "Create a new doctor",
+                "summary": "Restore clinic branch",
                 "parameters": [
                     {
-                        "description": "doctor data",
-                        "name": "doctor",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateDoctor"
-                        }
+                        "type": "string",
+                        "description": "clinic branch id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Doctor"
+                            "$ref": "#/definitions/models.ClinicBranch"
                         }
                     },
                     "400": {
@@ -1612,12 +2544,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1627,9 +2571,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/doctor/{id}": {
+        "/customer": {
             "get": {
-                "description": "Get doctor by id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get customers list",
                 "consumes": [
                     "application/json"
                 ],
@@ -1637,23 +2586,64 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "doctor"
+                    "customer"
                 ],
-                "summary": "Get doctor by id",
+                "summary": "Get customers list",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "doctor",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit, 10 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or after the date, YYYY-MM-DD",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or before the date, YYYY-MM-DD",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at (default), first_name, last_name, birth_date",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, desc for the default sorting and asc otherwise",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "list soft deleted records too, super admin only",
+                        "name": "include_deleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Doctor"
+                            "$ref": "#/definitions/models.CustomersResponse"
                         }
                     },
                     "400": {
@@ -1662,12 +2652,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1676,8 +2678,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Update doctor by id",
+            "post": {
+                "description": "Create a new customer",
                 "consumes": [
                     "application/json"
                 ],
@@ -1685,32 +2687,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "doctor"
+                    "customer"
                 ],
-                "summary": "Update doctor by id",
+                "summary": "Create a new customer",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "doctor id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "doctor",
-                        "name": "doctor",
+                        "description": "customer data",
+                        "name": "customer",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateDoctor"
+                            "$ref": "#/definitions/models.CreateCustomer"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Doctor"
+                            "$ref": "#/definitions/models.Customer"
                         }
                     },
                     "400": {
@@ -1725,6 +2720,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1732,9 +2733,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete doctor",
+            }
+        },
+        "/customer/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get customer by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1742,13 +2750,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "doctor"
+                    "customer"
                 ],
-                "summary": "Delete doctor",
+                "summary": "Get customer by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "doctor id",
+                        "description": "customer",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1758,7 +2766,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.Customer"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -1773,6 +2787,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1781,8 +2801,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
-                "description": "update doctor password",
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update customer by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
                 "consumes": [
                     "application/json"
                 ],
@@ -1790,32 +2815,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "doctor"
+                    "customer"
                 ],
-                "summary": "Update doctor password",
+                "summary": "Update customer by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "doctor",
+                        "description": "customer id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "doctor",
-                        "name": "doctor",
+                        "description": "customer",
+                        "name": "customer",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateDoctorPassword"
+                            "$ref": "#/definitions/models.UpdateCustomer"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.Customer"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -1830,6 +2867,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1837,11 +2886,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/doctor_type": {
-            "get": {
-                "description": "Get doctor_types list",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete customer",
                 "consumes": [
                     "application/json"
                 ],
@@ -1849,34 +2901,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "doctor_type"
+                    "customer"
                 ],
-                "summary": "Get doctor_types list",
+                "summary": "Delete customer",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "customer id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.DoctorTypesResponse"
+                            "$ref": "#/definitions/models.Response"
                         }
                     },
                     "400": {
@@ -1891,6 +2932,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1899,8 +2946,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "description": "Create a new doctor type",
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update customer by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
                 "consumes": [
                     "application/json"
                 ],
@@ -1908,25 +2960,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "doctor_type"
+                    "customer"
                 ],
-                "summary": "Create a new doctor type",
+                "summary": "Update customer by id",
                 "parameters": [
                     {
-                        "description": "doctor type data",
-                        "name": "doctor_type",
+                        "type": "string",
+                        "description": "customer id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "customer",
+                        "name": "customer",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateDoctorType"
+                            "$ref": "#/definitions/models.UpdateCustomer"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.DoctorType"
+                            "$ref": "#/definitions/models.Customer"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -1941,18 +3012,35 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "412": {
+                        "description": "Precondition Failed",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
-                    }
-                }
-            }
-        },
-        "/doctor_type/{id}": {
-            "get": {
-                "description": "Get doctor type by id",
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/customer/{id}/password": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update customer password",
                 "consumes": [
                     "application/json"
                 ],
@@ -1960,23 +3048,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "doctor_type"
+                    "customer"
                 ],
-                "summary": "Get doctor type by id",
+                "summary": "Update customer password",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "doctor_type",
+                        "description": "customer",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "customer",
+                        "name": "customer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateCustomerPassword"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.DoctorType"
+                            "$ref": "#/definitions/models.Response"
                         }
                     },
                     "400": {
@@ -1991,6 +3088,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1998,9 +3101,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update doctor type by id",
+            }
+        },
+        "/customer/{id}/prescriptions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get prescriptions of a customer, newest first",
                 "consumes": [
                     "application/json"
                 ],
@@ -2008,32 +3118,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "doctor_type"
+                    "prescription"
                 ],
-                "summary": "Update doctor type by id",
+                "summary": "Get prescriptions of a customer",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "doctor type id",
+                        "description": "customer id",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "doctor_type",
-                        "name": "doctor_type",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateDoctorType"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.DoctorType"
+                            "$ref": "#/definitions/models.PrescriptionsResponse"
                         }
                     },
                     "400": {
@@ -2042,8 +3143,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
@@ -2055,9 +3156,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete doctor type",
+            }
+        },
+        "/customer/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restore a soft deleted customer",
                 "consumes": [
                     "application/json"
                 ],
@@ -2065,13 +3173,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "doctor_type"
+                    "customer"
                 ],
-                "summary": "Delete doctor type",
+                "summary": "Restore customer",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "doctor_type id",
+                        "description": "customer id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2081,7 +3189,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.Customer"
                         }
                     },
                     "400": {
@@ -2090,12 +3198,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2105,9 +3225,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/drug": {
+        "/doctor": {
             "get": {
-                "description": "Get drugs list",
+                "description": "Get doctors list",
                 "consumes": [
                     "application/json"
                 ],
@@ -2115,9 +3235,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "drug"
+                    "doctor"
                 ],
-                "summary": "Get drugs list",
+                "summary": "Get doctors list",
                 "parameters": [
                     {
                         "type": "string",
@@ -2127,7 +3247,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "limit",
+                        "description": "limit, 10 by default and at most 100",
                         "name": "limit",
                         "in": "query"
                     },
@@ -2136,13 +3256,61 @@ const docTemplate = `{
                         "description": "search",
                         "name": "search",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or after the date, YYYY-MM-DD",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or before the date, YYYY-MM-DD",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at (default), first_name, last_name, status",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, desc for the default sorting and asc otherwise",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "doctor type id",
+                        "name": "doctor_type_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "clinic branch id",
+                        "name": "clinic_branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "busy or empty",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "list soft deleted records too, super admin only",
+                        "name": "include_deleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.DrugsResponse"
+                            "$ref": "#/definitions/models.DoctorsResponse"
                         }
                     },
                     "400": {
@@ -2151,12 +3319,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2166,7 +3346,12 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new drug",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new doctor",
                 "consumes": [
                     "application/json"
                 ],
@@ -2174,17 +3359,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "drug"
+                    "doctor"
                 ],
-                "summary": "Create a new drug",
+                "summary": "Create a new doctor",
                 "parameters": [
                     {
-                        "description": "drug data",
-                        "name": "drug",
+                        "description": "doctor data",
+                        "name": "doctor",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateDrug"
+                            "$ref": "#/definitions/models.CreateDoctor"
                         }
                     }
                 ],
@@ -2192,7 +3377,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Drug"
+                            "$ref": "#/definitions/models.Doctor"
                         }
                     },
                     "400": {
@@ -2207,6 +3392,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2216,9 +3407,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/drug/{id}": {
+        "/doctor/{id}": {
             "get": {
-                "description": "Get drug by id",
+                "description": "Get doctor by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -2226,13 +3417,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "drug"
+                    "doctor"
                 ],
-                "summary": "Get drug by id",
+                "summary": "Get doctor by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "drug",
+                        "description": "doctor",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2242,7 +3433,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Drug"
+                            "$ref": "#/definitions/models.Doctor"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -2257,6 +3454,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2266,7 +3469,12 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update drug by id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update doctor by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
                 "consumes": [
                     "application/json"
                 ],
@@ -2274,32 +3482,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "drug"
+                    "doctor"
                 ],
-                "summary": "Update drug by id",
+                "summary": "Update doctor by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "drug id",
+                        "description": "doctor id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "drug",
-                        "name": "drug",
+                        "description": "doctor",
+                        "name": "doctor",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateDrug"
+                            "$ref": "#/definitions/models.UpdateDoctor"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Drug"
+                            "$ref": "#/definitions/models.Doctor"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -2314,6 +3534,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2323,7 +3555,12 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete drug",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete doctor",
                 "consumes": [
                     "application/json"
                 ],
@@ -2331,13 +3568,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "drug"
+                    "doctor"
                 ],
-                "summary": "Delete drug",
+                "summary": "Delete doctor",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "drug id",
+                        "description": "doctor id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2362,6 +3599,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2369,11 +3612,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/drug_store": {
-            "get": {
-                "description": "Get drug stores list",
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update doctor by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
                 "consumes": [
                     "application/json"
                 ],
@@ -2381,34 +3627,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "drug_store"
+                    "doctor"
                 ],
-                "summary": "Get drug stores list",
+                "summary": "Update doctor by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query"
+                        "description": "doctor id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "doctor",
+                        "name": "doctor",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateDoctor"
+                        }
                     },
                     {
                         "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.DrugStoresResponse"
+                            "$ref": "#/definitions/models.Doctor"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -2423,6 +3679,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2430,9 +3698,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new drug store",
+            }
+        },
+        "/doctor/{id}/journals": {
+            "get": {
+                "description": "Published journals linked to the doctor type of the doctor, newest first by default",
                 "consumes": [
                     "application/json"
                 ],
@@ -2440,25 +3710,47 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "drug_store"
+                    "journal"
                 ],
-                "summary": "Create a new drug store",
+                "summary": "Get journals for a doctor profile",
                 "parameters": [
                     {
-                        "description": "drug_store data",
-                        "name": "drug_store",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateDrugStore"
-                        }
+                        "type": "string",
+                        "description": "doctor id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit, 10 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at (default), theme",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, desc for the default sorting and asc otherwise",
+                        "name": "order",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.DrugStore"
+                            "$ref": "#/definitions/models.JournalsResponse"
                         }
                     },
                     "400": {
@@ -2473,6 +3765,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2482,9 +3780,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/drug_store/{id}": {
-            "get": {
-                "description": "Get drug store by id",
+        "/doctor/{id}/password": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update doctor password",
                 "consumes": [
                     "application/json"
                 ],
@@ -2492,23 +3795,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "drug_store"
+                    "doctor"
                 ],
-                "summary": "Get drug store by id",
+                "summary": "Update doctor password",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "drug_store",
+                        "description": "doctor",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "doctor",
+                        "name": "doctor",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateDoctorPassword"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.DrugStore"
+                            "$ref": "#/definitions/models.Response"
                         }
                     },
                     "400": {
@@ -2523,6 +3835,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2530,9 +3848,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update drug store by id",
+            }
+        },
+        "/doctor/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restore a soft deleted doctor",
                 "consumes": [
                     "application/json"
                 ],
@@ -2540,32 +3865,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "drug_store"
+                    "doctor"
                 ],
-                "summary": "Update drug store by id",
+                "summary": "Restore doctor",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "drug_store id",
+                        "description": "doctor id",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "drug_store",
-                        "name": "drug_store",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateDrugStore"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.DrugStore"
+                            "$ref": "#/definitions/models.Doctor"
                         }
                     },
                     "400": {
@@ -2574,12 +3890,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2587,9 +3915,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete drug store",
+            }
+        },
+        "/doctor/{id}/schedule": {
+            "get": {
+                "description": "Get weekly schedule of a doctor",
                 "consumes": [
                     "application/json"
                 ],
@@ -2597,13 +3927,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "drug_store"
+                    "doctor_schedule"
                 ],
-                "summary": "Delete drug store",
+                "summary": "Get weekly schedule of a doctor",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "drug_store id",
+                        "description": "doctor id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2613,7 +3943,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.DoctorSchedulesResponse"
                         }
                     },
                     "400": {
@@ -2628,6 +3958,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2637,9 +3973,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/drug_store_branch": {
+        "/doctor/{id}/slots": {
             "get": {
-                "description": "Get drug store branchs list",
+                "description": "Get free appointment slots of a doctor on the given date, start_time of a slot can be used to book a queue",
                 "consumes": [
                     "application/json"
                 ],
@@ -2647,34 +3983,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "drug_store_branch"
+                    "doctor_schedule"
                 ],
-                "summary": "Get drug store branchs list",
+                "summary": "Get free slots of a doctor",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "doctor id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "date in YYYY-MM-DD format",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.DrugStoreBranchsResponse"
+                            "$ref": "#/definitions/models.DoctorSlots"
                         }
                     },
                     "400": {
@@ -2696,9 +4028,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/doctor_schedule": {
             "post": {
-                "description": "Create a new drug store branch",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create working hours of a doctor for one weekday (0 - sunday, 6 - saturday), times are in HH:MM format",
                 "consumes": [
                     "application/json"
                 ],
@@ -2706,17 +4045,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "drug_store_branch"
+                    "doctor_schedule"
                 ],
-                "summary": "Create a new drug store branch",
+                "summary": "Create a doctor schedule",
                 "parameters": [
                     {
-                        "description": "drug_store_branch data",
-                        "name": "drug_store_branch",
+                        "description": "doctor schedule data",
+                        "name": "doctor_schedule",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateDrugStoreBranch"
+                            "$ref": "#/definitions/models.CreateDoctorSchedule"
                         }
                     }
                 ],
@@ -2724,7 +4063,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.DrugStoreBranch"
+                            "$ref": "#/definitions/models.DoctorSchedule"
                         }
                     },
                     "400": {
@@ -2739,6 +4078,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2748,9 +4093,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/drug_store_branch/{id}": {
+        "/doctor_schedule/{id}": {
             "get": {
-                "description": "Get drug store branch by id",
+                "description": "Get doctor schedule by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -2758,13 +4103,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "drug_store_branch"
+                    "doctor_schedule"
                 ],
-                "summary": "Get drug store branch by id",
+                "summary": "Get doctor schedule by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "drug_store_branch",
+                        "description": "doctor schedule id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2774,7 +4119,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.DrugStoreBranch"
+                            "$ref": "#/definitions/models.DoctorSchedule"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -2789,6 +4140,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2798,7 +4155,12 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update drug store branch by id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update doctor schedule by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
                 "consumes": [
                     "application/json"
                 ],
@@ -2806,32 +4168,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "drug_store_branch"
+                    "doctor_schedule"
                 ],
-                "summary": "Update drug store branch by id",
+                "summary": "Update doctor schedule by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "drug_store_branch id",
+                        "description": "doctor schedule id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "drug_store_branch",
-                        "name": "drug_store_branch",
+                        "description": "doctor schedule",
+                        "name": "doctor_schedule",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateDrugStoreBranch"
+                            "$ref": "#/definitions/models.UpdateDoctorSchedule"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.DrugStoreBranch"
+                            "$ref": "#/definitions/models.DoctorSchedule"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -2846,6 +4220,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2855,7 +4241,12 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete drug store branch",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete doctor schedule",
                 "consumes": [
                     "application/json"
                 ],
@@ -2863,13 +4254,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "drug_store_branch"
+                    "doctor_schedule"
                 ],
-                "summary": "Delete drug store branch",
+                "summary": "Delete doctor schedule",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "drug_store_branch id",
+                        "description": "doctor schedule id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2894,6 +4285,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2901,11 +4298,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/journal": {
-            "get": {
-                "description": "Get journals list",
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update doctor schedule by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
                 "consumes": [
                     "application/json"
                 ],
@@ -2913,34 +4313,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "journal"
+                    "doctor_schedule"
                 ],
-                "summary": "Get journals list",
+                "summary": "Update doctor schedule by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query"
+                        "description": "doctor schedule id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "doctor schedule",
+                        "name": "doctor_schedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateDoctorSchedule"
+                        }
                     },
                     {
                         "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.JournalsResponse"
+                            "$ref": "#/definitions/models.DoctorSchedule"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -2955,6 +4365,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2962,27 +4384,141 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new journal",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/doctor_type": {
+            "get": {
+                "description": "Get doctor_types list",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "journal"
+                    "doctor_type"
                 ],
-                "summary": "Create a new journal",
+                "summary": "Get doctor_types list",
                 "parameters": [
                     {
-                        "description": "journal data",
-                        "name": "journal",
+                        "type": "string",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit, 10 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or after the date, YYYY-MM-DD",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or before the date, YYYY-MM-DD",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at (default), name",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, desc for the default sorting and asc otherwise",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "clinic branch id",
+                        "name": "clinic_branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "list soft deleted records too, super admin only",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DoctorTypesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new doctor type",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "doctor_type"
+                ],
+                "summary": "Create a new doctor type",
+                "parameters": [
+                    {
+                        "description": "doctor type data",
+                        "name": "doctor_type",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateJournal"
+                            "$ref": "#/definitions/models.CreateDoctorType"
                         }
                     }
                 ],
@@ -2990,7 +4526,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Journal"
+                            "$ref": "#/definitions/models.DoctorType"
                         }
                     },
                     "400": {
@@ -3005,6 +4541,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3014,9 +4556,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/journal/{id}": {
+        "/doctor_type/{id}": {
             "get": {
-                "description": "Get journal by id",
+                "description": "Get doctor type by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -3024,13 +4566,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "journal"
+                    "doctor_type"
                 ],
-                "summary": "Get journal by id",
+                "summary": "Get doctor type by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "journal",
+                        "description": "doctor_type",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3040,7 +4582,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Journal"
+                            "$ref": "#/definitions/models.DoctorType"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -3055,6 +4603,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3064,7 +4618,12 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update journal by id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update doctor type by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
                 "consumes": [
                     "application/json"
                 ],
@@ -3072,32 +4631,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "journal"
+                    "doctor_type"
                 ],
-                "summary": "Update journal by id",
+                "summary": "Update doctor type by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "journal id",
+                        "description": "doctor type id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "journal",
-                        "name": "journal",
+                        "description": "doctor_type",
+                        "name": "doctor_type",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateJournal"
+                            "$ref": "#/definitions/models.UpdateDoctorType"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Journal"
+                            "$ref": "#/definitions/models.DoctorType"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -3112,6 +4683,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3121,7 +4704,12 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete journal",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete doctor type",
                 "consumes": [
                     "application/json"
                 ],
@@ -3129,13 +4717,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "journal"
+                    "doctor_type"
                 ],
-                "summary": "Delete journal",
+                "summary": "Delete doctor type",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "journal id",
+                        "description": "doctor_type id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3160,6 +4748,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3167,11 +4761,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/order_drug": {
-            "get": {
-                "description": "Get OrderDrugs list",
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update doctor type by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
                 "consumes": [
                     "application/json"
                 ],
@@ -3179,34 +4776,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "order_drug"
+                    "doctor_type"
                 ],
-                "summary": "Get OrderDrugs list",
+                "summary": "Update doctor type by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query"
+                        "description": "doctor type id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "doctor_type",
+                        "name": "doctor_type",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateDoctorType"
+                        }
                     },
                     {
                         "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.OrderDrugsResponse"
+                            "$ref": "#/definitions/models.DoctorType"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -3221,6 +4828,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3228,9 +4847,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/doctor_type/{id}/restore": {
             "post": {
-                "description": "Create a new OrderDrug",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restore a soft deleted doctor type together with the doctors its delete took with it",
                 "consumes": [
                     "application/json"
                 ],
@@ -3238,25 +4864,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "order_drug"
+                    "doctor_type"
                 ],
-                "summary": "Create a new OrderDrug",
+                "summary": "Restore doctor type",
                 "parameters": [
                     {
-                        "description": "OrderDrug data",
-                        "name": "orderDrug",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateOrderDrug"
-                        }
+                        "type": "string",
+                        "description": "doctor type id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.OrderDrug"
+                            "$ref": "#/definitions/models.DoctorType"
                         }
                     },
                     "400": {
@@ -3265,12 +4889,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3280,9 +4916,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/order_drug/{id}": {
+        "/drug": {
             "get": {
-                "description": "Get OrderDrug by id",
+                "description": "Get drugs list",
                 "consumes": [
                     "application/json"
                 ],
@@ -3290,23 +4926,94 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "order_drug"
+                    "drug"
                 ],
-                "summary": "Get OrderDrug by id",
+                "summary": "Get drugs list",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OrderDrug",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit, 10 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or after the date, YYYY-MM-DD",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or before the date, YYYY-MM-DD",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at (default), name, price, count, best_before",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, desc for the default sorting and asc otherwise",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "drug store branch id",
+                        "name": "drug_store_branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "catalogue drug, lists the branches selling it",
+                        "name": "drug_catalogue_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "lowest price",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "highest price",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "prescription-only drugs",
+                        "name": "prescription_required",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "list soft deleted records too, super admin only",
+                        "name": "include_deleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.OrderDrug"
+                            "$ref": "#/definitions/models.DrugsResponse"
                         }
                     },
                     "400": {
@@ -3315,12 +5022,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3329,8 +5048,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Update OrderDrug by id",
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new drug, the branch starts selling a catalogue drug. Name and prescription rule come from the catalogue, dates are in YYYY-MM-DD format and count becomes the first lot of the drug",
                 "consumes": [
                     "application/json"
                 ],
@@ -3338,32 +5062,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "order_drug"
+                    "drug"
                 ],
-                "summary": "Update OrderDrug by id",
+                "summary": "Create a new drug",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "OrderDrug id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "OrderDrug",
-                        "name": "OrderDrug",
+                        "description": "drug data",
+                        "name": "drug",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateOrderDrug"
+                            "$ref": "#/definitions/models.CreateDrug"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.OrderDrug"
+                            "$ref": "#/definitions/models.Drug"
                         }
                     },
                     "400": {
@@ -3378,6 +5095,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3385,9 +5108,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete OrderDrug",
+            }
+        },
+        "/drug/{id}": {
+            "get": {
+                "description": "Get drug by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -3395,13 +5120,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "order_drug"
+                    "drug"
                 ],
-                "summary": "Delete OrderDrug",
+                "summary": "Get drug by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OrderDrug id",
+                        "description": "drug",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3411,7 +5136,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.Drug"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -3426,6 +5157,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3433,11 +5170,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/orders": {
-            "get": {
-                "description": "Get Orders list",
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update drug by id, stock is changed through drug lots and the branch and catalogue drug can not be changed. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
                 "consumes": [
                     "application/json"
                 ],
@@ -3445,34 +5185,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "drug"
                 ],
-                "summary": "Get Orders list",
+                "summary": "Update drug by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query"
+                        "description": "drug id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "drug",
+                        "name": "drug",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateDrug"
+                        }
                     },
                     {
                         "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.OrdersResponse"
+                            "$ref": "#/definitions/models.Drug"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -3487,52 +5237,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/models.Response"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "Create a new Orders",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "orders"
-                ],
-                "summary": "Create a new Orders",
-                "parameters": [
-                    {
-                        "description": "Orders data",
-                        "name": "Orders",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateOrders"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/models.Orders"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "412": {
+                        "description": "Precondition Failed",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
@@ -3544,11 +5256,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/orders/{id}": {
-            "get": {
-                "description": "Get Orders by id",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete drug",
                 "consumes": [
                     "application/json"
                 ],
@@ -3556,13 +5271,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "drug"
                 ],
-                "summary": "Get Orders by id",
+                "summary": "Delete drug",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Orders",
+                        "description": "drug id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3572,7 +5287,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Orders"
+                            "$ref": "#/definitions/models.Response"
                         }
                     },
                     "400": {
@@ -3587,6 +5302,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3595,8 +5316,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Update Orders by id",
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update drug by id, stock is changed through drug lots and the branch and catalogue drug can not be changed. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
                 "consumes": [
                     "application/json"
                 ],
@@ -3604,32 +5330,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "drug"
                 ],
-                "summary": "Update Orders by id",
+                "summary": "Update drug by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Orders id",
+                        "description": "drug id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Orders",
-                        "name": "Orders",
+                        "description": "drug",
+                        "name": "drug",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateOrders"
+                            "$ref": "#/definitions/models.UpdateDrug"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Orders"
+                            "$ref": "#/definitions/models.Drug"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -3644,6 +5382,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3651,23 +5401,30 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete Orders",
-                "consumes": [
+            }
+        },
+        "/drug/{id}/lots": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get lots of a drug, the first to expire comes first",
+                "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "drug_lot"
                 ],
-                "summary": "Delete Orders",
+                "summary": "Get lots of a drug",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Orders id",
+                        "description": "drug id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3676,22 +5433,89 @@ const docTemplate = `{
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DrugLotsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/drug/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restore a soft deleted drug",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drug"
+                ],
+                "summary": "Restore drug",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "drug id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Drug"
+                        }
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3701,9 +5525,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/pharmacist": {
+        "/drug_catalogue": {
             "get": {
-                "description": "Get Pharmacists list",
+                "description": "Get drugs of the catalogue",
                 "consumes": [
                     "application/json"
                 ],
@@ -3711,9 +5535,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "pharmacist"
+                    "drug_catalogue"
                 ],
-                "summary": "Get Pharmacists list",
+                "summary": "Get drug catalogue",
                 "parameters": [
                     {
                         "type": "string",
@@ -3723,7 +5547,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "limit",
+                        "description": "limit, 10 by default and at most 100",
                         "name": "limit",
                         "in": "query"
                     },
@@ -3732,13 +5556,43 @@ const docTemplate = `{
                         "description": "search",
                         "name": "search",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or after the date, YYYY-MM-DD",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or before the date, YYYY-MM-DD",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at (default), name",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, desc for the default sorting and asc otherwise",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "list soft deleted records too, super admin only",
+                        "name": "include_deleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.PharmacistsResponse"
+                            "$ref": "#/definitions/models.DrugCataloguesResponse"
                         }
                     },
                     "400": {
@@ -3747,12 +5601,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3762,7 +5628,12 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new Pharmacist",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Add a drug to the catalogue shared by all drug stores, branches sell it through their drugs. Names are unique regardless of case",
                 "consumes": [
                     "application/json"
                 ],
@@ -3770,17 +5641,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "pharmacist"
+                    "drug_catalogue"
                 ],
-                "summary": "Create a new Pharmacist",
+                "summary": "Add a drug to the catalogue",
                 "parameters": [
                     {
-                        "description": "Pharmacist data",
-                        "name": "Pharmacist",
+                        "description": "drug_catalogue data",
+                        "name": "drug_catalogue",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreatePharmacist"
+                            "$ref": "#/definitions/models.CreateDrugCatalogue"
                         }
                     }
                 ],
@@ -3788,7 +5659,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Pharmacist"
+                            "$ref": "#/definitions/models.DrugCatalogue"
                         }
                     },
                     "400": {
@@ -3803,6 +5674,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3812,9 +5695,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/pharmacist/{id}": {
+        "/drug_catalogue/{id}": {
             "get": {
-                "description": "Get Pharmacist by id",
+                "description": "Get catalogue drug by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -3822,13 +5705,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "pharmacist"
+                    "drug_catalogue"
                 ],
-                "summary": "Get Pharmacist by id",
+                "summary": "Get catalogue drug by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Pharmacist",
+                        "description": "drug_catalogue",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3838,7 +5721,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Pharmacist"
+                            "$ref": "#/definitions/models.DrugCatalogue"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -3853,6 +5742,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3862,7 +5757,12 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update Pharmacist by id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update catalogue drug by id, name and prescription rule change for every branch selling it. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
                 "consumes": [
                     "application/json"
                 ],
@@ -3870,32 +5770,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "pharmacist"
+                    "drug_catalogue"
                 ],
-                "summary": "Update Pharmacist by id",
+                "summary": "Update catalogue drug by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Pharmacist id",
+                        "description": "drug_catalogue id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Pharmacist",
-                        "name": "Pharmacist",
+                        "description": "drug_catalogue",
+                        "name": "drug_catalogue",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdatePharmacist"
+                            "$ref": "#/definitions/models.UpdateDrugCatalogue"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Pharmacist"
+                            "$ref": "#/definitions/models.DrugCatalogue"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -3910,6 +5822,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3919,7 +5849,12 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete Pharmacist",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Remove the drug from the catalogue, drugs of the branches selling it are deleted with it",
                 "consumes": [
                     "application/json"
                 ],
@@ -3927,13 +5862,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "pharmacist"
+                    "drug_catalogue"
                 ],
-                "summary": "Delete Pharmacist",
+                "summary": "Delete catalogue drug",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Pharmacist id",
+                        "description": "drug_catalogue id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3958,6 +5893,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3967,7 +5908,12 @@ const docTemplate = `{
                 }
             },
             "patch": {
-                "description": "update pharmacist password",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update catalogue drug by id, name and prescription rule change for every branch selling it. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
                 "consumes": [
                     "application/json"
                 ],
@@ -3975,32 +5921,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "pharmacist"
+                    "drug_catalogue"
                 ],
-                "summary": "Update pharmacist password",
+                "summary": "Update catalogue drug by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "pharmacist",
+                        "description": "drug_catalogue id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "pharmacist",
-                        "name": "pharmacist",
+                        "description": "drug_catalogue",
+                        "name": "drug_catalogue",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdatePharmacistPassword"
+                            "$ref": "#/definitions/models.UpdateDrugCatalogue"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.DrugCatalogue"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -4015,6 +5973,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4024,9 +6000,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/queue": {
-            "get": {
-                "description": "Get Queues list",
+        "/drug_catalogue/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restore a soft deleted catalogue drug with the branch drugs its delete took",
                 "consumes": [
                     "application/json"
                 ],
@@ -4034,34 +6015,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "queue"
+                    "drug_catalogue"
                 ],
-                "summary": "Get Queues list",
+                "summary": "Restore catalogue drug",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "drug_catalogue id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.QueuesResponse"
+                            "$ref": "#/definitions/models.DrugCatalogue"
                         }
                     },
                     "400": {
@@ -4070,12 +6040,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4083,9 +6065,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/drug_lot": {
             "post": {
-                "description": "Create a new Queue",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Receive a lot of a drug into stock, expiry_date is in YYYY-MM-DD format. The lot is held by the branch of the drug and the drug count becomes the sum of its lots",
                 "consumes": [
                     "application/json"
                 ],
@@ -4093,17 +6082,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "queue"
+                    "drug_lot"
                 ],
-                "summary": "Create a new Queue",
+                "summary": "Create a drug lot",
                 "parameters": [
                     {
-                        "description": "Queue data",
-                        "name": "Queue",
+                        "description": "drug lot data",
+                        "name": "drug_lot",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateQueue"
+                            "$ref": "#/definitions/models.CreateDrugLot"
                         }
                     }
                 ],
@@ -4111,7 +6100,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Queue"
+                            "$ref": "#/definitions/models.DrugLot"
                         }
                     },
                     "400": {
@@ -4120,8 +6109,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
@@ -4135,9 +6130,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/queue/{id}": {
+        "/drug_lot/{id}": {
             "get": {
-                "description": "Get Queue by id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get drug lot by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -4145,13 +6145,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "queue"
+                    "drug_lot"
                 ],
-                "summary": "Get Queue by id",
+                "summary": "Get drug lot by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Queue",
+                        "description": "drug lot id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -4161,7 +6161,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Queue"
+                            "$ref": "#/definitions/models.DrugLot"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -4176,6 +6182,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4185,7 +6197,12 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update Queue by id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update drug lot by id, e.g. to correct the quantity after a stock count. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
                 "consumes": [
                     "application/json"
                 ],
@@ -4193,32 +6210,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "queue"
+                    "drug_lot"
                 ],
-                "summary": "Update Queue by id",
+                "summary": "Update drug lot by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Queue id",
+                        "description": "drug lot id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Queue",
-                        "name": "Queue",
+                        "description": "drug lot",
+                        "name": "drug_lot",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateQueue"
+                            "$ref": "#/definitions/models.UpdateDrugLot"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Queue"
+                            "$ref": "#/definitions/models.DrugLot"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -4227,8 +6256,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
@@ -4242,7 +6283,12 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete Queue",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete drug lot, its quantity is taken from the drug count",
                 "consumes": [
                     "application/json"
                 ],
@@ -4250,13 +6296,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "queue"
+                    "drug_lot"
                 ],
-                "summary": "Delete Queue",
+                "summary": "Delete drug lot",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Queue id",
+                        "description": "drug lot id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -4275,8 +6321,100 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update drug lot by id, e.g. to correct the quantity after a stock count. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drug_lot"
+                ],
+                "summary": "Update drug lot by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "drug lot id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "drug lot",
+                        "name": "drug_lot",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateDrugLot"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DrugLot"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
@@ -4290,9 +6428,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/super_admin": {
+        "/drug_store": {
             "get": {
-                "description": "Get SuperAdmins list",
+                "description": "Get drug stores list",
                 "consumes": [
                     "application/json"
                 ],
@@ -4300,9 +6438,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "super_admin"
+                    "drug_store"
                 ],
-                "summary": "Get SuperAdmins list",
+                "summary": "Get drug stores list",
                 "parameters": [
                     {
                         "type": "string",
@@ -4312,7 +6450,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "limit",
+                        "description": "limit, 10 by default and at most 100",
                         "name": "limit",
                         "in": "query"
                     },
@@ -4321,13 +6459,43 @@ const docTemplate = `{
                         "description": "search",
                         "name": "search",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or after the date, YYYY-MM-DD",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or before the date, YYYY-MM-DD",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at (default), name",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, desc for the default sorting and asc otherwise",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "list soft deleted records too, super admin only",
+                        "name": "include_deleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SuperAdminsResponse"
+                            "$ref": "#/definitions/models.DrugStoresResponse"
                         }
                     },
                     "400": {
@@ -4336,12 +6504,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4351,7 +6531,12 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new SuperAdmin",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new drug store",
                 "consumes": [
                     "application/json"
                 ],
@@ -4359,17 +6544,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "super_admin"
+                    "drug_store"
                 ],
-                "summary": "Create a new SuperAdmin",
+                "summary": "Create a new drug store",
                 "parameters": [
                     {
-                        "description": "SuperAdmin data",
-                        "name": "SuperAdmin",
+                        "description": "drug_store data",
+                        "name": "drug_store",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateSuperAdmin"
+                            "$ref": "#/definitions/models.CreateDrugStore"
                         }
                     }
                 ],
@@ -4377,7 +6562,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.SuperAdmin"
+                            "$ref": "#/definitions/models.DrugStore"
                         }
                     },
                     "400": {
@@ -4392,6 +6577,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4401,9 +6592,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/super_admin/{id}": {
+        "/drug_store/{id}": {
             "get": {
-                "description": "Get SuperAdmin by id",
+                "description": "Get drug store by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -4411,13 +6602,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "super_admin"
+                    "drug_store"
                 ],
-                "summary": "Get SuperAdmin by id",
+                "summary": "Get drug store by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "SuperAdmin",
+                        "description": "drug_store",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -4427,7 +6618,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SuperAdmin"
+                            "$ref": "#/definitions/models.DrugStore"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -4442,6 +6639,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4451,7 +6654,12 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update SuperAdmin by id",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update drug store by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
                 "consumes": [
                     "application/json"
                 ],
@@ -4459,32 +6667,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "super_admin"
+                    "drug_store"
                 ],
-                "summary": "Update SuperAdmin by id",
+                "summary": "Update drug store by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "SuperAdmin id",
+                        "description": "drug_store id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "SuperAdmin",
-                        "name": "SuperAdmin",
+                        "description": "drug_store",
+                        "name": "drug_store",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateSuperAdmin"
+                            "$ref": "#/definitions/models.UpdateDrugStore"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SuperAdmin"
+                            "$ref": "#/definitions/models.DrugStore"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -4499,6 +6719,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4508,7 +6740,12 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete SuperAdmin",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete drug store",
                 "consumes": [
                     "application/json"
                 ],
@@ -4516,13 +6753,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "super_admin"
+                    "drug_store"
                 ],
-                "summary": "Delete SuperAdmin",
+                "summary": "Delete drug store",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "SuperAdmin id",
+                        "description": "drug_store id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -4547,6 +6784,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4556,7 +6799,12 @@ const docTemplate = `{
                 }
             },
             "patch": {
-                "description": "update super_admin password",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update drug store by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
                 "consumes": [
                     "application/json"
                 ],
@@ -4564,32 +6812,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "super_admin"
+                    "drug_store"
                 ],
-                "summary": "Update super_admin password",
+                "summary": "Update drug store by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "super_admin",
+                        "description": "drug_store id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "super_admin",
-                        "name": "super_admin",
+                        "description": "drug_store",
+                        "name": "drug_store",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateSuperAdminPassword"
+                            "$ref": "#/definitions/models.UpdateDrugStore"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "$ref": "#/definitions/models.DrugStore"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
                         }
                     },
                     "400": {
@@ -4604,6 +6864,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4612,91 +6884,8020 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.Author": {
-            "type": "object",
-            "properties": {
-                "address": {
-                    "type": "string"
-                },
-                "age": {
-                    "type": "integer"
-                },
-                "birth_date": {
-                    "type": "string"
-                },
-                "created_at": {
+        },
+        "/drug_store/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restore a soft deleted drug store",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drug_store"
+                ],
+                "summary": "Restore drug store",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "drug store id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DrugStore"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/drug_store_branch": {
+            "get": {
+                "description": "Get drug store branchs list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drug_store_branch"
+                ],
+                "summary": "Get drug store branchs list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit, 10 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or after the date, YYYY-MM-DD",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or before the date, YYYY-MM-DD",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at (default), address",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, desc for the default sorting and asc otherwise",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "drug store id",
+                        "name": "drug_store_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "list soft deleted records too, super admin only",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DrugStoreBranchsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new drug store branch",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drug_store_branch"
+                ],
+                "summary": "Create a new drug store branch",
+                "parameters": [
+                    {
+                        "description": "drug_store_branch data",
+                        "name": "drug_store_branch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateDrugStoreBranch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.DrugStoreBranch"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/drug_store_branch/{id}": {
+            "get": {
+                "description": "Get drug store branch by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drug_store_branch"
+                ],
+                "summary": "Get drug store branch by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "drug_store_branch",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DrugStoreBranch"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update drug store branch by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drug_store_branch"
+                ],
+                "summary": "Update drug store branch by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "drug_store_branch id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "drug_store_branch",
+                        "name": "drug_store_branch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateDrugStoreBranch"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DrugStoreBranch"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete drug store branch",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drug_store_branch"
+                ],
+                "summary": "Delete drug store branch",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "drug_store_branch id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update drug store branch by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drug_store_branch"
+                ],
+                "summary": "Update drug store branch by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "drug_store_branch id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "drug_store_branch",
+                        "name": "drug_store_branch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateDrugStoreBranch"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DrugStoreBranch"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/drug_store_branch/{id}/expiring": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lots with drugs left which are already expired or expire within the period, the first to expire comes first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drug_lot"
+                ],
+                "summary": "Expiring drugs of a drug store branch",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "drug store branch id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "period like 30d, 2w or 30 (days), 30d by default",
+                        "name": "within",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ExpiringLotsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/drug_store_branch/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restore a soft deleted drug store branch",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drug_store_branch"
+                ],
+                "summary": "Restore drug store branch",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "drug store branch id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DrugStoreBranch"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "200 while the process is up, nothing else is checked",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get published journals list. Authors also get their own journals of every status, super admins get all journals",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Get journals list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit, 10 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "words of the theme or the article, see /journal/search",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or after the date, YYYY-MM-DD",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or before the date, YYYY-MM-DD",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at (default), theme",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, desc for the default sorting and asc otherwise",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "author id",
+                        "name": "author_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "draft, in_review, published or archived",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "category id",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "doctor type (specialty) id",
+                        "name": "doctor_type_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "list soft deleted records too, super admin only",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.JournalsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new journal as a draft, it is public only after a super admin approves it. An author writes for themselves, a super admin gives author_id. Without slug one is made from the theme. Tags are stored as lowercase words joined by \"-\", at most 10",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Create a new journal",
+                "parameters": [
+                    {
+                        "description": "journal data",
+                        "name": "journal",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateJournal"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Journal"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/search": {
+            "get": {
+                "description": "Full-text search over themes and articles of published journals, best matches first. q accepts \"quoted phrases\", or and -excluded words. Without language articles of every language are searched. Matched words of the theme and the snippet are wrapped in \u003cmark\u003e\u003c/mark\u003e, the text around them is HTML escaped",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Search journals",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "search text",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "uz, ru or en",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit, 10 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.JournalSearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/slug/{slug}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a published journal by the slug of its public url, visibility is the same as for /journal/{id}",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Get journal by slug",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "journal slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Journal"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/tags": {
+            "get": {
+                "description": "Tags of published journals with the number of journals of each, the most used first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Get journal tags",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.JournalTagsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a published journal by id. Its author and super admins also get drafts, journals in review, scheduled and archived ones",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Get journal by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "journal",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Journal"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Save the edited draft as its next version, a journal in another status has to be withdrawn first. Only super admins can give another author_id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Update journal by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "journal id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "journal",
+                        "name": "journal",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateJournal"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Journal"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete journal",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Delete journal",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "journal id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Save the edited draft as its next version, a journal in another status has to be withdrawn first. Only super admins can give another author_id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Update journal by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "journal id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "journal",
+                        "name": "journal",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateJournal"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Journal"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Super admin publishes the reviewed journal, in_review -\u003e published. publish_at (RFC 3339, e.g. 2024-05-01T09:00:00+05:00) schedules the release, without it or with a past time the journal is public right away",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Approve the journal",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "journal id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "publication time",
+                        "name": "approve",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApproveJournal"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Journal"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/{id}/archive": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Take the published journal down, published -\u003e archived",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Archive the journal",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "journal id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Journal"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/{id}/classification": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replace the category, the tags and the doctor types of the journal in any status, it does not make a new version. Empty values remove them",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Set category, tags and specialties of the journal",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "journal id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "category, tags and doctor types",
+                        "name": "classification",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ClassifyJournal"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Journal"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/{id}/diff": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Line by line changes of the theme and the article from one version to another. Without to the current version is used, without from the version before to",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Compare versions of the journal",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "journal id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "older version",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "newer version",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.JournalDiff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Super admin returns the reviewed journal to its author with a note, in_review -\u003e draft",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Reject the journal",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "journal id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "why the journal is rejected",
+                        "name": "reject",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RejectJournal"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Journal"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restore a soft deleted journal",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Restore journal",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "journal id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Journal"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/{id}/revisions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Every saved version of the journal, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Get revisions of the journal",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "journal id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.JournalRevisionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/{id}/revisions/{version}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the text of the journal as it was saved in the version",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Get a revision of the journal",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "journal id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.JournalRevision"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/{id}/rollback/{version}": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Save the text of an older version as the next version of the draft, newer versions stay in the history",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Roll the journal back to a version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "journal id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "version to roll back to",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Journal"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/{id}/submit": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Author sends the draft to super admins, draft -\u003e in_review",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Submit the journal for review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "journal id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Journal"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal/{id}/withdraw": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Turn the journal back into a draft to edit it, in_review, published or archived -\u003e draft. It has to be reviewed again to be public",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal"
+                ],
+                "summary": "Withdraw the journal",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "journal id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Journal"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal_category": {
+            "get": {
+                "description": "Get journal categories list, journals of a category are listed by /journal?category_id=",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal_category"
+                ],
+                "summary": "Get journal categories list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit, 10 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at (default), name",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, desc for the default sorting and asc otherwise",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "list soft deleted records too, super admin only",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.JournalCategoriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a journal category, names are unique regardless of case",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal_category"
+                ],
+                "summary": "Create a journal category",
+                "parameters": [
+                    {
+                        "description": "journal category data",
+                        "name": "journal_category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateJournalCategory"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.JournalCategory"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal_category/{id}": {
+            "get": {
+                "description": "Get journal category by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal_category"
+                ],
+                "summary": "Get journal category by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "journal category id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.JournalCategory"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update journal category by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal_category"
+                ],
+                "summary": "Update journal category by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "journal category id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "journal category",
+                        "name": "journal_category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateJournalCategory"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.JournalCategory"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete journal category, its journals are left without a category",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal_category"
+                ],
+                "summary": "Delete journal category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "journal category id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update journal category by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal_category"
+                ],
+                "summary": "Update journal category by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "journal category id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "journal category",
+                        "name": "journal_category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateJournalCategory"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.JournalCategory"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/journal_category/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restore a soft deleted journal category",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "journal_category"
+                ],
+                "summary": "Restore journal category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "journal category id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.JournalCategory"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics": {
+            "get": {
+                "description": "Prometheus metrics: requests by route and status, database pool stats and bookings, orders and\nprescriptions made",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Metrics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/order_drug": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get OrderDrugs list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order_drug"
+                ],
+                "summary": "Get OrderDrugs list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit, 10 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or after the date, YYYY-MM-DD",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or before the date, YYYY-MM-DD",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at (default), quantity, line_total",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, desc for the default sorting and asc otherwise",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "orders id, required for pharmacists",
+                        "name": "orders_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "drug id",
+                        "name": "drug_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "list soft deleted records too, super admin only",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderDrugsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new OrderDrug. Lines can be changed only while the order is pending and not checked out",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order_drug"
+                ],
+                "summary": "Create a new OrderDrug",
+                "parameters": [
+                    {
+                        "description": "OrderDrug data",
+                        "name": "orderDrug",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateOrderDrug"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderDrug"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/order_drug/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get OrderDrug by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order_drug"
+                ],
+                "summary": "Get OrderDrug by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OrderDrug",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderDrug"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update OrderDrug by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order_drug"
+                ],
+                "summary": "Update OrderDrug by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OrderDrug id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "OrderDrug",
+                        "name": "OrderDrug",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateOrderDrug"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderDrug"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete OrderDrug",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order_drug"
+                ],
+                "summary": "Delete OrderDrug",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OrderDrug id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update OrderDrug by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order_drug"
+                ],
+                "summary": "Update OrderDrug by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OrderDrug id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "OrderDrug",
+                        "name": "OrderDrug",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateOrderDrug"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderDrug"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/order_drug/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restore a soft deleted order drug",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order_drug"
+                ],
+                "summary": "Restore order drug",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "order drug id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderDrug"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get Orders list. Customers get their own orders, pharmacists the orders of their branch and the ones they opened",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get Orders list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit, 10 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, confirmed, preparing, ready_for_pickup, delivered, cancelled or refunded",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or after the date, YYYY-MM-DD",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or before the date, YYYY-MM-DD",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at (default), total, status",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, desc for the default sorting and asc otherwise",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page, used instead of page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "customer id",
+                        "name": "customer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "pharmacist id",
+                        "name": "pharmacist_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "drug store branch id",
+                        "name": "drug_store_branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "list soft deleted records too, super admin only",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrdersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new Orders",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Create a new Orders",
+                "parameters": [
+                    {
+                        "description": "Orders data",
+                        "name": "Orders",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateOrders"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Orders"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/checkout": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create an order with its drugs in one transaction, drug counts are decremented. Unit prices are copied from the drugs and totals are calculated on the server, discount (pharmacist only) is an amount taken from the subtotal. If any line can not be sold nothing is saved and the rejected lines are returned with 409",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Checkout an order",
+                "parameters": [
+                    {
+                        "description": "Checkout data",
+                        "name": "Checkout",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CheckoutOrder"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Orders"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.CheckoutLineError"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get Orders by id with its lines",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get Orders by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Orders",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Orders"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update Orders by id, only a pending order can be changed. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Update Orders by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Orders id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Orders",
+                        "name": "Orders",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateOrders"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Orders"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete Orders, a checked out order is deleted only once it is cancelled, delivered or refunded",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Delete Orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Orders id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update Orders by id, only a pending order can be changed. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Update Orders by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Orders id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Orders",
+                        "name": "Orders",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateOrders"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Orders"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Cancel a not delivered order, its drugs go back to stock",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Cancel the order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "orders id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Orders"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Pharmacy accepted the order, pending -\u003e confirmed. Only a checked out order can be confirmed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Confirm the order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "orders id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Orders"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/deliver": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Customer got the drugs, ready_for_pickup -\u003e delivered",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Hand over the order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "orders id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Orders"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/prepare": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Pharmacist started collecting the drugs, confirmed -\u003e preparing",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Start preparing the order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "orders id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Orders"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/ready": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Drugs are packed, preparing -\u003e ready_for_pickup",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Mark the order ready for pickup",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "orders id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Orders"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/refund": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Money of a delivered order was returned, delivered -\u003e refunded",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Refund the order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "orders id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Orders"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restore a soft deleted orders",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Restore orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "orders id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Orders"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/pharmacist": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get Pharmacists list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pharmacist"
+                ],
+                "summary": "Get Pharmacists list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit, 10 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or after the date, YYYY-MM-DD",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or before the date, YYYY-MM-DD",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at (default), first_name, last_name",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, desc for the default sorting and asc otherwise",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "drug store branch id",
+                        "name": "drug_store_branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "list soft deleted records too, super admin only",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PharmacistsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new Pharmacist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pharmacist"
+                ],
+                "summary": "Create a new Pharmacist",
+                "parameters": [
+                    {
+                        "description": "Pharmacist data",
+                        "name": "Pharmacist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreatePharmacist"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Pharmacist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/pharmacist/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get Pharmacist by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pharmacist"
+                ],
+                "summary": "Get Pharmacist by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pharmacist",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Pharmacist"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update Pharmacist by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pharmacist"
+                ],
+                "summary": "Update Pharmacist by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pharmacist id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Pharmacist",
+                        "name": "Pharmacist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdatePharmacist"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Pharmacist"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete Pharmacist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pharmacist"
+                ],
+                "summary": "Delete Pharmacist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pharmacist id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update Pharmacist by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pharmacist"
+                ],
+                "summary": "Update Pharmacist by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pharmacist id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Pharmacist",
+                        "name": "Pharmacist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdatePharmacist"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Pharmacist"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/pharmacist/{id}/password": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update pharmacist password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pharmacist"
+                ],
+                "summary": "Update pharmacist password",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pharmacist",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "pharmacist",
+                        "name": "pharmacist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdatePharmacistPassword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/pharmacist/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restore a soft deleted pharmacist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pharmacist"
+                ],
+                "summary": "Restore pharmacist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pharmacist id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Pharmacist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/prescription": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Doctor prescribes drugs of the catalogue to the customer of a queue which is in consultation or completed, any branch selling them can redeem it. valid_until is in YYYY-MM-DD format, 30 days from today by default. Every item can be sold quantity * (refills + 1) in total, the returned code is given to the pharmacist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "prescription"
+                ],
+                "summary": "Create a prescription",
+                "parameters": [
+                    {
+                        "description": "prescription data",
+                        "name": "prescription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreatePrescription"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Prescription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/prescription/code/{code}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Pharmacist looks up a prescription by the code the customer brings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "prescription"
+                ],
+                "summary": "Get prescription by code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "prescription code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Prescription"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/prescription/redeem": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Pharmacist sells drugs of their branch for items of the prescription as one order, checked out like /orders/checkout. Each redeemed item sells at most its prescribed quantity, quantity defaults to it or to what is left of the prescription. A smaller sale keeps the rest for later, items left out stay available",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "prescription"
+                ],
+                "summary": "Redeem a prescription",
+                "parameters": [
+                    {
+                        "description": "redeem data",
+                        "name": "redeem",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RedeemPrescription"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Orders"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.CheckoutLineError"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/prescription/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get prescription by id with its items and how many times they were redeemed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "prescription"
+                ],
+                "summary": "Get prescription by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "prescription id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Prescription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Doctor withdraws their prescription, it can not be redeemed anymore",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "prescription"
+                ],
+                "summary": "Delete prescription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "prescription id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/queue": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get Queues list. Customers get their own queues, doctors their own queues and clinic admins the queues of their branch",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Get Queues list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit, 10 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or after the date, YYYY-MM-DD",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or before the date, YYYY-MM-DD",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at (default), start_time, queue_number, status",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, desc for the default sorting and asc otherwise",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page, used instead of page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "doctor id",
+                        "name": "doctor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "customer id",
+                        "name": "customer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "booked, checked_in, called, in_consultation, completed, cancelled or no_show",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "list soft deleted records too, super admin only",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.QueuesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Book a free slot of the doctor, start_time should be taken from GET /doctor/{id}/slots (YYYY-MM-DD HH:MM)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Create a new Queue",
+                "parameters": [
+                    {
+                        "description": "Queue data",
+                        "name": "Queue",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateQueue"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Queue"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/queue/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get Queue by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Get Queue by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Queue",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Queue"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update Queue by id, only a booked queue can be changed. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Update Queue by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Queue id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Queue",
+                        "name": "Queue",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateQueue"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Queue"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete Queue",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Delete Queue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Queue id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update Queue by id, only a booked queue can be changed. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Update Queue by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Queue id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Queue",
+                        "name": "Queue",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateQueue"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Queue"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/queue/{id}/call": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Doctor calls the customer, checked_in -\u003e called",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Call the customer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "queue id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Queue"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/queue/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Cancel a booked or checked in queue, the slot becomes free again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Cancel the queue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "queue id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Queue"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/queue/{id}/check_in": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Customer arrived to the clinic, booked -\u003e checked_in",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Check in to the queue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "queue id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Queue"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/queue/{id}/complete": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Consultation finished, in_consultation -\u003e completed, doctor becomes empty",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Complete consultation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "queue id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Queue"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/queue/{id}/no_show": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Customer did not come, booked/checked_in/called -\u003e no_show",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Mark the queue as no-show",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "queue id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Queue"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/queue/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restore a soft deleted queue",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Restore queue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "queue id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Queue"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/queue/{id}/start": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Consultation started, called -\u003e in_consultation, doctor becomes busy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Start consultation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "queue id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Queue"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "200 while the server takes requests, the database answers and its schema is migrated.\n503 when a check fails or once the server drains requests before it stops",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Readiness"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Readiness"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/super_admin": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get SuperAdmins list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "super_admin"
+                ],
+                "summary": "Get SuperAdmins list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit, 10 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or after the date, YYYY-MM-DD",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or before the date, YYYY-MM-DD",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at (default), first_name, last_name",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, desc for the default sorting and asc otherwise",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "list soft deleted records too, super admin only",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuperAdminsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new SuperAdmin",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "super_admin"
+                ],
+                "summary": "Create a new SuperAdmin",
+                "parameters": [
+                    {
+                        "description": "SuperAdmin data",
+                        "name": "SuperAdmin",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateSuperAdmin"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuperAdmin"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/super_admin/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get SuperAdmin by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "super_admin"
+                ],
+                "summary": "Get SuperAdmin by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SuperAdmin",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuperAdmin"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update SuperAdmin by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "super_admin"
+                ],
+                "summary": "Update SuperAdmin by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SuperAdmin id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "SuperAdmin",
+                        "name": "SuperAdmin",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateSuperAdmin"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuperAdmin"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete SuperAdmin",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "super_admin"
+                ],
+                "summary": "Delete SuperAdmin",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SuperAdmin id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update SuperAdmin by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "super_admin"
+                ],
+                "summary": "Update SuperAdmin by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SuperAdmin id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "SuperAdmin",
+                        "name": "SuperAdmin",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateSuperAdmin"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record, the update fails with 412 when the record was changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuperAdmin"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "version of the record, send it in If-Match to update the record"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/super_admin/{id}/password": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update super_admin password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "super_admin"
+                ],
+                "summary": "Update super_admin password",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "super_admin",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "super_admin",
+                        "name": "super_admin",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateSuperAdminPassword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/super_admin/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restore a soft deleted super admin",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "super_admin"
+                ],
+                "summary": "Restore super admin",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "super admin id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuperAdmin"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.ApproveJournal": {
+            "type": "object",
+            "properties": {
+                "publish_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AuditLog": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor_id": {
+                    "type": "string"
+                },
+                "actor_role": {
+                    "type": "string"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "entity": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AuditLogsResponse": {
+            "type": "object",
+            "properties": {
+                "audit_logs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AuditLog"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Author": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "age": {
+                    "type": "integer"
+                },
+                "birth_date": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "gender": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AuthorsResponse": {
+            "type": "object",
+            "properties": {
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Author"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CheckoutItem": {
+            "type": "object",
+            "required": [
+                "drug_id"
+            ],
+            "properties": {
+                "drug_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CheckoutLineError": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "integer"
+                },
+                "drug_id": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CheckoutOrder": {
+            "type": "object",
+            "required": [
+                "customer_id",
+                "drug_store_branch_id",
+                "items"
+            ],
+            "properties": {
+                "customer_id": {
+                    "type": "string"
+                },
+                "discount": {
+                    "type": "string"
+                },
+                "drug_store_branch_id": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.CheckoutItem"
+                    }
+                },
+                "pharmacist_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ClassifyJournal": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "string"
+                },
+                "doctor_type_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.Clinic": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ClinicAdmin": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "age": {
+                    "type": "integer"
+                },
+                "birth_date": {
+                    "type": "string"
+                },
+                "clinic_branch_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "doctor_type_id": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "gender": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ClinicAdminsResponse": {
+            "type": "object",
+            "properties": {
+                "clinic_admins": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ClinicAdmin"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ClinicBranch": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "clinic_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                },
+                "working_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ClinicBranchsResponse": {
+            "type": "object",
+            "properties": {
+                "clinic_branch": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ClinicBranch"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ClinicsResponse": {
+            "type": "object",
+            "properties": {
+                "clinics": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Clinic"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateAuthor": {
+            "type": "object",
+            "required": [
+                "address",
+                "birth_date",
+                "email",
+                "first_name",
+                "gender",
+                "last_name",
+                "password",
+                "phone"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "maxLength": 120
+                },
+                "birth_date": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "first_name": {
+                    "type": "string",
+                    "maxLength": 25
+                },
+                "gender": {
+                    "type": "string",
+                    "enum": [
+                        "male",
+                        "female"
+                    ]
+                },
+                "last_name": {
+                    "type": "string",
+                    "maxLength": 25
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateClinic": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50
+                }
+            }
+        },
+        "models.CreateClinicAdmin": {
+            "type": "object",
+            "required": [
+                "address",
+                "birth_date",
+                "clinic_branch_id",
+                "email",
+                "first_name",
+                "gender",
+                "last_name",
+                "password",
+                "phone"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "maxLength": 120
+                },
+                "birth_date": {
+                    "type": "string"
+                },
+                "clinic_branch_id": {
+                    "type": "string"
+                },
+                "doctor_type_id": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "first_name": {
+                    "type": "string",
+                    "maxLength": 25
+                },
+                "gender": {
+                    "type": "string",
+                    "enum": [
+                        "male",
+                        "female"
+                    ]
+                },
+                "last_name": {
+                    "type": "string",
+                    "maxLength": 25
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateClinicBranch": {
+            "type": "object",
+            "required": [
+                "address",
+                "clinic_id",
+                "phone",
+                "working_time"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "clinic_id": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "working_time": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "models.CreateCustomer": {
+            "type": "object",
+            "required": [
+                "address",
+                "birth_date",
+                "email",
+                "first_name",
+                "gender",
+                "last_name",
+                "password",
+                "phone"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "maxLength": 120
+                },
+                "birth_date": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "first_name": {
+                    "type": "string",
+                    "maxLength": 25
+                },
+                "gender": {
+                    "type": "string",
+                    "enum": [
+                        "male",
+                        "female"
+                    ]
+                },
+                "last_name": {
+                    "type": "string",
+                    "maxLength": 25
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateDoctor": {
+            "type": "object",
+            "required": [
+                "address",
+                "birth_date",
+                "doctor_type_id",
+                "email",
+                "first_name",
+                "gender",
+                "last_name",
+                "password",
+                "phone",
+                "working_time"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "maxLength": 120
+                },
+                "birth_date": {
+                    "type": "string"
+                },
+                "doctor_type_id": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "first_name": {
+                    "type": "string",
+                    "maxLength": 25
+                },
+                "gender": {
+                    "type": "string",
+                    "enum": [
+                        "male",
+                        "female"
+                    ]
+                },
+                "last_name": {
+                    "type": "string",
+                    "maxLength": 25
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is empty for a new doctor when it is omitted",
+                    "type": "string",
+                    "enum": [
+                        "busy",
+                        "empty"
+                    ]
+                },
+                "working_time": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "models.CreateDoctorSchedule": {
+            "type": "object",
+            "required": [
+                "doctor_id",
+                "end_time",
+                "start_time"
+            ],
+            "properties": {
+                "break_end": {
+                    "type": "string"
+                },
+                "break_start": {
+                    "type": "string"
+                },
+                "doctor_id": {
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "slot_duration": {
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "weekday": {
+                    "type": "integer",
+                    "maximum": 6,
+                    "minimum": 0
+                }
+            }
+        },
+        "models.CreateDoctorType": {
+            "type": "object",
+            "required": [
+                "clinic_branch_id",
+                "name"
+            ],
+            "properties": {
+                "clinic_branch_id": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50
+                }
+            }
+        },
+        "models.CreateDrug": {
+            "type": "object",
+            "required": [
+                "best_before",
+                "date_of_manufacture",
+                "drug_catalogue_id",
+                "drug_store_branch_id",
+                "price"
+            ],
+            "properties": {
+                "best_before": {
+                    "type": "string"
+                },
+                "count": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "date_of_manufacture": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "drug_catalogue_id": {
+                    "type": "string"
+                },
+                "drug_store_branch_id": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateDrugCatalogue": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "prescription_required": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.CreateDrugLot": {
+            "type": "object",
+            "required": [
+                "drug_id",
+                "expiry_date",
+                "lot_number"
+            ],
+            "properties": {
+                "drug_id": {
+                    "type": "string"
+                },
+                "expiry_date": {
+                    "type": "string"
+                },
+                "lot_number": {
+                    "type": "string"
+                },
+                "purchase_price": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "models.CreateDrugStore": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50
+                }
+            }
+        },
+        "models.CreateDrugStoreBranch": {
+            "type": "object",
+            "required": [
+                "address",
+                "drug_store_id",
+                "phone",
+                "working_time"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "maxLength": 120
+                },
+                "drug_store_id": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "working_time": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "models.CreateJournal": {
+            "type": "object",
+            "required": [
+                "article",
+                "theme"
+            ],
+            "properties": {
+                "article": {
+                    "type": "string"
+                },
+                "author_id": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "doctor_type_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "language": {
+                    "type": "string",
+                    "enum": [
+                        "uz",
+                        "ru",
+                        "en"
+                    ]
+                },
+                "slug": {
+                    "type": "string",
+                    "maxLength": 180
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "theme": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateJournalCategory": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "models.CreateOrderDrug": {
+            "type": "object",
+            "required": [
+                "drug_id",
+                "orders_id"
+            ],
+            "properties": {
+                "drug_id": {
+                    "type": "string"
+                },
+                "orders_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateOrders": {
+            "type": "object",
+            "required": [
+                "customer_id"
+            ],
+            "properties": {
+                "customer_id": {
+                    "type": "string"
+                },
+                "pharmacist_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreatePharmacist": {
+            "type": "object",
+            "required": [
+                "address",
+                "birth_date",
+                "drug_store_branch_id",
+                "email",
+                "first_name",
+                "gender",
+                "last_name",
+                "password",
+                "phone"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "maxLength": 120
+                },
+                "birth_date": {
                     "type": "string"
                 },
-                "deleted_at": {
+                "drug_store_branch_id": {
                     "type": "string"
                 },
                 "email": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 50
                 },
                 "first_name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 25
                 },
                 "gender": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
+                    "type": "string",
+                    "enum": [
+                        "male",
+                        "female"
+                    ]
                 },
                 "last_name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 25
                 },
                 "password": {
-                    "type": "string"
+                    "type": "string",
+                    "minLength": 8
                 },
                 "phone": {
                     "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
                 }
             }
         },
-        "models.AuthorsResponse": {
+        "models.CreatePrescription": {
             "type": "object",
+            "required": [
+                "items",
+                "queue_id"
+            ],
             "properties": {
-                "authors": {
+                "items": {
                     "type": "array",
+                    "minItems": 1,
                     "items": {
-                        "$ref": "#/definitions/models.Author"
+                        "$ref": "#/definitions/models.CreatePrescriptionItem"
                     }
                 },
-                "count": {
+                "note": {
+                    "type": "string"
+                },
+                "queue_id": {
+                    "type": "string"
+                },
+                "valid_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreatePrescriptionItem": {
+            "type": "object",
+            "required": [
+                "dosage",
+                "drug_catalogue_id"
+            ],
+            "properties": {
+                "dosage": {
+                    "type": "string"
+                },
+                "drug_catalogue_id": {
+                    "type": "string"
+                },
+                "duration_days": {
+                    "type": "integer"
+                },
+                "quantity": {
                     "type": "integer"
+                },
+                "refills": {
+                    "type": "integer",
+                    "minimum": 0
                 }
             }
         },
-        "models.Clinic": {
+        "models.CreateQueue": {
             "type": "object",
+            "required": [
+                "customer_id",
+                "doctor_id",
+                "start_time"
+            ],
             "properties": {
-                "created_at": {
+                "customer_id": {
                     "type": "string"
                 },
-                "deleted_at": {
+                "doctor_id": {
                     "type": "string"
                 },
-                "description": {
+                "start_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateSuperAdmin": {
+            "type": "object",
+            "required": [
+                "address",
+                "birth_date",
+                "email",
+                "first_name",
+                "gender",
+                "last_name",
+                "password",
+                "phone"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "maxLength": 120
+                },
+                "author_id": {
                     "type": "string"
                 },
-                "id": {
+                "birth_date": {
                     "type": "string"
                 },
-                "name": {
+                "clinic_id": {
                     "type": "string"
                 },
-                "updated_at": {
+                "drug_store_id": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "first_name": {
+                    "type": "string",
+                    "maxLength": 25
+                },
+                "gender": {
+                    "type": "string",
+                    "enum": [
+                        "male",
+                        "female"
+                    ]
+                },
+                "last_name": {
+                    "type": "string",
+                    "maxLength": 25
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                },
+                "phone": {
                     "type": "string"
                 }
             }
         },
-        "models.ClinicAdmin": {
+        "models.Customer": {
             "type": "object",
             "properties": {
                 "address": {
@@ -4708,18 +14909,12 @@ const docTemplate = `{
                 "birth_date": {
                     "type": "string"
                 },
-                "clinic_branch_id": {
-                    "type": "string"
-                },
                 "created_at": {
                     "type": "string"
                 },
                 "deleted_at": {
                     "type": "string"
                 },
-                "doctor_type_id": {
-                    "type": "string"
-                },
                 "email": {
                     "type": "string"
                 },
@@ -4735,38 +14930,52 @@ const docTemplate = `{
                 "last_name": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string"
-                },
                 "phone": {
                     "type": "string"
                 },
                 "updated_at": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "models.ClinicAdminsResponse": {
+        "models.CustomersResponse": {
             "type": "object",
             "properties": {
-                "clinic_admins": {
+                "count": {
+                    "type": "integer"
+                },
+                "customers": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.ClinicAdmin"
+                        "$ref": "#/definitions/models.Customer"
                     }
+                }
+            }
+        },
+        "models.DiffLine": {
+            "type": "object",
+            "properties": {
+                "op": {
+                    "type": "string"
                 },
-                "count": {
-                    "type": "integer"
+                "text": {
+                    "type": "string"
                 }
             }
         },
-        "models.ClinicBranch": {
+        "models.Doctor": {
             "type": "object",
             "properties": {
                 "address": {
                     "type": "string"
                 },
-                "clinic_id": {
+                "age": {
+                    "type": "integer"
+                },
+                "birth_date": {
                     "type": "string"
                 },
                 "created_at": {
@@ -4775,699 +14984,898 @@ const docTemplate = `{
                 "deleted_at": {
                     "type": "string"
                 },
+                "doctor_type_id": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "gender": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
+                "last_name": {
+                    "type": "string"
+                },
                 "phone": {
                     "type": "string"
                 },
+                "status": {
+                    "type": "string"
+                },
                 "updated_at": {
                     "type": "string"
                 },
+                "version": {
+                    "type": "integer"
+                },
                 "working_time": {
                     "type": "string"
                 }
             }
         },
-        "models.ClinicBranchsResponse": {
+        "models.DoctorSchedule": {
             "type": "object",
             "properties": {
-                "clinic_branch": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.ClinicBranch"
-                    }
+                "break_end": {
+                    "type": "string"
                 },
-                "count": {
+                "break_start": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "doctor_id": {
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "slot_duration": {
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                },
+                "weekday": {
                     "type": "integer"
                 }
             }
         },
-        "models.ClinicsResponse": {
+        "models.DoctorSchedulesResponse": {
             "type": "object",
             "properties": {
-                "clinics": {
+                "count": {
+                    "type": "integer"
+                },
+                "doctor_schedules": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Clinic"
+                        "$ref": "#/definitions/models.DoctorSchedule"
                     }
-                },
-                "count": {
-                    "type": "integer"
                 }
             }
         },
-        "models.CreateAuthor": {
+        "models.DoctorSlots": {
             "type": "object",
             "properties": {
-                "address": {
+                "date": {
                     "type": "string"
                 },
-                "birth_date": {
+                "doctor_id": {
                     "type": "string"
                 },
-                "email": {
+                "slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Slot"
+                    }
+                }
+            }
+        },
+        "models.DoctorType": {
+            "type": "object",
+            "properties": {
+                "clinic_branch_id": {
                     "type": "string"
                 },
-                "first_name": {
+                "created_at": {
                     "type": "string"
                 },
-                "gender": {
+                "deleted_at": {
                     "type": "string"
                 },
-                "last_name": {
+                "description": {
                     "type": "string"
                 },
-                "password": {
+                "id": {
                     "type": "string"
                 },
-                "phone": {
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "models.CreateClinic": {
+        "models.DoctorTypesResponse": {
             "type": "object",
             "properties": {
-                "description": {
-                    "type": "string"
+                "count": {
+                    "type": "integer"
                 },
-                "name": {
-                    "type": "string"
+                "doctor_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DoctorType"
+                    }
                 }
             }
         },
-        "models.CreateClinicAdmin": {
+        "models.DoctorsResponse": {
             "type": "object",
             "properties": {
-                "address": {
-                    "type": "string"
-                },
-                "birth_date": {
-                    "type": "string"
+                "count": {
+                    "type": "integer"
                 },
-                "clinic_branch_id": {
+                "doctors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Doctor"
+                    }
+                }
+            }
+        },
+        "models.Drug": {
+            "type": "object",
+            "properties": {
+                "best_before": {
                     "type": "string"
                 },
-                "doctor_type_id": {
-                    "type": "string"
+                "count": {
+                    "type": "integer"
                 },
-                "email": {
+                "created_at": {
                     "type": "string"
                 },
-                "first_name": {
+                "date_of_manufacture": {
                     "type": "string"
                 },
-                "gender": {
+                "deleted_at": {
                     "type": "string"
                 },
-                "last_name": {
+                "description": {
                     "type": "string"
                 },
-                "password": {
+                "drug_catalogue_id": {
                     "type": "string"
                 },
-                "phone": {
+                "drug_store_branch_id": {
                     "type": "string"
-                }
-            }
-        },
-        "models.CreateClinicBranch": {
-            "type": "object",
-            "properties": {
-                "address": {
+                },
+                "id": {
                     "type": "string"
                 },
-                "clinic_id": {
+                "name": {
                     "type": "string"
                 },
-                "phone": {
+                "prescription_required": {
+                    "type": "boolean"
+                },
+                "price": {
                     "type": "string"
                 },
-                "working_time": {
+                "updated_at": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "models.CreateCustomer": {
+        "models.DrugCatalogue": {
             "type": "object",
             "properties": {
-                "address": {
+                "created_at": {
                     "type": "string"
                 },
-                "birth_date": {
+                "deleted_at": {
                     "type": "string"
                 },
-                "email": {
+                "description": {
                     "type": "string"
                 },
-                "first_name": {
+                "id": {
                     "type": "string"
                 },
-                "gender": {
+                "name": {
                     "type": "string"
                 },
-                "last_name": {
-                    "type": "string"
+                "prescription_required": {
+                    "type": "boolean"
                 },
-                "password": {
+                "updated_at": {
                     "type": "string"
                 },
-                "phone": {
-                    "type": "string"
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "models.CreateDoctor": {
+        "models.DrugCataloguesResponse": {
             "type": "object",
             "properties": {
-                "address": {
-                    "type": "string"
+                "count": {
+                    "type": "integer"
                 },
-                "birth_date": {
+                "drug_catalogues": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DrugCatalogue"
+                    }
+                }
+            }
+        },
+        "models.DrugLot": {
+            "type": "object",
+            "properties": {
+                "created_at": {
                     "type": "string"
                 },
-                "doctor_type_id": {
+                "deleted_at": {
                     "type": "string"
                 },
-                "email": {
+                "drug_id": {
                     "type": "string"
                 },
-                "first_name": {
+                "drug_store_branch_id": {
                     "type": "string"
                 },
-                "gender": {
+                "expiry_date": {
                     "type": "string"
                 },
-                "last_name": {
+                "id": {
                     "type": "string"
                 },
-                "password": {
+                "lot_number": {
                     "type": "string"
                 },
-                "phone": {
+                "purchase_price": {
                     "type": "string"
                 },
-                "status": {
-                    "type": "string"
+                "quantity": {
+                    "type": "integer"
                 },
-                "working_time": {
+                "updated_at": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "models.CreateDoctorType": {
+        "models.DrugLotsResponse": {
             "type": "object",
             "properties": {
-                "clinic_branch_id": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
+                "count": {
+                    "type": "integer"
                 },
-                "name": {
-                    "type": "string"
+                "drug_lots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DrugLot"
+                    }
                 }
             }
         },
-        "models.CreateDrug": {
+        "models.DrugStore": {
             "type": "object",
             "properties": {
-                "best_before": {
+                "created_at": {
                     "type": "string"
                 },
-                "count": {
-                    "type": "integer"
-                },
-                "date_of_manufacture": {
+                "deleted_at": {
                     "type": "string"
                 },
                 "description": {
                     "type": "string"
                 },
-                "drug_store_branch_id": {
+                "drug_store": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
                 },
-                "price": {
+                "updated_at": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "models.CreateDrugStore": {
+        "models.DrugStoreBranch": {
             "type": "object",
             "properties": {
-                "description": {
+                "address": {
                     "type": "string"
                 },
-                "name": {
+                "created_at": {
                     "type": "string"
-                }
-            }
-        },
-        "models.CreateDrugStoreBranch": {
-            "type": "object",
-            "properties": {
-                "address": {
+                },
+                "deleted_at": {
                     "type": "string"
                 },
                 "drug_store_id": {
                     "type": "string"
                 },
+                "id": {
+                    "type": "string"
+                },
                 "phone": {
                     "type": "string"
                 },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                },
                 "working_time": {
                     "type": "string"
                 }
             }
         },
-        "models.CreateJournal": {
+        "models.DrugStoreBranchsResponse": {
             "type": "object",
             "properties": {
-                "article": {
-                    "type": "string"
-                },
-                "author_id": {
-                    "type": "string"
+                "count": {
+                    "type": "integer"
                 },
-                "theme": {
-                    "type": "string"
+                "drug_store_branchs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DrugStoreBranch"
+                    }
                 }
             }
         },
-        "models.CreateOrderDrug": {
+        "models.DrugStoresResponse": {
             "type": "object",
             "properties": {
-                "drug_id": {
-                    "type": "string"
+                "count": {
+                    "type": "integer"
                 },
-                "orders_id": {
-                    "type": "string"
+                "drug_stores": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DrugStore"
+                    }
                 }
             }
         },
-        "models.CreateOrders": {
+        "models.DrugsResponse": {
             "type": "object",
             "properties": {
-                "customer_id": {
-                    "type": "string"
+                "count": {
+                    "type": "integer"
                 },
-                "pharmacist_id": {
-                    "type": "string"
+                "drugs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Drug"
+                    }
                 }
             }
         },
-        "models.CreatePharmacist": {
+        "models.ExpiringLot": {
             "type": "object",
             "properties": {
-                "address": {
+                "created_at": {
                     "type": "string"
                 },
-                "birth_date": {
+                "days_left": {
+                    "type": "integer"
+                },
+                "deleted_at": {
                     "type": "string"
                 },
-                "drug_store_branch_id": {
+                "drug_id": {
                     "type": "string"
                 },
-                "email": {
+                "drug_name": {
                     "type": "string"
                 },
-                "first_name": {
+                "drug_store_branch_id": {
                     "type": "string"
                 },
-                "gender": {
+                "expiry_date": {
                     "type": "string"
                 },
-                "last_name": {
+                "id": {
                     "type": "string"
                 },
-                "password": {
+                "lot_number": {
                     "type": "string"
                 },
-                "phone": {
+                "purchase_price": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "updated_at": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "models.CreateQueue": {
+        "models.ExpiringLotsResponse": {
             "type": "object",
             "properties": {
-                "customer_id": {
-                    "type": "string"
-                },
-                "doctor_id": {
-                    "type": "string"
+                "count": {
+                    "type": "integer"
                 },
-                "queue_time": {
-                    "type": "string"
+                "lots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExpiringLot"
+                    }
                 }
             }
         },
-        "models.CreateSuperAdmin": {
+        "models.Journal": {
             "type": "object",
             "properties": {
-                "address": {
+                "article": {
                     "type": "string"
                 },
                 "author_id": {
                     "type": "string"
                 },
-                "birth_date": {
-                    "type": "string"
-                },
-                "clinic_id": {
-                    "type": "string"
-                },
-                "drug_store_id": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "first_name": {
-                    "type": "string"
-                },
-                "gender": {
+                "category_id": {
                     "type": "string"
                 },
-                "last_name": {
-                    "type": "string"
-                },
-                "password": {
+                "created_at": {
                     "type": "string"
                 },
-                "phone": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.Customer": {
-            "type": "object",
-            "properties": {
-                "address": {
+                "deleted_at": {
                     "type": "string"
                 },
-                "age": {
-                    "type": "integer"
-                },
-                "birth_date": {
-                    "type": "string"
+                "doctor_type_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "created_at": {
+                "id": {
                     "type": "string"
                 },
-                "deleted_at": {
+                "language": {
                     "type": "string"
                 },
-                "email": {
+                "published_at": {
                     "type": "string"
                 },
-                "first_name": {
+                "review_note": {
                     "type": "string"
                 },
-                "gender": {
+                "reviewed_by": {
                     "type": "string"
                 },
-                "id": {
+                "slug": {
                     "type": "string"
                 },
-                "last_name": {
+                "status": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string"
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "phone": {
+                "theme": {
                     "type": "string"
                 },
                 "updated_at": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "models.CustomersResponse": {
+        "models.JournalCategoriesResponse": {
             "type": "object",
             "properties": {
                 "count": {
                     "type": "integer"
                 },
-                "customers": {
+                "journal_categories": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Customer"
+                        "$ref": "#/definitions/models.JournalCategory"
                     }
                 }
             }
         },
-        "models.Doctor": {
+        "models.JournalCategory": {
             "type": "object",
             "properties": {
-                "address": {
-                    "type": "string"
-                },
-                "age": {
-                    "type": "integer"
-                },
-                "birth_date": {
-                    "type": "string"
-                },
                 "created_at": {
                     "type": "string"
                 },
                 "deleted_at": {
                     "type": "string"
                 },
-                "doctor_type_id": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "first_name": {
-                    "type": "string"
-                },
-                "gender": {
+                "description": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "last_name": {
+                "name": {
                     "type": "string"
                 },
-                "password": {
+                "updated_at": {
                     "type": "string"
                 },
-                "phone": {
-                    "type": "string"
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.JournalDiff": {
+            "type": "object",
+            "properties": {
+                "article": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DiffLine"
+                    }
                 },
-                "status": {
-                    "type": "string"
+                "from": {
+                    "type": "integer"
                 },
-                "updated_at": {
+                "journal_id": {
                     "type": "string"
                 },
-                "working_time": {
-                    "type": "string"
+                "theme": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DiffLine"
+                    }
+                },
+                "to": {
+                    "type": "integer"
                 }
             }
         },
-        "models.DoctorType": {
+        "models.JournalRevision": {
             "type": "object",
             "properties": {
-                "clinic_branch_id": {
+                "article": {
                     "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "deleted_at": {
+                "edited_by": {
                     "type": "string"
                 },
-                "description": {
+                "id": {
                     "type": "string"
                 },
-                "id": {
+                "journal_id": {
                     "type": "string"
                 },
-                "name": {
+                "language": {
                     "type": "string"
                 },
-                "updated_at": {
+                "theme": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "models.DoctorTypesResponse": {
+        "models.JournalRevisionsResponse": {
             "type": "object",
             "properties": {
                 "count": {
                     "type": "integer"
                 },
-                "doctor_types": {
+                "revisions": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.DoctorType"
+                        "$ref": "#/definitions/models.JournalRevision"
                     }
                 }
             }
         },
-        "models.DoctorsResponse": {
+        "models.JournalSearchResponse": {
             "type": "object",
             "properties": {
                 "count": {
                     "type": "integer"
                 },
-                "doctors": {
+                "results": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Doctor"
+                        "$ref": "#/definitions/models.JournalSearchResult"
                     }
                 }
             }
         },
-        "models.Drug": {
+        "models.JournalSearchResult": {
             "type": "object",
             "properties": {
-                "best_before": {
+                "author_id": {
                     "type": "string"
                 },
-                "count": {
-                    "type": "integer"
-                },
                 "created_at": {
                     "type": "string"
                 },
-                "date_of_manufacture": {
+                "id": {
                     "type": "string"
                 },
-                "deleted_at": {
+                "language": {
                     "type": "string"
                 },
-                "description": {
-                    "type": "string"
+                "rank": {
+                    "type": "number"
                 },
-                "drug_store_branch_id": {
+                "snippet": {
                     "type": "string"
                 },
-                "id": {
+                "theme": {
                     "type": "string"
+                }
+            }
+        },
+        "models.JournalTag": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
                 },
-                "name": {
+                "tag": {
                     "type": "string"
+                }
+            }
+        },
+        "models.JournalTagsResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
                 },
-                "price": {
-                    "type": "string"
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.JournalTag"
+                    }
+                }
+            }
+        },
+        "models.JournalsResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
                 },
-                "updated_at": {
-                    "type": "string"
+                "journals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Journal"
+                    }
                 }
             }
         },
-        "models.DrugStore": {
+        "models.LoginRequest": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "login": {
                     "type": "string"
                 },
-                "deleted_at": {
+                "password": {
                     "type": "string"
-                },
-                "description": {
+                }
+            }
+        },
+        "models.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
                     "type": "string"
                 },
-                "drug_store": {
+                "refresh_token": {
                     "type": "string"
                 },
-                "name": {
+                "user_id": {
                     "type": "string"
                 },
-                "updated_at": {
+                "user_role": {
                     "type": "string"
                 }
             }
         },
-        "models.DrugStoreBranch": {
+        "models.OrderDrug": {
             "type": "object",
             "properties": {
-                "address": {
-                    "type": "string"
-                },
                 "created_at": {
                     "type": "string"
                 },
                 "deleted_at": {
                     "type": "string"
                 },
-                "drug_store_id": {
+                "drug_id": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "phone": {
+                "line_total": {
                     "type": "string"
                 },
-                "updated_at": {
+                "orders_id": {
                     "type": "string"
                 },
-                "working_time": {
+                "prescription_item_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "unit_price": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "models.DrugStoreBranchsResponse": {
+        "models.OrderDrugsResponse": {
             "type": "object",
             "properties": {
                 "count": {
                     "type": "integer"
                 },
-                "drug_store_branchs": {
+                "order_drugs": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.DrugStoreBranch"
+                        "$ref": "#/definitions/models.OrderDrug"
                     }
                 }
             }
         },
-        "models.DrugStoresResponse": {
+        "models.Orders": {
             "type": "object",
             "properties": {
-                "count": {
-                    "type": "integer"
+                "cancelled_at": {
+                    "type": "string"
                 },
-                "drug_stores": {
+                "confirmed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "discount": {
+                    "type": "string"
+                },
+                "drug_store_branch_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lines": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.DrugStore"
+                        "$ref": "#/definitions/models.OrderDrug"
                     }
+                },
+                "pharmacist_id": {
+                    "type": "string"
+                },
+                "preparing_at": {
+                    "type": "string"
+                },
+                "prescription_id": {
+                    "type": "string"
+                },
+                "ready_for_pickup_at": {
+                    "type": "string"
+                },
+                "refunded_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subtotal": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "models.DrugsResponse": {
+        "models.OrdersResponse": {
             "type": "object",
             "properties": {
                 "count": {
                     "type": "integer"
                 },
-                "drugs": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "orderss": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Drug"
+                        "$ref": "#/definitions/models.Orders"
                     }
                 }
             }
         },
-        "models.Journal": {
+        "models.Pharmacist": {
             "type": "object",
             "properties": {
-                "article": {
+                "address": {
                     "type": "string"
                 },
-                "author_id": {
+                "age": {
+                    "type": "integer"
+                },
+                "birth_date": {
                     "type": "string"
                 },
                 "created_at": {
@@ -5476,191 +15884,213 @@ const docTemplate = `{
                 "deleted_at": {
                     "type": "string"
                 },
+                "drug_store_branch_id": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "gender": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
-                "theme": {
+                "last_name": {
+                    "type": "string"
+                },
+                "phone": {
                     "type": "string"
                 },
                 "updated_at": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "models.JournalsResponse": {
+        "models.PharmacistsResponse": {
             "type": "object",
             "properties": {
                 "count": {
                     "type": "integer"
                 },
-                "journals": {
+                "pharmacists": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Journal"
+                        "$ref": "#/definitions/models.Pharmacist"
                     }
                 }
             }
         },
-        "models.OrderDrug": {
+        "models.Prescription": {
             "type": "object",
             "properties": {
+                "code": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
+                "customer_id": {
+                    "type": "string"
+                },
                 "deleted_at": {
                     "type": "string"
                 },
-                "drug_id": {
+                "doctor_id": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "orders_id": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PrescriptionItem"
+                    }
+                },
+                "note": {
+                    "type": "string"
+                },
+                "queue_id": {
                     "type": "string"
                 },
                 "updated_at": {
                     "type": "string"
+                },
+                "valid_until": {
+                    "type": "string"
                 }
             }
         },
-        "models.OrderDrugsResponse": {
+        "models.PrescriptionItem": {
             "type": "object",
             "properties": {
-                "count": {
+                "dispensed_quantity": {
                     "type": "integer"
                 },
-                "order_drugs": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.OrderDrug"
-                    }
-                }
-            }
-        },
-        "models.Orders": {
-            "type": "object",
-            "properties": {
-                "created_at": {
+                "dosage": {
                     "type": "string"
                 },
-                "customer_id": {
+                "drug_catalogue_id": {
                     "type": "string"
                 },
-                "deleted_at": {
+                "drug_name": {
                     "type": "string"
                 },
+                "duration_days": {
+                    "type": "integer"
+                },
                 "id": {
                     "type": "string"
                 },
-                "pharmacist_id": {
+                "prescription_id": {
                     "type": "string"
                 },
-                "updated_at": {
-                    "type": "string"
+                "quantity": {
+                    "type": "integer"
+                },
+                "redeemed_count": {
+                    "type": "integer"
+                },
+                "refills": {
+                    "type": "integer"
                 }
             }
         },
-        "models.OrdersResponse": {
+        "models.PrescriptionsResponse": {
             "type": "object",
             "properties": {
                 "count": {
                     "type": "integer"
                 },
-                "orderss": {
+                "prescriptions": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Orders"
+                        "$ref": "#/definitions/models.Prescription"
                     }
                 }
             }
         },
-        "models.Pharmacist": {
+        "models.Queue": {
             "type": "object",
             "properties": {
-                "address": {
-                    "type": "string"
-                },
-                "age": {
-                    "type": "integer"
-                },
-                "birth_date": {
-                    "type": "string"
-                },
                 "created_at": {
                     "type": "string"
                 },
-                "deleted_at": {
-                    "type": "string"
-                },
-                "drug_store_branch_id": {
+                "customer_id": {
                     "type": "string"
                 },
-                "email": {
+                "deleted_at": {
                     "type": "string"
                 },
-                "first_name": {
+                "doctor_id": {
                     "type": "string"
                 },
-                "gender": {
+                "end_time": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "last_name": {
+                "queue_number": {
                     "type": "string"
                 },
-                "password": {
+                "start_time": {
                     "type": "string"
                 },
-                "phone": {
+                "status": {
                     "type": "string"
                 },
                 "updated_at": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "models.PharmacistsResponse": {
+        "models.QueueBoardEvent": {
             "type": "object",
             "properties": {
-                "count": {
-                    "type": "integer"
-                },
-                "pharmacists": {
+                "items": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Pharmacist"
+                        "$ref": "#/definitions/models.QueueBoardItem"
                     }
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         },
-        "models.Queue": {
+        "models.QueueBoardItem": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "customer_id": {
+                "doctor_id": {
                     "type": "string"
                 },
-                "deleted_at": {
+                "doctor_name": {
                     "type": "string"
                 },
-                "doctor_id": {
+                "end_time": {
                     "type": "string"
                 },
-                "id": {
+                "queue_id": {
                     "type": "string"
                 },
                 "queue_number": {
                     "type": "string"
                 },
-                "queue_time": {
+                "start_time": {
                     "type": "string"
                 },
-                "updated_at": {
+                "status": {
                     "type": "string"
                 }
             }
@@ -5671,6 +16101,9 @@ const docTemplate = `{
                 "count": {
                     "type": "integer"
                 },
+                "next_cursor": {
+                    "type": "string"
+                },
                 "queues": {
                     "type": "array",
                     "items": {
@@ -5679,6 +16112,77 @@ const docTemplate = `{
                 }
             }
         },
+        "models.Readiness": {
+            "type": "object",
+            "properties": {
+                "database": {
+                    "type": "string"
+                },
+                "migrations": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RedeemPrescription": {
+            "type": "object",
+            "required": [
+                "code",
+                "drug_store_branch_id",
+                "items"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "drug_store_branch_id": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.RedeemPrescriptionItem"
+                    }
+                }
+            }
+        },
+        "models.RedeemPrescriptionItem": {
+            "type": "object",
+            "required": [
+                "drug_id",
+                "prescription_item_id"
+            ],
+            "properties": {
+                "drug_id": {
+                    "type": "string"
+                },
+                "prescription_item_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RefreshTokenRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RejectJournal": {
+            "type": "object",
+            "required": [
+                "note"
+            ],
+            "properties": {
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
         "models.Response": {
             "type": "object",
             "properties": {
@@ -5691,6 +16195,17 @@ const docTemplate = `{
                 }
             }
         },
+        "models.Slot": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                }
+            }
+        },
         "models.SuperAdmin": {
             "type": "object",
             "properties": {
@@ -5733,14 +16248,14 @@ const docTemplate = `{
                 "last_name": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string"
-                },
                 "phone": {
                     "type": "string"
                 },
                 "updated_at": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
@@ -5760,21 +16275,32 @@ const docTemplate = `{
         },
         "models.UpdateAuthor": {
             "type": "object",
+            "required": [
+                "address",
+                "email",
+                "first_name",
+                "last_name",
+                "phone"
+            ],
             "properties": {
                 "address": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 120
                 },
                 "email": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 50
                 },
                 "first_name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 25
                 },
                 "id": {
                     "type": "string"
                 },
                 "last_name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 25
                 },
                 "phone": {
                     "type": "string"
@@ -5783,12 +16309,17 @@ const docTemplate = `{
         },
         "models.UpdateAuthorPassword": {
             "type": "object",
+            "required": [
+                "new_password",
+                "old_password"
+            ],
             "properties": {
                 "id": {
                     "type": "string"
                 },
                 "new_password": {
-                    "type": "string"
+                    "type": "string",
+                    "minLength": 8
                 },
                 "old_password": {
                     "type": "string"
@@ -5797,6 +16328,9 @@ const docTemplate = `{
         },
         "models.UpdateClinic": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
                 "description": {
                     "type": "string"
@@ -5805,15 +16339,25 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 50
                 }
             }
         },
         "models.UpdateClinicAdmin": {
             "type": "object",
+            "required": [
+                "address",
+                "clinic_branch_id",
+                "email",
+                "first_name",
+                "last_name",
+                "phone"
+            ],
             "properties": {
                 "address": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 120
                 },
                 "clinic_branch_id": {
                     "type": "string"
@@ -5822,16 +16366,19 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "email": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 50
                 },
                 "first_name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 25
                 },
                 "id": {
                     "type": "string"
                 },
                 "last_name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 25
                 },
                 "phone": {
                     "type": "string"
@@ -5840,12 +16387,17 @@ const docTemplate = `{
         },
         "models.UpdateClinicAdminPassword": {
             "type": "object",
+            "required": [
+                "new_password",
+                "old_password"
+            ],
             "properties": {
                 "id": {
                     "type": "string"
                 },
                 "new_password": {
-                    "type": "string"
+                    "type": "string",
+                    "minLength": 8
                 },
                 "old_password": {
                     "type": "string"
@@ -5854,9 +16406,16 @@ const docTemplate = `{
         },
         "models.UpdateClinicBranch": {
             "type": "object",
+            "required": [
+                "address",
+                "clinic_id",
+                "phone",
+                "working_time"
+            ],
             "properties": {
                 "address": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 50
                 },
                 "clinic_id": {
                     "type": "string"
@@ -5868,27 +16427,39 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "working_time": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 100
                 }
             }
         },
         "models.UpdateCustomer": {
             "type": "object",
+            "required": [
+                "address",
+                "email",
+                "first_name",
+                "last_name",
+                "phone"
+            ],
             "properties": {
                 "address": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 120
                 },
                 "email": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 50
                 },
                 "first_name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 25
                 },
                 "id": {
                     "type": "string"
                 },
                 "last_name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 25
                 },
                 "phone": {
                     "type": "string"
@@ -5897,9 +16468,14 @@ const docTemplate = `{
         },
         "models.UpdateCustomerPassword": {
             "type": "object",
+            "required": [
+                "new_password",
+                "old_password"
+            ],
             "properties": {
                 "new_password": {
-                    "type": "string"
+                    "type": "string",
+                    "minLength": 8
                 },
                 "old_password": {
                     "type": "string"
@@ -5908,49 +16484,101 @@ const docTemplate = `{
         },
         "models.UpdateDoctor": {
             "type": "object",
+            "required": [
+                "address",
+                "doctor_type_id",
+                "email",
+                "first_name",
+                "last_name",
+                "phone",
+                "working_time"
+            ],
             "properties": {
                 "address": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 120
                 },
                 "doctor_type_id": {
                     "type": "string"
                 },
                 "email": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 50
                 },
                 "first_name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 25
                 },
                 "id": {
                     "type": "string"
                 },
                 "last_name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 25
                 },
                 "phone": {
                     "type": "string"
                 },
                 "status": {
-                    "type": "string"
+                    "description": "Status keeps the stored one when it is omitted",
+                    "type": "string",
+                    "enum": [
+                        "busy",
+                        "empty"
+                    ]
                 },
                 "working_time": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 100
                 }
             }
         },
         "models.UpdateDoctorPassword": {
             "type": "object",
+            "required": [
+                "new_password",
+                "old_password"
+            ],
             "properties": {
                 "new_password": {
-                    "type": "string"
+                    "type": "string",
+                    "minLength": 8
                 },
                 "old_password": {
                     "type": "string"
                 }
             }
         },
+        "models.UpdateDoctorSchedule": {
+            "type": "object",
+            "required": [
+                "end_time",
+                "start_time"
+            ],
+            "properties": {
+                "break_end": {
+                    "type": "string"
+                },
+                "break_start": {
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "slot_duration": {
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "string"
+                }
+            }
+        },
         "models.UpdateDoctorType": {
             "type": "object",
+            "required": [
+                "clinic_branch_id",
+                "name"
+            ],
             "properties": {
                 "clinic_branch_id": {
                     "type": "string"
@@ -5962,35 +16590,84 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 50
                 }
             }
         },
         "models.UpdateDrug": {
             "type": "object",
+            "required": [
+                "best_before",
+                "date_of_manufacture",
+                "price"
+            ],
             "properties": {
-                "count": {
-                    "type": "integer"
+                "best_before": {
+                    "type": "string"
+                },
+                "date_of_manufacture": {
+                    "type": "string"
                 },
                 "description": {
                     "type": "string"
                 },
-                "drug_store_branch_id": {
+                "id": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UpdateDrugCatalogue": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
                 "name": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "prescription_required": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.UpdateDrugLot": {
+            "type": "object",
+            "required": [
+                "expiry_date",
+                "lot_number"
+            ],
+            "properties": {
+                "expiry_date": {
                     "type": "string"
                 },
-                "price": {
+                "lot_number": {
+                    "type": "string"
+                },
+                "purchase_price": {
                     "type": "string"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "minimum": 0
                 }
             }
         },
         "models.UpdateDrugStore": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
                 "description": {
                     "type": "string"
@@ -5999,15 +16676,23 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 50
                 }
             }
         },
         "models.UpdateDrugStoreBranch": {
             "type": "object",
+            "required": [
+                "address",
+                "drug_store_id",
+                "phone",
+                "working_time"
+            ],
             "properties": {
                 "address": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 120
                 },
                 "drug_store_id": {
                     "type": "string"
@@ -6019,12 +16704,17 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "working_time": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 100
                 }
             }
         },
         "models.UpdateJournal": {
             "type": "object",
+            "required": [
+                "article",
+                "theme"
+            ],
             "properties": {
                 "article": {
                     "type": "string"
@@ -6035,13 +16725,47 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
+                "language": {
+                    "type": "string",
+                    "enum": [
+                        "uz",
+                        "ru",
+                        "en"
+                    ]
+                },
+                "slug": {
+                    "type": "string",
+                    "maxLength": 180
+                },
                 "theme": {
                     "type": "string"
                 }
             }
         },
+        "models.UpdateJournalCategory": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
         "models.UpdateOrderDrug": {
             "type": "object",
+            "required": [
+                "drug_id",
+                "orders_id"
+            ],
             "properties": {
                 "drug_id": {
                     "type": "string"
@@ -6051,11 +16775,17 @@ const docTemplate = `{
                 },
                 "orders_id": {
                     "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
                 }
             }
         },
         "models.UpdateOrders": {
             "type": "object",
+            "required": [
+                "customer_id"
+            ],
             "properties": {
                 "customer_id": {
                     "type": "string"
@@ -6070,24 +16800,36 @@ const docTemplate = `{
         },
         "models.UpdatePharmacist": {
             "type": "object",
+            "required": [
+                "address",
+                "drug_store_branch_id",
+                "email",
+                "first_name",
+                "last_name",
+                "phone"
+            ],
             "properties": {
                 "address": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 120
                 },
                 "drug_store_branch_id": {
                     "type": "string"
                 },
                 "email": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 50
                 },
                 "first_name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 25
                 },
                 "id": {
                     "type": "string"
                 },
                 "last_name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 25
                 },
                 "phone": {
                     "type": "string"
@@ -6096,9 +16838,14 @@ const docTemplate = `{
         },
         "models.UpdatePharmacistPassword": {
             "type": "object",
+            "required": [
+                "new_password",
+                "old_password"
+            ],
             "properties": {
                 "new_password": {
-                    "type": "string"
+                    "type": "string",
+                    "minLength": 8
                 },
                 "old_password": {
                     "type": "string"
@@ -6107,6 +16854,11 @@ const docTemplate = `{
         },
         "models.UpdateQueue": {
             "type": "object",
+            "required": [
+                "customer_id",
+                "doctor_id",
+                "start_time"
+            ],
             "properties": {
                 "customer_id": {
                     "type": "string"
@@ -6117,16 +16869,24 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
-                "queue_time": {
+                "start_time": {
                     "type": "string"
                 }
             }
         },
         "models.UpdateSuperAdmin": {
             "type": "object",
+            "required": [
+                "address",
+                "email",
+                "first_name",
+                "last_name",
+                "phone"
+            ],
             "properties": {
                 "address": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 120
                 },
                 "author_id": {
                     "type": "string"
@@ -6138,16 +16898,19 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "email": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 50
                 },
                 "first_name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 25
                 },
                 "id": {
                     "type": "string"
                 },
                 "last_name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 25
                 },
                 "phone": {
                     "type": "string"
@@ -6156,15 +16919,27 @@ const docTemplate = `{
         },
         "models.UpdateSuperAdminPassword": {
             "type": "object",
+            "required": [
+                "new_password",
+                "old_password"
+            ],
             "properties": {
                 "new_password": {
-                    "type": "string"
+                    "type": "string",
+                    "minLength": 8
                 },
                 "old_password": {
                     "type": "string"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
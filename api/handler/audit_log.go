@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+	"shifolink/api/models"
+	"shifolink/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAuditLogs godoc
+// @Router       /audit [GET]
+// @Summary      Get audit log
+// @Description  Changes made through the api, newest first. Before and after hold only the changed fields
+// @Tags         audit
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        page query string false "page"
+// @Param        limit query string false "limit, 10 by default and at most 100"
+// @Param        entity query string false "table of the changed record, e.g. doctor, drug, queue"
+// @Param        entity_id query string false "id of the changed record"
+// @Param        actor query string false "id of the user who made the change"
+// @Param        action query string false "create, update, delete, restore or purge"
+// @Param        created_from query string false "created at or after the date, YYYY-MM-DD"
+// @Param        created_to query string false "created at or before the date, YYYY-MM-DD"
+// @Param        order query string false "asc or desc, desc by default"
+// @Param        cursor query string false "next_cursor of the previous page, used instead of page"
+// @Success      200  {object}  models.AuditLogsResponse
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetAuditLogs(c *gin.Context) {
+
+	request, err := getListRequest(c)
+	if err != nil {
+		handleResponse(c, "error while parsing list request", http.StatusBadRequest, err.Error())
+		return
+	}
+	request.Cursor = c.Query("cursor")
+
+	ids, err := getUUIDQueries(c, "entity_id", "actor")
+	if err != nil {
+		handleResponse(c, "error while parsing list filters", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	action := c.Query("action")
+	if action != "" && action != config.AuditCreate && action != config.AuditUpdate && action != config.AuditDelete &&
+		action != config.AuditRestore && action != config.AuditPurge {
+		handleResponse(c, "unknown audit action", http.StatusBadRequest, action)
+		return
+	}
+
+	response, err := h.services.AuditLog().GetList(c.Request.Context(), models.GetAuditLogsListRequest{
+		GetListRequest: request,
+		Entity:         c.Query("entity"),
+		EntityID:       ids["entity_id"],
+		ActorID:        ids["actor"],
+		Action:         action,
+	})
+	if err != nil {
+		handleError(c, "error while getting audit log", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, response)
+}
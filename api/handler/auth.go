@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"shifolink/api/models"
+	"shifolink/config"
+	"shifolink/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Login godoc
+// @Router       /auth/{role}/login [POST]
+// @Summary      Login
+// @Description  Login as author, clinic_admin, customer, doctor, pharmacist or super_admin
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        role path string true "role" Enums(author, clinic_admin, customer, doctor, pharmacist, super_admin)
+// @Param        login body models.LoginRequest true "login and password"
+// @Success      200  {object}  models.LoginResponse
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      401  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) Login(c *gin.Context) {
+	loginRequest := models.LoginRequest{}
+
+	role := c.Param("role")
+	if !isAccountRole(role) {
+		handleResponse(c, "invalid role", http.StatusBadRequest, "role is not valid")
+		return
+	}
+
+	if err := c.ShouldBindJSON(&loginRequest); err != nil {
+		handleBindError(c, err)
+		return
+	}
+
+	response, err := h.services.Auth().Login(c.Request.Context(), role, loginRequest)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			handleResponse(c, "unauthorized", http.StatusUnauthorized, err.Error())
+			return
+		}
+		handleError(c, "error while login", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, response)
+}
+
+// RefreshToken godoc
+// @Router       /auth/refresh [POST]
+// @Summary      Refresh tokens
+// @Description  Get a new access and refresh token pair by refresh token
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        token body models.RefreshTokenRequest true "refresh token"
+// @Success      200  {object}  models.LoginResponse
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      401  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) RefreshToken(c *gin.Context) {
+	refreshTokenRequest := models.RefreshTokenRequest{}
+
+	if err := c.ShouldBindJSON(&refreshTokenRequest); err != nil {
+		handleBindError(c, err)
+		return
+	}
+
+	response, err := h.services.Auth().RefreshToken(c.Request.Context(), refreshTokenRequest)
+	if err != nil {
+		handleResponse(c, "unauthorized", http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, response)
+}
+
+func isAccountRole(role string) bool {
+	switch role {
+	case config.AuthorRole,
+		config.ClinicAdminRole,
+		config.CustomerRole,
+		config.DoctorRole,
+		config.PharmacistRole,
+		config.SuperAdminRole:
+		return true
+	}
+
+	return false
+}
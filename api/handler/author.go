@@ -1,11 +1,10 @@
 package handler
 
 import (
-	"context"
 	"errors"
 	"net/http"
 	"shifolink/api/models"
-	"strconv"
+	"shifolink/config"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -16,23 +15,26 @@ import (
 // @Summary      Create a new author
 // @Description  Create a new author
 // @Tags         author
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        author  body  models.CreateAuthor  true  "author data"
 // @Success      201  {object}  models.Author
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) CreateAuthor(c *gin.Context) {
 	createAuthor := models.CreateAuthor{}
 
 	if err := c.ShouldBindJSON(&createAuthor); err != nil {
-		handleResponse(c, "error while reading body from client", http.StatusBadRequest, err)
+		handleBindError(c, err)
+		return
 	}
 
-	resp, err := h.services.Author().Create(context.Background(), createAuthor)
+	resp, err := h.services.Author().Create(c.Request.Context(), createAuthor)
 	if err != nil {
-		handleResponse(c, "error while creating author", http.StatusInternalServerError, err.Error())
+		handleError(c, "error while creating author", err)
 		return
 	}
 
@@ -49,7 +51,9 @@ func (h Handler) CreateAuthor(c *gin.Context) {
 // @Produce      json
 // @Param        id path string true "author"
 // @Success      200  {object}  models.Author
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetAuthorByID(c *gin.Context) {
@@ -64,14 +68,15 @@ func (h Handler) GetAuthorByID(c *gin.Context) {
 		return
 	}
 
-	author, err := h.services.Author().Get(context.Background(), models.PrimaryKey{
+	author, err := h.services.Author().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, "error while get author by id", http.StatusInternalServerError, err)
+		handleError(c, "error while get author by id", err)
 		return
 	}
 
+	setETag(c, author.Version)
 	handleResponse(c, "", http.StatusOK, author)
 
 }
@@ -84,43 +89,35 @@ func (h Handler) GetAuthorByID(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
-// @Param        limit query string false "limit"
+// @Param        limit query string false "limit, 10 by default and at most 100"
 // @Param        search query string false "search"
+// @Param        created_from query string false "created at or after the date, YYYY-MM-DD"
+// @Param        created_to query string false "created at or before the date, YYYY-MM-DD"
+// @Param        sort_by query string false "created_at (default), first_name, last_name, birth_date"
+// @Param        order query string false "asc or desc, desc for the default sorting and asc otherwise"
+// @Param        include_deleted query bool false "list soft deleted records too, super admin only"
 // @Success      200  {object}  models.AuthorsResponse
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetAuthorList(c *gin.Context) {
 
-	var (
-		page, limit int
-		search      string
-		err         error
-	)
-
-	pageStr := c.DefaultQuery("page", "1")
-	page, err = strconv.Atoi(pageStr)
+	request, err := getListRequest(c)
 	if err != nil {
-		handleResponse(c, "error while parsing page ", http.StatusBadRequest, err.Error())
+		handleResponse(c, "error while parsing list request", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err = strconv.Atoi(limitStr)
-	if err != nil {
-		handleResponse(c, "error while parsing limit", http.StatusBadRequest, err.Error())
+	if !readIncludeDeleted(c, &request) {
 		return
 	}
 
-	search = c.Query("search")
-	response, err := h.services.Author().GetList(context.Background(), models.GetListRequest{
-		Page:   page,
-		Limit:  limit,
-		Search: search,
-	})
+	response, err := h.services.Author().GetList(c.Request.Context(), request)
 
 	if err != nil {
-		handleResponse(c, "error while getting author", http.StatusInternalServerError, err)
+		handleError(c, "error while getting author", err)
 		return
 	}
 
@@ -130,16 +127,22 @@ func (h Handler) GetAuthorList(c *gin.Context) {
 
 // UpdateAuthor godoc
 // @Router       /author/{id} [PUT]
+// @Router       /author/{id} [PATCH]
 // @Summary      Update author by id
-// @Description  Update author by id
+// @Description  Update author by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields
 // @Tags         author
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "author id"
 // @Param        author body models.UpdateAuthor true "author"
+// @Param        If-Match header string false "ETag of the record, the update fails with 412 when the record was changed since"
 // @Success      200  {object}  models.Author
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      412  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdateAuthor(c *gin.Context) {
 	updateAuthor := models.UpdateAuthor{}
@@ -150,19 +153,32 @@ func (h Handler) UpdateAuthor(c *gin.Context) {
 		return
 	}
 
+	if !checkOwner(c, config.AuthorRole, uid) {
+		return
+	}
+
+	if !bindUpdate(c, &updateAuthor, func() (interface{}, error) {
+		return h.services.Author().Get(c.Request.Context(), models.PrimaryKey{ID: uid})
+	}) {
+		return
+	}
+
 	updateAuthor.ID = uid
 
-	if err := c.ShouldBindJSON(&updateAuthor); err != nil {
-		handleResponse(c, "error while reading body", http.StatusBadRequest, err.Error())
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
-	author, err := h.services.Author().Update(context.Background(), updateAuthor)
+	updateAuthor.Version = version
+
+	author, err := h.services.Author().Update(c.Request.Context(), updateAuthor)
 	if err != nil {
-		handleResponse(c, "error while updating author", http.StatusInternalServerError, err.Error())
+		handleError(c, "error while updating author", err)
 		return
 	}
 
+	setETag(c, author.Version)
 	handleResponse(c, "", http.StatusOK, author)
 
 }
@@ -172,11 +188,13 @@ func (h Handler) UpdateAuthor(c *gin.Context) {
 // @Summary      Delete Author
 // @Description  Delete Author
 // @Tags         author
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "author id"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) DeleteAuthor(c *gin.Context) {
@@ -188,8 +206,12 @@ func (h Handler) DeleteAuthor(c *gin.Context) {
 		return
 	}
 
-	if err := h.services.Author().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, "error while deleting author by id", http.StatusInternalServerError, err.Error())
+	if !checkOwner(c, config.AuthorRole, id.String()) {
+		return
+	}
+
+	if err := h.services.Author().Delete(c.Request.Context(), id.String()); err != nil {
+		handleError(c, "error while deleting author by id", err)
 		return
 	}
 
@@ -198,23 +220,25 @@ func (h Handler) DeleteAuthor(c *gin.Context) {
 }
 
 // UpdateAuthorPassword godoc
-// @Router       /author/{id} [PATCH]
+// @Router       /author/{id}/password [PATCH]
 // @Summary      Update author password
 // @Description  update author password
 // @Tags         author
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param 		 id path string true "author_id"
 // @Param        author body models.UpdateAuthorPassword true "author"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdateAuthorPassword(c *gin.Context) {
 	updateAuthorPassword := models.UpdateAuthorPassword{}
 
 	if err := c.ShouldBindJSON(&updateAuthorPassword); err != nil {
-		handleResponse(c, "error while reading body", http.StatusBadRequest, err.Error())
+		handleBindError(c, err)
 		return
 	}
 
@@ -226,11 +250,47 @@ func (h Handler) UpdateAuthorPassword(c *gin.Context) {
 
 	updateAuthorPassword.ID = uid.String()
 
-	err = h.services.Author().UpdatePassword(context.Background(), updateAuthorPassword)
+	if !checkSelf(c, config.AuthorRole, updateAuthorPassword.ID) {
+		return
+	}
+
+	err = h.services.Author().UpdatePassword(c.Request.Context(), updateAuthorPassword)
 	if err != nil {
-		handleResponse(c, "error while updating author by id", http.StatusInternalServerError, err.Error())
+		handleError(c, "error while updating author by id", err)
 		return
 	}
 
 	handleResponse(c, "", http.StatusOK, "password successfully updated")
 }
+
+// RestoreAuthor godoc
+// @Router       /author/{id}/restore [POST]
+// @Summary      Restore author
+// @Description  Restore a soft deleted author
+// @Tags         author
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "author id"
+// @Success      200  {object}  models.Author
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) RestoreAuthor(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	author, err := h.services.Author().Restore(c.Request.Context(), id.String())
+	if err != nil {
+		handleError(c, "error while restoring author", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, author)
+}
@@ -1,11 +1,9 @@
 package handler
 
 import (
-	"context"
 	"errors"
 	"net/http"
 	"shifolink/api/models"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -16,31 +14,26 @@ import (
 // @Summary      Create a new clinic 
 // @Description  Create a new clinic 
 // @Tags         clinic
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        clinic body  models.CreateClinic  true  "clinic data"
 // @Success      201  {object}  models.Clinic
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) CreateClinic(c *gin.Context) {
 	createClinic := models.CreateClinic{}
 
 	if err := c.ShouldBindJSON(&createClinic); err != nil {
-		handleResponse(c, "error while reading body from client", http.StatusBadRequest, err)
-	}
-
-	id, err := h.storage.Clinic().Create(context.Background(), createClinic)
-	if err != nil {
-		handleResponse(c, "error while creating clinic", http.StatusInternalServerError, err)
+		handleBindError(c, err)
 		return
 	}
 
-	clinic, err := h.storage.Clinic().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	clinic, err := h.services.Clinic().Create(c.Request.Context(), createClinic)
 	if err != nil {
-		handleResponse(c, "error while get clinic ", http.StatusInternalServerError, err)
+		handleError(c, "error while creating clinic", err)
 		return
 	}
 
@@ -57,7 +50,9 @@ func (h Handler) CreateClinic(c *gin.Context) {
 // @Produce      json
 // @Param        id path string true "clinic"
 // @Success      200  {object}  models.Clinic
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetClinicByID(c *gin.Context) {
@@ -72,14 +67,15 @@ func (h Handler) GetClinicByID(c *gin.Context) {
 		return
 	}
 
-	clinic, err := h.storage.Clinic().Get(context.Background(), models.PrimaryKey{
+	clinic, err := h.services.Clinic().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, "error while get clinic by id", http.StatusInternalServerError, err)
+		handleError(c, "error while get clinic by id", err)
 		return
 	}
 
+	setETag(c, clinic.Version)
 	handleResponse(c, "", http.StatusOK, clinic)
 
 }
@@ -92,44 +88,35 @@ func (h Handler) GetClinicByID(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
-// @Param        limit query string false "limit"
+// @Param        limit query string false "limit, 10 by default and at most 100"
 // @Param        search query string false "search"
+// @Param        created_from query string false "created at or after the date, YYYY-MM-DD"
+// @Param        created_to query string false "created at or before the date, YYYY-MM-DD"
+// @Param        sort_by query string false "created_at (default), name"
+// @Param        order query string false "asc or desc, desc for the default sorting and asc otherwise"
+// @Param        include_deleted query bool false "list soft deleted records too, super admin only"
 // @Success      200  {object}  models.ClinicsResponse
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetClinicsList(c *gin.Context) {
 
-	var (
-		page, limit int
-		search      string
-		err         error
-	)
-
-	pageStr := c.DefaultQuery("page", "1")
-	page, err = strconv.Atoi(pageStr)
+	request, err := getListRequest(c)
 	if err != nil {
-		handleResponse(c, "error while parsing page ", http.StatusBadRequest, err.Error())
+		handleResponse(c, "error while parsing list request", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err = strconv.Atoi(limitStr)
-	if err != nil {
-		handleResponse(c, "error while parsing limit", http.StatusBadRequest, err.Error())
+	if !readIncludeDeleted(c, &request) {
 		return
 	}
 
-	search = c.Query("search")
-
-	response, err := h.storage.Clinic().GetList(context.Background(), models.GetListRequest{
-		Page:   page,
-		Limit:  limit,
-		Search: search,
-	})
+	response, err := h.services.Clinic().GetList(c.Request.Context(), request)
 
 	if err != nil {
-		handleResponse(c, "error while getting clinic", http.StatusInternalServerError, err)
+		handleError(c, "error while getting clinic", err)
 		return
 	}
 
@@ -139,16 +126,22 @@ func (h Handler) GetClinicsList(c *gin.Context) {
 
 // UpdateClinic godoc
 // @Router       /clinic/{id} [PUT]
+// @Router       /clinic/{id} [PATCH]
 // @Summary      Update clinic by id
-// @Description  Update clinic by id
+// @Description  Update clinic by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields
 // @Tags         clinic
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "clinic id"
 // @Param        clinic body models.UpdateClinic true "clinic"
+// @Param        If-Match header string false "ETag of the record, the update fails with 412 when the record was changed since"
 // @Success      200  {object}  models.Clinic
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      412  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdateClinic(c *gin.Context) {
 	updateClinic := models.UpdateClinic{}
@@ -159,27 +152,28 @@ func (h Handler) UpdateClinic(c *gin.Context) {
 		return
 	}
 
-	//updateAuthor.ID = uid
-
-	if err := c.ShouldBindJSON(&updateClinic); err != nil {
-		handleResponse(c, "error while reading body", http.StatusBadRequest, err.Error())
+	if !bindUpdate(c, &updateClinic, func() (interface{}, error) {
+		return h.services.Clinic().Get(c.Request.Context(), models.PrimaryKey{ID: uid})
+	}) {
 		return
 	}
 
-	id, err := h.storage.Clinic().Update(context.Background(), updateClinic)
-	if err != nil {
-		handleResponse(c, "error while updating clinic ", http.StatusInternalServerError, err.Error())
+	updateClinic.ID = uid
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
-	clinic, err := h.storage.Clinic().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	updateClinic.Version = version
+
+	clinic, err := h.services.Clinic().Update(c.Request.Context(), updateClinic)
 	if err != nil {
-		handleResponse(c, "error while getting clinic by id", http.StatusInternalServerError, err)
+		handleError(c, "error while updating clinic ", err)
 		return
 	}
 
+	setETag(c, clinic.Version)
 	handleResponse(c, "", http.StatusOK, clinic)
 
 }
@@ -189,11 +183,13 @@ func (h Handler) UpdateClinic(c *gin.Context) {
 // @Summary      Delete clinic 
 // @Description  Delete clinic 
 // @Tags         clinic
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "clinic id"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) DeleteClinic(c *gin.Context) {
@@ -205,11 +201,43 @@ func (h Handler) DeleteClinic(c *gin.Context) {
 		return
 	}
 
-	if err := h.storage.Clinic().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, "error while deleting clinic by id", http.StatusInternalServerError, err.Error())
+	if err := h.services.Clinic().Delete(c.Request.Context(), id.String()); err != nil {
+		handleError(c, "error while deleting clinic by id", err)
 		return
 	}
 
 	handleResponse(c, "", http.StatusOK, "data succesfully deleted")
 
 }
+
+// RestoreClinic godoc
+// @Router       /clinic/{id}/restore [POST]
+// @Summary      Restore clinic
+// @Description  Restore a soft deleted clinic together with the branches, doctor types and doctors its delete took with it
+// @Tags         clinic
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "clinic id"
+// @Success      200  {object}  models.Clinic
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) RestoreClinic(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	clinic, err := h.services.Clinic().Restore(c.Request.Context(), id.String())
+	if err != nil {
+		handleError(c, "error while restoring clinic", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, clinic)
+}
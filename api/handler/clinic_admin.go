@@ -1,12 +1,10 @@
 package handler
 
 import (
-	"context"
 	"errors"
 	"net/http"
 	"shifolink/api/models"
-	"shifolink/pkg/check"
-	"strconv"
+	"shifolink/config"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -17,31 +15,26 @@ import (
 // @Summary      Create a new clinic admin
 // @Description  Create a new clinic admin
 // @Tags         clinic_admin
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        clinic_admin  body  models.CreateClinicAdmin  true  "clinic admin data"
 // @Success      201  {object}  models.ClinicAdmin
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) CreateClinicAdmin(c *gin.Context) {
 	createClinicAdmin := models.CreateClinicAdmin{}
 
 	if err := c.ShouldBindJSON(&createClinicAdmin); err != nil {
-		handleResponse(c, "error while reading body from client", http.StatusBadRequest, err)
-	}
-
-	id, err := h.storage.ClinicAdmin().Create(context.Background(), createClinicAdmin)
-	if err != nil {
-		handleResponse(c, "error while creating clinic admin", http.StatusInternalServerError, err)
+		handleBindError(c, err)
 		return
 	}
 
-	clinicAdmin, err := h.storage.ClinicAdmin().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	clinicAdmin, err := h.services.ClinicAdmin().Create(c.Request.Context(), createClinicAdmin)
 	if err != nil {
-		handleResponse(c, "error while get clinic admin", http.StatusInternalServerError, err)
+		handleError(c, "error while creating clinic admin", err)
 		return
 	}
 
@@ -54,11 +47,14 @@ func (h Handler) CreateClinicAdmin(c *gin.Context) {
 // @Summary      Get clinic admin by id
 // @Description  Get clinic admin by id
 // @Tags         clinic_admin
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "clinic_admin"
 // @Success      200  {object}  models.ClinicAdmin
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetClinicAdminByID(c *gin.Context) {
@@ -73,14 +69,15 @@ func (h Handler) GetClinicAdminByID(c *gin.Context) {
 		return
 	}
 
-	clinicAdmin, err := h.storage.ClinicAdmin().Get(context.Background(), models.PrimaryKey{
+	clinicAdmin, err := h.services.ClinicAdmin().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, "error while get clinic admin by id", http.StatusInternalServerError, err)
+		handleError(c, "error while get clinic admin by id", err)
 		return
 	}
 
+	setETag(c, clinicAdmin.Version)
 	handleResponse(c, "", http.StatusOK, clinicAdmin)
 
 }
@@ -90,47 +87,51 @@ func (h Handler) GetClinicAdminByID(c *gin.Context) {
 // @Summary      Get clinic admins list
 // @Description  Get clinic admins list
 // @Tags         clinic_admin
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
-// @Param        limit query string false "limit"
+// @Param        limit query string false "limit, 10 by default and at most 100"
 // @Param        search query string false "search"
+// @Param        created_from query string false "created at or after the date, YYYY-MM-DD"
+// @Param        created_to query string false "created at or before the date, YYYY-MM-DD"
+// @Param        sort_by query string false "created_at (default), first_name, last_name"
+// @Param        order query string false "asc or desc, desc for the default sorting and asc otherwise"
+// @Param        clinic_branch_id query string false "clinic branch id"
+// @Param        doctor_type_id query string false "doctor type id"
+// @Param        include_deleted query bool false "list soft deleted records too, super admin only"
 // @Success      200  {object}  models.ClinicAdminsResponse
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetClinicAdminsList(c *gin.Context) {
 
-	var (
-		page, limit int
-		search      string
-		err         error
-	)
-
-	pageStr := c.DefaultQuery("page", "1")
-	page, err = strconv.Atoi(pageStr)
+	request, err := getListRequest(c)
 	if err != nil {
-		handleResponse(c, "error while parsing page ", http.StatusBadRequest, err.Error())
+		handleResponse(c, "error while parsing list request", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err = strconv.Atoi(limitStr)
-	if err != nil {
-		handleResponse(c, "error while parsing limit", http.StatusBadRequest, err.Error())
+	if !readIncludeDeleted(c, &request) {
 		return
 	}
 
-	search = c.Query("search")
+	ids, err := getUUIDQueries(c, "clinic_branch_id", "doctor_type_id")
+	if err != nil {
+		handleResponse(c, "error while parsing list filters", http.StatusBadRequest, err.Error())
+		return
+	}
 
-	response, err := h.storage.ClinicAdmin().GetList(context.Background(), models.GetListRequest{
-		Page:   page,
-		Limit:  limit,
-		Search: search,
+	response, err := h.services.ClinicAdmin().GetList(c.Request.Context(), models.GetClinicAdminsListRequest{
+		GetListRequest: request,
+		ClinicBranchID: ids["clinic_branch_id"],
+		DoctorTypeID:   ids["doctor_type_id"],
 	})
 
 	if err != nil {
-		handleResponse(c, "error while getting clinic admin", http.StatusInternalServerError, err)
+		handleError(c, "error while getting clinic admin", err)
 		return
 	}
 
@@ -140,16 +141,22 @@ func (h Handler) GetClinicAdminsList(c *gin.Context) {
 
 // UpdateClinicAdmin godoc
 // @Router       /clinic_admin/{id} [PUT]
+// @Router       /clinic_admin/{id} [PATCH]
 // @Summary      Update clinic admin by id
-// @Description  Update clinic admin by id
+// @Description  Update clinic admin by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields
 // @Tags         clinic_admin
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "clinic admin id"
 // @Param        clinic_admin body models.UpdateClinicAdmin true "clinic_admin"
+// @Param        If-Match header string false "ETag of the record, the update fails with 412 when the record was changed since"
 // @Success      200  {object}  models.ClinicAdmin
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      412  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdateClinicAdmin(c *gin.Context) {
 	updateClinicAdmin := models.UpdateClinicAdmin{}
@@ -160,27 +167,32 @@ func (h Handler) UpdateClinicAdmin(c *gin.Context) {
 		return
 	}
 
-	//updateAuthor.ID = uid
+	if !checkOwner(c, config.ClinicAdminRole, uid) {
+		return
+	}
 
-	if err := c.ShouldBindJSON(&updateClinicAdmin); err != nil {
-		handleResponse(c, "error while reading body", http.StatusBadRequest, err.Error())
+	if !bindUpdate(c, &updateClinicAdmin, func() (interface{}, error) {
+		return h.services.ClinicAdmin().Get(c.Request.Context(), models.PrimaryKey{ID: uid})
+	}) {
 		return
 	}
 
-	id, err := h.storage.ClinicAdmin().Update(context.Background(), updateClinicAdmin)
-	if err != nil {
-		handleResponse(c, "error while updating clinic admin", http.StatusInternalServerError, err.Error())
+	updateClinicAdmin.ID = uid
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
-	clinicAdmin, err := h.storage.ClinicAdmin().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	updateClinicAdmin.Version = version
+
+	clinicAdmin, err := h.services.ClinicAdmin().Update(c.Request.Context(), updateClinicAdmin)
 	if err != nil {
-		handleResponse(c, "error while getting clinic admin by id", http.StatusInternalServerError, err)
+		handleError(c, "error while updating clinic admin", err)
 		return
 	}
 
+	setETag(c, clinicAdmin.Version)
 	handleResponse(c, "", http.StatusOK, clinicAdmin)
 
 }
@@ -190,11 +202,13 @@ func (h Handler) UpdateClinicAdmin(c *gin.Context) {
 // @Summary      Delete clinic admin
 // @Description  Delete clinic admin
 // @Tags         clinic_admin
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "clinic_admin id"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) DeleteClinicAdmin(c *gin.Context) {
@@ -206,8 +220,8 @@ func (h Handler) DeleteClinicAdmin(c *gin.Context) {
 		return
 	}
 
-	if err := h.storage.ClinicAdmin().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, "error while deleting clinic admin by id", http.StatusInternalServerError, err.Error())
+	if err := h.services.ClinicAdmin().Delete(c.Request.Context(), id.String()); err != nil {
+		handleError(c, "error while deleting clinic admin by id", err)
 		return
 	}
 
@@ -216,16 +230,18 @@ func (h Handler) DeleteClinicAdmin(c *gin.Context) {
 }
 
 // UpdateClinicAdminPassword godoc
-// @Router       /clinic_admin/{id} [PATCH]
+// @Router       /clinic_admin/{id}/password [PATCH]
 // @Summary      Update clinic admin password
 // @Description  update clinic admin password
 // @Tags         clinic_admin
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param 		 id path string true "clinic admin"
 // @Param        clinic_admin body models.UpdateClinicAdminPassword true "clinic_admin"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdateClinicAdminPassword(c *gin.Context) {
@@ -233,7 +249,7 @@ func (h Handler) UpdateClinicAdminPassword(c *gin.Context) {
 	updateClinicAdminPassword := models.UpdateClinicAdminPassword{}
 
 	if err := c.ShouldBindJSON(&updateClinicAdminPassword); err != nil {
-		handleResponse(c, "error while reading body", http.StatusBadRequest, err.Error())
+		handleBindError(c, err)
 		return
 	}
 
@@ -245,27 +261,46 @@ func (h Handler) UpdateClinicAdminPassword(c *gin.Context) {
 
 	updateClinicAdminPassword.ID = uid.String()
 
-	oldPassword, err := h.storage.ClinicAdmin().GetPassword(c, updateClinicAdminPassword.ID)
-	if err != nil {
-		handleResponse(c, "error while getting password by id", http.StatusInternalServerError, err.Error())
+	if !checkSelf(c, config.ClinicAdminRole, updateClinicAdminPassword.ID) {
 		return
 	}
 
-	if oldPassword != updateClinicAdminPassword.OldPassword{
-		handleResponse(c, "old password is not correct", http.StatusBadRequest, "old password is not correct")
+	if err = h.services.ClinicAdmin().UpdatePassword(c.Request.Context(), updateClinicAdminPassword); err != nil {
+		handleError(c, "error while updating clinic admin password", err)
 		return
 	}
 
-	if err = check.ValidatePassword(updateClinicAdminPassword.NewPassword); err != nil {
-		handleResponse(c, "new password is weak", http.StatusBadRequest, err.Error())
-		return
-	} 
+	handleResponse(c, "", http.StatusOK, "password successfully updated")
+}
+
+// RestoreClinicAdmin godoc
+// @Router       /clinic_admin/{id}/restore [POST]
+// @Summary      Restore clinic admin
+// @Description  Restore a soft deleted clinic admin
+// @Tags         clinic_admin
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "clinic admin id"
+// @Success      200  {object}  models.ClinicAdmin
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) RestoreClinicAdmin(c *gin.Context) {
 
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
 
-	if err = h.storage.ClinicAdmin().UpdatePassword(context.Background(), updateClinicAdminPassword); err != nil {
-		handleResponse(c, "error while updating clinic admin password", http.StatusInternalServerError, err.Error())
+	clinicAdmin, err := h.services.ClinicAdmin().Restore(c.Request.Context(), id.String())
+	if err != nil {
+		handleError(c, "error while restoring clinic admin", err)
 		return
 	}
 
-	handleResponse(c, "", http.StatusOK, "password successfully updated")
+	handleResponse(c, "", http.StatusOK, clinicAdmin)
 }
@@ -1,11 +1,9 @@
 package handler
 
 import (
-	"context"
 	"errors"
 	"net/http"
 	"shifolink/api/models"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -16,31 +14,26 @@ import (
 // @Summary      Create a new clinic branch
 // @Description  Create a new clinic branch
 // @Tags         clinic_branch
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        clinic_branch body  models.CreateClinicBranch  true  "clinic branch data"
 // @Success      201  {object}  models.ClinicBranch
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) CreateClinicBranch(c *gin.Context) {
 	createClinicBranch := models.CreateClinicBranch{}
 
 	if err := c.ShouldBindJSON(&createClinicBranch); err != nil {
-		handleResponse(c, "error while reading body from client", http.StatusBadRequest, err)
-	}
-
-	id, err := h.storage.ClinicBranch().Create(context.Background(), createClinicBranch)
-	if err != nil {
-		handleResponse(c, "error while creating clinic branch", http.StatusInternalServerError, err)
+		handleBindError(c, err)
 		return
 	}
 
-	clinicBranch, err := h.storage.ClinicBranch().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	clinicBranch, err := h.services.ClinicBranch().Create(c.Request.Context(), createClinicBranch)
 	if err != nil {
-		handleResponse(c, "error while get clinic branch", http.StatusInternalServerError, err)
+		handleError(c, "error while creating clinic branch", err)
 		return
 	}
 
@@ -57,7 +50,9 @@ func (h Handler) CreateClinicBranch(c *gin.Context) {
 // @Produce      json
 // @Param        id path string true "clinic_branch"
 // @Success      200  {object}  models.ClinicBranch
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetClinicBranchByID(c *gin.Context) {
@@ -72,14 +67,15 @@ func (h Handler) GetClinicBranchByID(c *gin.Context) {
 		return
 	}
 
-	clinicBranch, err := h.storage.ClinicBranch().Get(context.Background(), models.PrimaryKey{
+	clinicBranch, err := h.services.ClinicBranch().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, "error while get clinic branch by id", http.StatusInternalServerError, err)
+		handleError(c, "error while get clinic branch by id", err)
 		return
 	}
 
+	setETag(c, clinicBranch.Version)
 	handleResponse(c, "", http.StatusOK, clinicBranch)
 
 }
@@ -92,44 +88,45 @@ func (h Handler) GetClinicBranchByID(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
-// @Param        limit query string false "limit"
+// @Param        limit query string false "limit, 10 by default and at most 100"
 // @Param        search query string false "search"
+// @Param        created_from query string false "created at or after the date, YYYY-MM-DD"
+// @Param        created_to query string false "created at or before the date, YYYY-MM-DD"
+// @Param        sort_by query string false "created_at (default), address"
+// @Param        order query string false "asc or desc, desc for the default sorting and asc otherwise"
+// @Param        clinic_id query string false "clinic id"
+// @Param        include_deleted query bool false "list soft deleted records too, super admin only"
 // @Success      200  {object}  models.ClinicBranchsResponse
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetClinicBranchsList(c *gin.Context) {
 
-	var (
-		page, limit int
-		search      string
-		err         error
-	)
-
-	pageStr := c.DefaultQuery("page", "1")
-	page, err = strconv.Atoi(pageStr)
+	request, err := getListRequest(c)
 	if err != nil {
-		handleResponse(c, "error while parsing page ", http.StatusBadRequest, err.Error())
+		handleResponse(c, "error while parsing list request", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err = strconv.Atoi(limitStr)
-	if err != nil {
-		handleResponse(c, "error while parsing limit", http.StatusBadRequest, err.Error())
+	if !readIncludeDeleted(c, &request) {
 		return
 	}
 
-	search = c.Query("search")
+	ids, err := getUUIDQueries(c, "clinic_id")
+	if err != nil {
+		handleResponse(c, "error while parsing list filters", http.StatusBadRequest, err.Error())
+		return
+	}
 
-	response, err := h.storage.ClinicBranch().GetList(context.Background(), models.GetListRequest{
-		Page:   page,
-		Limit:  limit,
-		Search: search,
+	response, err := h.services.ClinicBranch().GetList(c.Request.Context(), models.GetClinicBranchsListRequest{
+		GetListRequest: request,
+		ClinicID:       ids["clinic_id"],
 	})
 
 	if err != nil {
-		handleResponse(c, "error while getting clinic branch", http.StatusInternalServerError, err)
+		handleError(c, "error while getting clinic branch", err)
 		return
 	}
 
@@ -139,16 +136,22 @@ func (h Handler) GetClinicBranchsList(c *gin.Context) {
 
 // UpdateClinicBranch godoc
 // @Router       /clinic_branch/{id} [PUT]
+// @Router       /clinic_branch/{id} [PATCH]
 // @Summary      Update clinic branch by id
-// @Description  Update clinic branch by id
+// @Description  Update clinic branch by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields
 // @Tags         clinic_branch
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "clinic branch id"
 // @Param        clinic_branch body models.UpdateClinicBranch true "clinic_branch"
+// @Param        If-Match header string false "ETag of the record, the update fails with 412 when the record was changed since"
 // @Success      200  {object}  models.ClinicBranch
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      412  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdateClinicBranch(c *gin.Context) {
 	updateClinicBranch := models.UpdateClinicBranch{}
@@ -159,27 +162,28 @@ func (h Handler) UpdateClinicBranch(c *gin.Context) {
 		return
 	}
 
-	//updateAuthor.ID = uid
-
-	if err := c.ShouldBindJSON(&updateClinicBranch); err != nil {
-		handleResponse(c, "error while reading body", http.StatusBadRequest, err.Error())
+	if !bindUpdate(c, &updateClinicBranch, func() (interface{}, error) {
+		return h.services.ClinicBranch().Get(c.Request.Context(), models.PrimaryKey{ID: uid})
+	}) {
 		return
 	}
 
-	id, err := h.storage.ClinicBranch().Update(context.Background(), updateClinicBranch)
-	if err != nil {
-		handleResponse(c, "error while updating clinic branch", http.StatusInternalServerError, err.Error())
+	updateClinicBranch.ID = uid
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
-	clinicBranch, err := h.storage.ClinicBranch().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	updateClinicBranch.Version = version
+
+	clinicBranch, err := h.services.ClinicBranch().Update(c.Request.Context(), updateClinicBranch)
 	if err != nil {
-		handleResponse(c, "error while getting clinic branch by id", http.StatusInternalServerError, err)
+		handleError(c, "error while updating clinic branch", err)
 		return
 	}
 
+	setETag(c, clinicBranch.Version)
 	handleResponse(c, "", http.StatusOK, clinicBranch)
 
 }
@@ -189,11 +193,13 @@ func (h Handler) UpdateClinicBranch(c *gin.Context) {
 // @Summary      Delete clinic branch
 // @Description  Delete clinic branch
 // @Tags         clinic_branch
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "clinic_branch id"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) DeleteClinicBranch(c *gin.Context) {
@@ -205,11 +211,43 @@ func (h Handler) DeleteClinicBranch(c *gin.Context) {
 		return
 	}
 
-	if err := h.storage.ClinicBranch().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, "error while deleting clinic branch by id", http.StatusInternalServerError, err.Error())
+	if err := h.services.ClinicBranch().Delete(c.Request.Context(), id.String()); err != nil {
+		handleError(c, "error while deleting clinic branch by id", err)
 		return
 	}
 
 	handleResponse(c, "", http.StatusOK, "data succesfully deleted")
 
 }
+
+// RestoreClinicBranch godoc
+// @Router       /clinic_branch/{id}/restore [POST]
+// @Summary      Restore clinic branch
+// @Description  Restore a soft deleted clinic branch together with the doctor types and doctors its delete took with it
+// @Tags         clinic_branch
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "clinic branch id"
+// @Success      200  {object}  models.ClinicBranch
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) RestoreClinicBranch(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	clinicBranch, err := h.services.ClinicBranch().Restore(c.Request.Context(), id.String())
+	if err != nil {
+		handleError(c, "error while restoring clinic branch", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, clinicBranch)
+}
@@ -1,12 +1,10 @@
 package handler
 
 import (
-	"context"
 	"errors"
 	"net/http"
 	"shifolink/api/models"
-	"shifolink/pkg/check"
-	"strconv"
+	"shifolink/config"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -22,26 +20,20 @@ import (
 // @Param        customer body  models.CreateCustomer true  "customer data"
 // @Success      201  {object}  models.Customer
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) CreateCustomer(c *gin.Context) {
 	createCustomer := models.CreateCustomer{}
 
 	if err := c.ShouldBindJSON(&createCustomer); err != nil {
-		handleResponse(c, "error while reading body from client", http.StatusBadRequest, err)
-	}
-
-	id, err := h.storage.Customer().Create(context.Background(), createCustomer)
-	if err != nil {
-		handleResponse(c, "error while creating customer", http.StatusInternalServerError, err)
+		handleBindError(c, err)
 		return
 	}
 
-	customer, err := h.storage.Customer().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	customer, err := h.services.Customer().Create(c.Request.Context(), createCustomer)
 	if err != nil {
-		handleResponse(c, "error while get customer ", http.StatusInternalServerError, err)
+		handleError(c, "error while creating customer", err)
 		return
 	}
 
@@ -54,11 +46,14 @@ func (h Handler) CreateCustomer(c *gin.Context) {
 // @Summary      Get customer by id
 // @Description  Get customer by id
 // @Tags         customer
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "customer"
 // @Success      200  {object}  models.Customer
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetCustomerByID(c *gin.Context) {
@@ -73,14 +68,19 @@ func (h Handler) GetCustomerByID(c *gin.Context) {
 		return
 	}
 
-	customer, err := h.storage.Customer().Get(context.Background(), models.PrimaryKey{
+	if getAuthInfo(c).UserRole == config.CustomerRole && !checkOwner(c, config.CustomerRole, id.String()) {
+		return
+	}
+
+	customer, err := h.services.Customer().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, "error while get customer by id", http.StatusInternalServerError, err)
+		handleError(c, "error while get customer by id", err)
 		return
 	}
 
+	setETag(c, customer.Version)
 	handleResponse(c, "", http.StatusOK, customer)
 
 }
@@ -90,47 +90,39 @@ func (h Handler) GetCustomerByID(c *gin.Context) {
 // @Summary      Get customers list
 // @Description  Get customers list
 // @Tags         customer
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
-// @Param        limit query string false "limit"
+// @Param        limit query string false "limit, 10 by default and at most 100"
 // @Param        search query string false "search"
+// @Param        created_from query string false "created at or after the date, YYYY-MM-DD"
+// @Param        created_to query string false "created at or before the date, YYYY-MM-DD"
+// @Param        sort_by query string false "created_at (default), first_name, last_name, birth_date"
+// @Param        order query string false "asc or desc, desc for the default sorting and asc otherwise"
+// @Param        include_deleted query bool false "list soft deleted records too, super admin only"
 // @Success      200  {object}  models.CustomersResponse
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetCustomersList(c *gin.Context) {
 
-	var (
-		page, limit int
-		search      string
-		err         error
-	)
-
-	pageStr := c.DefaultQuery("page", "1")
-	page, err = strconv.Atoi(pageStr)
+	request, err := getListRequest(c)
 	if err != nil {
-		handleResponse(c, "error while parsing page ", http.StatusBadRequest, err.Error())
+		handleResponse(c, "error while parsing list request", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err = strconv.Atoi(limitStr)
-	if err != nil {
-		handleResponse(c, "error while parsing limit", http.StatusBadRequest, err.Error())
+	if !readIncludeDeleted(c, &request) {
 		return
 	}
 
-	search = c.Query("search")
-
-	response, err := h.storage.Customer().GetList(context.Background(), models.GetListRequest{
-		Page:   page,
-		Limit:  limit,
-		Search: search,
-	})
+	response, err := h.services.Customer().GetList(c.Request.Context(), request)
 
 	if err != nil {
-		handleResponse(c, "error while getting customer", http.StatusInternalServerError, err)
+		handleError(c, "error while getting customer", err)
 		return
 	}
 
@@ -140,16 +132,22 @@ func (h Handler) GetCustomersList(c *gin.Context) {
 
 // UpdateCustomer godoc
 // @Router       /customer/{id} [PUT]
+// @Router       /customer/{id} [PATCH]
 // @Summary      Update customer by id
-// @Description  Update customer by id
+// @Description  Update customer by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields
 // @Tags         customer
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "customer id"
 // @Param        customer body models.UpdateCustomer true "customer"
+// @Param        If-Match header string false "ETag of the record, the update fails with 412 when the record was changed since"
 // @Success      200  {object}  models.Customer
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      412  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdateCustomer(c *gin.Context) {
 	updateCustomer := models.UpdateCustomer{}
@@ -160,27 +158,32 @@ func (h Handler) UpdateCustomer(c *gin.Context) {
 		return
 	}
 
-	//updateAuthor.ID = uid
+	if !checkOwner(c, config.CustomerRole, uid) {
+		return
+	}
 
-	if err := c.ShouldBindJSON(&updateCustomer); err != nil {
-		handleResponse(c, "error while reading body", http.StatusBadRequest, err.Error())
+	if !bindUpdate(c, &updateCustomer, func() (interface{}, error) {
+		return h.services.Customer().Get(c.Request.Context(), models.PrimaryKey{ID: uid})
+	}) {
 		return
 	}
 
-	id, err := h.storage.Customer().Update(context.Background(), updateCustomer)
-	if err != nil {
-		handleResponse(c, "error while updating customer ", http.StatusInternalServerError, err.Error())
+	updateCustomer.ID = uid
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
-	customer, err := h.storage.Customer().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	updateCustomer.Version = version
+
+	customer, err := h.services.Customer().Update(c.Request.Context(), updateCustomer)
 	if err != nil {
-		handleResponse(c, "error while getting customer by id", http.StatusInternalServerError, err)
+		handleError(c, "error while updating customer ", err)
 		return
 	}
 
+	setETag(c, customer.Version)
 	handleResponse(c, "", http.StatusOK, customer)
 
 }
@@ -190,11 +193,13 @@ func (h Handler) UpdateCustomer(c *gin.Context) {
 // @Summary      Delete customer
 // @Description  Delete customer
 // @Tags         customer
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "customer id"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) DeleteCustomer(c *gin.Context) {
@@ -206,8 +211,12 @@ func (h Handler) DeleteCustomer(c *gin.Context) {
 		return
 	}
 
-	if err := h.storage.Customer().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, "error while deleting customer by id", http.StatusInternalServerError, err.Error())
+	if !checkOwner(c, config.CustomerRole, id.String()) {
+		return
+	}
+
+	if err := h.services.Customer().Delete(c.Request.Context(), id.String()); err != nil {
+		handleError(c, "error while deleting customer by id", err)
 		return
 	}
 
@@ -216,23 +225,25 @@ func (h Handler) DeleteCustomer(c *gin.Context) {
 }
 
 // UpdateCustomerPassword godoc
-// @Router       /customer/{id} [PATCH]
+// @Router       /customer/{id}/password [PATCH]
 // @Summary      Update customer password
 // @Description  update customer password
 // @Tags         customer
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param 		 id path string true "customer"
 // @Param        customer body models.UpdateCustomerPassword true "customer"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdateCustomerPassword(c *gin.Context) {
 	updateCustomerPassword := models.UpdateCustomerPassword{}
 
 	if err := c.ShouldBindJSON(&updateCustomerPassword); err != nil {
-		handleResponse(c, "error while reading body", http.StatusBadRequest, err.Error())
+		handleBindError(c, err)
 		return
 	}
 
@@ -244,26 +255,46 @@ func (h Handler) UpdateCustomerPassword(c *gin.Context) {
 
 	updateCustomerPassword.ID = uid.String()
 
-	oldPassword, err := h.storage.Customer().GetPassword(c, updateCustomerPassword.ID)
-	if err != nil {
-		handleResponse(c, "error while getting password by id", http.StatusInternalServerError, err.Error())
+	if !checkSelf(c, config.CustomerRole, updateCustomerPassword.ID) {
 		return
 	}
 
-	if oldPassword != updateCustomerPassword.OldPassword {
-		handleResponse(c, "old password is not correct", http.StatusBadRequest, "old password is not correct")
+	if err = h.services.Customer().UpdatePassword(c.Request.Context(), updateCustomerPassword); err != nil {
+		handleError(c, "error while updating customer password", err)
 		return
 	}
 
-	if err = check.ValidatePassword(updateCustomerPassword.NewPassword); err != nil {
-		handleResponse(c, "new password is weak", http.StatusBadRequest, err.Error())
+	handleResponse(c, "", http.StatusOK, "password successfully updated")
+}
+
+// RestoreCustomer godoc
+// @Router       /customer/{id}/restore [POST]
+// @Summary      Restore customer
+// @Description  Restore a soft deleted customer
+// @Tags         customer
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "customer id"
+// @Success      200  {object}  models.Customer
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) RestoreCustomer(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if err = h.storage.Customer().UpdatePassword(context.Background(), updateCustomerPassword); err != nil {
-		handleResponse(c, "error while updating customer password", http.StatusInternalServerError, err.Error())
+	customer, err := h.services.Customer().Restore(c.Request.Context(), id.String())
+	if err != nil {
+		handleError(c, "error while restoring customer", err)
 		return
 	}
 
-	handleResponse(c, "", http.StatusOK, "password successfully updated")
+	handleResponse(c, "", http.StatusOK, customer)
 }
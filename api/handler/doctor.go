@@ -1,12 +1,10 @@
 package handler
 
 import (
-	"context"
 	"errors"
 	"net/http"
 	"shifolink/api/models"
-	"shifolink/pkg/check"
-	"strconv"
+	"shifolink/config"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -17,31 +15,30 @@ import (
 // @Summary      Create a new doctor
 // @Description  Create a new doctor
 // @Tags         doctor
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        doctor body  models.CreateDoctor true  "doctor data"
 // @Success      201  {object}  models.Doctor
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) CreateDoctor(c *gin.Context) {
 	createDoctor := models.CreateDoctor{}
 
 	if err := c.ShouldBindJSON(&createDoctor); err != nil {
-		handleResponse(c, "error while reading body from client", http.StatusBadRequest, err)
+		handleBindError(c, err)
+		return
 	}
 
-	id, err := h.storage.Doctor().Create(context.Background(), createDoctor)
-	if err != nil {
-		handleResponse(c, "error while creating doctor ", http.StatusInternalServerError, err)
+	if !h.checkDoctorTypeBranch(c, createDoctor.DoctorTypeID) {
 		return
 	}
 
-	doctor, err := h.storage.Doctor().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	doctor, err := h.services.Doctor().Create(c.Request.Context(), createDoctor)
 	if err != nil {
-		handleResponse(c, "error while get doctor ", http.StatusInternalServerError, err)
+		handleError(c, "error while creating doctor ", err)
 		return
 	}
 
@@ -58,7 +55,9 @@ func (h Handler) CreateDoctor(c *gin.Context) {
 // @Produce      json
 // @Param        id path string true "doctor"
 // @Success      200  {object}  models.Doctor
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetDoctorByID(c *gin.Context) {
@@ -73,14 +72,15 @@ func (h Handler) GetDoctorByID(c *gin.Context) {
 		return
 	}
 
-	doctor, err := h.storage.Doctor().Get(context.Background(), models.PrimaryKey{
+	doctor, err := h.services.Doctor().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, "error while get doctor by id", http.StatusInternalServerError, err)
+		handleError(c, "error while get doctor by id", err)
 		return
 	}
 
+	setETag(c, doctor.Version)
 	handleResponse(c, "", http.StatusOK, doctor)
 
 }
@@ -93,44 +93,55 @@ func (h Handler) GetDoctorByID(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
-// @Param        limit query string false "limit"
+// @Param        limit query string false "limit, 10 by default and at most 100"
 // @Param        search query string false "search"
+// @Param        created_from query string false "created at or after the date, YYYY-MM-DD"
+// @Param        created_to query string false "created at or before the date, YYYY-MM-DD"
+// @Param        sort_by query string false "created_at (default), first_name, last_name, status"
+// @Param        order query string false "asc or desc, desc for the default sorting and asc otherwise"
+// @Param        doctor_type_id query string false "doctor type id"
+// @Param        clinic_branch_id query string false "clinic branch id"
+// @Param        status query string false "busy or empty"
+// @Param        include_deleted query bool false "list soft deleted records too, super admin only"
 // @Success      200  {object}  models.DoctorsResponse
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetDoctorsList(c *gin.Context) {
 
-	var (
-		page, limit int
-		search      string
-		err         error
-	)
-
-	pageStr := c.DefaultQuery("page", "1")
-	page, err = strconv.Atoi(pageStr)
+	request, err := getListRequest(c)
 	if err != nil {
-		handleResponse(c, "error while parsing page ", http.StatusBadRequest, err.Error())
+		handleResponse(c, "error while parsing list request", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !readIncludeDeleted(c, &request) {
 		return
 	}
 
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err = strconv.Atoi(limitStr)
+	ids, err := getUUIDQueries(c, "doctor_type_id", "clinic_branch_id")
 	if err != nil {
-		handleResponse(c, "error while parsing limit", http.StatusBadRequest, err.Error())
+		handleResponse(c, "error while parsing list filters", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	search = c.Query("search")
+	status := c.Query("status")
+	if status != "" && status != config.DoctorBusy && status != config.DoctorEmpty {
+		handleResponse(c, "unknown doctor status", http.StatusBadRequest, status)
+		return
+	}
 
-	response, err := h.storage.Doctor().GetList(context.Background(), models.GetListRequest{
-		Page:   page,
-		Limit:  limit,
-		Search: search,
+	response, err := h.services.Doctor().GetList(c.Request.Context(), models.GetDoctorsListRequest{
+		GetListRequest: request,
+		DoctorTypeID:   ids["doctor_type_id"],
+		ClinicBranchID: ids["clinic_branch_id"],
+		Status:         status,
 	})
 
 	if err != nil {
-		handleResponse(c, "error while getting doctor ", http.StatusInternalServerError, err)
+		handleError(c, "error while getting doctor ", err)
 		return
 	}
 
@@ -140,16 +151,22 @@ func (h Handler) GetDoctorsList(c *gin.Context) {
 
 // UpdateDoctor godoc
 // @Router       /doctor/{id} [PUT]
+// @Router       /doctor/{id} [PATCH]
 // @Summary      Update doctor by id
-// @Description  Update doctor by id
+// @Description  Update doctor by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields
 // @Tags         doctor
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "doctor id"
 // @Param        doctor body models.UpdateDoctor true "doctor"
+// @Param        If-Match header string false "ETag of the record, the update fails with 412 when the record was changed since"
 // @Success      200  {object}  models.Doctor
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      412  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdateDoctor(c *gin.Context) {
 	updateDoctor := models.UpdateDoctor{}
@@ -160,27 +177,32 @@ func (h Handler) UpdateDoctor(c *gin.Context) {
 		return
 	}
 
-	//updateAuthor.ID = uid
+	if !bindUpdate(c, &updateDoctor, func() (interface{}, error) {
+		return h.services.Doctor().Get(c.Request.Context(), models.PrimaryKey{ID: uid})
+	}) {
+		return
+	}
+
+	updateDoctor.ID = uid
 
-	if err := c.ShouldBindJSON(&updateDoctor); err != nil {
-		handleResponse(c, "error while reading body", http.StatusBadRequest, err.Error())
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
-	id, err := h.storage.Doctor().Update(context.Background(), updateDoctor)
-	if err != nil {
-		handleResponse(c, "error while updating doctor ", http.StatusInternalServerError, err.Error())
+	updateDoctor.Version = version
+
+	if !h.checkDoctorBranch(c, uid) || !h.checkDoctorTypeBranch(c, updateDoctor.DoctorTypeID) {
 		return
 	}
 
-	doctor, err := h.storage.Doctor().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	doctor, err := h.services.Doctor().Update(c.Request.Context(), updateDoctor)
 	if err != nil {
-		handleResponse(c, "error while getting doctor by id", http.StatusInternalServerError, err)
+		handleError(c, "error while updating doctor ", err)
 		return
 	}
 
+	setETag(c, doctor.Version)
 	handleResponse(c, "", http.StatusOK, doctor)
 
 }
@@ -190,11 +212,13 @@ func (h Handler) UpdateDoctor(c *gin.Context) {
 // @Summary      Delete doctor
 // @Description  Delete doctor
 // @Tags         doctor
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "doctor id"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) DeleteDoctor(c *gin.Context) {
@@ -206,8 +230,12 @@ func (h Handler) DeleteDoctor(c *gin.Context) {
 		return
 	}
 
-	if err := h.storage.Doctor().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, "error while deleting doctor by id", http.StatusInternalServerError, err.Error())
+	if !h.checkDoctorBranch(c, id.String()) {
+		return
+	}
+
+	if err := h.services.Doctor().Delete(c.Request.Context(), id.String()); err != nil {
+		handleError(c, "error while deleting doctor by id", err)
 		return
 	}
 
@@ -216,23 +244,25 @@ func (h Handler) DeleteDoctor(c *gin.Context) {
 }
 
 // UpdateDoctorPassword godoc
-// @Router       /doctor/{id} [PATCH]
+// @Router       /doctor/{id}/password [PATCH]
 // @Summary      Update doctor password
 // @Description  update doctor password
 // @Tags         doctor
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param 		 id path string true "doctor"
 // @Param        doctor body models.UpdateDoctorPassword true "doctor"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdateDoctorPassword(c *gin.Context) {
 	updateDoctorPassword := models.UpdateDoctorPassword{}
 
 	if err := c.ShouldBindJSON(&updateDoctorPassword); err != nil {
-		handleResponse(c, "error while reading body", http.StatusBadRequest, err.Error())
+		handleBindError(c, err)
 		return
 	}
 
@@ -244,26 +274,46 @@ func (h Handler) UpdateDoctorPassword(c *gin.Context) {
 
 	updateDoctorPassword.ID = uid.String()
 
-	oldPassword, err := h.storage.Doctor().GetPassword(c, updateDoctorPassword.ID)
-	if err != nil {
-		handleResponse(c, "error while getting password by id", http.StatusInternalServerError, err.Error())
+	if !checkSelf(c, config.DoctorRole, updateDoctorPassword.ID) {
 		return
 	}
 
-	if oldPassword != updateDoctorPassword.OldPassword {
-		handleResponse(c, "old password is not correct", http.StatusBadRequest, "old password is not correct")
+	if err = h.services.Doctor().UpdatePassword(c.Request.Context(), updateDoctorPassword); err != nil {
+		handleError(c, "error while updating doctor password", err)
 		return
 	}
 
-	if err = check.ValidatePassword(updateDoctorPassword.NewPassword); err != nil {
-		handleResponse(c, "new password is weak", http.StatusBadRequest, err.Error())
+	handleResponse(c, "", http.StatusOK, "password successfully updated")
+}
+
+// RestoreDoctor godoc
+// @Router       /doctor/{id}/restore [POST]
+// @Summary      Restore doctor
+// @Description  Restore a soft deleted doctor
+// @Tags         doctor
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "doctor id"
+// @Success      200  {object}  models.Doctor
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) RestoreDoctor(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if err = h.storage.Doctor().UpdatePassword(context.Background(), updateDoctorPassword); err != nil {
-		handleResponse(c, "error while updating doctor password", http.StatusInternalServerError, err.Error())
+	doctor, err := h.services.Doctor().Restore(c.Request.Context(), id.String())
+	if err != nil {
+		handleError(c, "error while restoring doctor", err)
 		return
 	}
 
-	handleResponse(c, "", http.StatusOK, "password successfully updated")
+	handleResponse(c, "", http.StatusOK, doctor)
 }
@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"net/http"
+	"shifolink/api/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreateDoctorSchedule godoc
+// @Router       /doctor_schedule [POST]
+// @Summary      Create a doctor schedule
+// @Description  Create working hours of a doctor for one weekday (0 - sunday, 6 - saturday), times are in HH:MM format
+// @Tags         doctor_schedule
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        doctor_schedule body models.CreateDoctorSchedule true "doctor schedule data"
+// @Success      201  {object}  models.DoctorSchedule
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) CreateDoctorSchedule(c *gin.Context) {
+	createSchedule := models.CreateDoctorSchedule{}
+
+	if err := c.ShouldBindJSON(&createSchedule); err != nil {
+		handleBindError(c, err)
+		return
+	}
+
+	if !h.checkDoctorBranch(c, createSchedule.DoctorID) {
+		return
+	}
+
+	schedule, err := h.services.DoctorSchedule().Create(c.Request.Context(), createSchedule)
+	if err != nil {
+		handleError(c, "error while creating doctor schedule", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusCreated, schedule)
+}
+
+// GetDoctorScheduleByID godoc
+// @Router       /doctor_schedule/{id} [GET]
+// @Summary      Get doctor schedule by id
+// @Description  Get doctor schedule by id
+// @Tags         doctor_schedule
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "doctor schedule id"
+// @Success      200  {object}  models.DoctorSchedule
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetDoctorScheduleByID(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schedule, err := h.services.DoctorSchedule().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id.String(),
+	})
+	if err != nil {
+		handleError(c, "error while get doctor schedule by id", err)
+		return
+	}
+
+	setETag(c, schedule.Version)
+	handleResponse(c, "", http.StatusOK, schedule)
+}
+
+// GetDoctorSchedules godoc
+// @Router       /doctor/{id}/schedule [GET]
+// @Summary      Get weekly schedule of a doctor
+// @Description  Get weekly schedule of a doctor
+// @Tags         doctor_schedule
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "doctor id"
+// @Success      200  {object}  models.DoctorSchedulesResponse
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetDoctorSchedules(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schedules, err := h.services.DoctorSchedule().GetByDoctor(c.Request.Context(), id.String())
+	if err != nil {
+		handleError(c, "error while getting doctor schedules", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, schedules)
+}
+
+// UpdateDoctorSchedule godoc
+// @Router       /doctor_schedule/{id} [PUT]
+// @Router       /doctor_schedule/{id} [PATCH]
+// @Summary      Update doctor schedule by id
+// @Description  Update doctor schedule by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields
+// @Tags         doctor_schedule
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "doctor schedule id"
+// @Param        doctor_schedule body models.UpdateDoctorSchedule true "doctor schedule"
+// @Param        If-Match header string false "ETag of the record, the update fails with 412 when the record was changed since"
+// @Success      200  {object}  models.DoctorSchedule
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      412  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) UpdateDoctorSchedule(c *gin.Context) {
+	updateSchedule := models.UpdateDoctorSchedule{}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !bindUpdate(c, &updateSchedule, func() (interface{}, error) {
+		return h.services.DoctorSchedule().Get(c.Request.Context(), models.PrimaryKey{ID: id.String()})
+	}) {
+		return
+	}
+
+	updateSchedule.ID = id.String()
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	updateSchedule.Version = version
+
+	if !h.checkDoctorScheduleBranch(c, updateSchedule.ID) {
+		return
+	}
+
+	schedule, err := h.services.DoctorSchedule().Update(c.Request.Context(), updateSchedule)
+	if err != nil {
+		handleError(c, "error while updating doctor schedule", err)
+		return
+	}
+
+	setETag(c, schedule.Version)
+	handleResponse(c, "", http.StatusOK, schedule)
+}
+
+// DeleteDoctorSchedule godoc
+// @Router       /doctor_schedule/{id} [DELETE]
+// @Summary      Delete doctor schedule
+// @Description  Delete doctor schedule
+// @Tags         doctor_schedule
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "doctor schedule id"
+// @Success      200  {object}  models.Response
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) DeleteDoctorSchedule(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !h.checkDoctorScheduleBranch(c, id.String()) {
+		return
+	}
+
+	if err := h.services.DoctorSchedule().Delete(c.Request.Context(), id.String()); err != nil {
+		handleError(c, "error while deleting doctor schedule by id", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, "data succesfully deleted")
+}
+
+// GetDoctorSlots godoc
+// @Router       /doctor/{id}/slots [GET]
+// @Summary      Get free slots of a doctor
+// @Description  Get free appointment slots of a doctor on the given date, start_time of a slot can be used to book a queue
+// @Tags         doctor_schedule
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "doctor id"
+// @Param        date query string true "date in YYYY-MM-DD format"
+// @Success      200  {object}  models.DoctorSlots
+// @Failure      400  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetDoctorSlots(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	date := c.Query("date")
+	if date == "" {
+		handleResponse(c, "date is required", http.StatusBadRequest, "date query parameter is required")
+		return
+	}
+
+	slots, err := h.services.DoctorSchedule().GetSlots(c.Request.Context(), id.String(), date)
+	if err != nil {
+		handleResponse(c, "error while getting doctor slots", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, slots)
+}
+
+// checkDoctorScheduleBranch makes sure a clinic admin changes schedules only of their own branch doctors
+func (h Handler) checkDoctorScheduleBranch(c *gin.Context, scheduleID string) bool {
+
+	schedule, err := h.services.DoctorSchedule().Get(c.Request.Context(), models.PrimaryKey{
+		ID: scheduleID,
+	})
+	if err != nil {
+		handleResponse(c, "error while getting doctor schedule", http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	return h.checkDoctorBranch(c, schedule.DoctorID)
+}
@@ -1,11 +1,9 @@
 package handler
 
 import (
-	"context"
 	"errors"
 	"net/http"
 	"shifolink/api/models"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -16,31 +14,26 @@ import (
 // @Summary      Create a new doctor type
 // @Description  Create a new doctor type 
 // @Tags         doctor_type
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        doctor_type body  models.CreateDoctorType true  "doctor type data"
 // @Success      201  {object}  models.DoctorType
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) CreateDoctorType(c *gin.Context) {
 	createDoctorType := models.CreateDoctorType{}
 
 	if err := c.ShouldBindJSON(&createDoctorType); err != nil {
-		handleResponse(c, "error while reading body from client", http.StatusBadRequest, err)
-	}
-
-	id, err := h.storage.DoctorType().Create(context.Background(), createDoctorType)
-	if err != nil {
-		handleResponse(c, "error while creating doctor type", http.StatusInternalServerError, err)
+		handleBindError(c, err)
 		return
 	}
 
-	dtype, err := h.storage.DoctorType().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	dtype, err := h.services.DoctorType().Create(c.Request.Context(), createDoctorType)
 	if err != nil {
-		handleResponse(c, "error while get doctor type ", http.StatusInternalServerError, err)
+		handleError(c, "error while creating doctor type", err)
 		return
 	}
 
@@ -57,7 +50,9 @@ func (h Handler) CreateDoctorType(c *gin.Context) {
 // @Produce      json
 // @Param        id path string true "doctor_type"
 // @Success      200  {object}  models.DoctorType
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetDoctorTypeByID(c *gin.Context) {
@@ -72,14 +67,15 @@ func (h Handler) GetDoctorTypeByID(c *gin.Context) {
 		return
 	}
 
-	dtype, err := h.storage.DoctorType().Get(context.Background(), models.PrimaryKey{
+	dtype, err := h.services.DoctorType().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, "error while get doctor type by id", http.StatusInternalServerError, err)
+		handleError(c, "error while get doctor type by id", err)
 		return
 	}
 
+	setETag(c, dtype.Version)
 	handleResponse(c, "", http.StatusOK, dtype)
 
 }
@@ -92,44 +88,45 @@ func (h Handler) GetDoctorTypeByID(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
-// @Param        limit query string false "limit"
+// @Param        limit query string false "limit, 10 by default and at most 100"
 // @Param        search query string false "search"
+// @Param        created_from query string false "created at or after the date, YYYY-MM-DD"
+// @Param        created_to query string false "created at or before the date, YYYY-MM-DD"
+// @Param        sort_by query string false "created_at (default), name"
+// @Param        order query string false "asc or desc, desc for the default sorting and asc otherwise"
+// @Param        clinic_branch_id query string false "clinic branch id"
+// @Param        include_deleted query bool false "list soft deleted records too, super admin only"
 // @Success      200  {object}  models.DoctorTypesResponse
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetDoctorTypesList(c *gin.Context) {
 
-	var (
-		page, limit int
-		search      string
-		err         error
-	)
-
-	pageStr := c.DefaultQuery("page", "1")
-	page, err = strconv.Atoi(pageStr)
+	request, err := getListRequest(c)
 	if err != nil {
-		handleResponse(c, "error while parsing page ", http.StatusBadRequest, err.Error())
+		handleResponse(c, "error while parsing list request", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err = strconv.Atoi(limitStr)
-	if err != nil {
-		handleResponse(c, "error while parsing limit", http.StatusBadRequest, err.Error())
+	if !readIncludeDeleted(c, &request) {
 		return
 	}
 
-	search = c.Query("search")
+	ids, err := getUUIDQueries(c, "clinic_branch_id")
+	if err != nil {
+		handleResponse(c, "error while parsing list filters", http.StatusBadRequest, err.Error())
+		return
+	}
 
-	response, err := h.storage.DoctorType().GetList(context.Background(), models.GetListRequest{
-		Page:   page,
-		Limit:  limit,
-		Search: search,
+	response, err := h.services.DoctorType().GetList(c.Request.Context(), models.GetDoctorTypesListRequest{
+		GetListRequest: request,
+		ClinicBranchID: ids["clinic_branch_id"],
 	})
 
 	if err != nil {
-		handleResponse(c, "error while getting doctor type", http.StatusInternalServerError, err)
+		handleError(c, "error while getting doctor type", err)
 		return
 	}
 
@@ -139,16 +136,22 @@ func (h Handler) GetDoctorTypesList(c *gin.Context) {
 
 // UpdateDoctorType godoc
 // @Router       /doctor_type/{id} [PUT]
+// @Router       /doctor_type/{id} [PATCH]
 // @Summary      Update doctor type by id
-// @Description  Update doctor type by id
+// @Description  Update doctor type by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields
 // @Tags         doctor_type
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "doctor type id"
 // @Param        doctor_type body models.UpdateDoctorType true "doctor_type"
+// @Param        If-Match header string false "ETag of the record, the update fails with 412 when the record was changed since"
 // @Success      200  {object}  models.DoctorType
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      412  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdateDoctorType(c *gin.Context) {
 	updateDoctorType := models.UpdateDoctorType{}
@@ -159,27 +162,28 @@ func (h Handler) UpdateDoctorType(c *gin.Context) {
 		return
 	}
 
-	//updateAuthor.ID = uid
-
-	if err := c.ShouldBindJSON(&updateDoctorType); err != nil {
-		handleResponse(c, "error while reading body", http.StatusBadRequest, err.Error())
+	if !bindUpdate(c, &updateDoctorType, func() (interface{}, error) {
+		return h.services.DoctorType().Get(c.Request.Context(), models.PrimaryKey{ID: uid})
+	}) {
 		return
 	}
 
-	id, err := h.storage.DoctorType().Update(context.Background(), updateDoctorType)
-	if err != nil {
-		handleResponse(c, "error while updating doctor type ", http.StatusInternalServerError, err.Error())
+	updateDoctorType.ID = uid
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
-	dtype, err := h.storage.DoctorType().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	updateDoctorType.Version = version
+
+	dtype, err := h.services.DoctorType().Update(c.Request.Context(), updateDoctorType)
 	if err != nil {
-		handleResponse(c, "error while getting doctor type by id", http.StatusInternalServerError, err)
+		handleError(c, "error while updating doctor type ", err)
 		return
 	}
 
+	setETag(c, dtype.Version)
 	handleResponse(c, "", http.StatusOK, dtype)
 
 }
@@ -189,11 +193,13 @@ func (h Handler) UpdateDoctorType(c *gin.Context) {
 // @Summary      Delete doctor type
 // @Description  Delete doctor type
 // @Tags         doctor_type
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "doctor_type id"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) DeleteDoctorType(c *gin.Context) {
@@ -205,11 +211,43 @@ func (h Handler) DeleteDoctorType(c *gin.Context) {
 		return
 	}
 
-	if err := h.storage.DoctorType().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, "error while deleting doctor type by id", http.StatusInternalServerError, err.Error())
+	if err := h.services.DoctorType().Delete(c.Request.Context(), id.String()); err != nil {
+		handleError(c, "error while deleting doctor type by id", err)
 		return
 	}
 
 	handleResponse(c, "", http.StatusOK, "data succesfully deleted")
 
 }
+
+// RestoreDoctorType godoc
+// @Router       /doctor_type/{id}/restore [POST]
+// @Summary      Restore doctor type
+// @Description  Restore a soft deleted doctor type together with the doctors its delete took with it
+// @Tags         doctor_type
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "doctor type id"
+// @Success      200  {object}  models.DoctorType
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) RestoreDoctorType(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	doctorType, err := h.services.DoctorType().Restore(c.Request.Context(), id.String())
+	if err != nil {
+		handleError(c, "error while restoring doctor type", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, doctorType)
+}
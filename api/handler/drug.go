@@ -1,10 +1,10 @@
 package handler
 
 import (
-	"context"
 	"errors"
 	"net/http"
 	"shifolink/api/models"
+	"shifolink/pkg/money"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -14,33 +14,28 @@ import (
 // CreateDrug godoc
 // @Router       /drug [POST]
 // @Summary      Create a new drug
-// @Description  Create a new drug
+// @Description  Create a new drug, the branch starts selling a catalogue drug. Name and prescription rule come from the catalogue, dates are in YYYY-MM-DD format and count becomes the first lot of the drug
 // @Tags         drug
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        drug body  models.CreateDrug true  "drug data"
 // @Success      201  {object}  models.Drug
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) CreateDrug(c *gin.Context) {
 	createDrug := models.CreateDrug{}
 
 	if err := c.ShouldBindJSON(&createDrug); err != nil {
-		handleResponse(c, "error while reading body from client", http.StatusBadRequest, err)
-	}
-
-	id, err := h.storage.Drug().Create(context.Background(), createDrug)
-	if err != nil {
-		handleResponse(c, "error while creating drug ", http.StatusInternalServerError, err)
+		handleBindError(c, err)
 		return
 	}
 
-	drug, err := h.storage.Drug().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	drug, err := h.services.Drug().Create(c.Request.Context(), createDrug)
 	if err != nil {
-		handleResponse(c, "error while get drug ", http.StatusInternalServerError, err)
+		handleError(c, "error while creating drug ", err)
 		return
 	}
 
@@ -57,7 +52,9 @@ func (h Handler) CreateDrug(c *gin.Context) {
 // @Produce      json
 // @Param        id path string true "drug"
 // @Success      200  {object}  models.Drug
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetDrugByID(c *gin.Context) {
@@ -72,14 +69,15 @@ func (h Handler) GetDrugByID(c *gin.Context) {
 		return
 	}
 
-	drug, err := h.storage.Drug().Get(context.Background(), models.PrimaryKey{
+	drug, err := h.services.Drug().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, "error while get drug by id", http.StatusInternalServerError, err)
+		handleError(c, "error while get drug by id", err)
 		return
 	}
 
+	setETag(c, drug.Version)
 	handleResponse(c, "", http.StatusOK, drug)
 
 }
@@ -92,44 +90,67 @@ func (h Handler) GetDrugByID(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
-// @Param        limit query string false "limit"
+// @Param        limit query string false "limit, 10 by default and at most 100"
 // @Param        search query string false "search"
+// @Param        created_from query string false "created at or after the date, YYYY-MM-DD"
+// @Param        created_to query string false "created at or before the date, YYYY-MM-DD"
+// @Param        sort_by query string false "created_at (default), name, price, count, best_before"
+// @Param        order query string false "asc or desc, desc for the default sorting and asc otherwise"
+// @Param        drug_store_branch_id query string false "drug store branch id"
+// @Param        drug_catalogue_id query string false "catalogue drug, lists the branches selling it"
+// @Param        min_price query string false "lowest price"
+// @Param        max_price query string false "highest price"
+// @Param        prescription_required query bool false "prescription-only drugs"
+// @Param        include_deleted query bool false "list soft deleted records too, super admin only"
 // @Success      200  {object}  models.DrugsResponse
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetDrugsList(c *gin.Context) {
 
-	var (
-		page, limit int
-		search      string
-		err         error
-	)
-
-	pageStr := c.DefaultQuery("page", "1")
-	page, err = strconv.Atoi(pageStr)
+	request, err := getListRequest(c)
 	if err != nil {
-		handleResponse(c, "error while parsing page ", http.StatusBadRequest, err.Error())
+		handleResponse(c, "error while parsing list request", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err = strconv.Atoi(limitStr)
+	if !readIncludeDeleted(c, &request) {
+		return
+	}
+
+	ids, err := getUUIDQueries(c, "drug_store_branch_id", "drug_catalogue_id")
 	if err != nil {
-		handleResponse(c, "error while parsing limit", http.StatusBadRequest, err.Error())
+		handleResponse(c, "error while parsing list filters", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	search = c.Query("search")
+	for _, key := range []string{"min_price", "max_price"} {
+		if _, err = money.Parse(c.Query(key)); err != nil {
+			handleResponse(c, "error while parsing "+key, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
 
-	response, err := h.storage.Drug().GetList(context.Background(), models.GetListRequest{
-		Page:   page,
-		Limit:  limit,
-		Search: search,
+	if prescriptionRequired := c.Query("prescription_required"); prescriptionRequired != "" {
+		if _, err = strconv.ParseBool(prescriptionRequired); err != nil {
+			handleResponse(c, "error while parsing prescription_required", http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	response, err := h.services.Drug().GetList(c.Request.Context(), models.GetDrugsListRequest{
+		GetListRequest:       request,
+		DrugStoreBranchID:    ids["drug_store_branch_id"],
+		DrugCatalogueID:      ids["drug_catalogue_id"],
+		MinPrice:             c.Query("min_price"),
+		MaxPrice:             c.Query("max_price"),
+		PrescriptionRequired: c.Query("prescription_required"),
 	})
 
 	if err != nil {
-		handleResponse(c, "error while getting drug ", http.StatusInternalServerError, err)
+		handleError(c, "error while getting drug ", err)
 		return
 	}
 
@@ -139,16 +160,22 @@ func (h Handler) GetDrugsList(c *gin.Context) {
 
 // UpdateDrug godoc
 // @Router       /drug/{id} [PUT]
+// @Router       /drug/{id} [PATCH]
 // @Summary      Update drug by id
-// @Description  Update drug by id
+// @Description  Update drug by id, stock is changed through drug lots and the branch and catalogue drug can not be changed. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields
 // @Tags         drug
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "drug id"
 // @Param        drug body models.UpdateDrug true "drug"
+// @Param        If-Match header string false "ETag of the record, the update fails with 412 when the record was changed since"
 // @Success      200  {object}  models.Drug
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      412  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdateDrug(c *gin.Context) {
 	updateDrug := models.UpdateDrug{}
@@ -159,27 +186,28 @@ func (h Handler) UpdateDrug(c *gin.Context) {
 		return
 	}
 
-	//updateAuthor.ID = uid
-
-	if err := c.ShouldBindJSON(&updateDrug); err != nil {
-		handleResponse(c, "error while reading body", http.StatusBadRequest, err.Error())
+	if !bindUpdate(c, &updateDrug, func() (interface{}, error) {
+		return h.services.Drug().Get(c.Request.Context(), models.PrimaryKey{ID: uid})
+	}) {
 		return
 	}
 
-	id, err := h.storage.Drug().Update(context.Background(), updateDrug)
-	if err != nil {
-		handleResponse(c, "error while updating drug ", http.StatusInternalServerError, err.Error())
+	updateDrug.ID = uid
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
-	Drug, err := h.storage.Drug().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	updateDrug.Version = version
+
+	Drug, err := h.services.Drug().Update(c.Request.Context(), updateDrug)
 	if err != nil {
-		handleResponse(c, "error while getting drug by id", http.StatusInternalServerError, err)
+		handleError(c, "error while updating drug ", err)
 		return
 	}
 
+	setETag(c, Drug.Version)
 	handleResponse(c, "", http.StatusOK, Drug)
 
 }
@@ -189,11 +217,13 @@ func (h Handler) UpdateDrug(c *gin.Context) {
 // @Summary      Delete drug
 // @Description  Delete drug
 // @Tags         drug
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "drug id"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) DeleteDrug(c *gin.Context) {
@@ -205,11 +235,43 @@ func (h Handler) DeleteDrug(c *gin.Context) {
 		return
 	}
 
-	if err := h.storage.Drug().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, "error while deleting drug  by id", http.StatusInternalServerError, err.Error())
+	if err := h.services.Drug().Delete(c.Request.Context(), id.String()); err != nil {
+		handleError(c, "error while deleting drug  by id", err)
 		return
 	}
 
 	handleResponse(c, "", http.StatusOK, "data succesfully deleted")
 
 }
+
+// RestoreDrug godoc
+// @Router       /drug/{id}/restore [POST]
+// @Summary      Restore drug
+// @Description  Restore a soft deleted drug
+// @Tags         drug
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "drug id"
+// @Success      200  {object}  models.Drug
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) RestoreDrug(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	drug, err := h.services.Drug().Restore(c.Request.Context(), id.String())
+	if err != nil {
+		handleError(c, "error while restoring drug", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, drug)
+}
@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"shifolink/api/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreateDrugCatalogue godoc
+// @Router       /drug_catalogue [POST]
+// @Summary      Add a drug to the catalogue
+// @Description  Add a drug to the catalogue shared by all drug stores, branches sell it through their drugs. Names are unique regardless of case
+// @Tags         drug_catalogue
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        drug_catalogue body  models.CreateDrugCatalogue true  "drug_catalogue data"
+// @Success      201  {object}  models.DrugCatalogue
+// @Failure      400  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) CreateDrugCatalogue(c *gin.Context) {
+	createDrugCatalogue := models.CreateDrugCatalogue{}
+
+	if err := c.ShouldBindJSON(&createDrugCatalogue); err != nil {
+		handleBindError(c, err)
+		return
+	}
+
+	drugCatalogue, err := h.services.DrugCatalogue().Create(c.Request.Context(), createDrugCatalogue)
+	if err != nil {
+		handleError(c, "error while creating drug catalogue ", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusCreated, drugCatalogue)
+
+}
+
+// GetDrugCatalogueByID godoc
+// @Router       /drug_catalogue/{id} [GET]
+// @Summary      Get catalogue drug by id
+// @Description  Get catalogue drug by id
+// @Tags         drug_catalogue
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "drug_catalogue"
+// @Success      200  {object}  models.DrugCatalogue
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetDrugCatalogueByID(c *gin.Context) {
+
+	var err error
+
+	uid := c.Param("id")
+
+	id, err := uuid.Parse(uid)
+	if err != nil {
+		handleResponse(c, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	drugCatalogue, err := h.services.DrugCatalogue().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id.String(),
+	})
+	if err != nil {
+		handleError(c, "error while get drug catalogue by id", err)
+		return
+	}
+
+	setETag(c, drugCatalogue.Version)
+	handleResponse(c, "", http.StatusOK, drugCatalogue)
+
+}
+
+// GetDrugCataloguesList godoc
+// @Router       /drug_catalogue [GET]
+// @Summary      Get drug catalogue
+// @Description  Get drugs of the catalogue
+// @Tags         drug_catalogue
+// @Accept       json
+// @Produce      json
+// @Param        page query string false "page"
+// @Param        limit query string false "limit, 10 by default and at most 100"
+// @Param        search query string false "search"
+// @Param        created_from query string false "created at or after the date, YYYY-MM-DD"
+// @Param        created_to query string false "created at or before the date, YYYY-MM-DD"
+// @Param        sort_by query string false "created_at (default), name"
+// @Param        order query string false "asc or desc, desc for the default sorting and asc otherwise"
+// @Param        include_deleted query bool false "list soft deleted records too, super admin only"
+// @Success      200  {object}  models.DrugCataloguesResponse
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetDrugCataloguesList(c *gin.Context) {
+
+	request, err := getListRequest(c)
+	if err != nil {
+		handleResponse(c, "error while parsing list request", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !readIncludeDeleted(c, &request) {
+		return
+	}
+
+	response, err := h.services.DrugCatalogue().GetList(c.Request.Context(), request)
+
+	if err != nil {
+		handleError(c, "error while getting drug catalogue ", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, response)
+
+}
+
+// UpdateDrugCatalogue godoc
+// @Router       /drug_catalogue/{id} [PUT]
+// @Router       /drug_catalogue/{id} [PATCH]
+// @Summary      Update catalogue drug by id
+// @Description  Update catalogue drug by id, name and prescription rule change for every branch selling it. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields
+// @Tags         drug_catalogue
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "drug_catalogue id"
+// @Param        drug_catalogue body models.UpdateDrugCatalogue true "drug_catalogue"
+// @Param        If-Match header string false "ETag of the record, the update fails with 412 when the record was changed since"
+// @Success      200  {object}  models.DrugCatalogue
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      412  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) UpdateDrugCatalogue(c *gin.Context) {
+	updateDrugCatalogue := models.UpdateDrugCatalogue{}
+
+	uid := c.Param("id")
+	if uid == "" {
+		handleResponse(c, "invalid uuid", http.StatusBadRequest, errors.New("uuid is not valid"))
+		return
+	}
+
+	if !bindUpdate(c, &updateDrugCatalogue, func() (interface{}, error) {
+		return h.services.DrugCatalogue().Get(c.Request.Context(), models.PrimaryKey{ID: uid})
+	}) {
+		return
+	}
+
+	updateDrugCatalogue.ID = uid
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	updateDrugCatalogue.Version = version
+
+	drugCatalogue, err := h.services.DrugCatalogue().Update(c.Request.Context(), updateDrugCatalogue)
+	if err != nil {
+		handleError(c, "error while updating drug catalogue ", err)
+		return
+	}
+
+	setETag(c, drugCatalogue.Version)
+	handleResponse(c, "", http.StatusOK, drugCatalogue)
+
+}
+
+// DeleteDrugCatalogue godoc
+// @Router       /drug_catalogue/{id} [DELETE]
+// @Summary      Delete catalogue drug
+// @Description  Remove the drug from the catalogue, drugs of the branches selling it are deleted with it
+// @Tags         drug_catalogue
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "drug_catalogue id"
+// @Success      200  {object}  models.Response
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) DeleteDrugCatalogue(c *gin.Context) {
+
+	uid := c.Param("id")
+	id, err := uuid.Parse(uid)
+	if err != nil {
+		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.services.DrugCatalogue().Delete(c.Request.Context(), id.String()); err != nil {
+		handleError(c, "error while deleting drug catalogue  by id", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, "data succesfully deleted")
+
+}
+
+// RestoreDrugCatalogue godoc
+// @Router       /drug_catalogue/{id}/restore [POST]
+// @Summary      Restore catalogue drug
+// @Description  Restore a soft deleted catalogue drug with the branch drugs its delete took
+// @Tags         drug_catalogue
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "drug_catalogue id"
+// @Success      200  {object}  models.DrugCatalogue
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) RestoreDrugCatalogue(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	drugCatalogue, err := h.services.DrugCatalogue().Restore(c.Request.Context(), id.String())
+	if err != nil {
+		handleError(c, "error while restoring drug catalogue", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, drugCatalogue)
+}
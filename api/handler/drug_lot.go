@@ -0,0 +1,274 @@
+package handler
+
+import (
+	"net/http"
+	"shifolink/api/models"
+	"shifolink/config"
+	"shifolink/pkg/errs"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreateDrugLot godoc
+// @Router       /drug_lot [POST]
+// @Summary      Create a drug lot
+// @Description  Receive a lot of a drug into stock, expiry_date is in YYYY-MM-DD format. The lot is held by the branch of the drug and the drug count becomes the sum of its lots
+// @Tags         drug_lot
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        drug_lot body models.CreateDrugLot true "drug lot data"
+// @Success      201  {object}  models.DrugLot
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) CreateDrugLot(c *gin.Context) {
+	createLot := models.CreateDrugLot{}
+
+	if err := c.ShouldBindJSON(&createLot); err != nil {
+		handleBindError(c, err)
+		return
+	}
+
+	if !h.checkDrugBranch(c, createLot.DrugID) {
+		return
+	}
+
+	lot, err := h.services.DrugLot().Create(c.Request.Context(), createLot)
+	if err != nil {
+		handleError(c, "error while creating drug lot", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusCreated, lot)
+}
+
+// GetDrugLotByID godoc
+// @Router       /drug_lot/{id} [GET]
+// @Summary      Get drug lot by id
+// @Description  Get drug lot by id
+// @Tags         drug_lot
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "drug lot id"
+// @Success      200  {object}  models.DrugLot
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetDrugLotByID(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	lot, err := h.services.DrugLot().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id.String(),
+	})
+	if err != nil {
+		handleError(c, "error while get drug lot by id", err)
+		return
+	}
+
+	setETag(c, lot.Version)
+	handleResponse(c, "", http.StatusOK, lot)
+}
+
+// GetDrugLots godoc
+// @Router       /drug/{id}/lots [GET]
+// @Summary      Get lots of a drug
+// @Description  Get lots of a drug, the first to expire comes first
+// @Tags         drug_lot
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "drug id"
+// @Success      200  {object}  models.DrugLotsResponse
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetDrugLots(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	lots, err := h.services.DrugLot().GetByDrug(c.Request.Context(), id.String())
+	if err != nil {
+		handleError(c, "error while getting drug lots", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, lots)
+}
+
+// UpdateDrugLot godoc
+// @Router       /drug_lot/{id} [PUT]
+// @Router       /drug_lot/{id} [PATCH]
+// @Summary      Update drug lot by id
+// @Description  Update drug lot by id, e.g. to correct the quantity after a stock count. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields
+// @Tags         drug_lot
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "drug lot id"
+// @Param        drug_lot body models.UpdateDrugLot true "drug lot"
+// @Param        If-Match header string false "ETag of the record, the update fails with 412 when the record was changed since"
+// @Success      200  {object}  models.DrugLot
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      412  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) UpdateDrugLot(c *gin.Context) {
+	updateLot := models.UpdateDrugLot{}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !h.checkDrugLotBranch(c, id.String()) {
+		return
+	}
+
+	if !bindUpdate(c, &updateLot, func() (interface{}, error) {
+		return h.services.DrugLot().Get(c.Request.Context(), models.PrimaryKey{ID: id.String()})
+	}) {
+		return
+	}
+
+	updateLot.ID = id.String()
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	updateLot.Version = version
+
+	lot, err := h.services.DrugLot().Update(c.Request.Context(), updateLot)
+	if err != nil {
+		handleError(c, "error while updating drug lot", err)
+		return
+	}
+
+	setETag(c, lot.Version)
+	handleResponse(c, "", http.StatusOK, lot)
+}
+
+// DeleteDrugLot godoc
+// @Router       /drug_lot/{id} [DELETE]
+// @Summary      Delete drug lot
+// @Description  Delete drug lot, its quantity is taken from the drug count
+// @Tags         drug_lot
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "drug lot id"
+// @Success      200  {object}  models.Response
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) DeleteDrugLot(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !h.checkDrugLotBranch(c, id.String()) {
+		return
+	}
+
+	if err := h.services.DrugLot().Delete(c.Request.Context(), id.String()); err != nil {
+		handleError(c, "error while deleting drug lot by id", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, "data succesfully deleted")
+}
+
+// GetExpiringDrugLots godoc
+// @Router       /drug_store_branch/{id}/expiring [GET]
+// @Summary      Expiring drugs of a drug store branch
+// @Description  Lots with drugs left which are already expired or expire within the period, the first to expire comes first
+// @Tags         drug_lot
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "drug store branch id"
+// @Param        within query string false "period like 30d, 2w or 30 (days), 30d by default"
+// @Success      200  {object}  models.ExpiringLotsResponse
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetExpiringDrugLots(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if authInfo := getAuthInfo(c); authInfo.UserRole == config.PharmacistRole && authInfo.BranchID != id.String() {
+		handleError(c, "forbidden", errs.Forbidden("pharmacist can see only their own branch"))
+		return
+	}
+
+	lots, err := h.services.DrugLot().GetExpiring(c.Request.Context(), id.String(), c.Query("within"))
+	if err != nil {
+		handleError(c, "error while getting expiring drug lots", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, lots)
+}
+
+// checkDrugBranch makes sure a pharmacist manages stock of an existing drug of their own branch
+func (h Handler) checkDrugBranch(c *gin.Context, drugID string) bool {
+	authInfo := getAuthInfo(c)
+	if authInfo.UserRole != config.PharmacistRole {
+		return true
+	}
+
+	drug, err := h.services.Drug().Get(c.Request.Context(), models.PrimaryKey{
+		ID: drugID,
+	})
+	if err != nil {
+		handleResponse(c, "error while getting drug", http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	if drug.DrugStoreBranchID != authInfo.BranchID {
+		handleError(c, "forbidden", errs.Forbidden("drug belongs to another drug store branch"))
+		return false
+	}
+
+	return true
+}
+
+func (h Handler) checkDrugLotBranch(c *gin.Context, lotID string) bool {
+
+	lot, err := h.services.DrugLot().Get(c.Request.Context(), models.PrimaryKey{
+		ID: lotID,
+	})
+	if err != nil {
+		handleResponse(c, "error while getting drug lot", http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	return h.checkDrugBranch(c, lot.DrugID)
+}
@@ -1,11 +1,9 @@
 package handler
 
 import (
-	"context"
 	"errors"
 	"net/http"
 	"shifolink/api/models"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -16,31 +14,26 @@ import (
 // @Summary      Create a new drug store
 // @Description  Create a new drug store
 // @Tags         drug_store
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        drug_store body  models.CreateDrugStore true  "drug_store data"
 // @Success      201  {object}  models.DrugStore
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) CreateDrugStore(c *gin.Context) {
 	createDrugStore := models.CreateDrugStore{}
 
 	if err := c.ShouldBindJSON(&createDrugStore); err != nil {
-		handleResponse(c, "error while reading body from client", http.StatusBadRequest, err)
-	}
-
-	id, err := h.storage.DrugStore().Create(context.Background(), createDrugStore)
-	if err != nil {
-		handleResponse(c, "error while creating drug store ", http.StatusInternalServerError, err)
+		handleBindError(c, err)
 		return
 	}
 
-	drugStore, err := h.storage.DrugStore().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	drugStore, err := h.services.DrugStore().Create(c.Request.Context(), createDrugStore)
 	if err != nil {
-		handleResponse(c, "error while get drug store ", http.StatusInternalServerError, err)
+		handleError(c, "error while creating drug store ", err)
 		return
 	}
 
@@ -57,7 +50,9 @@ func (h Handler) CreateDrugStore(c *gin.Context) {
 // @Produce      json
 // @Param        id path string true "drug_store"
 // @Success      200  {object}  models.DrugStore
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetDrugStoreByID(c *gin.Context) {
@@ -72,14 +67,15 @@ func (h Handler) GetDrugStoreByID(c *gin.Context) {
 		return
 	}
 
-	drugStore, err := h.storage.DrugStore().Get(context.Background(), models.PrimaryKey{
+	drugStore, err := h.services.DrugStore().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, "error while get drug store by id", http.StatusInternalServerError, err)
+		handleError(c, "error while get drug store by id", err)
 		return
 	}
 
+	setETag(c, drugStore.Version)
 	handleResponse(c, "", http.StatusOK, drugStore)
 
 }
@@ -92,44 +88,35 @@ func (h Handler) GetDrugStoreByID(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
-// @Param        limit query string false "limit"
+// @Param        limit query string false "limit, 10 by default and at most 100"
 // @Param        search query string false "search"
+// @Param        created_from query string false "created at or after the date, YYYY-MM-DD"
+// @Param        created_to query string false "created at or before the date, YYYY-MM-DD"
+// @Param        sort_by query string false "created_at (default), name"
+// @Param        order query string false "asc or desc, desc for the default sorting and asc otherwise"
+// @Param        include_deleted query bool false "list soft deleted records too, super admin only"
 // @Success      200  {object}  models.DrugStoresResponse
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetDrugStoresList(c *gin.Context) {
 
-	var (
-		page, limit int
-		search      string
-		err         error
-	)
-
-	pageStr := c.DefaultQuery("page", "1")
-	page, err = strconv.Atoi(pageStr)
+	request, err := getListRequest(c)
 	if err != nil {
-		handleResponse(c, "error while parsing page ", http.StatusBadRequest, err.Error())
+		handleResponse(c, "error while parsing list request", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err = strconv.Atoi(limitStr)
-	if err != nil {
-		handleResponse(c, "error while parsing limit", http.StatusBadRequest, err.Error())
+	if !readIncludeDeleted(c, &request) {
 		return
 	}
 
-	search = c.Query("search")
-
-	response, err := h.storage.DrugStore().GetList(context.Background(), models.GetListRequest{
-		Page:   page,
-		Limit:  limit,
-		Search: search,
-	})
+	response, err := h.services.DrugStore().GetList(c.Request.Context(), request)
 
 	if err != nil {
-		handleResponse(c, "error while getting drug store ", http.StatusInternalServerError, err)
+		handleError(c, "error while getting drug store ", err)
 		return
 	}
 
@@ -139,16 +126,22 @@ func (h Handler) GetDrugStoresList(c *gin.Context) {
 
 // UpdateDrugStore godoc
 // @Router       /drug_store/{id} [PUT]
+// @Router       /drug_store/{id} [PATCH]
 // @Summary      Update drug store by id
-// @Description  Update drug store by id
+// @Description  Update drug store by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields
 // @Tags         drug_store
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "drug_store id"
 // @Param        drug_store body models.UpdateDrugStore true "drug_store"
+// @Param        If-Match header string false "ETag of the record, the update fails with 412 when the record was changed since"
 // @Success      200  {object}  models.DrugStore
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      412  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdateDrugStore(c *gin.Context) {
 	updateDrugStore := models.UpdateDrugStore{}
@@ -159,27 +152,28 @@ func (h Handler) UpdateDrugStore(c *gin.Context) {
 		return
 	}
 
-	//updateAuthor.ID = uid
-
-	if err := c.ShouldBindJSON(&updateDrugStore); err != nil {
-		handleResponse(c, "error while reading body", http.StatusBadRequest, err.Error())
+	if !bindUpdate(c, &updateDrugStore, func() (interface{}, error) {
+		return h.services.DrugStore().Get(c.Request.Context(), models.PrimaryKey{ID: uid})
+	}) {
 		return
 	}
 
-	id, err := h.storage.DrugStore().Update(context.Background(), updateDrugStore)
-	if err != nil {
-		handleResponse(c, "error while updating drug store ", http.StatusInternalServerError, err.Error())
+	updateDrugStore.ID = uid
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
-	drugStore, err := h.storage.DrugStore().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	updateDrugStore.Version = version
+
+	drugStore, err := h.services.DrugStore().Update(c.Request.Context(), updateDrugStore)
 	if err != nil {
-		handleResponse(c, "error while getting drug store by id", http.StatusInternalServerError, err)
+		handleError(c, "error while updating drug store ", err)
 		return
 	}
 
+	setETag(c, drugStore.Version)
 	handleResponse(c, "", http.StatusOK, drugStore)
 
 }
@@ -189,11 +183,13 @@ func (h Handler) UpdateDrugStore(c *gin.Context) {
 // @Summary      Delete drug store
 // @Description  Delete drug store
 // @Tags         drug_store
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "drug_store id"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) DeleteDrugStore(c *gin.Context) {
@@ -205,11 +201,43 @@ func (h Handler) DeleteDrugStore(c *gin.Context) {
 		return
 	}
 
-	if err := h.storage.DrugStore().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, "error while deleting drug store  by id", http.StatusInternalServerError, err.Error())
+	if err := h.services.DrugStore().Delete(c.Request.Context(), id.String()); err != nil {
+		handleError(c, "error while deleting drug store  by id", err)
 		return
 	}
 
 	handleResponse(c, "", http.StatusOK, "data succesfully deleted")
 
 }
+
+// RestoreDrugStore godoc
+// @Router       /drug_store/{id}/restore [POST]
+// @Summary      Restore drug store
+// @Description  Restore a soft deleted drug store
+// @Tags         drug_store
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "drug store id"
+// @Success      200  {object}  models.DrugStore
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) RestoreDrugStore(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	drugStore, err := h.services.DrugStore().Restore(c.Request.Context(), id.String())
+	if err != nil {
+		handleError(c, "error while restoring drug store", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, drugStore)
+}
@@ -1,11 +1,9 @@
 package handler
 
 import (
-	"context"
 	"errors"
 	"net/http"
 	"shifolink/api/models"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -16,31 +14,26 @@ import (
 // @Summary      Create a new drug store branch
 // @Description  Create a new drug store branch
 // @Tags         drug_store_branch
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        drug_store_branch body  models.CreateDrugStoreBranch true  "drug_store_branch data"
 // @Success      201  {object}  models.DrugStoreBranch
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) CreateDrugStoreBranch(c *gin.Context) {
 	createDrugStoreBranch := models.CreateDrugStoreBranch{}
 
 	if err := c.ShouldBindJSON(&createDrugStoreBranch); err != nil {
-		handleResponse(c, "error while reading body from client", http.StatusBadRequest, err)
-	}
-
-	id, err := h.storage.DrugStoreBranch().Create(context.Background(), createDrugStoreBranch)
-	if err != nil {
-		handleResponse(c, "error while creating drug store branch ", http.StatusInternalServerError, err)
+		handleBindError(c, err)
 		return
 	}
 
-	drugStoreBranch, err := h.storage.DrugStoreBranch().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	drugStoreBranch, err := h.services.DrugStoreBranch().Create(c.Request.Context(), createDrugStoreBranch)
 	if err != nil {
-		handleResponse(c, "error while get drug store branch ", http.StatusInternalServerError, err)
+		handleError(c, "error while creating drug store branch ", err)
 		return
 	}
 
@@ -57,7 +50,9 @@ func (h Handler) CreateDrugStoreBranch(c *gin.Context) {
 // @Produce      json
 // @Param        id path string true "drug_store_branch"
 // @Success      200  {object}  models.DrugStoreBranch
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetDrugStoreBranchByID(c *gin.Context) {
@@ -72,14 +67,15 @@ func (h Handler) GetDrugStoreBranchByID(c *gin.Context) {
 		return
 	}
 
-	drugStoreBranch, err := h.storage.DrugStoreBranch().Get(context.Background(), models.PrimaryKey{
+	drugStoreBranch, err := h.services.DrugStoreBranch().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, "error while get drug store branch by id", http.StatusInternalServerError, err)
+		handleError(c, "error while get drug store branch by id", err)
 		return
 	}
 
+	setETag(c, drugStoreBranch.Version)
 	handleResponse(c, "", http.StatusOK, drugStoreBranch)
 
 }
@@ -92,44 +88,45 @@ func (h Handler) GetDrugStoreBranchByID(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
-// @Param        limit query string false "limit"
+// @Param        limit query string false "limit, 10 by default and at most 100"
 // @Param        search query string false "search"
+// @Param        created_from query string false "created at or after the date, YYYY-MM-DD"
+// @Param        created_to query string false "created at or before the date, YYYY-MM-DD"
+// @Param        sort_by query string false "created_at (default), address"
+// @Param        order query string false "asc or desc, desc for the default sorting and asc otherwise"
+// @Param        drug_store_id query string false "drug store id"
+// @Param        include_deleted query bool false "list soft deleted records too, super admin only"
 // @Success      200  {object}  models.DrugStoreBranchsResponse
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetDrugStoreBranchsList(c *gin.Context) {
 
-	var (
-		page, limit int
-		search      string
-		err         error
-	)
-
-	pageStr := c.DefaultQuery("page", "1")
-	page, err = strconv.Atoi(pageStr)
+	request, err := getListRequest(c)
 	if err != nil {
-		handleResponse(c, "error while parsing page ", http.StatusBadRequest, err.Error())
+		handleResponse(c, "error while parsing list request", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err = strconv.Atoi(limitStr)
-	if err != nil {
-		handleResponse(c, "error while parsing limit", http.StatusBadRequest, err.Error())
+	if !readIncludeDeleted(c, &request) {
 		return
 	}
 
-	search = c.Query("search")
+	ids, err := getUUIDQueries(c, "drug_store_id")
+	if err != nil {
+		handleResponse(c, "error while parsing list filters", http.StatusBadRequest, err.Error())
+		return
+	}
 
-	response, err := h.storage.DrugStoreBranch().GetList(context.Background(), models.GetListRequest{
-		Page:   page,
-		Limit:  limit,
-		Search: search,
+	response, err := h.services.DrugStoreBranch().GetList(c.Request.Context(), models.GetDrugStoreBranchsListRequest{
+		GetListRequest: request,
+		DrugStoreID:    ids["drug_store_id"],
 	})
 
 	if err != nil {
-		handleResponse(c, "error while getting drug store branch ", http.StatusInternalServerError, err)
+		handleError(c, "error while getting drug store branch ", err)
 		return
 	}
 
@@ -139,16 +136,22 @@ func (h Handler) GetDrugStoreBranchsList(c *gin.Context) {
 
 // UpdateDrugStoreBranch godoc
 // @Router       /drug_store_branch/{id} [PUT]
+// @Router       /drug_store_branch/{id} [PATCH]
 // @Summary      Update drug store branch by id
-// @Description  Update drug store branch by id
+// @Description  Update drug store branch by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields
 // @Tags         drug_store_branch
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "drug_store_branch id"
 // @Param        drug_store_branch body models.UpdateDrugStoreBranch true "drug_store_branch"
+// @Param        If-Match header string false "ETag of the record, the update fails with 412 when the record was changed since"
 // @Success      200  {object}  models.DrugStoreBranch
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      412  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdateDrugStoreBranch(c *gin.Context) {
 	updateDrugStoreBranch := models.UpdateDrugStoreBranch{}
@@ -159,27 +162,28 @@ func (h Handler) UpdateDrugStoreBranch(c *gin.Context) {
 		return
 	}
 
-	//updateAuthor.ID = uid
-
-	if err := c.ShouldBindJSON(&updateDrugStoreBranch); err != nil {
-		handleResponse(c, "error while reading body", http.StatusBadRequest, err.Error())
+	if !bindUpdate(c, &updateDrugStoreBranch, func() (interface{}, error) {
+		return h.services.DrugStoreBranch().Get(c.Request.Context(), models.PrimaryKey{ID: uid})
+	}) {
 		return
 	}
 
-	id, err := h.storage.DrugStoreBranch().Update(context.Background(), updateDrugStoreBranch)
-	if err != nil {
-		handleResponse(c, "error while updating drug store branch ", http.StatusInternalServerError, err.Error())
+	updateDrugStoreBranch.ID = uid
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
-	drugStoreBranch, err := h.storage.DrugStoreBranch().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	updateDrugStoreBranch.Version = version
+
+	drugStoreBranch, err := h.services.DrugStoreBranch().Update(c.Request.Context(), updateDrugStoreBranch)
 	if err != nil {
-		handleResponse(c, "error while getting drug store branch by id", http.StatusInternalServerError, err)
+		handleError(c, "error while updating drug store branch ", err)
 		return
 	}
 
+	setETag(c, drugStoreBranch.Version)
 	handleResponse(c, "", http.StatusOK, drugStoreBranch)
 
 }
@@ -189,11 +193,13 @@ func (h Handler) UpdateDrugStoreBranch(c *gin.Context) {
 // @Summary      Delete drug store branch
 // @Description  Delete drug store branch
 // @Tags         drug_store_branch
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "drug_store_branch id"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) DeleteDrugStoreBranch(c *gin.Context) {
@@ -205,11 +211,43 @@ func (h Handler) DeleteDrugStoreBranch(c *gin.Context) {
 		return
 	}
 
-	if err := h.storage.DrugStoreBranch().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, "error while deleting drug store branch by id", http.StatusInternalServerError, err.Error())
+	if err := h.services.DrugStoreBranch().Delete(c.Request.Context(), id.String()); err != nil {
+		handleError(c, "error while deleting drug store branch by id", err)
 		return
 	}
 
 	handleResponse(c, "", http.StatusOK, "data succesfully deleted")
 
 }
+
+// RestoreDrugStoreBranch godoc
+// @Router       /drug_store_branch/{id}/restore [POST]
+// @Summary      Restore drug store branch
+// @Description  Restore a soft deleted drug store branch
+// @Tags         drug_store_branch
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "drug store branch id"
+// @Success      200  {object}  models.DrugStoreBranch
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) RestoreDrugStoreBranch(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	drugStoreBranch, err := h.services.DrugStoreBranch().Restore(c.Request.Context(), id.String())
+	if err != nil {
+		handleError(c, "error while restoring drug store branch", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, drugStoreBranch)
+}
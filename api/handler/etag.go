@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"shifolink/pkg/errs"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setETag sends the version of the record as its ETag, an update sent with it in If-Match fails with 412
+// once somebody else has changed the record
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// readVersionKey keeps the version of the record a PATCH was merged into in the gin context
+const readVersionKey = "read_version"
+
+// ifMatchVersion reads the version the client has read from If-Match. Without it a PATCH is bound to the
+// version bindUpdate merged it into and other updates get 0, any version may be updated.
+// A value which is not an ETag of a record can never match, the client is answered with 412
+func ifMatchVersion(c *gin.Context) (int, bool) {
+
+	value := strings.TrimSpace(c.GetHeader("If-Match"))
+	if value == "" || value == "*" {
+		return c.GetInt(readVersionKey), true
+	}
+
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(value, "W/"), `"`))
+	if err != nil || version < 1 {
+		handleError(c, "precondition failed", errs.PreconditionFailed("If-Match should be the ETag of the record"))
+		return 0, false
+	}
+
+	return version, true
+}
@@ -1,22 +1,27 @@
 package handler
 
 import (
+	"net/http"
 	"shifolink/api/models"
+	"shifolink/pkg/errs"
 	"shifolink/service"
-	"shifolink/storage"
+	"sync/atomic"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Handler struct {
-	storage storage.IStorage
 	services service.IServiceManager
+	// ready is false while the server drains requests before it stops
+	ready *atomic.Bool
 }
 
-func New(store storage.IStorage, services service.IServiceManager) Handler {
+func New(services service.IServiceManager, ready *atomic.Bool) Handler {
+	registerValidators()
+
 	return Handler{
-		storage: store,
 		services: services,
+		ready:    ready,
 	}
 }
 
@@ -26,16 +31,82 @@ func handleResponse(c *gin.Context, msg string, statusCode int, data interface{}
 	switch code := statusCode; {
 	case code < 400:
 		response.Description = "succes"
-	case code < 500:
-		response.Description = "bad request"
+	case msg != "":
+		response.Description = msg
 	default:
-		response.Description = "internal server error"
-
+		response.Description = http.StatusText(code)
 	}
 
 	response.StatusCode = statusCode
 	response.Data = data
 
+	if statusCode >= 400 {
+		response.Data = errorResponse(statusCode, msg, data)
+	}
+
 	c.JSON(response.StatusCode, response)
 
 }
+
+// handleError answers with the status of the domain error found in err, errors of postgres are
+// translated first. Anything else is a 500
+func handleError(c *gin.Context, msg string, err error) {
+	err = errs.FromDB(err)
+
+	domainErr, ok := errs.As(err)
+	if !ok {
+		handleResponse(c, msg, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	handleResponse(c, msg, kindStatuses[domainErr.Kind], models.ErrorResponse{
+		Code:    string(domainErr.Kind),
+		Message: err.Error(),
+		Fields:  domainErr.Fields,
+	})
+}
+
+var kindStatuses = map[errs.Kind]int{
+	errs.KindNotFound:           http.StatusNotFound,
+	errs.KindConflict:           http.StatusConflict,
+	errs.KindValidation:         http.StatusUnprocessableEntity,
+	errs.KindForbidden:          http.StatusForbidden,
+	errs.KindPreconditionFailed: http.StatusPreconditionFailed,
+}
+
+var statusCodes = map[int]string{
+	http.StatusBadRequest:          "bad_request",
+	http.StatusUnauthorized:        "unauthorized",
+	http.StatusForbidden:           string(errs.KindForbidden),
+	http.StatusNotFound:            string(errs.KindNotFound),
+	http.StatusConflict:            string(errs.KindConflict),
+	http.StatusUnprocessableEntity: string(errs.KindValidation),
+	http.StatusPreconditionFailed:  string(errs.KindPreconditionFailed),
+	http.StatusServiceUnavailable:  "unavailable",
+}
+
+// errorResponse gives every failure the same shape, texts and errors become the message and
+// other data, like rejected checkout lines, is kept in details
+func errorResponse(statusCode int, msg string, data interface{}) models.ErrorResponse {
+	response, ok := data.(models.ErrorResponse)
+	if ok {
+		return response
+	}
+
+	response.Code = statusCodes[statusCode]
+	if response.Code == "" {
+		response.Code = "internal"
+	}
+
+	switch value := data.(type) {
+	case string:
+		response.Message = value
+	case error:
+		response.Message = value.Error()
+	default:
+		response.Message = msg
+		response.Details = value
+	}
+
+	return response
+}
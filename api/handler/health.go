@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"shifolink/service"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// readinessTimeout bounds the checks of the readiness probe, a slow database answers as not ready
+const readinessTimeout = 2 * time.Second
+
+var metricsHandler = promhttp.Handler()
+
+// Liveness godoc
+// @Router       /healthz [GET]
+// @Summary      Liveness
+// @Description  200 while the process is up, nothing else is checked
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  models.Response
+func (h Handler) Liveness(c *gin.Context) {
+	handleResponse(c, "", http.StatusOK, "ok")
+}
+
+// Readiness godoc
+// @Router       /readyz [GET]
+// @Summary      Readiness
+// @Description  200 while the server takes requests, the database answers and its schema is migrated.
+// @Description  503 when a check fails or once the server drains requests before it stops
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  models.Response{data=models.Readiness}
+// @Failure      503  {object}  models.Response{data=models.Readiness}
+func (h Handler) Readiness(c *gin.Context) {
+
+	if !h.ready.Load() {
+		handleResponse(c, "not ready", http.StatusServiceUnavailable, "server is shutting down")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	readiness, err := h.services.Health().Ready(ctx)
+	if errors.Is(err, service.ErrNotReady) {
+		handleResponse(c, "not ready", http.StatusServiceUnavailable, readiness)
+		return
+	}
+	if err != nil {
+		handleError(c, "error while checking readiness", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, readiness)
+}
+
+// Metrics godoc
+// @Router       /metrics [GET]
+// @Summary      Metrics
+// @Description  Prometheus metrics: requests by route and status, database pool stats and bookings, orders and
+// @Description  prescriptions made
+// @Tags         health
+// @Produce      plain
+// @Success      200  {string}  string
+func (h Handler) Metrics(c *gin.Context) {
+	metricsHandler.ServeHTTP(c.Writer, c.Request)
+}
@@ -1,11 +1,14 @@
 package handler
 
 import (
-	"context"
-	"errors"
 	"net/http"
 	"shifolink/api/models"
+	"shifolink/config"
+	"shifolink/pkg/errs"
+	"shifolink/pkg/slug"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -14,33 +17,45 @@ import (
 // CreateJournal godoc
 // @Router       /journal [POST]
 // @Summary      Create a new journal
-// @Description  Create a new journal
+// @Description  Create a new journal as a draft, it is public only after a super admin approves it. An author writes for themselves, a super admin gives author_id. Without slug one is made from the theme. Tags are stored as lowercase words joined by "-", at most 10
 // @Tags         journal
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        journal body  models.CreateJournal true  "journal data"
 // @Success      201  {object}  models.Journal
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) CreateJournal(c *gin.Context) {
 	createJournal := models.CreateJournal{}
 
 	if err := c.ShouldBindJSON(&createJournal); err != nil {
-		handleResponse(c, "error while reading body from client", http.StatusBadRequest, err)
+		handleBindError(c, err)
+		return
 	}
 
-	id, err := h.storage.Journal().Create(context.Background(), createJournal)
-	if err != nil {
-		handleResponse(c, "error while creating journal ", http.StatusInternalServerError, err)
+	authInfo := getAuthInfo(c)
+	if authInfo.UserRole == config.AuthorRole {
+		createJournal.AuthorID = authInfo.UserID
+	}
+
+	if _, err := uuid.Parse(createJournal.AuthorID); err != nil {
+		handleResponse(c, "author_id is not valid", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	journal, err := h.storage.Journal().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	if !checkJournalLinkIDs(c, createJournal.CategoryID, createJournal.DoctorTypeIDs) {
+		return
+	}
+
+	createJournal.EditorID = authInfo.UserID
+
+	journal, err := h.services.Journal().Create(c.Request.Context(), createJournal)
 	if err != nil {
-		handleResponse(c, "error while get journal ", http.StatusInternalServerError, err)
+		handleError(c, "error while creating journal ", err)
 		return
 	}
 
@@ -51,85 +66,141 @@ func (h Handler) CreateJournal(c *gin.Context) {
 // GetJournalByID godoc
 // @Router       /journal/{id} [GET]
 // @Summary      Get journal by id
-// @Description  Get journal by id
+// @Description  Get a published journal by id. Its author and super admins also get drafts, journals in review, scheduled and archived ones
 // @Tags         journal
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "journal"
 // @Success      200  {object}  models.Journal
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetJournalByID(c *gin.Context) {
 
-	var err error
+	journal, ok := h.getJournal(c)
+	if !ok {
+		return
+	}
+
+	if !canSeeJournal(c, journal) {
+		handleResponse(c, "journal not found", http.StatusNotFound, "journal is not published")
+		return
+	}
+
+	setETag(c, journal.Version)
+	handleResponse(c, "", http.StatusOK, journal)
+
+}
 
-	uid := c.Param("id")
+// GetJournalBySlug godoc
+// @Router       /journal/slug/{slug} [GET]
+// @Summary      Get journal by slug
+// @Description  Get a published journal by the slug of its public url, visibility is the same as for /journal/{id}
+// @Tags         journal
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        slug path string true "journal slug"
+// @Success      200  {object}  models.Journal
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetJournalBySlug(c *gin.Context) {
 
-	id, err := uuid.Parse(uid)
+	journal, err := h.services.Journal().GetBySlug(c.Request.Context(), c.Param("slug"))
 	if err != nil {
-		handleResponse(c, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		handleError(c, "error while get journal by slug", err)
 		return
 	}
 
-	journal, err := h.storage.Journal().Get(context.Background(), models.PrimaryKey{
-		ID: id.String(),
-	})
-	if err != nil {
-		handleResponse(c, "error while get journal by id", http.StatusInternalServerError, err)
+	if !canSeeJournal(c, journal) {
+		handleResponse(c, "journal not found", http.StatusNotFound, "journal is not published")
 		return
 	}
 
+	setETag(c, journal.Version)
 	handleResponse(c, "", http.StatusOK, journal)
-
 }
 
 // GetJournalsList godoc
 // @Router       /journal [GET]
 // @Summary      Get journals list
-// @Description  Get journals list
+// @Description  Get published journals list. Authors also get their own journals of every status, super admins get all journals
 // @Tags         journal
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
-// @Param        limit query string false "limit"
-// @Param        search query string false "search"
+// @Param        limit query string false "limit, 10 by default and at most 100"
+// @Param        search query string false "words of the theme or the article, see /journal/search"
+// @Param        created_from query string false "created at or after the date, YYYY-MM-DD"
+// @Param        created_to query string false "created at or before the date, YYYY-MM-DD"
+// @Param        sort_by query string false "created_at (default), theme"
+// @Param        order query string false "asc or desc, desc for the default sorting and asc otherwise"
+// @Param        author_id query string false "author id"
+// @Param        status query string false "draft, in_review, published or archived"
+// @Param        category_id query string false "category id"
+// @Param        tag query string false "tag"
+// @Param        doctor_type_id query string false "doctor type (specialty) id"
+// @Param        include_deleted query bool false "list soft deleted records too, super admin only"
 // @Success      200  {object}  models.JournalsResponse
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetJournalsList(c *gin.Context) {
 
-	var (
-		page, limit int
-		search      string
-		err         error
-	)
-
-	pageStr := c.DefaultQuery("page", "1")
-	page, err = strconv.Atoi(pageStr)
+	request, err := getListRequest(c)
 	if err != nil {
-		handleResponse(c, "error while parsing page ", http.StatusBadRequest, err.Error())
+		handleResponse(c, "error while parsing list request", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err = strconv.Atoi(limitStr)
+	if !readIncludeDeleted(c, &request) {
+		return
+	}
+
+	ids, err := getUUIDQueries(c, "author_id", "category_id", "doctor_type_id")
 	if err != nil {
-		handleResponse(c, "error while parsing limit", http.StatusBadRequest, err.Error())
+		handleResponse(c, "error while parsing list filters", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	search = c.Query("search")
+	status := c.Query("status")
+	if status != "" && !isJournalStatus(status) {
+		handleResponse(c, "unknown journal status", http.StatusBadRequest, status)
+		return
+	}
 
-	response, err := h.storage.Journal().GetList(context.Background(), models.GetListRequest{
-		Page:   page,
-		Limit:  limit,
-		Search: search,
-	})
+	listRequest := models.GetJournalsListRequest{
+		GetListRequest: request,
+		AuthorID:       ids["author_id"],
+		Status:         status,
+		CategoryID:     ids["category_id"],
+		DoctorTypeID:   ids["doctor_type_id"],
+	}
+
+	if tag := c.Query("tag"); tag != "" {
+		if listRequest.Tag = slug.Make(tag); listRequest.Tag == "" {
+			handleResponse(c, "tag is not valid", http.StatusBadRequest, tag)
+			return
+		}
+	}
 
+	switch authInfo := getAuthInfo(c); authInfo.UserRole {
+	case config.SuperAdminRole:
+	case config.AuthorRole:
+		listRequest.PublishedOnly, listRequest.OwnerID = true, authInfo.UserID
+	default:
+		listRequest.PublishedOnly = true
+	}
+
+	response, err := h.services.Journal().GetList(c.Request.Context(), listRequest)
 	if err != nil {
-		handleResponse(c, "error while getting journal ", http.StatusInternalServerError, err)
+		handleError(c, "error while getting journal ", err)
 		return
 	}
 
@@ -137,49 +208,119 @@ func (h Handler) GetJournalsList(c *gin.Context) {
 
 }
 
+// SearchJournals godoc
+// @Router       /journal/search [GET]
+// @Summary      Search journals
+// @Description  Full-text search over themes and articles of published journals, best matches first. q accepts "quoted phrases", or and -excluded words. Without language articles of every language are searched. Matched words of the theme and the snippet are wrapped in <mark></mark>, the text around them is HTML escaped
+// @Tags         journal
+// @Accept       json
+// @Produce      json
+// @Param        q query string true "search text"
+// @Param        language query string false "uz, ru or en"
+// @Param        page query string false "page"
+// @Param        limit query string false "limit, 10 by default and at most 100"
+// @Success      200  {object}  models.JournalSearchResponse
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) SearchJournals(c *gin.Context) {
+
+	listRequest, err := getListRequest(c)
+	if err != nil {
+		handleResponse(c, "error while parsing list request", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	request := models.SearchJournalsRequest{
+		Query:    strings.TrimSpace(c.Query("q")),
+		Language: c.Query("language"),
+		Page:     listRequest.Page,
+		Limit:    listRequest.Limit,
+	}
+
+	if request.Query == "" {
+		handleResponse(c, "search text is required", http.StatusBadRequest, "q should not be empty")
+		return
+	}
+
+	if request.Language != "" && !isJournalLanguage(request.Language) {
+		handleResponse(c, "unknown journal language", http.StatusBadRequest, request.Language)
+		return
+	}
+
+	response, err := h.services.Journal().Search(c.Request.Context(), request)
+	if err != nil {
+		handleError(c, "error while searching journals", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, response)
+}
+
 // UpdateJournal godoc
 // @Router       /journal/{id} [PUT]
+// @Router       /journal/{id} [PATCH]
 // @Summary      Update journal by id
-// @Description  Update journal by id
+// @Description  Save the edited draft as its next version, a journal in another status has to be withdrawn first. Only super admins can give another author_id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields
 // @Tags         journal
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "journal id"
 // @Param        journal body models.UpdateJournal true "journal"
+// @Param        If-Match header string false "ETag of the record, the update fails with 412 when the record was changed since"
 // @Success      200  {object}  models.Journal
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      403  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      412  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdateJournal(c *gin.Context) {
 	updateJournal := models.UpdateJournal{}
 
-	uid := c.Param("id")
-	if uid == "" {
-		handleResponse(c, "invalid uuid", http.StatusBadRequest, errors.New("uuid is not valid"))
+	journal, ok := h.getJournal(c)
+	if !ok || !checkJournalAccess(c, journal) {
 		return
 	}
 
-	//updateAuthor.ID = uid
-
-	if err := c.ShouldBindJSON(&updateJournal); err != nil {
-		handleResponse(c, "error while reading body", http.StatusBadRequest, err.Error())
+	if !bindUpdate(c, &updateJournal, func() (interface{}, error) {
+		return journal, nil
+	}) {
 		return
 	}
 
-	id, err := h.storage.Journal().Update(context.Background(), updateJournal)
-	if err != nil {
-		handleResponse(c, "error while updating journal ", http.StatusInternalServerError, err.Error())
+	authInfo := getAuthInfo(c)
+	if authInfo.UserRole == config.AuthorRole {
+		updateJournal.AuthorID = ""
+	}
+
+	if updateJournal.AuthorID != "" {
+		if _, err := uuid.Parse(updateJournal.AuthorID); err != nil {
+			handleResponse(c, "author_id is not valid", http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	updateJournal.ID = journal.ID
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
-	journal, err := h.storage.Journal().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	updateJournal.Version = version
+	updateJournal.EditorID = authInfo.UserID
+
+	journal, err := h.services.Journal().Update(c.Request.Context(), updateJournal)
 	if err != nil {
-		handleResponse(c, "error while getting journal by id", http.StatusInternalServerError, err)
+		handleError(c, "error while updating journal ", err)
 		return
 	}
 
+	setETag(c, journal.Version)
 	handleResponse(c, "", http.StatusOK, journal)
 
 }
@@ -189,27 +330,586 @@ func (h Handler) UpdateJournal(c *gin.Context) {
 // @Summary      Delete journal
 // @Description  Delete journal
 // @Tags         journal
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "journal id"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      403  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) DeleteJournal(c *gin.Context) {
 
-	uid := c.Param("id")
-	id, err := uuid.Parse(uid)
-	if err != nil {
-		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
+	journal, ok := h.getJournal(c)
+	if !ok || !checkJournalAccess(c, journal) {
 		return
 	}
 
-	if err := h.storage.Journal().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, "error while deleting journal  by id", http.StatusInternalServerError, err.Error())
+	if err := h.services.Journal().Delete(c.Request.Context(), journal.ID); err != nil {
+		handleError(c, "error while deleting journal  by id", err)
 		return
 	}
 
 	handleResponse(c, "", http.StatusOK, "data succesfully deleted")
 
 }
+
+// SubmitJournal godoc
+// @Router       /journal/{id}/submit [POST]
+// @Summary      Submit the journal for review
+// @Description  Author sends the draft to super admins, draft -> in_review
+// @Tags         journal
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "journal id"
+// @Success      200  {object}  models.Journal
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) SubmitJournal(c *gin.Context) {
+
+	journal, ok := h.getJournal(c)
+	if !ok || !checkJournalAccess(c, journal) {
+		return
+	}
+
+	journal, err := h.services.Journal().Submit(c.Request.Context(), journal.ID)
+	if err != nil {
+		handleError(c, "error while submitting journal", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, journal)
+}
+
+// ApproveJournal godoc
+// @Router       /journal/{id}/approve [POST]
+// @Summary      Approve the journal
+// @Description  Super admin publishes the reviewed journal, in_review -> published. publish_at (RFC 3339, e.g. 2024-05-01T09:00:00+05:00) schedules the release, without it or with a past time the journal is public right away
+// @Tags         journal
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "journal id"
+// @Param        approve body models.ApproveJournal false "publication time"
+// @Success      200  {object}  models.Journal
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) ApproveJournal(c *gin.Context) {
+	approve := models.ApproveJournal{}
+
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&approve); err != nil {
+			handleBindError(c, err)
+			return
+		}
+	}
+
+	publishAt := time.Time{}
+	if approve.PublishAt != "" {
+		var err error
+		if publishAt, err = time.Parse(time.RFC3339, approve.PublishAt); err != nil {
+			handleResponse(c, "publish_at should be in RFC 3339 format", http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	journal, ok := h.getJournal(c)
+	if !ok {
+		return
+	}
+
+	journal, err := h.services.Journal().Approve(c.Request.Context(), journal.ID, getAuthInfo(c).UserID, publishAt)
+	if err != nil {
+		handleError(c, "error while approving journal", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, journal)
+}
+
+// RejectJournal godoc
+// @Router       /journal/{id}/reject [POST]
+// @Summary      Reject the journal
+// @Description  Super admin returns the reviewed journal to its author with a note, in_review -> draft
+// @Tags         journal
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "journal id"
+// @Param        reject body models.RejectJournal true "why the journal is rejected"
+// @Success      200  {object}  models.Journal
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) RejectJournal(c *gin.Context) {
+	reject := models.RejectJournal{}
+
+	if err := c.ShouldBindJSON(&reject); err != nil {
+		handleBindError(c, err)
+		return
+	}
+
+	journal, ok := h.getJournal(c)
+	if !ok {
+		return
+	}
+
+	journal, err := h.services.Journal().Reject(c.Request.Context(), journal.ID, getAuthInfo(c).UserID, reject.Note)
+	if err != nil {
+		handleError(c, "error while rejecting journal", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, journal)
+}
+
+// ArchiveJournal godoc
+// @Router       /journal/{id}/archive [POST]
+// @Summary      Archive the journal
+// @Description  Take the published journal down, published -> archived
+// @Tags         journal
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "journal id"
+// @Success      200  {object}  models.Journal
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) ArchiveJournal(c *gin.Context) {
+
+	journal, ok := h.getJournal(c)
+	if !ok || !checkJournalAccess(c, journal) {
+		return
+	}
+
+	journal, err := h.services.Journal().Archive(c.Request.Context(), journal.ID)
+	if err != nil {
+		handleError(c, "error while archiving journal", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, journal)
+}
+
+// WithdrawJournal godoc
+// @Router       /journal/{id}/withdraw [POST]
+// @Summary      Withdraw the journal
+// @Description  Turn the journal back into a draft to edit it, in_review, published or archived -> draft. It has to be reviewed again to be public
+// @Tags         journal
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "journal id"
+// @Success      200  {object}  models.Journal
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) WithdrawJournal(c *gin.Context) {
+
+	journal, ok := h.getJournal(c)
+	if !ok || !checkJournalAccess(c, journal) {
+		return
+	}
+
+	journal, err := h.services.Journal().Withdraw(c.Request.Context(), journal.ID)
+	if err != nil {
+		handleError(c, "error while withdrawing journal", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, journal)
+}
+
+// GetJournalRevisions godoc
+// @Router       /journal/{id}/revisions [GET]
+// @Summary      Get revisions of the journal
+// @Description  Every saved version of the journal, newest first
+// @Tags         journal
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "journal id"
+// @Success      200  {object}  models.JournalRevisionsResponse
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetJournalRevisions(c *gin.Context) {
+
+	journal, ok := h.getJournal(c)
+	if !ok || !checkJournalAccess(c, journal) {
+		return
+	}
+
+	revisions, err := h.services.Journal().GetRevisions(c.Request.Context(), journal.ID)
+	if err != nil {
+		handleError(c, "error while getting journal revisions", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, revisions)
+}
+
+// GetJournalRevision godoc
+// @Router       /journal/{id}/revisions/{version} [GET]
+// @Summary      Get a revision of the journal
+// @Description  Get the text of the journal as it was saved in the version
+// @Tags         journal
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "journal id"
+// @Param        version path int true "version"
+// @Success      200  {object}  models.JournalRevision
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetJournalRevision(c *gin.Context) {
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		handleResponse(c, "version should be a positive number", http.StatusBadRequest, c.Param("version"))
+		return
+	}
+
+	journal, ok := h.getJournal(c)
+	if !ok || !checkJournalAccess(c, journal) {
+		return
+	}
+
+	revision, err := h.services.Journal().GetRevision(c.Request.Context(), journal.ID, version)
+	if err != nil {
+		handleError(c, "error while getting journal revision", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, revision)
+}
+
+// GetJournalDiff godoc
+// @Router       /journal/{id}/diff [GET]
+// @Summary      Compare versions of the journal
+// @Description  Line by line changes of the theme and the article from one version to another. Without to the current version is used, without from the version before to
+// @Tags         journal
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "journal id"
+// @Param        from query int false "older version"
+// @Param        to query int false "newer version"
+// @Success      200  {object}  models.JournalDiff
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetJournalDiff(c *gin.Context) {
+
+	versions := map[string]int{}
+	for _, key := range []string{"from", "to"} {
+		value := c.Query(key)
+		if value == "" {
+			continue
+		}
+
+		version, err := strconv.Atoi(value)
+		if err != nil || version < 1 {
+			handleResponse(c, key+" should be a positive number", http.StatusBadRequest, value)
+			return
+		}
+		versions[key] = version
+	}
+
+	journal, ok := h.getJournal(c)
+	if !ok || !checkJournalAccess(c, journal) {
+		return
+	}
+
+	diff, err := h.services.Journal().Diff(c.Request.Context(), journal, versions["from"], versions["to"])
+	if err != nil {
+		handleError(c, "error while comparing journal versions", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, diff)
+}
+
+// RollbackJournal godoc
+// @Router       /journal/{id}/rollback/{version} [POST]
+// @Summary      Roll the journal back to a version
+// @Description  Save the text of an older version as the next version of the draft, newer versions stay in the history
+// @Tags         journal
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "journal id"
+// @Param        version path int true "version to roll back to"
+// @Success      200  {object}  models.Journal
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) RollbackJournal(c *gin.Context) {
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		handleResponse(c, "version should be a positive number", http.StatusBadRequest, c.Param("version"))
+		return
+	}
+
+	journal, ok := h.getJournal(c)
+	if !ok || !checkJournalAccess(c, journal) {
+		return
+	}
+
+	journal, err = h.services.Journal().Rollback(c.Request.Context(), journal.ID, version, getAuthInfo(c).UserID)
+	if err != nil {
+		handleError(c, "error while rolling journal back", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, journal)
+}
+
+// ClassifyJournal godoc
+// @Router       /journal/{id}/classification [PUT]
+// @Summary      Set category, tags and specialties of the journal
+// @Description  Replace the category, the tags and the doctor types of the journal in any status, it does not make a new version. Empty values remove them
+// @Tags         journal
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "journal id"
+// @Param        classification body models.ClassifyJournal true "category, tags and doctor types"
+// @Success      200  {object}  models.Journal
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) ClassifyJournal(c *gin.Context) {
+	classify := models.ClassifyJournal{}
+
+	if err := c.ShouldBindJSON(&classify); err != nil {
+		handleBindError(c, err)
+		return
+	}
+
+	if !checkJournalLinkIDs(c, classify.CategoryID, classify.DoctorTypeIDs) {
+		return
+	}
+
+	journal, ok := h.getJournal(c)
+	if !ok || !checkJournalAccess(c, journal) {
+		return
+	}
+
+	classify.ID = journal.ID
+
+	journal, err := h.services.Journal().Classify(c.Request.Context(), classify)
+	if err != nil {
+		handleError(c, "error while classifying journal", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, journal)
+}
+
+// GetJournalTags godoc
+// @Router       /journal/tags [GET]
+// @Summary      Get journal tags
+// @Description  Tags of published journals with the number of journals of each, the most used first
+// @Tags         journal
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  models.JournalTagsResponse
+// @Failure      500  {object}  models.Response
+func (h Handler) GetJournalTags(c *gin.Context) {
+
+	tags, err := h.services.Journal().GetTags(c.Request.Context())
+	if err != nil {
+		handleError(c, "error while getting journal tags", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, tags)
+}
+
+// GetDoctorJournals godoc
+// @Router       /doctor/{id}/journals [GET]
+// @Summary      Get journals for a doctor profile
+// @Description  Published journals linked to the doctor type of the doctor, newest first by default
+// @Tags         journal
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "doctor id"
+// @Param        page query string false "page"
+// @Param        limit query string false "limit, 10 by default and at most 100"
+// @Param        sort_by query string false "created_at (default), theme"
+// @Param        order query string false "asc or desc, desc for the default sorting and asc otherwise"
+// @Success      200  {object}  models.JournalsResponse
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetDoctorJournals(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	request, err := getListRequest(c)
+	if err != nil {
+		handleResponse(c, "error while parsing list request", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, err := h.services.Journal().GetForDoctor(c.Request.Context(), id.String(), request)
+	if err != nil {
+		handleError(c, "error while getting doctor journals", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, response)
+}
+
+// getJournal loads the journal of the id path parameter, answering the client itself when it can not
+func (h Handler) getJournal(c *gin.Context) (models.Journal, bool) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return models.Journal{}, false
+	}
+
+	journal, err := h.services.Journal().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id.String(),
+	})
+	if err != nil {
+		handleError(c, "error while get journal by id", err)
+		return models.Journal{}, false
+	}
+
+	return journal, true
+}
+
+// checkJournalLinkIDs makes sure the category and the doctor types of a journal are uuids
+func checkJournalLinkIDs(c *gin.Context, categoryID string, doctorTypeIDs []string) bool {
+
+	if categoryID != "" {
+		if _, err := uuid.Parse(categoryID); err != nil {
+			handleResponse(c, "category_id is not valid", http.StatusBadRequest, err.Error())
+			return false
+		}
+	}
+
+	for _, id := range doctorTypeIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			handleResponse(c, "doctor_type_ids are not valid", http.StatusBadRequest, id)
+			return false
+		}
+	}
+
+	return true
+}
+
+// canSeeJournal shows released journals to everyone, the rest only to their author and super admins
+func canSeeJournal(c *gin.Context, journal models.Journal) bool {
+
+	if journal.Status == config.JournalPublished && journal.PublishedAt != nil && !journal.PublishedAt.After(time.Now()) {
+		return true
+	}
+
+	authInfo := getAuthInfo(c)
+
+	return authInfo.UserRole == config.SuperAdminRole ||
+		(authInfo.UserRole == config.AuthorRole && authInfo.UserID == journal.AuthorID)
+}
+
+// checkJournalAccess lets authors manage only their own journals
+func checkJournalAccess(c *gin.Context, journal models.Journal) bool {
+	authInfo := getAuthInfo(c)
+
+	if authInfo.UserRole == config.AuthorRole && journal.AuthorID != authInfo.UserID {
+		handleError(c, "forbidden", errs.Forbidden("journal was written by another author"))
+		return false
+	}
+
+	return true
+}
+
+func isJournalStatus(status string) bool {
+	switch status {
+	case config.JournalDraft, config.JournalInReview, config.JournalPublished, config.JournalArchived:
+		return true
+	}
+
+	return false
+}
+
+func isJournalLanguage(language string) bool {
+	switch language {
+	case config.LanguageUzbek, config.LanguageRussian, config.LanguageEnglish:
+		return true
+	}
+
+	return false
+}
+
+// RestoreJournal godoc
+// @Router       /journal/{id}/restore [POST]
+// @Summary      Restore journal
+// @Description  Restore a soft deleted journal
+// @Tags         journal
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "journal id"
+// @Success      200  {object}  models.Journal
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) RestoreJournal(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	journal, err := h.services.Journal().Restore(c.Request.Context(), id.String())
+	if err != nil {
+		handleError(c, "error while restoring journal", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, journal)
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"net/http"
+	"shifolink/api/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreateJournalCategory godoc
+// @Router       /journal_category [POST]
+// @Summary      Create a journal category
+// @Description  Create a journal category, names are unique regardless of case
+// @Tags         journal_category
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        journal_category body models.CreateJournalCategory true "journal category data"
+// @Success      201  {object}  models.JournalCategory
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) CreateJournalCategory(c *gin.Context) {
+	createCategory := models.CreateJournalCategory{}
+
+	if err := c.ShouldBindJSON(&createCategory); err != nil {
+		handleBindError(c, err)
+		return
+	}
+
+	category, err := h.services.JournalCategory().Create(c.Request.Context(), createCategory)
+	if err != nil {
+		handleError(c, "error while creating journal category", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusCreated, category)
+}
+
+// GetJournalCategoryByID godoc
+// @Router       /journal_category/{id} [GET]
+// @Summary      Get journal category by id
+// @Description  Get journal category by id
+// @Tags         journal_category
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "journal category id"
+// @Success      200  {object}  models.JournalCategory
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetJournalCategoryByID(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	category, err := h.services.JournalCategory().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id.String(),
+	})
+	if err != nil {
+		handleError(c, "error while get journal category by id", err)
+		return
+	}
+
+	setETag(c, category.Version)
+	handleResponse(c, "", http.StatusOK, category)
+}
+
+// GetJournalCategoriesList godoc
+// @Router       /journal_category [GET]
+// @Summary      Get journal categories list
+// @Description  Get journal categories list, journals of a category are listed by /journal?category_id=
+// @Tags         journal_category
+// @Accept       json
+// @Produce      json
+// @Param        page query string false "page"
+// @Param        limit query string false "limit, 10 by default and at most 100"
+// @Param        search query string false "name"
+// @Param        sort_by query string false "created_at (default), name"
+// @Param        order query string false "asc or desc, desc for the default sorting and asc otherwise"
+// @Param        include_deleted query bool false "list soft deleted records too, super admin only"
+// @Success      200  {object}  models.JournalCategoriesResponse
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetJournalCategoriesList(c *gin.Context) {
+
+	request, err := getListRequest(c)
+	if err != nil {
+		handleResponse(c, "error while parsing list request", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !readIncludeDeleted(c, &request) {
+		return
+	}
+
+	response, err := h.services.JournalCategory().GetList(c.Request.Context(), request)
+	if err != nil {
+		handleError(c, "error while getting journal categories", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, response)
+}
+
+// UpdateJournalCategory godoc
+// @Router       /journal_category/{id} [PUT]
+// @Router       /journal_category/{id} [PATCH]
+// @Summary      Update journal category by id
+// @Description  Update journal category by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields
+// @Tags         journal_category
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "journal category id"
+// @Param        journal_category body models.UpdateJournalCategory true "journal category"
+// @Param        If-Match header string false "ETag of the record, the update fails with 412 when the record was changed since"
+// @Success      200  {object}  models.JournalCategory
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      412  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) UpdateJournalCategory(c *gin.Context) {
+	updateCategory := models.UpdateJournalCategory{}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !bindUpdate(c, &updateCategory, func() (interface{}, error) {
+		return h.services.JournalCategory().Get(c.Request.Context(), models.PrimaryKey{ID: id.String()})
+	}) {
+		return
+	}
+
+	updateCategory.ID = id.String()
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	updateCategory.Version = version
+
+	category, err := h.services.JournalCategory().Update(c.Request.Context(), updateCategory)
+	if err != nil {
+		handleError(c, "error while updating journal category", err)
+		return
+	}
+
+	setETag(c, category.Version)
+	handleResponse(c, "", http.StatusOK, category)
+}
+
+// DeleteJournalCategory godoc
+// @Router       /journal_category/{id} [DELETE]
+// @Summary      Delete journal category
+// @Description  Delete journal category, its journals are left without a category
+// @Tags         journal_category
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "journal category id"
+// @Success      200  {object}  models.Response
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) DeleteJournalCategory(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err = h.services.JournalCategory().Delete(c.Request.Context(), id.String()); err != nil {
+		handleError(c, "error while deleting journal category by id", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, "data succesfully deleted")
+}
+
+// RestoreJournalCategory godoc
+// @Router       /journal_category/{id}/restore [POST]
+// @Summary      Restore journal category
+// @Description  Restore a soft deleted journal category
+// @Tags         journal_category
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "journal category id"
+// @Success      200  {object}  models.JournalCategory
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) RestoreJournalCategory(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	journalCategory, err := h.services.JournalCategory().Restore(c.Request.Context(), id.String())
+	if err != nil {
+		handleError(c, "error while restoring journal category", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, journalCategory)
+}
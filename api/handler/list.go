@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"shifolink/api/models"
+	"shifolink/config"
+	"shifolink/pkg/errs"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxListLimit is the largest page a list returns, a client can not load a whole table in one request
+const maxListLimit = 100
+
+// getListRequest reads page, limit, search and the created_at range every list endpoint accepts
+func getListRequest(c *gin.Context) (models.GetListRequest, error) {
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		return models.GetListRequest{}, fmt.Errorf("page should be a positive number")
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > maxListLimit {
+		return models.GetListRequest{}, fmt.Errorf("limit should be a number from 1 to %d", maxListLimit)
+	}
+
+	request := models.GetListRequest{
+		Page:        page,
+		Limit:       limit,
+		Search:      c.Query("search"),
+		CreatedFrom: c.Query("created_from"),
+		CreatedTo:   c.Query("created_to"),
+		SortBy:      c.Query("sort_by"),
+		Order:       c.Query("order"),
+	}
+
+	if request.Order != "" && request.Order != "asc" && request.Order != "desc" {
+		return models.GetListRequest{}, fmt.Errorf("order should be asc or desc")
+	}
+
+	for key, value := range map[string]string{"created_from": request.CreatedFrom, "created_to": request.CreatedTo} {
+		if value == "" {
+			continue
+		}
+
+		if _, err = time.Parse("2006-01-02", value); err != nil {
+			return models.GetListRequest{}, fmt.Errorf("%s should be a date in YYYY-MM-DD format", key)
+		}
+	}
+
+	return request, nil
+}
+
+// getUUIDQueries reads id filters of a list, a filter which is not given is an empty string
+func getUUIDQueries(c *gin.Context, keys ...string) (map[string]string, error) {
+
+	ids := make(map[string]string, len(keys))
+
+	for _, key := range keys {
+		value := c.Query(key)
+		if value == "" {
+			continue
+		}
+
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a valid uuid", key)
+		}
+
+		ids[key] = id.String()
+	}
+
+	return ids, nil
+}
+
+// readIncludeDeleted reads include_deleted of a list into the request, only super admins may list soft
+// deleted records. The client is answered when the value can not be used
+func readIncludeDeleted(c *gin.Context, request *models.GetListRequest) bool {
+
+	value := c.Query("include_deleted")
+	if value == "" {
+		return true
+	}
+
+	includeDeleted, err := strconv.ParseBool(value)
+	if err != nil {
+		handleResponse(c, "error while parsing list request", http.StatusBadRequest, "include_deleted should be true or false")
+		return false
+	}
+
+	if includeDeleted && getAuthInfo(c).UserRole != config.SuperAdminRole {
+		handleError(c, "forbidden", errs.Forbidden("only super admins can list deleted records"))
+		return false
+	}
+
+	request.IncludeDeleted = includeDeleted
+
+	return true
+}
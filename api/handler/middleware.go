@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"net/http"
+	"shifolink/api/models"
+	"shifolink/config"
+	"shifolink/pkg/errs"
+	"shifolink/pkg/metrics"
+	"shifolink/pkg/reqctx"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	authInfoKey     = "auth_info"
+	requestIDHeader = "X-Request-ID"
+)
+
+// RequestIDMiddleware gives every request an id, the one sent by the client or a new one. The id is sent back
+// in the X-Request-ID header and written to the audit log next to the changes made by the request
+func (h Handler) RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		requestID := c.GetHeader(requestIDHeader)
+		if requestID == "" || len(requestID) > 64 {
+			requestID = uuid.NewString()
+		}
+
+		c.Header(requestIDHeader, requestID)
+		c.Request = c.Request.WithContext(reqctx.WithRequestID(c.Request.Context(), requestID))
+		c.Next()
+	}
+}
+
+// MetricsMiddleware counts answered requests and their latency by route and status. Paths which match
+// no route are counted together so scanners do not make a series per path
+func (h Handler) MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		status := strconv.Itoa(c.Writer.Status())
+
+		metrics.HTTPRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// AuthorizerMiddleware lets the request through only if it carries a valid
+// access token issued for one of the given roles
+func (h Handler) AuthorizerMiddleware(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		token := strings.TrimSpace(strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "))
+		if token == "" {
+			handleResponse(c, "unauthorized", http.StatusUnauthorized, "authorization token is not provided")
+			c.Abort()
+			return
+		}
+
+		authInfo, err := h.services.Auth().ParseAccessToken(token)
+		if err != nil {
+			handleResponse(c, "unauthorized", http.StatusUnauthorized, err.Error())
+			c.Abort()
+			return
+		}
+
+		if !hasRole(authInfo.UserRole, roles) {
+			handleError(c, "forbidden", errs.Forbidden("you don't have permission for this action"))
+			c.Abort()
+			return
+		}
+
+		setAuthInfo(c, authInfo)
+		c.Next()
+	}
+}
+
+// OptionalAuthMiddleware is for public routes which show more to signed in users. Requests without
+// a token go through as guests, a token which is given has to be valid
+func (h Handler) OptionalAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		token := strings.TrimSpace(strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "))
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		authInfo, err := h.services.Auth().ParseAccessToken(token)
+		if err != nil {
+			handleResponse(c, "unauthorized", http.StatusUnauthorized, err.Error())
+			c.Abort()
+			return
+		}
+
+		setAuthInfo(c, authInfo)
+		c.Next()
+	}
+}
+
+func hasRole(role string, roles []string) bool {
+	if len(roles) == 0 {
+		return true
+	}
+
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setAuthInfo keeps the signed in user for the handlers and puts them into the request context as the actor
+// of the changes the request makes
+func setAuthInfo(c *gin.Context, authInfo models.AuthInfo) {
+	c.Set(authInfoKey, authInfo)
+
+	c.Request = c.Request.WithContext(reqctx.WithActor(c.Request.Context(), reqctx.Actor{
+		ID:   authInfo.UserID,
+		Role: authInfo.UserRole,
+	}))
+}
+
+func getAuthInfo(c *gin.Context) models.AuthInfo {
+	authInfo, _ := c.Get(authInfoKey)
+
+	info, _ := authInfo.(models.AuthInfo)
+
+	return info
+}
+
+// checkOwner allows super admins and the owner of the account with the given role,
+// every other caller gets 403
+func checkOwner(c *gin.Context, role, id string) bool {
+	authInfo := getAuthInfo(c)
+
+	if authInfo.UserRole == config.SuperAdminRole || (authInfo.UserRole == role && authInfo.UserID == id) {
+		return true
+	}
+
+	handleError(c, "forbidden", errs.Forbidden("you can manage only your own account"))
+	return false
+}
+
+// checkSelf allows only the owner of the account, used for password changes
+func checkSelf(c *gin.Context, role, id string) bool {
+	authInfo := getAuthInfo(c)
+
+	if authInfo.UserRole == role && authInfo.UserID == id {
+		return true
+	}
+
+	handleError(c, "forbidden", errs.Forbidden("you can change only your own password"))
+	return false
+}
+
+// checkDoctorTypeBranch makes sure a clinic admin manages only doctors of their own clinic branch
+func (h Handler) checkDoctorTypeBranch(c *gin.Context, doctorTypeID string) bool {
+	authInfo := getAuthInfo(c)
+
+	if authInfo.UserRole != config.ClinicAdminRole {
+		return true
+	}
+
+	doctorType, err := h.services.DoctorType().Get(c.Request.Context(), models.PrimaryKey{
+		ID: doctorTypeID,
+	})
+	if err != nil {
+		handleResponse(c, "error while getting doctor type", http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	if doctorType.ClinicBranchID != authInfo.BranchID {
+		handleError(c, "forbidden", errs.Forbidden("doctor type belongs to another clinic branch"))
+		return false
+	}
+
+	return true
+}
+
+// checkDoctorBranch makes sure a clinic admin manages an existing doctor of their own clinic branch
+func (h Handler) checkDoctorBranch(c *gin.Context, doctorID string) bool {
+	if getAuthInfo(c).UserRole != config.ClinicAdminRole {
+		return true
+	}
+
+	doctor, err := h.services.Doctor().Get(c.Request.Context(), models.PrimaryKey{
+		ID: doctorID,
+	})
+	if err != nil {
+		handleResponse(c, "error while getting doctor", http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	return h.checkDoctorTypeBranch(c, doctor.DoctorTypeID)
+}
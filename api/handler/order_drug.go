@@ -1,11 +1,10 @@
 package handler
 
 import (
-	"context"
 	"errors"
 	"net/http"
 	"shifolink/api/models"
-	"strconv"
+	"shifolink/config"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -14,33 +13,34 @@ import (
 // CreateOrderDrug godoc
 // @Router       /order_drug [POST]
 // @Summary      Create a new OrderDrug
-// @Description  Create a new OrderDrug
+// @Description  Create a new OrderDrug. Lines can be changed only while the order is pending and not checked out
 // @Tags         order_drug
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        orderDrug body  models.CreateOrderDrug true  "OrderDrug data"
 // @Success      201  {object}  models.OrderDrug
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) CreateOrderDrug(c *gin.Context) {
 	createOrderDrug := models.CreateOrderDrug{}
 
 	if err := c.ShouldBindJSON(&createOrderDrug); err != nil {
-		handleResponse(c, "error while reading body from client", http.StatusBadRequest, err)
+		handleBindError(c, err)
+		return
 	}
 
-	id, err := h.storage.OrderDrug().Create(context.Background(), createOrderDrug)
-	if err != nil {
-		handleResponse(c, "error while creating orderDrug ", http.StatusInternalServerError, err)
+	if !h.checkOrderAccessByID(c, createOrderDrug.OrdersID) {
 		return
 	}
 
-	orderDrug, err := h.storage.OrderDrug().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	orderDrug, err := h.services.OrderDrug().Create(c.Request.Context(), createOrderDrug)
 	if err != nil {
-		handleResponse(c, "error while get orderDrug ", http.StatusInternalServerError, err)
+		handleError(c, "error while creating orderDrug ", err)
 		return
 	}
 
@@ -53,11 +53,15 @@ func (h Handler) CreateOrderDrug(c *gin.Context) {
 // @Summary      Get OrderDrug by id
 // @Description  Get OrderDrug by id
 // @Tags         order_drug
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "OrderDrug"
 // @Success      200  {object}  models.OrderDrug
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetOrderDrugByID(c *gin.Context) {
@@ -72,14 +76,19 @@ func (h Handler) GetOrderDrugByID(c *gin.Context) {
 		return
 	}
 
-	orderDrug, err := h.storage.OrderDrug().Get(context.Background(), models.PrimaryKey{
+	orderDrug, err := h.services.OrderDrug().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, "error while get OrderDrug by id", http.StatusInternalServerError, err)
+		handleError(c, "error while get OrderDrug by id", err)
 		return
 	}
 
+	if !h.checkOrderAccessByID(c, orderDrug.OrdersID) {
+		return
+	}
+
+	setETag(c, orderDrug.Version)
 	handleResponse(c, "", http.StatusOK, orderDrug)
 
 }
@@ -89,47 +98,63 @@ func (h Handler) GetOrderDrugByID(c *gin.Context) {
 // @Summary      Get OrderDrugs list
 // @Description  Get OrderDrugs list
 // @Tags         order_drug
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
-// @Param        limit query string false "limit"
+// @Param        limit query string false "limit, 10 by default and at most 100"
 // @Param        search query string false "search"
+// @Param        created_from query string false "created at or after the date, YYYY-MM-DD"
+// @Param        created_to query string false "created at or before the date, YYYY-MM-DD"
+// @Param        sort_by query string false "created_at (default), quantity, line_total"
+// @Param        order query string false "asc or desc, desc for the default sorting and asc otherwise"
+// @Param        orders_id query string false "orders id, required for pharmacists"
+// @Param        drug_id query string false "drug id"
+// @Param        include_deleted query bool false "list soft deleted records too, super admin only"
 // @Success      200  {object}  models.OrderDrugsResponse
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetOrderDrugsList(c *gin.Context) {
 
-	var (
-		page, limit int
-		search      string
-		err         error
-	)
-
-	pageStr := c.DefaultQuery("page", "1")
-	page, err = strconv.Atoi(pageStr)
+	request, err := getListRequest(c)
 	if err != nil {
-		handleResponse(c, "error while parsing page ", http.StatusBadRequest, err.Error())
+		handleResponse(c, "error while parsing list request", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err = strconv.Atoi(limitStr)
+	if !readIncludeDeleted(c, &request) {
+		return
+	}
+
+	ids, err := getUUIDQueries(c, "orders_id", "drug_id")
 	if err != nil {
-		handleResponse(c, "error while parsing limit", http.StatusBadRequest, err.Error())
+		handleResponse(c, "error while parsing list filters", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	search = c.Query("search")
+	// pharmacists list lines of one order they can see, not of every drug store
+	if getAuthInfo(c).UserRole == config.PharmacistRole {
+		if ids["orders_id"] == "" {
+			handleResponse(c, "error while parsing list filters", http.StatusBadRequest, "orders_id is required")
+			return
+		}
 
-	response, err := h.storage.OrderDrug().GetList(context.Background(), models.GetListRequest{
-		Page:   page,
-		Limit:  limit,
-		Search: search,
+		if !h.checkOrderAccessByID(c, ids["orders_id"]) {
+			return
+		}
+	}
+
+	response, err := h.services.OrderDrug().GetList(c.Request.Context(), models.GetOrderDrugsListRequest{
+		GetListRequest: request,
+		OrdersID:       ids["orders_id"],
+		DrugID:         ids["drug_id"],
 	})
 
 	if err != nil {
-		handleResponse(c, "error while getting OrderDrug ", http.StatusInternalServerError, err)
+		handleError(c, "error while getting OrderDrug ", err)
 		return
 	}
 
@@ -139,16 +164,24 @@ func (h Handler) GetOrderDrugsList(c *gin.Context) {
 
 // UpdateOrderDrug godoc
 // @Router       /order_drug/{id} [PUT]
+// @Router       /order_drug/{id} [PATCH]
 // @Summary      Update OrderDrug by id
-// @Description  Update OrderDrug by id
+// @Description  Update OrderDrug by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields
 // @Tags         order_drug
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "OrderDrug id"
 // @Param        OrderDrug body models.UpdateOrderDrug true "OrderDrug"
+// @Param        If-Match header string false "ETag of the record, the update fails with 412 when the record was changed since"
 // @Success      200  {object}  models.OrderDrug
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      412  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdateOrderDrug(c *gin.Context) {
 	updateOrderDrug := models.UpdateOrderDrug{}
@@ -159,27 +192,43 @@ func (h Handler) UpdateOrderDrug(c *gin.Context) {
 		return
 	}
 
-	//updateAuthor.ID = uid
+	orderDrug, err := h.services.OrderDrug().Get(c.Request.Context(), models.PrimaryKey{ID: uid})
+	if err != nil {
+		handleError(c, "error while getting OrderDrug by id", err)
+		return
+	}
+
+	if !h.checkOrderAccessByID(c, orderDrug.OrdersID) {
+		return
+	}
 
-	if err := c.ShouldBindJSON(&updateOrderDrug); err != nil {
-		handleResponse(c, "error while reading body", http.StatusBadRequest, err.Error())
+	if !bindUpdate(c, &updateOrderDrug, func() (interface{}, error) {
+		return orderDrug, nil
+	}) {
 		return
 	}
 
-	id, err := h.storage.OrderDrug().Update(context.Background(), updateOrderDrug)
-	if err != nil {
-		handleResponse(c, "error while updating OrderDrug ", http.StatusInternalServerError, err.Error())
+	// the line may be moved only to another order of the same caller
+	if updateOrderDrug.OrdersID != orderDrug.OrdersID && !h.checkOrderAccessByID(c, updateOrderDrug.OrdersID) {
 		return
 	}
 
-	OrderDrug, err := h.storage.OrderDrug().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	updateOrderDrug.ID = uid
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	updateOrderDrug.Version = version
+
+	OrderDrug, err := h.services.OrderDrug().Update(c.Request.Context(), updateOrderDrug)
 	if err != nil {
-		handleResponse(c, "error while getting OrderDrug by id", http.StatusInternalServerError, err)
+		handleError(c, "error while updating OrderDrug ", err)
 		return
 	}
 
+	setETag(c, OrderDrug.Version)
 	handleResponse(c, "", http.StatusOK, OrderDrug)
 
 }
@@ -189,12 +238,16 @@ func (h Handler) UpdateOrderDrug(c *gin.Context) {
 // @Summary      Delete OrderDrug
 // @Description  Delete OrderDrug
 // @Tags         order_drug
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "OrderDrug id"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) DeleteOrderDrug(c *gin.Context) {
 
@@ -205,11 +258,53 @@ func (h Handler) DeleteOrderDrug(c *gin.Context) {
 		return
 	}
 
-	if err := h.storage.OrderDrug().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, "error while deleting OrderDrug  by id", http.StatusInternalServerError, err.Error())
+	orderDrug, err := h.services.OrderDrug().Get(c.Request.Context(), models.PrimaryKey{ID: id.String()})
+	if err != nil {
+		handleError(c, "error while getting OrderDrug by id", err)
+		return
+	}
+
+	if !h.checkOrderAccessByID(c, orderDrug.OrdersID) {
+		return
+	}
+
+	if err = h.services.OrderDrug().Delete(c.Request.Context(), id.String()); err != nil {
+		handleError(c, "error while deleting OrderDrug  by id", err)
 		return
 	}
 
 	handleResponse(c, "", http.StatusOK, "data succesfully deleted")
 
 }
+
+// RestoreOrderDrug godoc
+// @Router       /order_drug/{id}/restore [POST]
+// @Summary      Restore order drug
+// @Description  Restore a soft deleted order drug
+// @Tags         order_drug
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "order drug id"
+// @Success      200  {object}  models.OrderDrug
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) RestoreOrderDrug(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	orderDrug, err := h.services.OrderDrug().Restore(c.Request.Context(), id.String())
+	if err != nil {
+		handleError(c, "error while restoring order drug", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, orderDrug)
+}
@@ -1,11 +1,12 @@
 package handler
 
 import (
-	"context"
 	"errors"
 	"net/http"
 	"shifolink/api/models"
-	"strconv"
+	"shifolink/config"
+	"shifolink/pkg/errs"
+	"shifolink/storage"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -16,31 +17,31 @@ import (
 // @Summary      Create a new Orders
 // @Description  Create a new Orders
 // @Tags         orders
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        Orders body  models.CreateOrders true  "Orders data"
 // @Success      201  {object}  models.Orders
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) CreateOrders(c *gin.Context) {
 	createOrders := models.CreateOrders{}
 
 	if err := c.ShouldBindJSON(&createOrders); err != nil {
-		handleResponse(c, "error while reading body from client", http.StatusBadRequest, err)
+		handleBindError(c, err)
+		return
 	}
 
-	id, err := h.storage.Orders().Create(context.Background(), createOrders)
-	if err != nil {
-		handleResponse(c, "error while creating Orders ", http.StatusInternalServerError, err)
+	if authInfo := getAuthInfo(c); authInfo.UserRole == config.CustomerRole && authInfo.UserID != createOrders.CustomerID {
+		handleError(c, "forbidden", errs.Forbidden("customer can create an order only for themselves"))
 		return
 	}
 
-	orders, err := h.storage.Orders().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	orders, err := h.services.Orders().Create(c.Request.Context(), createOrders)
 	if err != nil {
-		handleResponse(c, "error while get Orders ", http.StatusInternalServerError, err)
+		handleError(c, "error while creating Orders ", err)
 		return
 	}
 
@@ -48,16 +49,79 @@ func (h Handler) CreateOrders(c *gin.Context) {
 
 }
 
+// CheckoutOrders godoc
+// @Router       /orders/checkout [POST]
+// @Summary      Checkout an order
+// @Description  Create an order with its drugs in one transaction, drug counts are decremented. Unit prices are copied from the drugs and totals are calculated on the server, discount (pharmacist only) is an amount taken from the subtotal. If any line can not be sold nothing is saved and the rejected lines are returned with 409
+// @Tags         orders
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        Checkout body  models.CheckoutOrder true  "Checkout data"
+// @Success      201  {object}  models.Orders
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      409  {object}  models.Response{data=[]models.CheckoutLineError}
+// @Failure      500  {object}  models.Response
+func (h Handler) CheckoutOrders(c *gin.Context) {
+	checkout := models.CheckoutOrder{}
+
+	if err := c.ShouldBindJSON(&checkout); err != nil {
+		handleBindError(c, err)
+		return
+	}
+
+	switch authInfo := getAuthInfo(c); authInfo.UserRole {
+	case config.CustomerRole:
+		if authInfo.UserID != checkout.CustomerID {
+			handleError(c, "forbidden", errs.Forbidden("customer can create an order only for themselves"))
+			return
+		}
+
+		if checkout.Discount != "" {
+			handleError(c, "forbidden", errs.Forbidden("only a pharmacist can give a discount"))
+			return
+		}
+		checkout.PharmacistID = ""
+
+	case config.PharmacistRole:
+		if authInfo.BranchID != checkout.DrugStoreBranchID {
+			handleError(c, "forbidden", errs.Forbidden("pharmacist can sell only drugs of their own branch"))
+			return
+		}
+		checkout.PharmacistID = authInfo.UserID
+	}
+
+	orders, err := h.services.Orders().Checkout(c.Request.Context(), checkout)
+	if err != nil {
+		checkoutErr := storage.CheckoutError{}
+
+		if errors.As(err, &checkoutErr) {
+			handleResponse(c, "some drugs can not be sold", http.StatusConflict, checkoutErr.Lines)
+			return
+		}
+		handleError(c, "error while checking out order", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusCreated, orders)
+}
+
 // GetOrdersByID godoc
 // @Router       /orders/{id} [GET]
 // @Summary      Get Orders by id
-// @Description  Get Orders by id
+// @Description  Get Orders by id with its lines
 // @Tags         orders
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "Orders"
 // @Success      200  {object}  models.Orders
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetOrdersByID(c *gin.Context) {
@@ -72,14 +136,19 @@ func (h Handler) GetOrdersByID(c *gin.Context) {
 		return
 	}
 
-	orders, err := h.storage.Orders().Get(context.Background(), models.PrimaryKey{
+	orders, err := h.services.Orders().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, "error while get Orders by id", http.StatusInternalServerError, err)
+		handleError(c, "error while get Orders by id", err)
+		return
+	}
+
+	if !checkOrderAccess(c, orders) {
 		return
 	}
 
+	setETag(c, orders.Version)
 	handleResponse(c, "", http.StatusOK, orders)
 
 }
@@ -87,49 +156,75 @@ func (h Handler) GetOrdersByID(c *gin.Context) {
 // GetOrdersList godoc
 // @Router       /orders [GET]
 // @Summary      Get Orders list
-// @Description  Get Orders list
+// @Description  Get Orders list. Customers get their own orders, pharmacists the orders of their branch and the ones they opened
 // @Tags         orders
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
-// @Param        limit query string false "limit"
+// @Param        limit query string false "limit, 10 by default and at most 100"
 // @Param        search query string false "search"
+// @Param        status query string false "pending, confirmed, preparing, ready_for_pickup, delivered, cancelled or refunded"
+// @Param        created_from query string false "created at or after the date, YYYY-MM-DD"
+// @Param        created_to query string false "created at or before the date, YYYY-MM-DD"
+// @Param        sort_by query string false "created_at (default), total, status"
+// @Param        order query string false "asc or desc, desc for the default sorting and asc otherwise"
+// @Param        cursor query string false "next_cursor of the previous page, used instead of page"
+// @Param        customer_id query string false "customer id"
+// @Param        pharmacist_id query string false "pharmacist id"
+// @Param        drug_store_branch_id query string false "drug store branch id"
+// @Param        include_deleted query bool false "list soft deleted records too, super admin only"
 // @Success      200  {object}  models.OrdersResponse
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetOrderssList(c *gin.Context) {
 
-	var (
-		page, limit int
-		search      string
-		err         error
-	)
-
-	pageStr := c.DefaultQuery("page", "1")
-	page, err = strconv.Atoi(pageStr)
+	request, err := getListRequest(c)
 	if err != nil {
-		handleResponse(c, "error while parsing page ", http.StatusBadRequest, err.Error())
+		handleResponse(c, "error while parsing list request", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !readIncludeDeleted(c, &request) {
 		return
 	}
+	request.Cursor = c.Query("cursor")
 
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err = strconv.Atoi(limitStr)
+	ids, err := getUUIDQueries(c, "customer_id", "pharmacist_id", "drug_store_branch_id")
 	if err != nil {
-		handleResponse(c, "error while parsing limit", http.StatusBadRequest, err.Error())
+		handleResponse(c, "error while parsing list filters", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	search = c.Query("search")
+	status := c.Query("status")
+	if status != "" && !isOrderStatus(status) {
+		handleResponse(c, "unknown order status", http.StatusBadRequest, status)
+		return
+	}
 
-	response, err := h.storage.Orders().GetList(context.Background(), models.GetListRequest{
-		Page:   page,
-		Limit:  limit,
-		Search: search,
-	})
+	listRequest := models.GetOrdersListRequest{
+		GetListRequest:    request,
+		Status:            status,
+		CustomerID:        ids["customer_id"],
+		PharmacistID:      ids["pharmacist_id"],
+		DrugStoreBranchID: ids["drug_store_branch_id"],
+	}
+
+	// the list is scoped like checkOrderAccess, the filters of the client only narrow it down
+	switch authInfo := getAuthInfo(c); authInfo.UserRole {
+	case config.CustomerRole:
+		listRequest.CustomerID = authInfo.UserID
+	case config.PharmacistRole:
+		listRequest.AccessBranchID, listRequest.AccessPharmacistID = authInfo.BranchID, authInfo.UserID
+	}
+
+	response, err := h.services.Orders().GetList(c.Request.Context(), listRequest)
 
 	if err != nil {
-		handleResponse(c, "error while getting Orders ", http.StatusInternalServerError, err)
+		handleError(c, "error while getting Orders ", err)
 		return
 	}
 
@@ -139,16 +234,24 @@ func (h Handler) GetOrderssList(c *gin.Context) {
 
 // UpdateOrders godoc
 // @Router       /orders/{id} [PUT]
+// @Router       /orders/{id} [PATCH]
 // @Summary      Update Orders by id
-// @Description  Update Orders by id
+// @Description  Update Orders by id, only a pending order can be changed. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields
 // @Tags         orders
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "Orders id"
 // @Param        Orders body models.UpdateOrders true "Orders"
+// @Param        If-Match header string false "ETag of the record, the update fails with 412 when the record was changed since"
 // @Success      200  {object}  models.Orders
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      412  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdateOrders(c *gin.Context) {
 	updateOrders := models.UpdateOrders{}
@@ -159,27 +262,38 @@ func (h Handler) UpdateOrders(c *gin.Context) {
 		return
 	}
 
-	//updateAuthor.ID = uid
+	orders, err := h.services.Orders().Get(c.Request.Context(), models.PrimaryKey{ID: uid})
+	if err != nil {
+		handleError(c, "error while getting Orders by id", err)
+		return
+	}
 
-	if err := c.ShouldBindJSON(&updateOrders); err != nil {
-		handleResponse(c, "error while reading body", http.StatusBadRequest, err.Error())
+	if !checkOrderAccess(c, orders) {
 		return
 	}
 
-	id, err := h.storage.Orders().Update(context.Background(), updateOrders)
-	if err != nil {
-		handleResponse(c, "error while updating Orders ", http.StatusInternalServerError, err.Error())
+	if !bindUpdate(c, &updateOrders, func() (interface{}, error) {
+		return orders, nil
+	}) {
 		return
 	}
 
-	orders, err := h.storage.Orders().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	updateOrders.ID = uid
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	updateOrders.Version = version
+
+	orders, err = h.services.Orders().Update(c.Request.Context(), updateOrders)
 	if err != nil {
-		handleResponse(c, "error while getting Orders by id", http.StatusInternalServerError, err)
+		handleError(c, "error while updating Orders ", err)
 		return
 	}
 
+	setETag(c, orders.Version)
 	handleResponse(c, "", http.StatusOK, orders)
 
 }
@@ -187,14 +301,18 @@ func (h Handler) UpdateOrders(c *gin.Context) {
 // DeleteOrders godoc
 // @Router       /orders/{id} [DELETE]
 // @Summary      Delete Orders
-// @Description  Delete Orders
+// @Description  Delete Orders, a checked out order is deleted only once it is cancelled, delivered or refunded
 // @Tags         orders
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "Orders id"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) DeleteOrders(c *gin.Context) {
 
@@ -205,11 +323,228 @@ func (h Handler) DeleteOrders(c *gin.Context) {
 		return
 	}
 
-	if err := h.storage.Orders().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, "error while deleting Orders  by id", http.StatusInternalServerError, err.Error())
+	if !h.checkOrderAccessByID(c, id.String()) {
+		return
+	}
+
+	if err := h.services.Orders().Delete(c.Request.Context(), id.String()); err != nil {
+		handleError(c, "error while deleting Orders  by id", err)
 		return
 	}
 
 	handleResponse(c, "", http.StatusOK, "data succesfully deleted")
 
 }
+
+// ConfirmOrder godoc
+// @Router       /orders/{id}/confirm [POST]
+// @Summary      Confirm the order
+// @Description  Pharmacy accepted the order, pending -> confirmed. Only a checked out order can be confirmed
+// @Tags         orders
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "orders id"
+// @Success      200  {object}  models.Orders
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) ConfirmOrder(c *gin.Context) {
+	h.changeOrderStatus(c, config.OrderConfirmed)
+}
+
+// PrepareOrder godoc
+// @Router       /orders/{id}/prepare [POST]
+// @Summary      Start preparing the order
+// @Description  Pharmacist started collecting the drugs, confirmed -> preparing
+// @Tags         orders
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "orders id"
+// @Success      200  {object}  models.Orders
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) PrepareOrder(c *gin.Context) {
+	h.changeOrderStatus(c, config.OrderPreparing)
+}
+
+// ReadyOrder godoc
+// @Router       /orders/{id}/ready [POST]
+// @Summary      Mark the order ready for pickup
+// @Description  Drugs are packed, preparing -> ready_for_pickup
+// @Tags         orders
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "orders id"
+// @Success      200  {object}  models.Orders
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) ReadyOrder(c *gin.Context) {
+	h.changeOrderStatus(c, config.OrderReadyForPickup)
+}
+
+// DeliverOrder godoc
+// @Router       /orders/{id}/deliver [POST]
+// @Summary      Hand over the order
+// @Description  Customer got the drugs, ready_for_pickup -> delivered
+// @Tags         orders
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "orders id"
+// @Success      200  {object}  models.Orders
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) DeliverOrder(c *gin.Context) {
+	h.changeOrderStatus(c, config.OrderDelivered)
+}
+
+// CancelOrder godoc
+// @Router       /orders/{id}/cancel [POST]
+// @Summary      Cancel the order
+// @Description  Cancel a not delivered order, its drugs go back to stock
+// @Tags         orders
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "orders id"
+// @Success      200  {object}  models.Orders
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) CancelOrder(c *gin.Context) {
+	h.changeOrderStatus(c, config.OrderCancelled)
+}
+
+// RefundOrder godoc
+// @Router       /orders/{id}/refund [POST]
+// @Summary      Refund the order
+// @Description  Money of a delivered order was returned, delivered -> refunded
+// @Tags         orders
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "orders id"
+// @Success      200  {object}  models.Orders
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) RefundOrder(c *gin.Context) {
+	h.changeOrderStatus(c, config.OrderRefunded)
+}
+
+func (h Handler) changeOrderStatus(c *gin.Context, status string) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	orders, err := h.services.Orders().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id.String(),
+	})
+	if err != nil {
+		handleError(c, "error while getting orders by id", err)
+		return
+	}
+
+	if !checkOrderAccess(c, orders) {
+		return
+	}
+
+	orders, err = h.services.Orders().ChangeStatus(c.Request.Context(), id.String(), status)
+	if err != nil {
+		handleError(c, "error while changing order status", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, orders)
+}
+
+// checkOrderAccess lets customers see and change only their own orders and pharmacists only orders of their
+// branch or the ones they opened
+func checkOrderAccess(c *gin.Context, orders models.Orders) bool {
+	authInfo := getAuthInfo(c)
+
+	switch authInfo.UserRole {
+	case config.CustomerRole:
+		if orders.CustomerID != authInfo.UserID {
+			handleError(c, "forbidden", errs.Forbidden("order belongs to another customer"))
+			return false
+		}
+
+	case config.PharmacistRole:
+		if orders.DrugStoreBranchID != authInfo.BranchID && orders.PharmacistID != authInfo.UserID {
+			handleError(c, "forbidden", errs.Forbidden("order belongs to another drug store branch"))
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkOrderAccessByID gets the order and checks it with checkOrderAccess, the client is answered when it fails
+func (h Handler) checkOrderAccessByID(c *gin.Context, ordersID string) bool {
+
+	orders, err := h.services.Orders().Get(c.Request.Context(), models.PrimaryKey{ID: ordersID})
+	if err != nil {
+		handleError(c, "error while getting orders by id", err)
+		return false
+	}
+
+	return checkOrderAccess(c, orders)
+}
+
+func isOrderStatus(status string) bool {
+	switch status {
+	case config.OrderPending, config.OrderConfirmed, config.OrderPreparing, config.OrderReadyForPickup,
+		config.OrderDelivered, config.OrderCancelled, config.OrderRefunded:
+		return true
+	}
+
+	return false
+}
+
+// RestoreOrders godoc
+// @Router       /orders/{id}/restore [POST]
+// @Summary      Restore orders
+// @Description  Restore a soft deleted orders
+// @Tags         orders
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "orders id"
+// @Success      200  {object}  models.Orders
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) RestoreOrders(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	orders, err := h.services.Orders().Restore(c.Request.Context(), id.String())
+	if err != nil {
+		handleError(c, "error while restoring orders", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, orders)
+}
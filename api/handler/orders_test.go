@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"shifolink/api/models"
+	"shifolink/config"
+	"shifolink/pkg/pubsub"
+	"shifolink/service"
+	"shifolink/storage"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// listStorage serves only the orders list, other repos are not used by the tests
+type listStorage struct {
+	storage.IStorage
+	orders *listOrdersRepo
+}
+
+func (s listStorage) Orders() storage.IOrdersRepo {
+	return s.orders
+}
+
+// listOrdersRepo filters its orders the way the where clause of ordersRepo.GetList does
+type listOrdersRepo struct {
+	storage.IOrdersRepo
+	orders []models.Orders
+}
+
+func (r *listOrdersRepo) GetList(_ context.Context, request models.GetOrdersListRequest) (models.OrdersResponse, error) {
+	response := models.OrdersResponse{Orderss: []models.Orders{}}
+
+	for _, orders := range r.orders {
+		if request.DrugStoreBranchID != "" && orders.DrugStoreBranchID != request.DrugStoreBranchID {
+			continue
+		}
+
+		if request.AccessPharmacistID != "" && orders.DrugStoreBranchID != request.AccessBranchID &&
+			orders.PharmacistID != request.AccessPharmacistID {
+			continue
+		}
+
+		response.Orderss = append(response.Orderss, orders)
+	}
+
+	response.Count = len(response.Orderss)
+
+	return response, nil
+}
+
+func TestGetOrdersListScopedToPharmacistBranch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const (
+		ownBranch   = "6f2a4c1e-52d4-4f0e-9d5c-0a6f2c1b7e01"
+		otherBranch = "6f2a4c1e-52d4-4f0e-9d5c-0a6f2c1b7e02"
+		pharmacist  = "6f2a4c1e-52d4-4f0e-9d5c-0a6f2c1b7e03"
+	)
+
+	repo := &listOrdersRepo{orders: []models.Orders{
+		{ID: "own", DrugStoreBranchID: ownBranch},
+		{ID: "other", DrugStoreBranchID: otherBranch},
+	}}
+
+	ready := &atomic.Bool{}
+	ready.Store(true)
+
+	h := New(service.New(config.Config{}, listStorage{orders: repo}, pubsub.New()), ready)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "without filters", query: "", want: []string{"own"}},
+		{name: "filtered by another branch", query: "?drug_store_branch_id=" + otherBranch, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/orders", func(c *gin.Context) {
+				c.Set(authInfoKey, models.AuthInfo{
+					UserID:   pharmacist,
+					UserRole: config.PharmacistRole,
+					BranchID: ownBranch,
+				})
+			}, h.GetOrderssList)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/orders"+tt.query, nil))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status is %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+			}
+
+			body := struct {
+				Data models.OrdersResponse
+			}{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("error while reading the response: %v", err)
+			}
+
+			got := []string{}
+			for _, orders := range body.Data.Orderss {
+				got = append(got, orders.ID)
+			}
+
+			if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+				t.Fatalf("orders are %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
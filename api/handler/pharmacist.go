@@ -1,12 +1,10 @@
 package handler
 
 import (
-	"context"
 	"errors"
 	"net/http"
 	"shifolink/api/models"
-	"shifolink/pkg/check"
-	"strconv"
+	"shifolink/config"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -17,31 +15,26 @@ import (
 // @Summary      Create a new Pharmacist
 // @Description  Create a new Pharmacist
 // @Tags         pharmacist
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        Pharmacist body  models.CreatePharmacist true  "Pharmacist data"
 // @Success      201  {object}  models.Pharmacist
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) CreatePharmacist(c *gin.Context) {
 	createPharmacist := models.CreatePharmacist{}
 
 	if err := c.ShouldBindJSON(&createPharmacist); err != nil {
-		handleResponse(c, "error while reading body from client", http.StatusBadRequest, err)
-	}
-
-	id, err := h.storage.Pharmacist().Create(context.Background(), createPharmacist)
-	if err != nil {
-		handleResponse(c, "error while creating Pharmacist ", http.StatusInternalServerError, err)
+		handleBindError(c, err)
 		return
 	}
 
-	Pharmacist, err := h.storage.Pharmacist().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	Pharmacist, err := h.services.Pharmacist().Create(c.Request.Context(), createPharmacist)
 	if err != nil {
-		handleResponse(c, "error while get Pharmacist ", http.StatusInternalServerError, err)
+		handleError(c, "error while creating Pharmacist ", err)
 		return
 	}
 
@@ -54,11 +47,14 @@ func (h Handler) CreatePharmacist(c *gin.Context) {
 // @Summary      Get Pharmacist by id
 // @Description  Get Pharmacist by id
 // @Tags         pharmacist
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "Pharmacist"
 // @Success      200  {object}  models.Pharmacist
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetPharmacistByID(c *gin.Context) {
@@ -73,14 +69,15 @@ func (h Handler) GetPharmacistByID(c *gin.Context) {
 		return
 	}
 
-	Pharmacist, err := h.storage.Pharmacist().Get(context.Background(), models.PrimaryKey{
+	Pharmacist, err := h.services.Pharmacist().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, "error while get Pharmacist by id", http.StatusInternalServerError, err)
+		handleError(c, "error while get Pharmacist by id", err)
 		return
 	}
 
+	setETag(c, Pharmacist.Version)
 	handleResponse(c, "", http.StatusOK, Pharmacist)
 
 }
@@ -90,47 +87,49 @@ func (h Handler) GetPharmacistByID(c *gin.Context) {
 // @Summary      Get Pharmacists list
 // @Description  Get Pharmacists list
 // @Tags         pharmacist
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
-// @Param        limit query string false "limit"
+// @Param        limit query string false "limit, 10 by default and at most 100"
 // @Param        search query string false "search"
+// @Param        created_from query string false "created at or after the date, YYYY-MM-DD"
+// @Param        created_to query string false "created at or before the date, YYYY-MM-DD"
+// @Param        sort_by query string false "created_at (default), first_name, last_name"
+// @Param        order query string false "asc or desc, desc for the default sorting and asc otherwise"
+// @Param        drug_store_branch_id query string false "drug store branch id"
+// @Param        include_deleted query bool false "list soft deleted records too, super admin only"
 // @Success      200  {object}  models.PharmacistsResponse
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetPharmacistsList(c *gin.Context) {
 
-	var (
-		page, limit int
-		search      string
-		err         error
-	)
-
-	pageStr := c.DefaultQuery("page", "1")
-	page, err = strconv.Atoi(pageStr)
+	request, err := getListRequest(c)
 	if err != nil {
-		handleResponse(c, "error while parsing page ", http.StatusBadRequest, err.Error())
+		handleResponse(c, "error while parsing list request", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err = strconv.Atoi(limitStr)
-	if err != nil {
-		handleResponse(c, "error while parsing limit", http.StatusBadRequest, err.Error())
+	if !readIncludeDeleted(c, &request) {
 		return
 	}
 
-	search = c.Query("search")
+	ids, err := getUUIDQueries(c, "drug_store_branch_id")
+	if err != nil {
+		handleResponse(c, "error while parsing list filters", http.StatusBadRequest, err.Error())
+		return
+	}
 
-	response, err := h.storage.Pharmacist().GetList(context.Background(), models.GetListRequest{
-		Page:   page,
-		Limit:  limit,
-		Search: search,
+	response, err := h.services.Pharmacist().GetList(c.Request.Context(), models.GetPharmacistsListRequest{
+		GetListRequest:    request,
+		DrugStoreBranchID: ids["drug_store_branch_id"],
 	})
 
 	if err != nil {
-		handleResponse(c, "error while getting Pharmacist ", http.StatusInternalServerError, err)
+		handleError(c, "error while getting Pharmacist ", err)
 		return
 	}
 
@@ -140,16 +139,22 @@ func (h Handler) GetPharmacistsList(c *gin.Context) {
 
 // UpdatePharmacist godoc
 // @Router       /pharmacist/{id} [PUT]
+// @Router       /pharmacist/{id} [PATCH]
 // @Summary      Update Pharmacist by id
-// @Description  Update Pharmacist by id
+// @Description  Update Pharmacist by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields
 // @Tags         pharmacist
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "Pharmacist id"
 // @Param        Pharmacist body models.UpdatePharmacist true "Pharmacist"
+// @Param        If-Match header string false "ETag of the record, the update fails with 412 when the record was changed since"
 // @Success      200  {object}  models.Pharmacist
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      412  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdatePharmacist(c *gin.Context) {
 	updatePharmacist := models.UpdatePharmacist{}
@@ -160,27 +165,32 @@ func (h Handler) UpdatePharmacist(c *gin.Context) {
 		return
 	}
 
-	//updateAuthor.ID = uid
+	if !checkOwner(c, config.PharmacistRole, uid) {
+		return
+	}
 
-	if err := c.ShouldBindJSON(&updatePharmacist); err != nil {
-		handleResponse(c, "error while reading body", http.StatusBadRequest, err.Error())
+	if !bindUpdate(c, &updatePharmacist, func() (interface{}, error) {
+		return h.services.Pharmacist().Get(c.Request.Context(), models.PrimaryKey{ID: uid})
+	}) {
 		return
 	}
 
-	id, err := h.storage.Pharmacist().Update(context.Background(), updatePharmacist)
-	if err != nil {
-		handleResponse(c, "error while updating Pharmacist ", http.StatusInternalServerError, err.Error())
+	updatePharmacist.ID = uid
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
-	Pharmacist, err := h.storage.Pharmacist().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	updatePharmacist.Version = version
+
+	Pharmacist, err := h.services.Pharmacist().Update(c.Request.Context(), updatePharmacist)
 	if err != nil {
-		handleResponse(c, "error while getting Pharmacist by id", http.StatusInternalServerError, err)
+		handleError(c, "error while updating Pharmacist ", err)
 		return
 	}
 
+	setETag(c, Pharmacist.Version)
 	handleResponse(c, "", http.StatusOK, Pharmacist)
 
 }
@@ -190,11 +200,13 @@ func (h Handler) UpdatePharmacist(c *gin.Context) {
 // @Summary      Delete Pharmacist
 // @Description  Delete Pharmacist
 // @Tags         pharmacist
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "Pharmacist id"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) DeletePharmacist(c *gin.Context) {
@@ -206,8 +218,8 @@ func (h Handler) DeletePharmacist(c *gin.Context) {
 		return
 	}
 
-	if err := h.storage.Pharmacist().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, "error while deleting Pharmacist  by id", http.StatusInternalServerError, err.Error())
+	if err := h.services.Pharmacist().Delete(c.Request.Context(), id.String()); err != nil {
+		handleError(c, "error while deleting Pharmacist  by id", err)
 		return
 	}
 
@@ -216,23 +228,25 @@ func (h Handler) DeletePharmacist(c *gin.Context) {
 }
 
 // UpdatePharmacistPassword godoc
-// @Router       /pharmacist/{id} [PATCH]
+// @Router       /pharmacist/{id}/password [PATCH]
 // @Summary      Update pharmacist password
 // @Description  update pharmacist password
 // @Tags         pharmacist
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param 		 id path string true "pharmacist"
 // @Param        pharmacist body models.UpdatePharmacistPassword true "pharmacist"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdatePharmacistPassword(c *gin.Context) {
 	updatePharmacistPassword := models.UpdatePharmacistPassword{}
 
 	if err := c.ShouldBindJSON(&updatePharmacistPassword); err != nil {
-		handleResponse(c, "error while reading body", http.StatusBadRequest, err.Error())
+		handleBindError(c, err)
 		return
 	}
 
@@ -244,26 +258,46 @@ func (h Handler) UpdatePharmacistPassword(c *gin.Context) {
 
 	updatePharmacistPassword.ID = uid.String()
 
-	oldPassword, err := h.storage.Pharmacist().GetPassword(c, updatePharmacistPassword.ID)
-	if err != nil {
-		handleResponse(c, "error while getting password by id", http.StatusInternalServerError, err.Error())
+	if !checkSelf(c, config.PharmacistRole, updatePharmacistPassword.ID) {
 		return
 	}
 
-	if oldPassword != updatePharmacistPassword.OldPassword {
-		handleResponse(c, "old password is not correct", http.StatusBadRequest, "old password is not correct")
+	if err = h.services.Pharmacist().UpdatePassword(c.Request.Context(), updatePharmacistPassword); err != nil {
+		handleError(c, "error while updating pharmacist password", err)
 		return
 	}
 
-	if err = check.ValidatePassword(updatePharmacistPassword.NewPassword); err != nil {
-		handleResponse(c, "new password is weak", http.StatusBadRequest, err.Error())
+	handleResponse(c, "", http.StatusOK, "password successfully updated")
+}
+
+// RestorePharmacist godoc
+// @Router       /pharmacist/{id}/restore [POST]
+// @Summary      Restore pharmacist
+// @Description  Restore a soft deleted pharmacist
+// @Tags         pharmacist
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "pharmacist id"
+// @Success      200  {object}  models.Pharmacist
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) RestorePharmacist(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if err = h.storage.Pharmacist().UpdatePassword(context.Background(), updatePharmacistPassword); err != nil {
-		handleResponse(c, "error while updating pharmacist password", http.StatusInternalServerError, err.Error())
+	pharmacist, err := h.services.Pharmacist().Restore(c.Request.Context(), id.String())
+	if err != nil {
+		handleError(c, "error while restoring pharmacist", err)
 		return
 	}
 
-	handleResponse(c, "", http.StatusOK, "password successfully updated")
+	handleResponse(c, "", http.StatusOK, pharmacist)
 }
@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"shifolink/api/models"
+	"shifolink/config"
+	"shifolink/pkg/errs"
+	"shifolink/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreatePrescription godoc
+// @Router       /prescription [POST]
+// @Summary      Create a prescription
+// @Description  Doctor prescribes drugs of the catalogue to the customer of a queue which is in consultation or completed, any branch selling them can redeem it. valid_until is in YYYY-MM-DD format, 30 days from today by default. Every item can be sold quantity * (refills + 1) in total, the returned code is given to the pharmacist
+// @Tags         prescription
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        prescription body models.CreatePrescription true "prescription data"
+// @Success      201  {object}  models.Prescription
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) CreatePrescription(c *gin.Context) {
+	createPrescription := models.CreatePrescription{}
+
+	if err := c.ShouldBindJSON(&createPrescription); err != nil {
+		handleBindError(c, err)
+		return
+	}
+
+	if _, err := uuid.Parse(createPrescription.QueueID); err != nil {
+		handleResponse(c, "queue_id is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	createPrescription.DoctorID = getAuthInfo(c).UserID
+
+	prescription, err := h.services.Prescription().Create(c.Request.Context(), createPrescription)
+	if err != nil {
+		handleError(c, "error while creating prescription", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusCreated, prescription)
+}
+
+// GetPrescriptionByID godoc
+// @Router       /prescription/{id} [GET]
+// @Summary      Get prescription by id
+// @Description  Get prescription by id with its items and how many times they were redeemed
+// @Tags         prescription
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "prescription id"
+// @Success      200  {object}  models.Prescription
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetPrescriptionByID(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	prescription, err := h.services.Prescription().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id.String(),
+	})
+	if err != nil {
+		handleError(c, "error while get prescription by id", err)
+		return
+	}
+
+	if !checkPrescriptionAccess(c, prescription) {
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, prescription)
+}
+
+// GetPrescriptionByCode godoc
+// @Router       /prescription/code/{code} [GET]
+// @Summary      Get prescription by code
+// @Description  Pharmacist looks up a prescription by the code the customer brings
+// @Tags         prescription
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        code path string true "prescription code"
+// @Success      200  {object}  models.Prescription
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetPrescriptionByCode(c *gin.Context) {
+
+	prescription, err := h.services.Prescription().GetByCode(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		handleError(c, "error while get prescription by code", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, prescription)
+}
+
+// GetCustomerPrescriptions godoc
+// @Router       /customer/{id}/prescriptions [GET]
+// @Summary      Get prescriptions of a customer
+// @Description  Get prescriptions of a customer, newest first
+// @Tags         prescription
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "customer id"
+// @Success      200  {object}  models.PrescriptionsResponse
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) GetCustomerPrescriptions(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if authInfo := getAuthInfo(c); authInfo.UserRole == config.CustomerRole && authInfo.UserID != id.String() {
+		handleError(c, "forbidden", errs.Forbidden("customer can see only their own prescriptions"))
+		return
+	}
+
+	prescriptions, err := h.services.Prescription().GetByCustomer(c.Request.Context(), id.String())
+	if err != nil {
+		handleError(c, "error while getting customer prescriptions", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, prescriptions)
+}
+
+// RedeemPrescription godoc
+// @Router       /prescription/redeem [POST]
+// @Summary      Redeem a prescription
+// @Description  Pharmacist sells drugs of their branch for items of the prescription as one order, checked out like /orders/checkout. Each redeemed item sells at most its prescribed quantity, quantity defaults to it or to what is left of the prescription. A smaller sale keeps the rest for later, items left out stay available
+// @Tags         prescription
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        redeem body models.RedeemPrescription true "redeem data"
+// @Success      201  {object}  models.Orders
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response{data=[]models.CheckoutLineError}
+// @Failure      500  {object}  models.Response
+func (h Handler) RedeemPrescription(c *gin.Context) {
+	redeem := models.RedeemPrescription{}
+
+	if err := c.ShouldBindJSON(&redeem); err != nil {
+		handleBindError(c, err)
+		return
+	}
+
+	authInfo := getAuthInfo(c)
+	if authInfo.BranchID != redeem.DrugStoreBranchID {
+		handleError(c, "forbidden", errs.Forbidden("pharmacist can sell only drugs of their own branch"))
+		return
+	}
+	redeem.PharmacistID = authInfo.UserID
+
+	orders, err := h.services.Prescription().Redeem(c.Request.Context(), redeem)
+	if err != nil {
+		checkoutErr := storage.CheckoutError{}
+
+		if errors.As(err, &checkoutErr) {
+			handleResponse(c, "some drugs can not be sold", http.StatusConflict, checkoutErr.Lines)
+			return
+		}
+		handleError(c, "error while redeeming prescription", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusCreated, orders)
+}
+
+// DeletePrescription godoc
+// @Router       /prescription/{id} [DELETE]
+// @Summary      Delete prescription
+// @Description  Doctor withdraws their prescription, it can not be redeemed anymore
+// @Tags         prescription
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "prescription id"
+// @Success      200  {object}  models.Response
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) DeletePrescription(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	prescription, err := h.services.Prescription().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id.String(),
+	})
+	if err != nil {
+		handleError(c, "error while get prescription by id", err)
+		return
+	}
+
+	if !checkPrescriptionAccess(c, prescription) {
+		return
+	}
+
+	if err = h.services.Prescription().Delete(c.Request.Context(), id.String()); err != nil {
+		handleError(c, "error while deleting prescription by id", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, id)
+}
+
+// checkPrescriptionAccess lets doctors and customers reach only prescriptions they wrote or got
+func checkPrescriptionAccess(c *gin.Context, prescription models.Prescription) bool {
+	authInfo := getAuthInfo(c)
+
+	switch authInfo.UserRole {
+	case config.DoctorRole:
+		if prescription.DoctorID != authInfo.UserID {
+			handleError(c, "forbidden", errs.Forbidden("prescription was written by another doctor"))
+			return false
+		}
+
+	case config.CustomerRole:
+		if prescription.CustomerID != authInfo.UserID {
+			handleError(c, "forbidden", errs.Forbidden("prescription belongs to another customer"))
+			return false
+		}
+	}
+
+	return true
+}
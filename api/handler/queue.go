@@ -1,11 +1,15 @@
 package handler
 
 import (
-	"context"
 	"errors"
+	"io"
 	"net/http"
 	"shifolink/api/models"
-	"strconv"
+	"shifolink/config"
+	"shifolink/pkg/errs"
+	"shifolink/pkg/slot"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -14,33 +18,34 @@ import (
 // CreateQueue godoc
 // @Router       /queue [POST]
 // @Summary      Create a new Queue
-// @Description  Create a new Queue
+// @Description  Book a free slot of the doctor, start_time should be taken from GET /doctor/{id}/slots (YYYY-MM-DD HH:MM)
 // @Tags         queue
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        Queue body  models.CreateQueue true  "Queue data"
 // @Success      201  {object}  models.Queue
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) CreateQueue(c *gin.Context) {
 	createQueue := models.CreateQueue{}
 
 	if err := c.ShouldBindJSON(&createQueue); err != nil {
-		handleResponse(c, "error while reading body from client", http.StatusBadRequest, err)
+		handleBindError(c, err)
+		return
 	}
 
-	id, err := h.storage.Queue().Create(context.Background(), createQueue)
-	if err != nil {
-		handleResponse(c, "error while creating Queue ", http.StatusInternalServerError, err)
+	if authInfo := getAuthInfo(c); authInfo.UserRole == config.CustomerRole && authInfo.UserID != createQueue.CustomerID {
+		handleError(c, "forbidden", errs.Forbidden("customer can book a queue only for themselves"))
 		return
 	}
 
-	Queue, err := h.storage.Queue().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	Queue, err := h.services.Queue().Create(c.Request.Context(), createQueue)
 	if err != nil {
-		handleResponse(c, "error while get Queue ", http.StatusInternalServerError, err)
+		handleError(c, "error while creating Queue ", err)
 		return
 	}
 
@@ -53,11 +58,15 @@ func (h Handler) CreateQueue(c *gin.Context) {
 // @Summary      Get Queue by id
 // @Description  Get Queue by id
 // @Tags         queue
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "Queue"
 // @Success      200  {object}  models.Queue
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetQueueByID(c *gin.Context) {
@@ -72,14 +81,19 @@ func (h Handler) GetQueueByID(c *gin.Context) {
 		return
 	}
 
-	Queue, err := h.storage.Queue().Get(context.Background(), models.PrimaryKey{
+	Queue, err := h.services.Queue().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, "error while get Queue by id", http.StatusInternalServerError, err)
+		handleError(c, "error while get Queue by id", err)
+		return
+	}
+
+	if !h.checkQueueAccess(c, Queue) {
 		return
 	}
 
+	setETag(c, Queue.Version)
 	handleResponse(c, "", http.StatusOK, Queue)
 
 }
@@ -87,49 +101,74 @@ func (h Handler) GetQueueByID(c *gin.Context) {
 // GetQueuesList godoc
 // @Router       /queue [GET]
 // @Summary      Get Queues list
-// @Description  Get Queues list
+// @Description  Get Queues list. Customers get their own queues, doctors their own queues and clinic admins the queues of their branch
 // @Tags         queue
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
-// @Param        limit query string false "limit"
+// @Param        limit query string false "limit, 10 by default and at most 100"
 // @Param        search query string false "search"
+// @Param        created_from query string false "created at or after the date, YYYY-MM-DD"
+// @Param        created_to query string false "created at or before the date, YYYY-MM-DD"
+// @Param        sort_by query string false "created_at (default), start_time, queue_number, status"
+// @Param        order query string false "asc or desc, desc for the default sorting and asc otherwise"
+// @Param        cursor query string false "next_cursor of the previous page, used instead of page"
+// @Param        doctor_id query string false "doctor id"
+// @Param        customer_id query string false "customer id"
+// @Param        status query string false "booked, checked_in, called, in_consultation, completed, cancelled or no_show"
+// @Param        include_deleted query bool false "list soft deleted records too, super admin only"
 // @Success      200  {object}  models.QueuesResponse
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetQueuesList(c *gin.Context) {
 
-	var (
-		page, limit int
-		search      string
-		err         error
-	)
-
-	pageStr := c.DefaultQuery("page", "1")
-	page, err = strconv.Atoi(pageStr)
+	request, err := getListRequest(c)
 	if err != nil {
-		handleResponse(c, "error while parsing page ", http.StatusBadRequest, err.Error())
+		handleResponse(c, "error while parsing list request", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err = strconv.Atoi(limitStr)
+	if !readIncludeDeleted(c, &request) {
+		return
+	}
+	request.Cursor = c.Query("cursor")
+
+	ids, err := getUUIDQueries(c, "doctor_id", "customer_id")
 	if err != nil {
-		handleResponse(c, "error while parsing limit", http.StatusBadRequest, err.Error())
+		handleResponse(c, "error while parsing list filters", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	search = c.Query("search")
+	status := c.Query("status")
+	if status != "" && !isQueueStatus(status) {
+		handleResponse(c, "unknown queue status", http.StatusBadRequest, status)
+		return
+	}
 
-	response, err := h.storage.Queue().GetList(context.Background(), models.GetListRequest{
-		Page:   page,
-		Limit:  limit,
-		Search: search,
-	})
+	listRequest := models.GetQueuesListRequest{
+		GetListRequest: request,
+		DoctorID:       ids["doctor_id"],
+		CustomerID:     ids["customer_id"],
+		Status:         status,
+	}
+
+	switch authInfo := getAuthInfo(c); authInfo.UserRole {
+	case config.CustomerRole:
+		listRequest.CustomerID = authInfo.UserID
+	case config.DoctorRole:
+		listRequest.DoctorID = authInfo.UserID
+	case config.ClinicAdminRole:
+		listRequest.ClinicBranchID = authInfo.BranchID
+	}
+
+	response, err := h.services.Queue().GetList(c.Request.Context(), listRequest)
 
 	if err != nil {
-		handleResponse(c, "error while getting Queue ", http.StatusInternalServerError, err)
+		handleError(c, "error while getting Queue ", err)
 		return
 	}
 
@@ -139,16 +178,24 @@ func (h Handler) GetQueuesList(c *gin.Context) {
 
 // UpdateQueue godoc
 // @Router       /queue/{id} [PUT]
+// @Router       /queue/{id} [PATCH]
 // @Summary      Update Queue by id
-// @Description  Update Queue by id
+// @Description  Update Queue by id, only a booked queue can be changed. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields
 // @Tags         queue
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "Queue id"
 // @Param        Queue body models.UpdateQueue true "Queue"
+// @Param        If-Match header string false "ETag of the record, the update fails with 412 when the record was changed since"
 // @Success      200  {object}  models.Queue
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      412  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdateQueue(c *gin.Context) {
 	updateQueue := models.UpdateQueue{}
@@ -159,27 +206,38 @@ func (h Handler) UpdateQueue(c *gin.Context) {
 		return
 	}
 
-	//updateAuthor.ID = uid
+	Queue, err := h.services.Queue().Get(c.Request.Context(), models.PrimaryKey{ID: uid})
+	if err != nil {
+		handleError(c, "error while getting Queue by id", err)
+		return
+	}
 
-	if err := c.ShouldBindJSON(&updateQueue); err != nil {
-		handleResponse(c, "error while reading body", http.StatusBadRequest, err.Error())
+	if !h.checkQueueAccess(c, Queue) {
 		return
 	}
 
-	id, err := h.storage.Queue().Update(context.Background(), updateQueue)
-	if err != nil {
-		handleResponse(c, "error while updating Queue ", http.StatusInternalServerError, err.Error())
+	if !bindUpdate(c, &updateQueue, func() (interface{}, error) {
+		return Queue, nil
+	}) {
 		return
 	}
 
-	Queue, err := h.storage.Queue().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	updateQueue.ID = uid
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	updateQueue.Version = version
+
+	Queue, err = h.services.Queue().Update(c.Request.Context(), updateQueue)
 	if err != nil {
-		handleResponse(c, "error while getting Queue by id", http.StatusInternalServerError, err)
+		handleError(c, "error while updating Queue ", err)
 		return
 	}
 
+	setETag(c, Queue.Version)
 	handleResponse(c, "", http.StatusOK, Queue)
 
 }
@@ -189,11 +247,14 @@ func (h Handler) UpdateQueue(c *gin.Context) {
 // @Summary      Delete Queue
 // @Description  Delete Queue
 // @Tags         queue
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "Queue id"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) DeleteQueue(c *gin.Context) {
@@ -205,11 +266,331 @@ func (h Handler) DeleteQueue(c *gin.Context) {
 		return
 	}
 
-	if err := h.storage.Queue().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, "error while deleting Queue  by id", http.StatusInternalServerError, err.Error())
+	queue, err := h.services.Queue().Get(c.Request.Context(), models.PrimaryKey{ID: id.String()})
+	if err != nil {
+		handleError(c, "error while getting Queue by id", err)
+		return
+	}
+
+	if !h.checkQueueAccess(c, queue) {
+		return
+	}
+
+	if err = h.services.Queue().Delete(c.Request.Context(), id.String()); err != nil {
+		handleError(c, "error while deleting Queue  by id", err)
 		return
 	}
 
 	handleResponse(c, "", http.StatusOK, "data succesfully deleted")
 
 }
+
+// CheckInQueue godoc
+// @Router       /queue/{id}/check_in [POST]
+// @Summary      Check in to the queue
+// @Description  Customer arrived to the clinic, booked -> checked_in
+// @Tags         queue
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "queue id"
+// @Success      200  {object}  models.Queue
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) CheckInQueue(c *gin.Context) {
+	h.changeQueueStatus(c, config.QueueCheckedIn)
+}
+
+// CallQueue godoc
+// @Router       /queue/{id}/call [POST]
+// @Summary      Call the customer
+// @Description  Doctor calls the customer, checked_in -> called
+// @Tags         queue
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "queue id"
+// @Success      200  {object}  models.Queue
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) CallQueue(c *gin.Context) {
+	h.changeQueueStatus(c, config.QueueCalled)
+}
+
+// StartQueue godoc
+// @Router       /queue/{id}/start [POST]
+// @Summary      Start consultation
+// @Description  Consultation started, called -> in_consultation, doctor becomes busy
+// @Tags         queue
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "queue id"
+// @Success      200  {object}  models.Queue
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) StartQueue(c *gin.Context) {
+	h.changeQueueStatus(c, config.QueueInConsultation)
+}
+
+// CompleteQueue godoc
+// @Router       /queue/{id}/complete [POST]
+// @Summary      Complete consultation
+// @Description  Consultation finished, in_consultation -> completed, doctor becomes empty
+// @Tags         queue
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "queue id"
+// @Success      200  {object}  models.Queue
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) CompleteQueue(c *gin.Context) {
+	h.changeQueueStatus(c, config.QueueCompleted)
+}
+
+// CancelQueue godoc
+// @Router       /queue/{id}/cancel [POST]
+// @Summary      Cancel the queue
+// @Description  Cancel a booked or checked in queue, the slot becomes free again
+// @Tags         queue
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "queue id"
+// @Success      200  {object}  models.Queue
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) CancelQueue(c *gin.Context) {
+	h.changeQueueStatus(c, config.QueueCancelled)
+}
+
+// NoShowQueue godoc
+// @Router       /queue/{id}/no_show [POST]
+// @Summary      Mark the queue as no-show
+// @Description  Customer did not come, booked/checked_in/called -> no_show
+// @Tags         queue
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "queue id"
+// @Success      200  {object}  models.Queue
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) NoShowQueue(c *gin.Context) {
+	h.changeQueueStatus(c, config.QueueNoShow)
+}
+
+func (h Handler) changeQueueStatus(c *gin.Context, status string) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	queue, err := h.services.Queue().Get(c.Request.Context(), models.PrimaryKey{
+		ID: id.String(),
+	})
+	if err != nil {
+		handleError(c, "error while getting queue by id", err)
+		return
+	}
+
+	if !h.checkQueueAccess(c, queue) {
+		return
+	}
+
+	queue, err = h.services.Queue().ChangeStatus(c.Request.Context(), id.String(), status)
+	if err != nil {
+		handleError(c, "error while changing queue status", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, queue)
+}
+
+// checkQueueAccess lets customers and doctors see and change only their own queues
+// and clinic admins only queues of their branch doctors
+func (h Handler) checkQueueAccess(c *gin.Context, queue models.Queue) bool {
+	authInfo := getAuthInfo(c)
+
+	switch authInfo.UserRole {
+	case config.CustomerRole:
+		if queue.CustomerID != authInfo.UserID {
+			handleError(c, "forbidden", errs.Forbidden("queue belongs to another customer"))
+			return false
+		}
+
+	case config.DoctorRole:
+		if queue.DoctorID != authInfo.UserID {
+			handleError(c, "forbidden", errs.Forbidden("queue belongs to another doctor"))
+			return false
+		}
+
+	case config.ClinicAdminRole:
+		return h.checkDoctorBranch(c, queue.DoctorID)
+	}
+
+	return true
+}
+
+// boardPingInterval keeps idle board connections alive through proxies
+const boardPingInterval = 30 * time.Second
+
+// QueueBoard godoc
+// @Router       /clinic_branch/{id}/queue_board [GET]
+// @Summary      Live queue board of a clinic branch
+// @Description  Server-Sent Events stream for waiting room screens. The first "queue" event is a snapshot of the day, next ones are created/updated/deleted deltas keyed by queue_id. A reconnecting client gets a fresh snapshot
+// @Tags         queue
+// @Produce      text/event-stream
+// @Param        id path string true "clinic branch id"
+// @Param        date query string false "date in YYYY-MM-DD format, today by default"
+// @Success      200  {object}  models.QueueBoardEvent
+// @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) QueueBoard(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "invalid uuid type ", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	date := c.Query("date")
+	if date == "" {
+		date = slot.Now().Format(slot.DateLayout)
+	}
+
+	if _, err = time.Parse(slot.DateLayout, date); err != nil {
+		handleResponse(c, "date should be in YYYY-MM-DD format", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	messages, unsubscribe := h.services.Queue().SubscribeBoard(id.String())
+	defer unsubscribe()
+
+	items, err := h.services.Queue().GetBoard(c.Request.Context(), id.String(), date)
+	if err != nil {
+		handleError(c, "error while getting queue board", err)
+		return
+	}
+
+	// the board stays open much longer than the write timeout of the server allows ordinary requests
+	if err = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		handleResponse(c, "error while opening queue board", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.SSEvent("queue", models.QueueBoardEvent{
+		Type:  config.BoardSnapshot,
+		Items: items,
+	})
+	c.Writer.Flush()
+
+	ping := time.NewTicker(boardPingInterval)
+	defer ping.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+
+		case <-ping.C:
+			c.SSEvent("ping", "")
+			return true
+
+		case message, ok := <-messages:
+			// the broker closes lagging subscriptions, the client reconnects and gets a new snapshot
+			if !ok {
+				return false
+			}
+
+			event, ok := message.Data.(models.QueueBoardEvent)
+			if !ok {
+				return true
+			}
+
+			dayItems := []models.QueueBoardItem{}
+			for _, item := range event.Items {
+				if strings.HasPrefix(item.StartTime, date) {
+					dayItems = append(dayItems, item)
+				}
+			}
+
+			if len(dayItems) > 0 {
+				c.SSEvent("queue", models.QueueBoardEvent{
+					Type:  event.Type,
+					Items: dayItems,
+				})
+			}
+			return true
+		}
+	})
+}
+
+func isQueueStatus(status string) bool {
+	switch status {
+	case config.QueueBooked, config.QueueCheckedIn, config.QueueCalled, config.QueueInConsultation,
+		config.QueueCompleted, config.QueueCancelled, config.QueueNoShow:
+		return true
+	}
+
+	return false
+}
+
+// RestoreQueue godoc
+// @Router       /queue/{id}/restore [POST]
+// @Summary      Restore queue
+// @Description  Restore a soft deleted queue
+// @Tags         queue
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "queue id"
+// @Success      200  {object}  models.Queue
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) RestoreQueue(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	queue, err := h.services.Queue().Restore(c.Request.Context(), id.String())
+	if err != nil {
+		handleError(c, "error while restoring queue", err)
+		return
+	}
+
+	handleResponse(c, "", http.StatusOK, queue)
+}
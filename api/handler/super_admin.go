@@ -1,12 +1,10 @@
 package handler
 
 import (
-	"context"
 	"errors"
 	"net/http"
 	"shifolink/api/models"
-	"shifolink/pkg/check"
-	"strconv"
+	"shifolink/config"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -17,31 +15,26 @@ import (
 // @Summary      Create a new SuperAdmin
 // @Description  Create a new SuperAdmin
 // @Tags         super_admin
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        SuperAdmin body  models.CreateSuperAdmin true  "SuperAdmin data"
 // @Success      201  {object}  models.SuperAdmin
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) CreateSuperAdmin(c *gin.Context) {
 	createSuperAdmin := models.CreateSuperAdmin{}
 
 	if err := c.ShouldBindJSON(&createSuperAdmin); err != nil {
-		handleResponse(c, "error while reading body from client", http.StatusBadRequest, err)
-	}
-
-	id, err := h.storage.SuperAdmin().Create(context.Background(), createSuperAdmin)
-	if err != nil {
-		handleResponse(c, "error while creating SuperAdmin ", http.StatusInternalServerError, err)
+		handleBindError(c, err)
 		return
 	}
 
-	SuperAdmin, err := h.storage.SuperAdmin().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	SuperAdmin, err := h.services.SuperAdmin().Create(c.Request.Context(), createSuperAdmin)
 	if err != nil {
-		handleResponse(c, "error while get SuperAdmin ", http.StatusInternalServerError, err)
+		handleError(c, "error while creating SuperAdmin ", err)
 		return
 	}
 
@@ -54,11 +47,14 @@ func (h Handler) CreateSuperAdmin(c *gin.Context) {
 // @Summary      Get SuperAdmin by id
 // @Description  Get SuperAdmin by id
 // @Tags         super_admin
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "SuperAdmin"
 // @Success      200  {object}  models.SuperAdmin
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetSuperAdminByID(c *gin.Context) {
@@ -73,14 +69,15 @@ func (h Handler) GetSuperAdminByID(c *gin.Context) {
 		return
 	}
 
-	SuperAdmin, err := h.storage.SuperAdmin().Get(context.Background(), models.PrimaryKey{
+	SuperAdmin, err := h.services.SuperAdmin().Get(c.Request.Context(), models.PrimaryKey{
 		ID: id.String(),
 	})
 	if err != nil {
-		handleResponse(c, "error while get SuperAdmin by id", http.StatusInternalServerError, err)
+		handleError(c, "error while get SuperAdmin by id", err)
 		return
 	}
 
+	setETag(c, SuperAdmin.Version)
 	handleResponse(c, "", http.StatusOK, SuperAdmin)
 
 }
@@ -90,47 +87,39 @@ func (h Handler) GetSuperAdminByID(c *gin.Context) {
 // @Summary      Get SuperAdmins list
 // @Description  Get SuperAdmins list
 // @Tags         super_admin
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        page query string false "page"
-// @Param        limit query string false "limit"
+// @Param        limit query string false "limit, 10 by default and at most 100"
 // @Param        search query string false "search"
+// @Param        created_from query string false "created at or after the date, YYYY-MM-DD"
+// @Param        created_to query string false "created at or before the date, YYYY-MM-DD"
+// @Param        sort_by query string false "created_at (default), first_name, last_name"
+// @Param        order query string false "asc or desc, desc for the default sorting and asc otherwise"
+// @Param        include_deleted query bool false "list soft deleted records too, super admin only"
 // @Success      200  {object}  models.SuperAdminsResponse
 // @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) GetSuperAdminsList(c *gin.Context) {
 
-	var (
-		page, limit int
-		search      string
-		err         error
-	)
-
-	pageStr := c.DefaultQuery("page", "1")
-	page, err = strconv.Atoi(pageStr)
+	request, err := getListRequest(c)
 	if err != nil {
-		handleResponse(c, "error while parsing page ", http.StatusBadRequest, err.Error())
+		handleResponse(c, "error while parsing list request", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err = strconv.Atoi(limitStr)
-	if err != nil {
-		handleResponse(c, "error while parsing limit", http.StatusBadRequest, err.Error())
+	if !readIncludeDeleted(c, &request) {
 		return
 	}
 
-	search = c.Query("search")
-
-	response, err := h.storage.SuperAdmin().GetList(context.Background(), models.GetListRequest{
-		Page:   page,
-		Limit:  limit,
-		Search: search,
-	})
+	response, err := h.services.SuperAdmin().GetList(c.Request.Context(), request)
 
 	if err != nil {
-		handleResponse(c, "error while getting SuperAdmin ", http.StatusInternalServerError, err)
+		handleError(c, "error while getting SuperAdmin ", err)
 		return
 	}
 
@@ -140,16 +129,22 @@ func (h Handler) GetSuperAdminsList(c *gin.Context) {
 
 // UpdateSuperAdmin godoc
 // @Router       /super_admin/{id} [PUT]
+// @Router       /super_admin/{id} [PATCH]
 // @Summary      Update SuperAdmin by id
-// @Description  Update SuperAdmin by id
+// @Description  Update SuperAdmin by id. PUT replaces every field, PATCH takes a JSON merge patch and changes only the given fields
 // @Tags         super_admin
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "SuperAdmin id"
 // @Param        SuperAdmin body models.UpdateSuperAdmin true "SuperAdmin"
+// @Param        If-Match header string false "ETag of the record, the update fails with 412 when the record was changed since"
 // @Success      200  {object}  models.SuperAdmin
+// @Header       200  {string}  ETag  "version of the record, send it in If-Match to update the record"
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
+// @Failure      412  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdateSuperAdmin(c *gin.Context) {
 	updateSuperAdmin := models.UpdateSuperAdmin{}
@@ -160,27 +155,28 @@ func (h Handler) UpdateSuperAdmin(c *gin.Context) {
 		return
 	}
 
-	//updateAuthor.ID = uid
-
-	if err := c.ShouldBindJSON(&updateSuperAdmin); err != nil {
-		handleResponse(c, "error while reading body", http.StatusBadRequest, err.Error())
+	if !bindUpdate(c, &updateSuperAdmin, func() (interface{}, error) {
+		return h.services.SuperAdmin().Get(c.Request.Context(), models.PrimaryKey{ID: uid})
+	}) {
 		return
 	}
 
-	id, err := h.storage.SuperAdmin().Update(context.Background(), updateSuperAdmin)
-	if err != nil {
-		handleResponse(c, "error while updating SuperAdmin ", http.StatusInternalServerError, err.Error())
+	updateSuperAdmin.ID = uid
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
-	SuperAdmin, err := h.storage.SuperAdmin().Get(context.Background(), models.PrimaryKey{
-		ID: id,
-	})
+	updateSuperAdmin.Version = version
+
+	SuperAdmin, err := h.services.SuperAdmin().Update(c.Request.Context(), updateSuperAdmin)
 	if err != nil {
-		handleResponse(c, "error while getting SuperAdmin by id", http.StatusInternalServerError, err)
+		handleError(c, "error while updating SuperAdmin ", err)
 		return
 	}
 
+	setETag(c, SuperAdmin.Version)
 	handleResponse(c, "", http.StatusOK, SuperAdmin)
 
 }
@@ -190,11 +186,13 @@ func (h Handler) UpdateSuperAdmin(c *gin.Context) {
 // @Summary      Delete SuperAdmin
 // @Description  Delete SuperAdmin
 // @Tags         super_admin
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "SuperAdmin id"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) DeleteSuperAdmin(c *gin.Context) {
@@ -206,8 +204,8 @@ func (h Handler) DeleteSuperAdmin(c *gin.Context) {
 		return
 	}
 
-	if err := h.storage.SuperAdmin().Delete(context.Background(), id.String()); err != nil {
-		handleResponse(c, "error while deleting SuperAdmin  by id", http.StatusInternalServerError, err.Error())
+	if err := h.services.SuperAdmin().Delete(c.Request.Context(), id.String()); err != nil {
+		handleError(c, "error while deleting SuperAdmin  by id", err)
 		return
 	}
 
@@ -216,23 +214,25 @@ func (h Handler) DeleteSuperAdmin(c *gin.Context) {
 }
 
 // UpdateSuperAdminPassword godoc
-// @Router       /super_admin/{id} [PATCH]
+// @Router       /super_admin/{id}/password [PATCH]
 // @Summary      Update super_admin password
 // @Description  update super_admin password
 // @Tags         super_admin
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param 		 id path string true "super_admin"
 // @Param        super_admin body models.UpdateSuperAdminPassword true "super_admin"
 // @Success      200  {object}  models.Response
 // @Failure      400  {object}  models.Response
+// @Failure      422  {object}  models.Response
 // @Failure      404  {object}  models.Response
 // @Failure      500  {object}  models.Response
 func (h Handler) UpdateSuperAdminPassword(c *gin.Context) {
 	updateSuperAdminPassword := models.UpdateSuperAdminPassword{}
 
 	if err := c.ShouldBindJSON(&updateSuperAdminPassword); err != nil {
-		handleResponse(c, "error while reading body", http.StatusBadRequest, err.Error())
+		handleBindError(c, err)
 		return
 	}
 
@@ -244,26 +244,46 @@ func (h Handler) UpdateSuperAdminPassword(c *gin.Context) {
 
 	updateSuperAdminPassword.ID = uid.String()
 
-	oldPassword, err := h.storage.SuperAdmin().GetPassword(c, updateSuperAdminPassword.ID)
-	if err != nil {
-		handleResponse(c, "error while getting password by id", http.StatusInternalServerError, err.Error())
+	if !checkSelf(c, config.SuperAdminRole, updateSuperAdminPassword.ID) {
 		return
 	}
 
-	if oldPassword != updateSuperAdminPassword.OldPassword {
-		handleResponse(c, "old password is not correct", http.StatusBadRequest, "old password is not correct")
+	if err = h.services.SuperAdmin().UpdatePassword(c.Request.Context(), updateSuperAdminPassword); err != nil {
+		handleError(c, "error while updating super_admin password", err)
 		return
 	}
 
-	if err = check.ValidatePassword(updateSuperAdminPassword.NewPassword); err != nil {
-		handleResponse(c, "new password is weak", http.StatusBadRequest, err.Error())
+	handleResponse(c, "", http.StatusOK, "password successfully updated")
+}
+
+// RestoreSuperAdmin godoc
+// @Router       /super_admin/{id}/restore [POST]
+// @Summary      Restore super admin
+// @Description  Restore a soft deleted super admin
+// @Tags         super_admin
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "super admin id"
+// @Success      200  {object}  models.SuperAdmin
+// @Failure      400  {object}  models.Response
+// @Failure      403  {object}  models.Response
+// @Failure      404  {object}  models.Response
+// @Failure      409  {object}  models.Response
+// @Failure      500  {object}  models.Response
+func (h Handler) RestoreSuperAdmin(c *gin.Context) {
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		handleResponse(c, "uuid is not valid", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if err = h.storage.SuperAdmin().UpdatePassword(context.Background(), updateSuperAdminPassword); err != nil {
-		handleResponse(c, "error while updating super_admin password", http.StatusInternalServerError, err.Error())
+	superAdmin, err := h.services.SuperAdmin().Restore(c.Request.Context(), id.String())
+	if err != nil {
+		handleError(c, "error while restoring super admin", err)
 		return
 	}
 
-	handleResponse(c, "", http.StatusOK, "password successfully updated")
+	handleResponse(c, "", http.StatusOK, superAdmin)
 }
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"shifolink/pkg/check"
+	"shifolink/pkg/errs"
+	"shifolink/pkg/money"
+	"shifolink/pkg/patch"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// registerValidators adds the rules binding tags of the models use besides the validator's own and makes
+// errors name fields by their json names
+func registerValidators() {
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	validate.RegisterValidation("uz_phone", func(fl validator.FieldLevel) bool {
+		return check.IsUzPhone(fl.Field().String())
+	})
+
+	validate.RegisterValidation("money", func(fl validator.FieldLevel) bool {
+		amount, err := money.Parse(fl.Field().String())
+		return err == nil && amount >= 0
+	})
+}
+
+// handleBindError answers 422 with every invalid field of the body, a body which can not be read into
+// the model at all is a 400
+func handleBindError(c *gin.Context, err error) {
+	validationErrs := validator.ValidationErrors{}
+	if !errors.As(err, &validationErrs) {
+		handleResponse(c, "error while reading body from client", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	fields := make(map[string]string, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		// the namespace starts with the model name, e.g. CheckoutOrder.items[0].quantity
+		_, field, _ := strings.Cut(fieldErr.Namespace(), ".")
+		fields[field] = fieldMessage(fieldErr)
+	}
+
+	handleError(c, "request is not valid", &errs.Error{
+		Kind:    errs.KindValidation,
+		Message: "request is not valid",
+		Fields:  fields,
+	})
+}
+
+// bindUpdate reads the body of an update route. PUT replaces the whole record, PATCH is a JSON merge patch
+// applied over the current record, so fields which are not given keep their values. The merged record is
+// validated like a PUT body. The version of the record a PATCH was merged into is kept for ifMatchVersion,
+// so the patch fails instead of overwriting a change made after it was read.
+// It answers the client itself and returns false when the request can't go on
+func bindUpdate(c *gin.Context, request interface{}, current func() (interface{}, error)) bool {
+	if c.Request.Method != http.MethodPatch {
+		if err := c.ShouldBindJSON(request); err != nil {
+			handleBindError(c, err)
+			return false
+		}
+		return true
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		handleResponse(c, "error while reading body from client", http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	record, err := current()
+	if err != nil {
+		handleError(c, "error while getting record", err)
+		return false
+	}
+
+	document, err := json.Marshal(record)
+	if err != nil {
+		handleError(c, "error while merging patch", err)
+		return false
+	}
+
+	read := struct {
+		Version int `json:"version"`
+	}{}
+	if err = json.Unmarshal(document, &read); err != nil {
+		handleError(c, "error while merging patch", err)
+		return false
+	}
+	c.Set(readVersionKey, read.Version)
+
+	merged, err := patch.Merge(document, body)
+	if errors.Is(err, patch.ErrNotObject) {
+		handleResponse(c, "error while reading body from client", http.StatusBadRequest, err.Error())
+		return false
+	}
+	if err != nil {
+		handleError(c, "error while merging patch", err)
+		return false
+	}
+
+	if err = json.Unmarshal(merged, request); err != nil {
+		handleBindError(c, err)
+		return false
+	}
+
+	if err = binding.Validator.ValidateStruct(request); err != nil {
+		handleBindError(c, err)
+		return false
+	}
+
+	return true
+}
+
+func fieldMessage(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "should be a valid email"
+	case "uuid":
+		return "should be a valid uuid"
+	case "uz_phone":
+		return "should be an Uzbek phone number like +998901234567"
+	case "money":
+		return "should be a non negative amount with at most 2 fractional digits"
+	case "oneof":
+		return "should be one of: " + strings.ReplaceAll(fieldErr.Param(), " ", ", ")
+	case "datetime":
+		return fmt.Sprintf("should be in %q format", fieldErr.Param())
+	case "min", "gte":
+		return limitMessage(fieldErr, "at least")
+	case "max", "lte":
+		return limitMessage(fieldErr, "at most")
+	case "gt":
+		return "should be more than " + fieldErr.Param()
+	}
+
+	return "is not valid (" + fieldErr.Tag() + ")"
+}
+
+func limitMessage(fieldErr validator.FieldError, limit string) string {
+	switch fieldErr.Kind() {
+	case reflect.String:
+		return fmt.Sprintf("should be %s %s characters long", limit, fieldErr.Param())
+	case reflect.Slice:
+		return fmt.Sprintf("should have %s %s items", limit, fieldErr.Param())
+	}
+
+	return fmt.Sprintf("should be %s %s", limit, fieldErr.Param())
+}
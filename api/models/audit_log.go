@@ -0,0 +1,46 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditLog is one change made through the api. Before and after hold only the changed fields, before
+// is empty for created records and after for deleted ones
+type AuditLog struct {
+	ID        string          `json:"id"`
+	ActorID   string          `json:"actor_id"`
+	ActorRole string          `json:"actor_role"`
+	Entity    string          `json:"entity"`
+	EntityID  string          `json:"entity_id"`
+	Action    string          `json:"action"`
+	RequestID string          `json:"request_id"`
+	Before    json.RawMessage `json:"before" swaggertype:"object"`
+	After     json.RawMessage `json:"after" swaggertype:"object"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type CreateAuditLog struct {
+	ActorID   string
+	ActorRole string
+	Entity    string
+	EntityID  string
+	Action    string
+	RequestID string
+	Before    json.RawMessage
+	After     json.RawMessage
+}
+
+type GetAuditLogsListRequest struct {
+	GetListRequest
+	Entity   string `json:"entity"`
+	EntityID string `json:"entity_id"`
+	ActorID  string `json:"actor_id"`
+	Action   string `json:"action"`
+}
+
+type AuditLogsResponse struct {
+	AuditLogs  []AuditLog `json:"audit_logs"`
+	Count      int        `json:"count"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
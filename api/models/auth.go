@@ -0,0 +1,23 @@
+package models
+
+type LoginRequest struct {
+	Login    string `json:"login"`
+	Password string `json:"password"`
+}
+
+type LoginResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	UserID       string `json:"user_id"`
+	UserRole     string `json:"user_role"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type AuthInfo struct {
+	UserID   string `json:"user_id"`
+	UserRole string `json:"user_role"`
+	BranchID string `json:"branch_id"`
+}
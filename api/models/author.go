@@ -7,41 +7,43 @@ type Author struct {
 	FirstName string    `json:"first_name"`
 	LastName  string    `json:"last_name"`
 	Email     string    `json:"email"`
-	Password  string    `json:"password"`
 	Phone     string    `json:"phone"`
 	Gender    string    `json:"gender"`
 	BirthDate string    `json:"birth_date"`
 	Age       int       `json:"age"`
 	Address   string    `json:"address"`
+	Version   int       `json:"version"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	DeletedAt time.Time `json:"deleted_at"`
 }
 
 type CreateAuthor struct {
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Email     string `json:"email"`
-	Password  string `json:"password"`
-	Phone     string `json:"phone"`
-	Gender    string `json:"gender"`
-	BirthDate string `json:"birth_date"`
-	Address   string `json:"address"`
+	FirstName string `json:"first_name" binding:"required,max=25"`
+	LastName  string `json:"last_name" binding:"required,max=25"`
+	Email     string `json:"email" binding:"required,email,max=50"`
+	Password  string `json:"password" binding:"required,min=8"`
+	Phone     string `json:"phone" binding:"required,uz_phone"`
+	Gender    string `json:"gender" binding:"required,oneof=male female"`
+	BirthDate string `json:"birth_date" binding:"required,datetime=2006-01-02"`
+	Age       int    `json:"-"`
+	Address   string `json:"address" binding:"required,max=120"`
 }
 
 type UpdateAuthor struct {
 	ID        string `json:"id"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Email     string `json:"email"`
-	Phone     string `json:"phone"`
-	Address   string `json:"address"`
+	FirstName string `json:"first_name" binding:"required,max=25"`
+	LastName  string `json:"last_name" binding:"required,max=25"`
+	Email     string `json:"email" binding:"required,email,max=50"`
+	Phone     string `json:"phone" binding:"required,uz_phone"`
+	Address   string `json:"address" binding:"required,max=120"`
+	Version   int    `json:"-"`
 }
 
 type UpdateAuthorPassword struct {
 	ID          string `json:"id"`
-	NewPassword string `json:"new_password"`
-	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password" binding:"required,min=8"`
+	OldPassword string `json:"old_password" binding:"required"`
 }
 
 type AuthorsResponse struct {
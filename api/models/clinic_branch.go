@@ -8,24 +8,31 @@ type ClinicBranch struct {
 	Address     string    `json:"address"`
 	Phone       string    `json:"phone"`
 	WorkingTime string    `json:"working_time"`
+	Version     int       `json:"version"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	DeletedAt   time.Time `json:"deleted_at"`
 }
 
 type CreateClinicBranch struct {
-	ClinicID    string `json:"clinic_id"`
-	Address     string `json:"address"`
-	Phone       string `json:"phone"`
-	WorkingTime string `json:"working_time"`
+	ClinicID    string `json:"clinic_id" binding:"required,uuid"`
+	Address     string `json:"address" binding:"required,max=50"`
+	Phone       string `json:"phone" binding:"required,uz_phone"`
+	WorkingTime string `json:"working_time" binding:"required,max=100"`
 }
 
 type UpdateClinicBranch struct {
 	ID          string `json:"id"`
-	ClinicID    string `json:"clinic_id"`
-	Address     string `json:"address"`
-	Phone       string `json:"phone"`
-	WorkingTime string `json:"working_time"`
+	ClinicID    string `json:"clinic_id" binding:"required,uuid"`
+	Address     string `json:"address" binding:"required,max=50"`
+	Phone       string `json:"phone" binding:"required,uz_phone"`
+	WorkingTime string `json:"working_time" binding:"required,max=100"`
+	Version     int    `json:"-"`
+}
+
+type GetClinicBranchsListRequest struct {
+	GetListRequest
+	ClinicID string `json:"clinic_id"`
 }
 
 type ClinicBranchsResponse struct {
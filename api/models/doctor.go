@@ -8,7 +8,6 @@ type Doctor struct {
 	FirstName    string    `json:"first_name"`
 	LastName     string    `json:"last_name"`
 	Email        string    `json:"email"`
-	Password     string    `json:"password"`
 	Phone        string    `json:"phone"`
 	Gender       string    `json:"gender"`
 	BirthDate    string    `json:"birth_date"`
@@ -16,41 +15,53 @@ type Doctor struct {
 	Address      string    `json:"address"`
 	WorkingTime  string    `json:"working_time"`
 	Status       string    `json:"status"`
+	Version      int       `json:"version"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 	DeletedAt    time.Time `json:"deleted_at"`
 }
 
 type CreateDoctor struct {
-	DoctorTypeID string `json:"doctor_type_id"`
-	FirstName    string `json:"first_name"`
-	LastName     string `json:"last_name"`
-	Email        string `json:"email"`
-	Password     string `json:"password"`
-	Phone        string `json:"phone"`
-	Gender       string `json:"gender"`
-	BirthDate    string `json:"birth_date"`
-	Address      string `json:"address"`
-	WorkingTime  string `json:"working_time"`
-	Status       string `json:"status"`
+	DoctorTypeID string `json:"doctor_type_id" binding:"required,uuid"`
+	FirstName    string `json:"first_name" binding:"required,max=25"`
+	LastName     string `json:"last_name" binding:"required,max=25"`
+	Email        string `json:"email" binding:"required,email,max=50"`
+	Password     string `json:"password" binding:"required,min=8"`
+	Phone        string `json:"phone" binding:"required,uz_phone"`
+	Gender       string `json:"gender" binding:"required,oneof=male female"`
+	BirthDate    string `json:"birth_date" binding:"required,datetime=2006-01-02"`
+	Age          int    `json:"-"`
+	Address      string `json:"address" binding:"required,max=120"`
+	WorkingTime  string `json:"working_time" binding:"required,max=100"`
+	// Status is empty for a new doctor when it is omitted
+	Status string `json:"status" binding:"omitempty,oneof=busy empty"`
 }
 
 type UpdateDoctor struct {
 	ID           string `json:"id"`
-	DoctorTypeID string `json:"doctor_type_id"`
-	FirstName    string `json:"first_name"`
-	LastName     string `json:"last_name"`
-	Email        string `json:"email"`
-	Phone        string `json:"phone"`
-	Address      string `json:"address"`
-	WorkingTime  string `json:"working_time"`
-	Status       string `json:"status"`
+	DoctorTypeID string `json:"doctor_type_id" binding:"required,uuid"`
+	FirstName    string `json:"first_name" binding:"required,max=25"`
+	LastName     string `json:"last_name" binding:"required,max=25"`
+	Email        string `json:"email" binding:"required,email,max=50"`
+	Phone        string `json:"phone" binding:"required,uz_phone"`
+	Address      string `json:"address" binding:"required,max=120"`
+	WorkingTime  string `json:"working_time" binding:"required,max=100"`
+	// Status keeps the stored one when it is omitted
+	Status  string `json:"status" binding:"omitempty,oneof=busy empty"`
+	Version int    `json:"-"`
 }
 
 type UpdateDoctorPassword struct {
 	ID          string `json:"-"`
-	NewPassword string `json:"new_password"`
-	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password" binding:"required,min=8"`
+	OldPassword string `json:"old_password" binding:"required"`
+}
+
+type GetDoctorsListRequest struct {
+	GetListRequest
+	DoctorTypeID   string `json:"doctor_type_id"`
+	ClinicBranchID string `json:"clinic_branch_id"`
+	Status         string `json:"status"`
 }
 
 type DoctorsResponse struct {
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+type DoctorSchedule struct {
+	ID           string    `json:"id"`
+	DoctorID     string    `json:"doctor_id"`
+	Weekday      int       `json:"weekday"`
+	StartTime    string    `json:"start_time"`
+	EndTime      string    `json:"end_time"`
+	BreakStart   string    `json:"break_start"`
+	BreakEnd     string    `json:"break_end"`
+	SlotDuration int       `json:"slot_duration"`
+	Version      int       `json:"version"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	DeletedAt    time.Time `json:"deleted_at"`
+}
+
+type CreateDoctorSchedule struct {
+	DoctorID     string `json:"doctor_id" binding:"required,uuid"`
+	Weekday      int    `json:"weekday" binding:"gte=0,lte=6"`
+	StartTime    string `json:"start_time" binding:"required,datetime=15:04"`
+	EndTime      string `json:"end_time" binding:"required,datetime=15:04"`
+	BreakStart   string `json:"break_start" binding:"omitempty,datetime=15:04"`
+	BreakEnd     string `json:"break_end" binding:"omitempty,datetime=15:04"`
+	SlotDuration int    `json:"slot_duration" binding:"gt=0"`
+}
+
+type UpdateDoctorSchedule struct {
+	ID           string `json:"-"`
+	StartTime    string `json:"start_time" binding:"required,datetime=15:04"`
+	EndTime      string `json:"end_time" binding:"required,datetime=15:04"`
+	BreakStart   string `json:"break_start" binding:"omitempty,datetime=15:04"`
+	BreakEnd     string `json:"break_end" binding:"omitempty,datetime=15:04"`
+	SlotDuration int    `json:"slot_duration" binding:"gt=0"`
+	Version      int    `json:"-"`
+}
+
+type DoctorSchedulesResponse struct {
+	DoctorSchedules []DoctorSchedule `json:"doctor_schedules"`
+	Count           int              `json:"count"`
+}
+
+type Slot struct {
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+type DoctorSlots struct {
+	DoctorID string `json:"doctor_id"`
+	Date     string `json:"date"`
+	Slots    []Slot `json:"slots"`
+}
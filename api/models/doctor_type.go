@@ -7,21 +7,28 @@ type DoctorType struct {
 	Name           string    `json:"name"`
 	Description    string    `json:"description"`
 	ClinicBranchID string    `json:"clinic_branch_id"`
+	Version        int       `json:"version"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
 	DeletedAt      time.Time `json:"deleted_at"`
 }
 
 type CreateDoctorType struct {
-	Name           string `json:"name"`
+	Name           string `json:"name" binding:"required,max=50"`
 	Description    string `json:"description"`
-	ClinicBranchID string `json:"clinic_branch_id"`
+	ClinicBranchID string `json:"clinic_branch_id" binding:"required,uuid"`
 }
 
 type UpdateDoctorType struct {
 	ID             string    `json:"id"`
-	Name           string `json:"name"`
+	Name           string `json:"name" binding:"required,max=50"`
 	Description    string `json:"description"`
+	ClinicBranchID string `json:"clinic_branch_id" binding:"required,uuid"`
+	Version        int    `json:"-"`
+}
+
+type GetDoctorTypesListRequest struct {
+	GetListRequest
 	ClinicBranchID string `json:"clinic_branch_id"`
 }
 
@@ -2,37 +2,52 @@ package models
 
 import "time"
 
+// Drug is a catalogue drug sold by a branch, its name and prescription rule come from the catalogue
 type Drug struct {
-	ID                string    `json:"id"`
-	DrugStoreBranchID string    `json:"drug_store_branch_id"`
-	Name              string    `json:"name"`
-	Description       string    `json:"description"`
-	Count             int       `json:"count"`
-	Price             string    `json:"price"`
-	DateOfManufacture string    `json:"date_of_manufacture"`
-	BestBefore        string    `json:"best_before"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
-	DeletedAt         time.Time `json:"deleted_at"`
+	ID                   string    `json:"id"`
+	DrugStoreBranchID    string    `json:"drug_store_branch_id"`
+	DrugCatalogueID      string    `json:"drug_catalogue_id"`
+	Name                 string    `json:"name"`
+	Description          string    `json:"description"`
+	Count                int       `json:"count"`
+	Price                string    `json:"price"`
+	DateOfManufacture    string    `json:"date_of_manufacture"`
+	BestBefore           string    `json:"best_before"`
+	PrescriptionRequired bool      `json:"prescription_required"`
+	Version              int       `json:"version"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+	DeletedAt            time.Time `json:"deleted_at"`
 }
 
 type CreateDrug struct {
-	DrugStoreBranchID string `json:"drug_store_branch_id"`
-	Name              string `json:"name"`
+	DrugStoreBranchID string `json:"drug_store_branch_id" binding:"required,uuid"`
+	DrugCatalogueID   string `json:"drug_catalogue_id" binding:"required,uuid"`
 	Description       string `json:"description"`
-	Count             int    `json:"count"`
-	Price             string `json:"price"`
-	DateOfManufacture string `json:"date_of_manufacture"`
-	BestBefore        string `json:"best_before"`
+	Count             int    `json:"count" binding:"gte=0"`
+	Price             string `json:"price" binding:"required,money"`
+	DateOfManufacture string `json:"date_of_manufacture" binding:"required,datetime=2006-01-02"`
+	BestBefore        string `json:"best_before" binding:"required,datetime=2006-01-02"`
 }
 
+// UpdateDrug does not change the count, stock is changed through drug lots. The branch and the
+// catalogue drug are fixed, lots of the drug stay in its branch
 type UpdateDrug struct {
 	ID                string `json:"id"`
-	DrugStoreBranchID string `json:"drug_store_branch_id"`
-	Name              string `json:"name"`
 	Description       string `json:"description"`
-	Count             int    `json:"count"`
-	Price             string `json:"price"`
+	Price             string `json:"price" binding:"required,money"`
+	DateOfManufacture string `json:"date_of_manufacture" binding:"required,datetime=2006-01-02"`
+	BestBefore        string `json:"best_before" binding:"required,datetime=2006-01-02"`
+	Version           int    `json:"-"`
+}
+
+type GetDrugsListRequest struct {
+	GetListRequest
+	DrugStoreBranchID    string `json:"drug_store_branch_id"`
+	DrugCatalogueID      string `json:"drug_catalogue_id"`
+	MinPrice             string `json:"min_price"`
+	MaxPrice             string `json:"max_price"`
+	PrescriptionRequired string `json:"prescription_required"`
 }
 
 type DrugsResponse struct {
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// DrugCatalogue is a drug known to every drug store, branches sell it through their own drug rows
+type DrugCatalogue struct {
+	ID                   string    `json:"id"`
+	Name                 string    `json:"name"`
+	Description          string    `json:"description"`
+	PrescriptionRequired bool      `json:"prescription_required"`
+	Version              int       `json:"version"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+	DeletedAt            time.Time `json:"deleted_at"`
+}
+
+type CreateDrugCatalogue struct {
+	Name                 string `json:"name" binding:"required,max=50"`
+	Description          string `json:"description"`
+	PrescriptionRequired bool   `json:"prescription_required"`
+}
+
+type UpdateDrugCatalogue struct {
+	ID                   string `json:"id"`
+	Name                 string `json:"name" binding:"required,max=50"`
+	Description          string `json:"description"`
+	PrescriptionRequired bool   `json:"prescription_required"`
+	Version              int    `json:"-"`
+}
+
+type DrugCataloguesResponse struct {
+	DrugCatalogues []DrugCatalogue `json:"drug_catalogues"`
+	Count          int             `json:"count"`
+}
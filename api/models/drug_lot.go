@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// DrugLot is stock of a drug held by the branch of the drug
+type DrugLot struct {
+	ID                string    `json:"id"`
+	DrugID            string    `json:"drug_id"`
+	DrugStoreBranchID string    `json:"drug_store_branch_id"`
+	LotNumber         string    `json:"lot_number"`
+	Quantity          int       `json:"quantity"`
+	ExpiryDate        string    `json:"expiry_date"`
+	PurchasePrice     string    `json:"purchase_price"`
+	Version           int       `json:"version"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+	DeletedAt         time.Time `json:"deleted_at"`
+}
+
+type CreateDrugLot struct {
+	DrugID        string `json:"drug_id" binding:"required,uuid"`
+	LotNumber     string `json:"lot_number" binding:"required"`
+	Quantity      int    `json:"quantity" binding:"gte=0"`
+	ExpiryDate    string `json:"expiry_date" binding:"required,datetime=2006-01-02"`
+	PurchasePrice string `json:"purchase_price" binding:"omitempty,money"`
+}
+
+type UpdateDrugLot struct {
+	ID            string `json:"-"`
+	LotNumber     string `json:"lot_number" binding:"required"`
+	Quantity      int    `json:"quantity" binding:"gte=0"`
+	ExpiryDate    string `json:"expiry_date" binding:"required,datetime=2006-01-02"`
+	PurchasePrice string `json:"purchase_price" binding:"omitempty,money"`
+	Version       int    `json:"-"`
+}
+
+type DrugLotsResponse struct {
+	DrugLots []DrugLot `json:"drug_lots"`
+	Count    int       `json:"count"`
+}
+
+// DrugLotMove is a lot with the quantity an order took from it
+type DrugLotMove struct {
+	DrugLot
+	Moved int
+}
+
+type ExpiringLot struct {
+	DrugLot
+	DrugName string `json:"drug_name"`
+	DaysLeft int    `json:"days_left"`
+}
+
+type ExpiringLotsRequest struct {
+	DrugStoreBranchID string
+	Within            int
+}
+
+type ExpiringLotsResponse struct {
+	Lots  []ExpiringLot `json:"lots"`
+	Count int           `json:"count"`
+}
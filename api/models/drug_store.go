@@ -6,20 +6,22 @@ type DrugStore struct {
 	ID          string    `json:"drug_store"`
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
+	Version     int       `json:"version"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	DeletedAt   time.Time `json:"deleted_at"`
 }
 
 type CreateDrugStore struct {
-	Name        string `json:"name"`
+	Name        string `json:"name" binding:"required,max=50"`
 	Description string `json:"description"`
 }
 
 type UpdateDrugStore struct {
 	ID          string `json:"id"`
-	Name        string `json:"name"`
+	Name        string `json:"name" binding:"required,max=50"`
 	Description string `json:"description"`
+	Version     int    `json:"-"`
 }
 
 type DrugStoresResponse struct {
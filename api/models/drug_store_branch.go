@@ -8,24 +8,31 @@ type DrugStoreBranch struct {
 	Address     string    `json:"address"`
 	Phone       string    `json:"phone"`
 	WorkingTime string    `json:"working_time"`
+	Version     int       `json:"version"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	DeletedAt   time.Time `json:"deleted_at"`
 }
 
 type CreateDrugStoreBranch struct {
-	DrugStoreID string `json:"drug_store_id"`
-	Address     string `json:"address"`
-	Phone       string `json:"phone"`
-	WorkingTime string `json:"working_time"`
+	DrugStoreID string `json:"drug_store_id" binding:"required,uuid"`
+	Address     string `json:"address" binding:"required,max=120"`
+	Phone       string `json:"phone" binding:"required,uz_phone"`
+	WorkingTime string `json:"working_time" binding:"required,max=100"`
 }
 
 type UpdateDrugStoreBranch struct {
 	ID          string `json:"id"`
+	DrugStoreID string `json:"drug_store_id" binding:"required,uuid"`
+	Address     string `json:"address" binding:"required,max=120"`
+	Phone       string `json:"phone" binding:"required,uz_phone"`
+	WorkingTime string `json:"working_time" binding:"required,max=100"`
+	Version     int    `json:"-"`
+}
+
+type GetDrugStoreBranchsListRequest struct {
+	GetListRequest
 	DrugStoreID string `json:"drug_store_id"`
-	Address     string `json:"address"`
-	Phone       string `json:"phone"`
-	WorkingTime string `json:"working_time"`
 }
 
 type DrugStoreBranchsResponse struct {
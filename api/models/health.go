@@ -0,0 +1,7 @@
+package models
+
+// Readiness holds the result of every check of the readiness probe, "ok" or why the check failed
+type Readiness struct {
+	Database   string `json:"database"`
+	Migrations string `json:"migrations"`
+}
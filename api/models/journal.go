@@ -3,29 +3,158 @@ package models
 import "time"
 
 type Journal struct {
-	ID        string    `json:"id"`
-	AuthorID  string    `json:"author_id"`
-	Theme     string    `json:"theme"`
-	Article   string    `json:"article"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	DeletedAt time.Time `json:"deleted_at"`
+	ID            string     `json:"id"`
+	AuthorID      string     `json:"author_id"`
+	Theme         string     `json:"theme"`
+	Article       string     `json:"article"`
+	Language      string     `json:"language"`
+	Slug          string     `json:"slug"`
+	Status        string     `json:"status"`
+	Version       int        `json:"version"`
+	PublishedAt   *time.Time `json:"published_at"`
+	ReviewedBy    string     `json:"reviewed_by"`
+	ReviewNote    string     `json:"review_note"`
+	CategoryID    string     `json:"category_id"`
+	Tags          []string   `json:"tags"`
+	DoctorTypeIDs []string   `json:"doctor_type_ids"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	DeletedAt     time.Time  `json:"deleted_at"`
 }
 
+// CreateJournal starts a draft, the slug is made from the theme when it is not given
 type CreateJournal struct {
-	AuthorID string `json:"author_id"`
-	Theme    string `json:"theme"`
-	Article  string `json:"article"`
+	AuthorID      string   `json:"author_id" binding:"omitempty,uuid"`
+	Theme         string   `json:"theme" binding:"required"`
+	Article       string   `json:"article" binding:"required"`
+	Language      string   `json:"language" binding:"omitempty,oneof=uz ru en"`
+	Slug          string   `json:"slug" binding:"omitempty,max=180"`
+	CategoryID    string   `json:"category_id" binding:"omitempty,uuid"`
+	Tags          []string `json:"tags" binding:"max=10"`
+	DoctorTypeIDs []string `json:"doctor_type_ids" binding:"dive,uuid"`
+	EditorID      string   `json:"-"`
 }
 
+// UpdateJournal saves a new version of a draft, EditorID is who saved it
 type UpdateJournal struct {
 	ID       string `json:"id"`
-	AuthorID string `json:"author_id"`
-	Theme    string `json:"theme"`
-	Article  string `json:"article"`
+	AuthorID string `json:"author_id" binding:"omitempty,uuid"`
+	Theme    string `json:"theme" binding:"required"`
+	Article  string `json:"article" binding:"required"`
+	Language string `json:"language" binding:"omitempty,oneof=uz ru en"`
+	Slug     string `json:"slug" binding:"omitempty,max=180"`
+	EditorID string `json:"-"`
+	Version  int    `json:"-"`
+}
+
+// ClassifyJournal replaces the category, the tags and the specialties of the article
+type ClassifyJournal struct {
+	ID            string   `json:"-"`
+	CategoryID    string   `json:"category_id" binding:"omitempty,uuid"`
+	Tags          []string `json:"tags" binding:"max=10"`
+	DoctorTypeIDs []string `json:"doctor_type_ids" binding:"dive,uuid"`
+}
+
+type UpdateJournalStatus struct {
+	ID          string
+	FromStatus  string
+	ToStatus    string
+	PublishedAt *time.Time
+	ReviewedBy  string
+	ReviewNote  string
+}
+
+// ApproveJournal publishes the article at publish_at (RFC 3339), right away when it is empty
+type ApproveJournal struct {
+	PublishAt string `json:"publish_at" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+// RejectJournal sends the article back to its author with the reason
+type RejectJournal struct {
+	Note string `json:"note" binding:"required"`
+}
+
+// GetJournalsListRequest lists every article to super admins. For everyone else PublishedOnly
+// keeps published articles which are already released, plus any article of OwnerID
+type GetJournalsListRequest struct {
+	GetListRequest
+	AuthorID      string `json:"author_id"`
+	Status        string `json:"status"`
+	CategoryID    string `json:"category_id"`
+	Tag           string `json:"tag"`
+	DoctorTypeID  string `json:"doctor_type_id"`
+	PublishedOnly bool   `json:"-"`
+	OwnerID       string `json:"-"`
 }
 
 type JournalsResponse struct {
 	Journals []Journal `json:"journals"`
 	Count    int       `json:"count"`
 }
+
+// SearchJournalsRequest looks the text up in themes and articles, Language limits it to articles of one language
+type SearchJournalsRequest struct {
+	Query    string `json:"q"`
+	Language string `json:"language"`
+	Page     int    `json:"page"`
+	Limit    int    `json:"limit"`
+}
+
+// JournalSearchResult has the matched words of the theme and the article marked with <mark></mark>,
+// the rest of the text is HTML escaped so the marks are its only tags
+type JournalSearchResult struct {
+	ID        string    `json:"id"`
+	AuthorID  string    `json:"author_id"`
+	Theme     string    `json:"theme"`
+	Snippet   string    `json:"snippet"`
+	Language  string    `json:"language"`
+	Rank      float64   `json:"rank"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type JournalSearchResponse struct {
+	Results []JournalSearchResult `json:"results"`
+	Count   int                   `json:"count"`
+}
+
+// JournalTag is a tag with the number of published articles which have it
+type JournalTag struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+type JournalTagsResponse struct {
+	Tags  []JournalTag `json:"tags"`
+	Count int          `json:"count"`
+}
+
+type JournalRevision struct {
+	ID        string    `json:"id"`
+	JournalID string    `json:"journal_id"`
+	Version   int       `json:"version"`
+	Theme     string    `json:"theme"`
+	Article   string    `json:"article"`
+	Language  string    `json:"language"`
+	EditedBy  string    `json:"edited_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type JournalRevisionsResponse struct {
+	Revisions []JournalRevision `json:"revisions"`
+	Count     int               `json:"count"`
+}
+
+// DiffLine is a line kept ("="), added ("+") or removed ("-") between two versions
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// JournalDiff shows how the theme and the article changed from one version to another
+type JournalDiff struct {
+	JournalID string     `json:"journal_id"`
+	From      int        `json:"from"`
+	To        int        `json:"to"`
+	Theme     []DiffLine `json:"theme"`
+	Article   []DiffLine `json:"article"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+type JournalCategory struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Version     int       `json:"version"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	DeletedAt   time.Time `json:"deleted_at"`
+}
+
+type CreateJournalCategory struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description"`
+}
+
+type UpdateJournalCategory struct {
+	ID          string `json:"id"`
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description"`
+	Version     int    `json:"-"`
+}
+
+type JournalCategoriesResponse struct {
+	JournalCategories []JournalCategory `json:"journal_categories"`
+	Count             int               `json:"count"`
+}
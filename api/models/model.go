@@ -1,9 +1,18 @@
 package models
 
+// GetListRequest is embedded by list requests with filters of their own, created_from and created_to are
+// YYYY-MM-DD dates and both are inclusive. Cursor, taken from next_cursor of the previous page, replaces page
 type GetListRequest struct {
-	Page   int    `json:"page"`
-	Limit  int    `json:"limit"`
-	Search string `json:"search"`
+	Page        int    `json:"page"`
+	Limit       int    `json:"limit"`
+	Search      string `json:"search"`
+	CreatedFrom string `json:"created_from"`
+	CreatedTo   string `json:"created_to"`
+	SortBy      string `json:"sort_by"`
+	Order       string `json:"order"`
+	Cursor      string `json:"cursor"`
+	// IncludeDeleted lists soft deleted rows too, they have deleted_at set
+	IncludeDeleted bool `json:"include_deleted"`
 }
 
 type PrimaryKey struct {
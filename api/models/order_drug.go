@@ -3,23 +3,37 @@ package models
 import "time"
 
 type OrderDrug struct {
-	ID        string    `json:"id"`
-	DrugID    string    `json:"drug_id"`
-	OrdersID  string    `json:"orders_id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	DeletedAt time.Time `json:"deleted_at"`
+	ID                 string    `json:"id"`
+	DrugID             string    `json:"drug_id"`
+	OrdersID           string    `json:"orders_id"`
+	Quantity           int       `json:"quantity"`
+	UnitPrice          string    `json:"unit_price"`
+	LineTotal          string    `json:"line_total"`
+	PrescriptionItemID string    `json:"prescription_item_id"`
+	Version            int       `json:"version"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+	DeletedAt          time.Time `json:"deleted_at"`
 }
 
 type CreateOrderDrug struct {
-	DrugID   string `json:"drug_id"`
-	OrdersID string `json:"orders_id"`
+	DrugID   string `json:"drug_id" binding:"required,uuid"`
+	OrdersID string `json:"orders_id" binding:"required,uuid"`
+	Quantity int    `json:"quantity" binding:"gt=0"`
 }
 
 type UpdateOrderDrug struct {
 	ID       string `json:"id"`
-	DrugID   string `json:"drug_id"`
+	DrugID   string `json:"drug_id" binding:"required,uuid"`
+	OrdersID string `json:"orders_id" binding:"required,uuid"`
+	Quantity int    `json:"quantity" binding:"gt=0"`
+	Version  int    `json:"-"`
+}
+
+type GetOrderDrugsListRequest struct {
+	GetListRequest
 	OrdersID string `json:"orders_id"`
+	DrugID   string `json:"drug_id"`
 }
 
 type OrderDrugsResponse struct {
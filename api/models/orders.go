@@ -3,26 +3,92 @@ package models
 import "time"
 
 type Orders struct {
-	ID           string    `json:"id"`
-	PharmacistID string    `json:"pharmacist_id"`
-	CustomerID   string    `json:"customer_id"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
-	DeletedAt    time.Time `json:"deleted_at"`
+	ID                string      `json:"id"`
+	PharmacistID      string      `json:"pharmacist_id"`
+	CustomerID        string      `json:"customer_id"`
+	DrugStoreBranchID string      `json:"drug_store_branch_id"`
+	Subtotal          string      `json:"subtotal"`
+	Discount          string      `json:"discount"`
+	Total             string      `json:"total"`
+	Currency          string      `json:"currency"`
+	Status            string      `json:"status"`
+	PrescriptionID    string      `json:"prescription_id"`
+	ConfirmedAt       *time.Time  `json:"confirmed_at"`
+	PreparingAt       *time.Time  `json:"preparing_at"`
+	ReadyForPickupAt  *time.Time  `json:"ready_for_pickup_at"`
+	DeliveredAt       *time.Time  `json:"delivered_at"`
+	CancelledAt       *time.Time  `json:"cancelled_at"`
+	RefundedAt        *time.Time  `json:"refunded_at"`
+	Lines             []OrderDrug `json:"lines,omitempty"`
+	Version           int         `json:"version"`
+	CreatedAt         time.Time   `json:"created_at"`
+	UpdatedAt         time.Time   `json:"updated_at"`
+	DeletedAt         time.Time   `json:"deleted_at"`
 }
 
 type CreateOrders struct {
-	PharmacistID string `json:"pharmacist_id"`
-	CustomerID   string `json:"customer_id"`
+	PharmacistID string `json:"pharmacist_id" binding:"omitempty,uuid"`
+	CustomerID   string `json:"customer_id" binding:"required,uuid"`
 }
 
 type UpdateOrders struct {
 	ID           string `json:"id"`
-	PharmacistID string `json:"pharmacist_id"`
-	CustomerID   string `json:"customer_id"`
+	PharmacistID string `json:"pharmacist_id" binding:"omitempty,uuid"`
+	CustomerID   string `json:"customer_id" binding:"required,uuid"`
+	Version      int    `json:"-"`
+}
+
+type UpdateOrderStatus struct {
+	ID           string
+	FromStatus   string
+	ToStatus     string
+	RestoreStock bool
+}
+
+type GetOrdersListRequest struct {
+	GetListRequest
+	Status            string `json:"status"`
+	CustomerID        string `json:"customer_id"`
+	PharmacistID      string `json:"pharmacist_id"`
+	DrugStoreBranchID string `json:"drug_store_branch_id"`
+	// AccessBranchID with AccessPharmacistID keeps orders of the branch plus the ones the pharmacist opened,
+	// they are taken from the token of a pharmacist
+	AccessBranchID     string `json:"-"`
+	AccessPharmacistID string `json:"-"`
 }
 
 type OrdersResponse struct {
-	Orderss []Orders `json:"orderss"`
-	Count   int      `json:"count"`
+	Orderss    []Orders `json:"orderss"`
+	Count      int      `json:"count"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+type CheckoutOrder struct {
+	CustomerID        string         `json:"customer_id" binding:"required,uuid"`
+	PharmacistID      string         `json:"pharmacist_id" binding:"omitempty,uuid"`
+	DrugStoreBranchID string         `json:"drug_store_branch_id" binding:"required,uuid"`
+	Discount          string         `json:"discount" binding:"omitempty,money"`
+	Items             []CheckoutItem `json:"items" binding:"required,min=1,dive"`
+	Subtotal          string         `json:"-"`
+	Total             string         `json:"-"`
+	Currency          string         `json:"-"`
+	PrescriptionID    string         `json:"-"`
+}
+
+// CheckoutItem prices are filled by the orders service, the client sends only the drug and the quantity
+type CheckoutItem struct {
+	DrugID    string `json:"drug_id" binding:"required,uuid"`
+	Quantity  int    `json:"quantity" binding:"gt=0"`
+	UnitPrice string `json:"-"`
+	LineTotal string `json:"-"`
+	// PrescriptionItemID is set when the line is sold for a prescription
+	PrescriptionItemID string `json:"-"`
+}
+
+// CheckoutLineError explains why a line of the checkout can not be sold
+type CheckoutLineError struct {
+	DrugID    string `json:"drug_id"`
+	Quantity  int    `json:"quantity"`
+	Available int    `json:"available"`
+	Error     string `json:"error"`
 }
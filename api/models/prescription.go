@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+type Prescription struct {
+	ID         string             `json:"id"`
+	Code       string             `json:"code"`
+	QueueID    string             `json:"queue_id"`
+	DoctorID   string             `json:"doctor_id"`
+	CustomerID string             `json:"customer_id"`
+	Note       string             `json:"note"`
+	ValidUntil string             `json:"valid_until"`
+	Items      []PrescriptionItem `json:"items"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+	DeletedAt  time.Time          `json:"deleted_at"`
+}
+
+// PrescriptionItem can be sold quantity * (refills + 1) in total, at most quantity at a time.
+// DispensedQuantity is sold over all fills and RedeemedCount is the fills begun. Items written before
+// the drug catalogue have no DrugCatalogueID and are matched by DrugName
+type PrescriptionItem struct {
+	ID                string `json:"id"`
+	PrescriptionID    string `json:"prescription_id"`
+	DrugCatalogueID   string `json:"drug_catalogue_id"`
+	DrugName          string `json:"drug_name"`
+	Dosage            string `json:"dosage"`
+	DurationDays      int    `json:"duration_days"`
+	Quantity          int    `json:"quantity"`
+	Refills           int    `json:"refills"`
+	RedeemedCount     int    `json:"redeemed_count"`
+	DispensedQuantity int    `json:"dispensed_quantity"`
+}
+
+type CreatePrescription struct {
+	QueueID    string                   `json:"queue_id" binding:"required,uuid"`
+	Note       string                   `json:"note"`
+	ValidUntil string                   `json:"valid_until" binding:"omitempty,datetime=2006-01-02"`
+	Items      []CreatePrescriptionItem `json:"items" binding:"required,min=1,dive"`
+	Code       string                   `json:"-"`
+	DoctorID   string                   `json:"-"`
+	CustomerID string                   `json:"-"`
+}
+
+// CreatePrescriptionItem prescribes a catalogue drug, the drug name is copied from the catalogue
+type CreatePrescriptionItem struct {
+	DrugCatalogueID string `json:"drug_catalogue_id" binding:"required,uuid"`
+	Dosage          string `json:"dosage" binding:"required"`
+	DurationDays    int    `json:"duration_days" binding:"gt=0"`
+	Quantity        int    `json:"quantity" binding:"gt=0"`
+	Refills         int    `json:"refills" binding:"gte=0"`
+	DrugName        string `json:"-"`
+}
+
+type RedeemPrescription struct {
+	Code              string                   `json:"code" binding:"required"`
+	DrugStoreBranchID string                   `json:"drug_store_branch_id" binding:"required,uuid"`
+	Items             []RedeemPrescriptionItem `json:"items" binding:"required,min=1,dive"`
+	PharmacistID      string                   `json:"-"`
+}
+
+// RedeemPrescriptionItem sells a drug of the branch for a prescription item, quantity defaults to the prescribed one
+type RedeemPrescriptionItem struct {
+	PrescriptionItemID string `json:"prescription_item_id" binding:"required,uuid"`
+	DrugID             string `json:"drug_id" binding:"required,uuid"`
+	Quantity           int    `json:"quantity" binding:"gt=0"`
+}
+
+type PrescriptionsResponse struct {
+	Prescriptions []Prescription `json:"prescriptions"`
+	Count         int            `json:"count"`
+}
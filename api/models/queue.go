@@ -7,26 +7,63 @@ type Queue struct {
 	CustomerID  string    `json:"customer_id"`
 	DoctorID    string    `json:"doctor_id"`
 	QueueNumber string    `json:"queue_number"`
-	QueueTime   string    `json:"queue_time"`
+	StartTime   string    `json:"start_time"`
+	EndTime     string    `json:"end_time"`
+	Status      string    `json:"status"`
+	Version     int       `json:"version"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	DeletedAt   time.Time `json:"deleted_at"`
 }
 
 type CreateQueue struct {
-	CustomerID  string `json:"customer_id"`
-	DoctorID    string `json:"doctor_id"`
-	QueueTime   string `json:"queue_time"`
+	CustomerID string `json:"customer_id" binding:"required,uuid"`
+	DoctorID   string `json:"doctor_id" binding:"required,uuid"`
+	StartTime  string `json:"start_time" binding:"required,datetime=2006-01-02 15:04"`
 }
 
 type UpdateQueue struct {
 	ID         string `json:"id"`
-	CustomerID string `json:"customer_id"`
+	CustomerID string `json:"customer_id" binding:"required,uuid"`
+	DoctorID   string `json:"doctor_id" binding:"required,uuid"`
+	StartTime  string `json:"start_time" binding:"required,datetime=2006-01-02 15:04"`
+	Version    int    `json:"-"`
+}
+
+type UpdateQueueStatus struct {
+	ID           string
+	FromStatus   string
+	ToStatus     string
+	DoctorStatus string
+}
+
+type GetQueuesListRequest struct {
+	GetListRequest
 	DoctorID   string `json:"doctor_id"`
-	QueueTime  string `json:"queue_time"`
+	CustomerID string `json:"customer_id"`
+	Status     string `json:"status"`
+	// ClinicBranchID keeps queues of doctors of the branch, it is taken from the token of a clinic admin
+	ClinicBranchID string `json:"-"`
 }
 
 type QueuesResponse struct {
-	Queues []Queue `json:"queues"`
-	Count  int     `json:"count"`
+	Queues     []Queue `json:"queues"`
+	Count      int     `json:"count"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+type QueueBoardItem struct {
+	QueueID     string `json:"queue_id"`
+	QueueNumber string `json:"queue_number"`
+	DoctorID    string `json:"doctor_id"`
+	DoctorName  string `json:"doctor_name"`
+	StartTime   string `json:"start_time"`
+	EndTime     string `json:"end_time"`
+	Status      string `json:"status"`
+}
+
+// QueueBoardEvent is sent to the live queue board, type is snapshot, created, updated or deleted
+type QueueBoardEvent struct {
+	Type  string           `json:"type"`
+	Items []QueueBoardItem `json:"items"`
 }
@@ -5,3 +5,12 @@ type Response struct {
 	Description string
 	Data        interface{}
 }
+
+// ErrorResponse is the data of every failed request. Code is not_found, conflict, validation, forbidden,
+// precondition_failed, bad_request, unauthorized, unavailable or internal, fields tell which request fields are wrong
+type ErrorResponse struct {
+	Code    string            `json:"code"`
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"`
+	Details interface{}       `json:"details,omitempty"`
+}
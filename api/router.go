@@ -1,8 +1,9 @@
 package api
 
 import (
+	"shifolink/config"
 	"shifolink/service"
-	"shifolink/storage"
+	"sync/atomic"
 
 	"shifolink/api/handler"
 
@@ -14,145 +15,274 @@ import (
 // @title           ShifoLink
 // @version         1.0.0
 // @description     Online doctor appointments and drug orders
-func New(services service.IServiceManager, store storage.IStorage) *gin.Engine {
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name Authorization
+func New(services service.IServiceManager, ready *atomic.Bool) *gin.Engine {
 
-	h := handler.New(store, services)
+	h := handler.New(services, ready)
 
 	r := gin.New()
 
+	r.Use(h.MetricsMiddleware(), h.RequestIDMiddleware())
+
+	// HEALTH
+
+	r.GET("healthz", h.Liveness)
+	r.GET("readyz", h.Readiness)
+	r.GET("metrics", h.Metrics)
+
+	// AUTH
+
+	r.POST("auth/:role/login", h.Login)
+	r.POST("auth/refresh", h.RefreshToken)
+
+	// AUDIT
+
+	r.GET("audit", h.AuthorizerMiddleware(config.SuperAdminRole), h.GetAuditLogs)
+
 	// 	AUTHOR
 
-	r.POST("author", h.CreateAuthor)
+	r.POST("author", h.AuthorizerMiddleware(config.SuperAdminRole), h.CreateAuthor)
 	r.GET("author/:id", h.GetAuthorByID)
-	r.GET("author", h.GetAuthorList)
-	r.PUT("author/:id", h.UpdateAuthor)
-	r.DELETE("author/:id", h.DeleteAuthor)
-	r.PATCH("author/:id", h.UpdateAuthorPassword)
+	r.GET("author", h.OptionalAuthMiddleware(), h.GetAuthorList)
+	r.PUT("author/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.AuthorRole), h.UpdateAuthor)
+	r.PATCH("author/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.AuthorRole), h.UpdateAuthor)
+	r.DELETE("author/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.AuthorRole), h.DeleteAuthor)
+	r.POST("author/:id/restore", h.AuthorizerMiddleware(config.SuperAdminRole), h.RestoreAuthor)
+	r.PATCH("author/:id/password", h.AuthorizerMiddleware(config.AuthorRole), h.UpdateAuthorPassword)
 
 	// CLINIC ADMIN
 
-	r.POST("clinic_admin", h.CreateClinicAdmin)
-	r.GET("clinic_admin/:id", h.GetClinicAdminByID)
-	r.GET("clinic_admin", h.GetClinicAdminsList)
-	r.PUT("clinic_admin/:id", h.UpdateClinicAdmin)
-	r.DELETE("clinic_admin/:id", h.DeleteClinicAdmin)
-	r.PATCH("clinic_admin/:id", h.UpdateClinicAdminPassword)
+	r.POST("clinic_admin", h.AuthorizerMiddleware(config.SuperAdminRole), h.CreateClinicAdmin)
+	r.GET("clinic_admin/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.ClinicAdminRole), h.GetClinicAdminByID)
+	r.GET("clinic_admin", h.AuthorizerMiddleware(config.SuperAdminRole, config.ClinicAdminRole), h.GetClinicAdminsList)
+	r.PUT("clinic_admin/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.ClinicAdminRole), h.UpdateClinicAdmin)
+	r.PATCH("clinic_admin/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.ClinicAdminRole), h.UpdateClinicAdmin)
+	r.DELETE("clinic_admin/:id", h.AuthorizerMiddleware(config.SuperAdminRole), h.DeleteClinicAdmin)
+	r.POST("clinic_admin/:id/restore", h.AuthorizerMiddleware(config.SuperAdminRole), h.RestoreClinicAdmin)
+	r.PATCH("clinic_admin/:id/password", h.AuthorizerMiddleware(config.ClinicAdminRole), h.UpdateClinicAdminPassword)
 
 	// CLINIC BRANCH
 
-	r.POST("clinic_branch", h.CreateClinicBranch)
+	r.POST("clinic_branch", h.AuthorizerMiddleware(config.SuperAdminRole), h.CreateClinicBranch)
 	r.GET("clinic_branch/:id", h.GetClinicBranchByID)
-	r.GET("clinic_branch", h.GetClinicBranchsList)
-	r.PUT("clinic_branch/:id", h.UpdateClinicBranch)
-	r.DELETE("clinic_branch/:id", h.DeleteClinicBranch)
+	r.GET("clinic_branch", h.OptionalAuthMiddleware(), h.GetClinicBranchsList)
+	r.PUT("clinic_branch/:id", h.AuthorizerMiddleware(config.SuperAdminRole), h.UpdateClinicBranch)
+	r.PATCH("clinic_branch/:id", h.AuthorizerMiddleware(config.SuperAdminRole), h.UpdateClinicBranch)
+	r.DELETE("clinic_branch/:id", h.AuthorizerMiddleware(config.SuperAdminRole), h.DeleteClinicBranch)
+	r.POST("clinic_branch/:id/restore", h.AuthorizerMiddleware(config.SuperAdminRole), h.RestoreClinicBranch)
+	r.GET("clinic_branch/:id/queue_board", h.QueueBoard)
 
 	// CLINIC
 
-	r.POST("clinic", h.CreateClinic)
+	r.POST("clinic", h.AuthorizerMiddleware(config.SuperAdminRole), h.CreateClinic)
 	r.GET("clinic/:id", h.GetClinicByID)
-	r.GET("clinic", h.GetClinicsList)
-	r.PUT("clinic/:id", h.UpdateClinic)
-	r.DELETE("clinic/:id", h.DeleteClinic)
+	r.GET("clinic", h.OptionalAuthMiddleware(), h.GetClinicsList)
+	r.PUT("clinic/:id", h.AuthorizerMiddleware(config.SuperAdminRole), h.UpdateClinic)
+	r.PATCH("clinic/:id", h.AuthorizerMiddleware(config.SuperAdminRole), h.UpdateClinic)
+	r.DELETE("clinic/:id", h.AuthorizerMiddleware(config.SuperAdminRole), h.DeleteClinic)
+	r.POST("clinic/:id/restore", h.AuthorizerMiddleware(config.SuperAdminRole), h.RestoreClinic)
 
 	// CUSTOMER
 
 	r.POST("customer", h.CreateCustomer)
-	r.GET("customer/:id", h.GetCustomerByID)
-	r.GET("customer", h.GetCustomersList)
-	r.PUT("customer/:id", h.UpdateCustomer)
-	r.DELETE("customer/:id", h.DeleteCustomer)
-	r.PATCH("customer/:id", h.UpdateCustomerPassword)
+	r.GET("customer/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.ClinicAdminRole, config.DoctorRole, config.PharmacistRole, config.CustomerRole), h.GetCustomerByID)
+	r.GET("customer", h.AuthorizerMiddleware(config.SuperAdminRole, config.ClinicAdminRole, config.DoctorRole, config.PharmacistRole), h.GetCustomersList)
+	r.PUT("customer/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.CustomerRole), h.UpdateCustomer)
+	r.PATCH("customer/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.CustomerRole), h.UpdateCustomer)
+	r.DELETE("customer/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.CustomerRole), h.DeleteCustomer)
+	r.POST("customer/:id/restore", h.AuthorizerMiddleware(config.SuperAdminRole), h.RestoreCustomer)
+	r.PATCH("customer/:id/password", h.AuthorizerMiddleware(config.CustomerRole), h.UpdateCustomerPassword)
 
 	// DOCTOR TYPE
 
-	r.POST("doctor_type", h.CreateDoctorType)
+	r.POST("doctor_type", h.AuthorizerMiddleware(config.SuperAdminRole, config.ClinicAdminRole), h.CreateDoctorType)
 	r.GET("doctor_type/:id", h.GetDoctorTypeByID)
-	r.GET("doctor_type", h.GetDoctorTypesList)
-	r.PUT("doctor_type/:id", h.UpdateDoctorType)
-	r.DELETE("doctor_type/:id", h.DeleteDoctorType)
+	r.GET("doctor_type", h.OptionalAuthMiddleware(), h.GetDoctorTypesList)
+	r.PUT("doctor_type/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.ClinicAdminRole), h.UpdateDoctorType)
+	r.PATCH("doctor_type/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.ClinicAdminRole), h.UpdateDoctorType)
+	r.DELETE("doctor_type/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.ClinicAdminRole), h.DeleteDoctorType)
+	r.POST("doctor_type/:id/restore", h.AuthorizerMiddleware(config.SuperAdminRole), h.RestoreDoctorType)
 
 	// DOCTOR
 
-	r.POST("doctor", h.CreateDoctor)
+	r.POST("doctor", h.AuthorizerMiddleware(config.SuperAdminRole, config.ClinicAdminRole), h.CreateDoctor)
 	r.GET("doctor/:id", h.GetDoctorByID)
-	r.GET("doctor", h.GetDoctorsList)
-	r.PUT("doctor/:id", h.UpdateDoctor)
-	r.DELETE("doctor/:id", h.DeleteDoctor)
-	r.PATCH("doctor/:id", h.UpdateDoctorPassword)
+	r.GET("doctor", h.OptionalAuthMiddleware(), h.GetDoctorsList)
+	r.PUT("doctor/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.ClinicAdminRole), h.UpdateDoctor)
+	r.PATCH("doctor/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.ClinicAdminRole), h.UpdateDoctor)
+	r.DELETE("doctor/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.ClinicAdminRole), h.DeleteDoctor)
+	r.POST("doctor/:id/restore", h.AuthorizerMiddleware(config.SuperAdminRole), h.RestoreDoctor)
+	r.PATCH("doctor/:id/password", h.AuthorizerMiddleware(config.DoctorRole), h.UpdateDoctorPassword)
+	r.GET("doctor/:id/schedule", h.GetDoctorSchedules)
+	r.GET("doctor/:id/slots", h.GetDoctorSlots)
+	r.GET("doctor/:id/journals", h.GetDoctorJournals)
+
+	// DOCTOR SCHEDULE
+
+	r.POST("doctor_schedule", h.AuthorizerMiddleware(config.SuperAdminRole, config.ClinicAdminRole), h.CreateDoctorSchedule)
+	r.GET("doctor_schedule/:id", h.GetDoctorScheduleByID)
+	r.PUT("doctor_schedule/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.ClinicAdminRole), h.UpdateDoctorSchedule)
+	r.PATCH("doctor_schedule/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.ClinicAdminRole), h.UpdateDoctorSchedule)
+	r.DELETE("doctor_schedule/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.ClinicAdminRole), h.DeleteDoctorSchedule)
 
 	// DRUG STORE BRANCH
 
-	r.POST("drug_store_branch", h.CreateDrugStoreBranch)
+	r.POST("drug_store_branch", h.AuthorizerMiddleware(config.SuperAdminRole), h.CreateDrugStoreBranch)
 	r.GET("drug_store_branch/:id", h.GetDrugStoreBranchByID)
-	r.GET("drug_store_branch", h.GetDrugStoreBranchsList)
-	r.PUT("drug_store_branch/:id", h.UpdateDrugStoreBranch)
-	r.DELETE("drug_store_branch/:id", h.DeleteDrugStoreBranch)
+	r.GET("drug_store_branch", h.OptionalAuthMiddleware(), h.GetDrugStoreBranchsList)
+	r.PUT("drug_store_branch/:id", h.AuthorizerMiddleware(config.SuperAdminRole), h.UpdateDrugStoreBranch)
+	r.PATCH("drug_store_branch/:id", h.AuthorizerMiddleware(config.SuperAdminRole), h.UpdateDrugStoreBranch)
+	r.DELETE("drug_store_branch/:id", h.AuthorizerMiddleware(config.SuperAdminRole), h.DeleteDrugStoreBranch)
+	r.POST("drug_store_branch/:id/restore", h.AuthorizerMiddleware(config.SuperAdminRole), h.RestoreDrugStoreBranch)
+	r.GET("drug_store_branch/:id/expiring", h.AuthorizerMiddleware(config.SuperAdminRole, config.PharmacistRole), h.GetExpiringDrugLots)
 
 	// DRUG STORE
 
-	r.POST("drug_store", h.CreateDrugStore)
+	r.POST("drug_store", h.AuthorizerMiddleware(config.SuperAdminRole), h.CreateDrugStore)
 	r.GET("drug_store/:id", h.GetDrugStoreByID)
-	r.GET("drug_store", h.GetDrugStoresList)
-	r.PUT("drug_store/:id", h.UpdateDrugStore)
-	r.DELETE("drug_store/:id", h.DeleteDrugStore)
+	r.GET("drug_store", h.OptionalAuthMiddleware(), h.GetDrugStoresList)
+	r.PUT("drug_store/:id", h.AuthorizerMiddleware(config.SuperAdminRole), h.UpdateDrugStore)
+	r.PATCH("drug_store/:id", h.AuthorizerMiddleware(config.SuperAdminRole), h.UpdateDrugStore)
+	r.DELETE("drug_store/:id", h.AuthorizerMiddleware(config.SuperAdminRole), h.DeleteDrugStore)
+	r.POST("drug_store/:id/restore", h.AuthorizerMiddleware(config.SuperAdminRole), h.RestoreDrugStore)
+
+	// DRUG CATALOGUE
+
+	r.POST("drug_catalogue", h.AuthorizerMiddleware(config.SuperAdminRole), h.CreateDrugCatalogue)
+	r.GET("drug_catalogue/:id", h.GetDrugCatalogueByID)
+	r.GET("drug_catalogue", h.OptionalAuthMiddleware(), h.GetDrugCataloguesList)
+	r.PUT("drug_catalogue/:id", h.AuthorizerMiddleware(config.SuperAdminRole), h.UpdateDrugCatalogue)
+	r.PATCH("drug_catalogue/:id", h.AuthorizerMiddleware(config.SuperAdminRole), h.UpdateDrugCatalogue)
+	r.DELETE("drug_catalogue/:id", h.AuthorizerMiddleware(config.SuperAdminRole), h.DeleteDrugCatalogue)
+	r.POST("drug_catalogue/:id/restore", h.AuthorizerMiddleware(config.SuperAdminRole), h.RestoreDrugCatalogue)
 
 	// DRUG
 
-	r.POST("drug", h.CreateDrug)
+	r.POST("drug", h.AuthorizerMiddleware(config.SuperAdminRole, config.PharmacistRole), h.CreateDrug)
 	r.GET("drug/:id", h.GetDrugByID)
-	r.GET("drug", h.GetDrugsList)
-	r.PUT("drug/:id", h.UpdateDrug)
-	r.DELETE("drug/:id", h.DeleteDrug)
+	r.GET("drug", h.OptionalAuthMiddleware(), h.GetDrugsList)
+	r.PUT("drug/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.PharmacistRole), h.UpdateDrug)
+	r.PATCH("drug/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.PharmacistRole), h.UpdateDrug)
+	r.DELETE("drug/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.PharmacistRole), h.DeleteDrug)
+	r.POST("drug/:id/restore", h.AuthorizerMiddleware(config.SuperAdminRole), h.RestoreDrug)
+	r.GET("drug/:id/lots", h.AuthorizerMiddleware(config.SuperAdminRole, config.PharmacistRole), h.GetDrugLots)
+
+	// DRUG LOT
+
+	r.POST("drug_lot", h.AuthorizerMiddleware(config.SuperAdminRole, config.PharmacistRole), h.CreateDrugLot)
+	r.GET("drug_lot/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.PharmacistRole), h.GetDrugLotByID)
+	r.PUT("drug_lot/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.PharmacistRole), h.UpdateDrugLot)
+	r.PATCH("drug_lot/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.PharmacistRole), h.UpdateDrugLot)
+	r.DELETE("drug_lot/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.PharmacistRole), h.DeleteDrugLot)
 
 	// JOURNAL
 
-	r.POST("journal", h.CreateJournal)
-	r.GET("journal/:id", h.GetJournalByID)
-	r.GET("journal", h.GetJournalsList)
-	r.PUT("journal/:id", h.UpdateJournal)
-	r.DELETE("journal/:id", h.DeleteJournal)
+	r.POST("journal", h.AuthorizerMiddleware(config.SuperAdminRole, config.AuthorRole), h.CreateJournal)
+	r.GET("journal/search", h.SearchJournals)
+	r.GET("journal/tags", h.GetJournalTags)
+	r.GET("journal/slug/:slug", h.OptionalAuthMiddleware(), h.GetJournalBySlug)
+	r.GET("journal/:id", h.OptionalAuthMiddleware(), h.GetJournalByID)
+	r.GET("journal", h.OptionalAuthMiddleware(), h.GetJournalsList)
+	r.PUT("journal/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.AuthorRole), h.UpdateJournal)
+	r.PATCH("journal/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.AuthorRole), h.UpdateJournal)
+	r.DELETE("journal/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.AuthorRole), h.DeleteJournal)
+	r.POST("journal/:id/restore", h.AuthorizerMiddleware(config.SuperAdminRole), h.RestoreJournal)
+	r.POST("journal/:id/submit", h.AuthorizerMiddleware(config.SuperAdminRole, config.AuthorRole), h.SubmitJournal)
+	r.POST("journal/:id/approve", h.AuthorizerMiddleware(config.SuperAdminRole), h.ApproveJournal)
+	r.POST("journal/:id/reject", h.AuthorizerMiddleware(config.SuperAdminRole), h.RejectJournal)
+	r.POST("journal/:id/archive", h.AuthorizerMiddleware(config.SuperAdminRole, config.AuthorRole), h.ArchiveJournal)
+	r.POST("journal/:id/withdraw", h.AuthorizerMiddleware(config.SuperAdminRole, config.AuthorRole), h.WithdrawJournal)
+	r.GET("journal/:id/revisions", h.AuthorizerMiddleware(config.SuperAdminRole, config.AuthorRole), h.GetJournalRevisions)
+	r.GET("journal/:id/revisions/:version", h.AuthorizerMiddleware(config.SuperAdminRole, config.AuthorRole), h.GetJournalRevision)
+	r.GET("journal/:id/diff", h.AuthorizerMiddleware(config.SuperAdminRole, config.AuthorRole), h.GetJournalDiff)
+	r.POST("journal/:id/rollback/:version", h.AuthorizerMiddleware(config.SuperAdminRole, config.AuthorRole), h.RollbackJournal)
+	r.PUT("journal/:id/classification", h.AuthorizerMiddleware(config.SuperAdminRole, config.AuthorRole), h.ClassifyJournal)
+
+	// JOURNAL CATEGORY
+
+	r.POST("journal_category", h.AuthorizerMiddleware(config.SuperAdminRole), h.CreateJournalCategory)
+	r.GET("journal_category/:id", h.GetJournalCategoryByID)
+	r.GET("journal_category", h.OptionalAuthMiddleware(), h.GetJournalCategoriesList)
+	r.PUT("journal_category/:id", h.AuthorizerMiddleware(config.SuperAdminRole), h.UpdateJournalCategory)
+	r.PATCH("journal_category/:id", h.AuthorizerMiddleware(config.SuperAdminRole), h.UpdateJournalCategory)
+	r.DELETE("journal_category/:id", h.AuthorizerMiddleware(config.SuperAdminRole), h.DeleteJournalCategory)
+	r.POST("journal_category/:id/restore", h.AuthorizerMiddleware(config.SuperAdminRole), h.RestoreJournalCategory)
 
 	// ORDER DRUG
 
-	r.POST("order_drug", h.CreateOrderDrug)
-	r.GET("order_drug/:id", h.GetOrderDrugByID)
-	r.GET("order_drug", h.GetOrderDrugsList)
-	r.PUT("order_drug/:id", h.UpdateOrderDrug)
-	r.DELETE("order_drug/:id", h.DeleteOrderDrug)
+	r.POST("order_drug", h.AuthorizerMiddleware(config.PharmacistRole, config.CustomerRole), h.CreateOrderDrug)
+	r.GET("order_drug/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.PharmacistRole, config.CustomerRole), h.GetOrderDrugByID)
+	r.GET("order_drug", h.AuthorizerMiddleware(config.SuperAdminRole, config.PharmacistRole), h.GetOrderDrugsList)
+	r.PUT("order_drug/:id", h.AuthorizerMiddleware(config.PharmacistRole), h.UpdateOrderDrug)
+	r.PATCH("order_drug/:id", h.AuthorizerMiddleware(config.PharmacistRole), h.UpdateOrderDrug)
+	r.DELETE("order_drug/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.PharmacistRole), h.DeleteOrderDrug)
+	r.POST("order_drug/:id/restore", h.AuthorizerMiddleware(config.SuperAdminRole), h.RestoreOrderDrug)
 
 	// ORDERS
 
-	r.POST("orders", h.CreateOrders)
-	r.GET("orders/:id", h.GetOrdersByID)
-	r.GET("orders", h.GetOrderssList)
-	r.PUT("orders/:id", h.UpdateOrders)
-	r.DELETE("orders/:id", h.DeleteOrders)
+	r.POST("orders", h.AuthorizerMiddleware(config.PharmacistRole, config.CustomerRole), h.CreateOrders)
+	r.POST("orders/checkout", h.AuthorizerMiddleware(config.PharmacistRole, config.CustomerRole), h.CheckoutOrders)
+	r.GET("orders/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.PharmacistRole, config.CustomerRole), h.GetOrdersByID)
+	r.GET("orders", h.AuthorizerMiddleware(config.SuperAdminRole, config.PharmacistRole, config.CustomerRole), h.GetOrderssList)
+	r.PUT("orders/:id", h.AuthorizerMiddleware(config.PharmacistRole), h.UpdateOrders)
+	r.PATCH("orders/:id", h.AuthorizerMiddleware(config.PharmacistRole), h.UpdateOrders)
+	r.DELETE("orders/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.PharmacistRole), h.DeleteOrders)
+	r.POST("orders/:id/restore", h.AuthorizerMiddleware(config.SuperAdminRole), h.RestoreOrders)
+	r.POST("orders/:id/confirm", h.AuthorizerMiddleware(config.PharmacistRole), h.ConfirmOrder)
+	r.POST("orders/:id/prepare", h.AuthorizerMiddleware(config.PharmacistRole), h.PrepareOrder)
+	r.POST("orders/:id/ready", h.AuthorizerMiddleware(config.PharmacistRole), h.ReadyOrder)
+	r.POST("orders/:id/deliver", h.AuthorizerMiddleware(config.PharmacistRole), h.DeliverOrder)
+	r.POST("orders/:id/cancel", h.AuthorizerMiddleware(config.PharmacistRole, config.CustomerRole), h.CancelOrder)
+	r.POST("orders/:id/refund", h.AuthorizerMiddleware(config.SuperAdminRole, config.PharmacistRole), h.RefundOrder)
 
 	// PHARMACIST
 
-	r.POST("pharmacist", h.CreatePharmacist)
-	r.GET("pharmacist/:id", h.GetPharmacistByID)
-	r.GET("pharmacist", h.GetPharmacistsList)
-	r.PUT("pharmacist/:id", h.UpdatePharmacist)
-	r.DELETE("pharmacist/:id", h.DeletePharmacist)
-	r.PATCH("pharmacist/:id", h.UpdatePharmacistPassword)
+	r.POST("pharmacist", h.AuthorizerMiddleware(config.SuperAdminRole), h.CreatePharmacist)
+	r.GET("pharmacist/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.PharmacistRole), h.GetPharmacistByID)
+	r.GET("pharmacist", h.AuthorizerMiddleware(config.SuperAdminRole, config.PharmacistRole), h.GetPharmacistsList)
+	r.PUT("pharmacist/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.PharmacistRole), h.UpdatePharmacist)
+	r.PATCH("pharmacist/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.PharmacistRole), h.UpdatePharmacist)
+	r.DELETE("pharmacist/:id", h.AuthorizerMiddleware(config.SuperAdminRole), h.DeletePharmacist)
+	r.POST("pharmacist/:id/restore", h.AuthorizerMiddleware(config.SuperAdminRole), h.RestorePharmacist)
+	r.PATCH("pharmacist/:id/password", h.AuthorizerMiddleware(config.PharmacistRole), h.UpdatePharmacistPassword)
+
+	// PRESCRIPTION
+
+	r.POST("prescription", h.AuthorizerMiddleware(config.DoctorRole), h.CreatePrescription)
+	r.POST("prescription/redeem", h.AuthorizerMiddleware(config.PharmacistRole), h.RedeemPrescription)
+	r.GET("prescription/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.DoctorRole, config.CustomerRole, config.PharmacistRole), h.GetPrescriptionByID)
+	r.GET("prescription/code/:code", h.AuthorizerMiddleware(config.PharmacistRole), h.GetPrescriptionByCode)
+	r.DELETE("prescription/:id", h.AuthorizerMiddleware(config.DoctorRole), h.DeletePrescription)
+	r.GET("customer/:id/prescriptions", h.AuthorizerMiddleware(config.SuperAdminRole, config.CustomerRole), h.GetCustomerPrescriptions)
 
 	// QUEUE
 
-	r.POST("queue", h.CreateQueue)
-	r.GET("queue/:id", h.GetQueueByID)
-	r.GET("queue", h.GetQueuesList)
-	r.PUT("queue/:id", h.UpdateQueue)
-	r.DELETE("queue/:id", h.DeleteQueue)
+	r.POST("queue", h.AuthorizerMiddleware(config.ClinicAdminRole, config.CustomerRole), h.CreateQueue)
+	r.GET("queue/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.ClinicAdminRole, config.DoctorRole, config.CustomerRole), h.GetQueueByID)
+	r.GET("queue", h.AuthorizerMiddleware(config.SuperAdminRole, config.ClinicAdminRole, config.DoctorRole, config.CustomerRole), h.GetQueuesList)
+	r.PUT("queue/:id", h.AuthorizerMiddleware(config.ClinicAdminRole), h.UpdateQueue)
+	r.PATCH("queue/:id", h.AuthorizerMiddleware(config.ClinicAdminRole), h.UpdateQueue)
+	r.DELETE("queue/:id", h.AuthorizerMiddleware(config.SuperAdminRole, config.ClinicAdminRole), h.DeleteQueue)
+	r.POST("queue/:id/restore", h.AuthorizerMiddleware(config.SuperAdminRole), h.RestoreQueue)
+	r.POST("queue/:id/check_in", h.AuthorizerMiddleware(config.ClinicAdminRole, config.CustomerRole), h.CheckInQueue)
+	r.POST("queue/:id/call", h.AuthorizerMiddleware(config.ClinicAdminRole, config.DoctorRole), h.CallQueue)
+	r.POST("queue/:id/start", h.AuthorizerMiddleware(config.DoctorRole), h.StartQueue)
+	r.POST("queue/:id/complete", h.AuthorizerMiddleware(config.DoctorRole), h.CompleteQueue)
+	r.POST("queue/:id/cancel", h.AuthorizerMiddleware(config.ClinicAdminRole, config.CustomerRole), h.CancelQueue)
+	r.POST("queue/:id/no_show", h.AuthorizerMiddleware(config.ClinicAdminRole, config.DoctorRole), h.NoShowQueue)
 
 	// SUPER ADMIN
 
-	r.POST("super_admin", h.CreateSuperAdmin)
-	r.GET("super_admin/:id", h.GetSuperAdminByID)
-	r.GET("super_admin", h.GetSuperAdminsList)
-	r.PUT("super_admin/:id", h.UpdateSuperAdmin)
-	r.DELETE("super_admin/:id", h.DeleteSuperAdmin)
-	r.PATCH("super_admin/:id", h.UpdateSuperAdminPassword)
+	r.POST("super_admin", h.AuthorizerMiddleware(config.SuperAdminRole), h.CreateSuperAdmin)
+	r.GET("super_admin/:id", h.AuthorizerMiddleware(config.SuperAdminRole), h.GetSuperAdminByID)
+	r.GET("super_admin", h.AuthorizerMiddleware(config.SuperAdminRole), h.GetSuperAdminsList)
+	r.PUT("super_admin/:id", h.AuthorizerMiddleware(config.SuperAdminRole), h.UpdateSuperAdmin)
+	r.PATCH("super_admin/:id", h.AuthorizerMiddleware(config.SuperAdminRole), h.UpdateSuperAdmin)
+	r.DELETE("super_admin/:id", h.AuthorizerMiddleware(config.SuperAdminRole), h.DeleteSuperAdmin)
+	r.POST("super_admin/:id/restore", h.AuthorizerMiddleware(config.SuperAdminRole), h.RestoreSuperAdmin)
+	r.PATCH("super_admin/:id/password", h.AuthorizerMiddleware(config.SuperAdminRole), h.UpdateSuperAdminPassword)
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	return r
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"shifolink/config"
+	"shifolink/service"
+	"sync/atomic"
+	"time"
+)
+
+// Server serves the api over http. It reports itself ready until Drain is called
+type Server struct {
+	httpServer *http.Server
+	ready      *atomic.Bool
+	drainDelay time.Duration
+}
+
+func NewServer(cfg config.Config, services service.IServiceManager) *Server {
+
+	ready := &atomic.Bool{}
+	ready.Store(true)
+
+	return &Server{
+		httpServer: &http.Server{
+			Addr:         net.JoinHostPort(cfg.HTTPHost, cfg.HTTPPort),
+			Handler:      New(services, ready),
+			ReadTimeout:  cfg.HTTPReadTimeout,
+			WriteTimeout: cfg.HTTPWriteTimeout,
+			IdleTimeout:  cfg.HTTPIdleTimeout,
+		},
+		ready:      ready,
+		drainDelay: cfg.HTTPDrainDelay,
+	}
+}
+
+// Run serves requests until Shutdown is called, it returns nil after a shutdown
+func (s *Server) Run() error {
+
+	log.Println("server is listening on", s.httpServer.Addr)
+
+	if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// Drain marks the server as not ready and keeps serving for the drain delay, so load balancers stop sending
+// it requests before it stops taking connections
+func (s *Server) Drain() {
+
+	s.ready.Store(false)
+
+	log.Println("server is draining requests")
+
+	time.Sleep(s.drainDelay)
+}
+
+// Shutdown stops taking connections and waits for the requests in flight until the context is done
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
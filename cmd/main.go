@@ -3,10 +3,13 @@ package main
 import (
 	"context"
 	"log"
+	"os/signal"
 	"shifolink/api"
 	"shifolink/config"
+	"shifolink/pkg/pubsub"
 	"shifolink/service"
 	"shifolink/storage/postgres"
+	"syscall"
 	_ "shifolink/api/docs"
 
 )
@@ -17,9 +20,18 @@ func main() {
 
 	cfg := config.Load()
 
+	if err := cfg.Validate(); err != nil {
+		log.Fatalln("error in config: ", err.Error())
+	}
+
 	// keyin olingan manzil postgresga berib yuboriladi va shu joydan service layerga malumot uzatiladi
 
-	pgStore, err := postgres.New(context.Background(), cfg)
+	// navbat tablosi uchun o'zgarishlar shu broker orqali tarqatiladi
+
+	broker := pubsub.New()
+	defer broker.Close()
+
+	pgStore, err := postgres.New(context.Background(), cfg, broker)
 	if err != nil {
 		log.Fatalln("error while connecting to db err: ", err.Error())
 		return
@@ -29,15 +41,60 @@ func main() {
 
     // service layerda biznes logikalar bajariladi
 
-	services := service.New(pgStore)
+	services := service.New(cfg, pgStore, broker)
+
+	// soft delete qilingan yozuvlar saqlash muddati o'tgach butunlay o'chiriladi
+
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	purgeDone := make(chan struct{})
+
+	// to'xtatilgan tozalash tugashi kutiladi, undan keyingina db ulanishlari yopiladi
+	defer func() {
+		stopPurge()
+		<-purgeDone
+	}()
+
+	go func() {
+		defer close(purgeDone)
+		services.Trash().RunPurge(purgeCtx, cfg.PurgeInterval)
+	}()
 
 	// keyin api orqali dastur ishga tushadi
 
-	server := api.New(services, pgStore)
+	server := api.NewServer(cfg, services)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.Run()
+	}()
+
+	// SIGTERM yoki SIGINT kelganda server yangi so'rovlarni qabul qilishni to'xtatadi va boshlanganlarini tugatadi
+
+	stopCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	select {
+	case err = <-serverErr:
+		if err != nil {
+			log.Println("error while server run", err.Error())
+		}
+		return
+	case <-stopCtx.Done():
+	}
+
+	server.Drain()
+
+	// navbat tablosini kuzatayotgan ulanishlar broker yopilganda tugaydi
+
+	broker.Close()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTPShutdownTimeout)
+	defer cancel()
 
-	if err = server.Run("localhost:8080"); err != nil {
-		log.Println("error while server run")
+	if err = server.Shutdown(shutdownCtx); err != nil {
+		log.Println("error while shutting down server", err.Error())
 		return
 	}
 
+	log.Println("server stopped")
 }
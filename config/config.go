@@ -1,19 +1,111 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/cast"
 )
 
+const (
+	AuthorRole      = "author"
+	ClinicAdminRole = "clinic_admin"
+	CustomerRole    = "customer"
+	DoctorRole      = "doctor"
+	PharmacistRole  = "pharmacist"
+	SuperAdminRole  = "super_admin"
+)
+
+const (
+	QueueBooked         = "booked"
+	QueueCheckedIn      = "checked_in"
+	QueueCalled         = "called"
+	QueueInConsultation = "in_consultation"
+	QueueCompleted      = "completed"
+	QueueCancelled      = "cancelled"
+	QueueNoShow         = "no_show"
+
+	DoctorBusy  = "busy"
+	DoctorEmpty = "empty"
+)
+
+const (
+	OrderPending        = "pending"
+	OrderConfirmed      = "confirmed"
+	OrderPreparing      = "preparing"
+	OrderReadyForPickup = "ready_for_pickup"
+	OrderDelivered      = "delivered"
+	OrderCancelled      = "cancelled"
+	OrderRefunded       = "refunded"
+)
+
+const (
+	JournalDraft     = "draft"
+	JournalInReview  = "in_review"
+	JournalPublished = "published"
+	JournalArchived  = "archived"
+)
+
+// languages of journal articles, each is searched with its own text search configuration
+const (
+	LanguageUzbek   = "uz"
+	LanguageRussian = "ru"
+	LanguageEnglish = "en"
+)
+
+// actions of the audit log
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	// AuditPurge is written by the purge job for every record it removes for good
+	AuditPurge = "purge"
+)
+
+const (
+	BoardSnapshot = "snapshot"
+	BoardCreated  = "created"
+	BoardUpdated  = "updated"
+	BoardDeleted  = "deleted"
+)
+
 type Config struct {
+	HTTPHost string
+	HTTPPort string
+
+	HTTPReadTimeout  time.Duration
+	HTTPWriteTimeout time.Duration
+	HTTPIdleTimeout  time.Duration
+	// HTTPDrainDelay is how long the server keeps answering as not ready before it stops taking
+	// connections, so load balancers stop sending requests to it
+	HTTPDrainDelay time.Duration
+	// HTTPShutdownTimeout is how long requests in flight may take to finish once the server stops
+	HTTPShutdownTimeout time.Duration
+
 	PostgresHost     string
 	PostgresPort     string
 	PostgresUser     string
 	PostgresPassword string
 	PostgresDB       string
+
+	JWTSecretKey           string
+	AccessTokenExpireTime  time.Duration
+	RefreshTokenExpireTime time.Duration
+
+	// QueueNumberFormat is a fmt format for the per doctor per day queue number, e.g. "A-%03d" gives A-001
+	QueueNumberFormat string
+
+	// Currency is the ISO 4217 code order totals are kept in
+	Currency string
+
+	// PurgeRetention is how long soft deleted records are kept before they are removed for good,
+	// the purge job looks for them every PurgeInterval
+	PurgeRetention time.Duration
+	PurgeInterval  time.Duration
 }
 
 func Load() Config {
@@ -24,15 +116,51 @@ func Load() Config {
 
 	cfg := Config{}
 
+	// an empty host listens on every interface, so the service can be reached from outside its container
+	cfg.HTTPHost = cast.ToString(getOrReturnDefault("HTTP_HOST", ""))
+	cfg.HTTPPort = cast.ToString(getOrReturnDefault("HTTP_PORT", "8080"))
+
+	cfg.HTTPReadTimeout = cast.ToDuration(getOrReturnDefault("HTTP_READ_TIMEOUT", "15s"))
+	cfg.HTTPWriteTimeout = cast.ToDuration(getOrReturnDefault("HTTP_WRITE_TIMEOUT", "30s"))
+	cfg.HTTPIdleTimeout = cast.ToDuration(getOrReturnDefault("HTTP_IDLE_TIMEOUT", "60s"))
+	cfg.HTTPDrainDelay = cast.ToDuration(getOrReturnDefault("HTTP_DRAIN_DELAY", "5s"))
+	cfg.HTTPShutdownTimeout = cast.ToDuration(getOrReturnDefault("HTTP_SHUTDOWN_TIMEOUT", "30s"))
+
 	cfg.PostgresHost = cast.ToString(getOrReturnDefault("POSTGRES_HOST", "localhost"))
 	cfg.PostgresPort = cast.ToString(getOrReturnDefault("POSTGRES_PORT", "5432"))
 	cfg.PostgresUser = cast.ToString(getOrReturnDefault("POSTGRES_USER", "postgres"))
 	cfg.PostgresPassword = cast.ToString(getOrReturnDefault("POSTGRES_PASSWORD", "password"))
 	cfg.PostgresDB = cast.ToString(getOrReturnDefault("POSTGRES_DB", "db"))
 
+	// there is no default secret, tokens signed with a known one could be forged for every role
+	cfg.JWTSecretKey = cast.ToString(getOrReturnDefault("JWT_SECRET_KEY", ""))
+	cfg.AccessTokenExpireTime = cast.ToDuration(getOrReturnDefault("ACCESS_TOKEN_EXPIRE_TIME", "1h"))
+	cfg.RefreshTokenExpireTime = cast.ToDuration(getOrReturnDefault("REFRESH_TOKEN_EXPIRE_TIME", "720h"))
+
+	cfg.QueueNumberFormat = cast.ToString(getOrReturnDefault("QUEUE_NUMBER_FORMAT", "%03d"))
+
+	cfg.Currency = cast.ToString(getOrReturnDefault("CURRENCY", "UZS"))
+
+	cfg.PurgeRetention = cast.ToDuration(getOrReturnDefault("PURGE_RETENTION", "720h"))
+	cfg.PurgeInterval = cast.ToDuration(getOrReturnDefault("PURGE_INTERVAL", "24h"))
+
 	return cfg
 }
 
+// Validate reports settings the service can not start with
+func (c Config) Validate() error {
+
+	if c.JWTSecretKey == "" {
+		return errors.New("JWT_SECRET_KEY is not set")
+	}
+
+	if c.PurgeInterval <= 0 {
+		return errors.New("PURGE_INTERVAL should be a positive duration, like 24h")
+	}
+
+	return nil
+}
+
 func getOrReturnDefault(key string, defaultValue interface{}) interface{} {
 	value := os.Getenv(key)
 	if value != "" {
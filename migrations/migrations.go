@@ -0,0 +1,9 @@
+// Package migrations keeps the schema migrations in the binary, so they are found whatever the working directory is
+package migrations
+
+import "embed"
+
+// Postgres has the migrations of the database under postgres/
+//
+//go:embed postgres/*.sql
+var Postgres embed.FS
@@ -0,0 +1 @@
+-- hashed passwords can not be turned back into plaintext, nothing to revert
@@ -0,0 +1,15 @@
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+
+-- bcrypt hashes start with $2a$/$2b$/$2y$, everything else is a plaintext password left from before hashing
+
+UPDATE author SET password = crypt(password, gen_salt('bf', 10)) WHERE password !~ '^\$2[aby]\$';
+
+UPDATE clinic_admin SET password = crypt(password, gen_salt('bf', 10)) WHERE password !~ '^\$2[aby]\$';
+
+UPDATE customer SET password = crypt(password, gen_salt('bf', 10)) WHERE password !~ '^\$2[aby]\$';
+
+UPDATE doctor SET password = crypt(password, gen_salt('bf', 10)) WHERE password !~ '^\$2[aby]\$';
+
+UPDATE pharmacist SET password = crypt(password, gen_salt('bf', 10)) WHERE password !~ '^\$2[aby]\$';
+
+UPDATE super_admin SET password = crypt(password, gen_salt('bf', 10)) WHERE password !~ '^\$2[aby]\$';
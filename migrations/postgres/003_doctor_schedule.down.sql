@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS queue_doctor_id_start_time_idx;
+
+ALTER TABLE queue ADD COLUMN IF NOT EXISTS queue_time VARCHAR(10) NOT NULL DEFAULT '';
+
+UPDATE queue SET queue_time = to_char(start_time, 'HH24:MI') WHERE start_time IS NOT NULL;
+
+ALTER TABLE queue DROP COLUMN IF EXISTS start_time;
+ALTER TABLE queue DROP COLUMN IF EXISTS end_time;
+
+DROP TABLE IF EXISTS doctor_schedule;
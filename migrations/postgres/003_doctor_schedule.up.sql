@@ -0,0 +1,47 @@
+CREATE TABLE IF NOT EXISTS doctor_schedule (
+    id UUID PRIMARY KEY,
+    doctor_id UUID NOT NULL REFERENCES doctor(id),
+    weekday INT NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    break_start TIME,
+    break_end TIME,
+    slot_duration INT NOT NULL CHECK (slot_duration > 0),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP,
+    CHECK (start_time < end_time),
+    CHECK (
+        (break_start IS NULL AND break_end IS NULL) OR
+        (break_start < break_end AND break_start >= start_time AND break_end <= end_time)
+    )
+);
+
+-- one working day per weekday for each doctor
+CREATE UNIQUE INDEX IF NOT EXISTS doctor_schedule_doctor_id_weekday_idx ON doctor_schedule (doctor_id, weekday) WHERE deleted_at IS NULL;
+
+ALTER TABLE queue ADD COLUMN IF NOT EXISTS start_time TIMESTAMP;
+ALTER TABLE queue ADD COLUMN IF NOT EXISTS end_time TIMESTAMP;
+
+-- old queue_time values like '10:30' are kept on the day the queue was created
+UPDATE queue SET
+    start_time = created_at::date + queue_time::time,
+    end_time = created_at::date + queue_time::time
+WHERE queue_time ~ '^\d{1,2}:\d{2}$';
+
+ALTER TABLE queue DROP COLUMN IF EXISTS queue_time;
+
+-- queues booked twice for the same doctor and time before the index existed keep only the first booking,
+-- later ones are soft deleted so they can still be looked at
+UPDATE queue SET deleted_at = NOW()
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (PARTITION BY doctor_id, start_time ORDER BY created_at, id) AS position
+        FROM queue
+        WHERE deleted_at IS NULL AND doctor_id IS NOT NULL AND start_time IS NOT NULL
+    ) booked
+    WHERE position > 1
+);
+
+-- last line of defence against double booking, queueRepo checks overlaps inside a transaction
+CREATE UNIQUE INDEX IF NOT EXISTS queue_doctor_id_start_time_idx ON queue (doctor_id, start_time) WHERE deleted_at IS NULL;
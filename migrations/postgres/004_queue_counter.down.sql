@@ -1,5 +1,10 @@
---queue table ga malumot kiritganda queue number uchun quyidagi trigger ishlaydi
+DROP INDEX IF EXISTS queue_doctor_id_date_number_idx;
 
+ALTER TABLE queue ALTER COLUMN queue_number TYPE VARCHAR(15);
+
+DROP TABLE IF EXISTS queue_counter;
+
+-- queue numbers are generated by the trigger again, as before the counter table
 CREATE OR REPLACE FUNCTION generate_queue_number() RETURNS TRIGGER AS $$
 DECLARE
     doc_count INTEGER;
@@ -16,6 +21,7 @@ BEGIN
 END;
 $$ LANGUAGE plpgsql;
 
+DROP TRIGGER IF EXISTS before_insert_queue ON queue;
 CREATE TRIGGER before_insert_queue
 BEFORE INSERT ON queue
 FOR EACH ROW
@@ -0,0 +1,34 @@
+-- queue numbers were generated by a trigger, queueRepo assigns them now. The down migration brings the trigger back
+DROP TRIGGER IF EXISTS before_insert_queue ON queue;
+DROP FUNCTION IF EXISTS generate_queue_number();
+
+CREATE TABLE IF NOT EXISTS queue_counter (
+    doctor_id UUID NOT NULL REFERENCES doctor(id),
+    queue_date DATE NOT NULL,
+    last_number INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (doctor_id, queue_date)
+);
+
+-- continue numbering after the queues which already exist, a day numbered by the old trigger may have
+-- numbers bigger than its count of queues
+INSERT INTO queue_counter (doctor_id, queue_date, last_number)
+SELECT doctor_id, start_time::date,
+       GREATEST(COUNT(*), COALESCE(MAX(CASE WHEN queue_number ~ '^\d{1,9}$' THEN queue_number::int END), 0))
+FROM queue
+WHERE doctor_id IS NOT NULL AND start_time IS NOT NULL
+GROUP BY doctor_id, start_time::date
+ON CONFLICT (doctor_id, queue_date) DO NOTHING;
+
+ALTER TABLE queue ALTER COLUMN queue_number TYPE VARCHAR(30);
+
+-- numbers given twice on the same day of a doctor are kept by the first queue, the next ones get a suffix
+UPDATE queue SET queue_number = numbered.queue_number || '-' || numbered.position
+FROM (
+    SELECT id, queue_number,
+           ROW_NUMBER() OVER (PARTITION BY doctor_id, start_time::date, queue_number ORDER BY created_at, id) AS position
+    FROM queue
+    WHERE doctor_id IS NOT NULL AND start_time IS NOT NULL
+) numbered
+WHERE queue.id = numbered.id AND numbered.position > 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS queue_doctor_id_date_number_idx ON queue (doctor_id, (start_time::date), queue_number);
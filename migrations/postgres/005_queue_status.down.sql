@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS queue_doctor_id_start_time_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS queue_doctor_id_start_time_idx ON queue (doctor_id, start_time) WHERE deleted_at IS NULL;
+
+ALTER TABLE queue DROP COLUMN IF EXISTS status;
@@ -0,0 +1,6 @@
+ALTER TABLE queue ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'booked'
+    CHECK (status IN ('booked', 'checked_in', 'called', 'in_consultation', 'completed', 'cancelled', 'no_show'));
+
+-- a cancelled queue frees its slot for other customers
+DROP INDEX IF EXISTS queue_doctor_id_start_time_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS queue_doctor_id_start_time_idx ON queue (doctor_id, start_time) WHERE deleted_at IS NULL AND status <> 'cancelled';
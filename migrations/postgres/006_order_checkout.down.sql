@@ -0,0 +1,5 @@
+ALTER TABLE drug DROP CONSTRAINT IF EXISTS drug_count_check;
+
+ALTER TABLE order_drug DROP COLUMN IF EXISTS quantity;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS drug_store_branch_id;
@@ -0,0 +1,5 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS drug_store_branch_id UUID REFERENCES drug_store_branch(id);
+
+ALTER TABLE order_drug ADD COLUMN IF NOT EXISTS quantity INT NOT NULL DEFAULT 1 CHECK (quantity > 0);
+
+ALTER TABLE drug ADD CONSTRAINT drug_count_check CHECK (count >= 0) NOT VALID;
@@ -0,0 +1,7 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS currency;
+ALTER TABLE orders DROP COLUMN IF EXISTS total;
+ALTER TABLE orders DROP COLUMN IF EXISTS discount;
+ALTER TABLE orders DROP COLUMN IF EXISTS subtotal;
+
+ALTER TABLE order_drug DROP COLUMN IF EXISTS line_total;
+ALTER TABLE order_drug DROP COLUMN IF EXISTS unit_price;
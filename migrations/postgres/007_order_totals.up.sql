@@ -0,0 +1,21 @@
+ALTER TABLE order_drug ADD COLUMN IF NOT EXISTS unit_price NUMERIC(100,2) NOT NULL DEFAULT 0;
+ALTER TABLE order_drug ADD COLUMN IF NOT EXISTS line_total NUMERIC(100,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS subtotal NUMERIC(100,2) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS discount NUMERIC(100,2) NOT NULL DEFAULT 0 CHECK (discount >= 0);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS total NUMERIC(100,2) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'UZS';
+
+-- the price paid for old lines is unknown, the current drug price is the best guess
+UPDATE order_drug od SET
+    unit_price = d.price,
+    line_total = d.price * od.quantity
+FROM drug d WHERE d.id = od.drug_id;
+
+UPDATE orders o SET
+    subtotal = t.subtotal,
+    total = t.subtotal
+FROM (
+    SELECT orders_id, SUM(line_total) AS subtotal FROM order_drug
+    WHERE deleted_at IS NULL GROUP BY orders_id
+) t WHERE t.orders_id = o.id;
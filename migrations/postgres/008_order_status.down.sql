@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS orders_status_idx;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS refunded_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS cancelled_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS delivered_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS ready_for_pickup_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS preparing_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS confirmed_at;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS status;
@@ -0,0 +1,11 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'pending'
+    CHECK (status IN ('pending', 'confirmed', 'preparing', 'ready_for_pickup', 'delivered', 'cancelled', 'refunded'));
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS confirmed_at TIMESTAMP;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS preparing_at TIMESTAMP;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS ready_for_pickup_at TIMESTAMP;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS delivered_at TIMESTAMP;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMP;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS refunded_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS orders_status_idx ON orders (status) WHERE deleted_at IS NULL;
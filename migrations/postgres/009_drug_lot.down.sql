@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS order_drug_lot;
+DROP TABLE IF EXISTS drug_lot;
+
+ALTER TABLE drug ALTER COLUMN best_before TYPE VARCHAR(15) USING coalesce(to_char(best_before, 'YYYY-MM-DD'), '');
+ALTER TABLE drug ALTER COLUMN date_of_manufacture TYPE VARCHAR(50) USING coalesce(to_char(date_of_manufacture, 'YYYY-MM-DD'), '');
+ALTER TABLE drug ALTER COLUMN best_before SET NOT NULL;
+ALTER TABLE drug ALTER COLUMN date_of_manufacture SET NOT NULL;
@@ -0,0 +1,43 @@
+-- dates were free text, values which are not real dates become NULL
+CREATE OR REPLACE FUNCTION pg_temp.to_date_or_null(value TEXT) RETURNS DATE AS $$
+BEGIN
+    RETURN value::DATE;
+EXCEPTION WHEN others THEN
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE drug ALTER COLUMN date_of_manufacture DROP NOT NULL;
+ALTER TABLE drug ALTER COLUMN best_before DROP NOT NULL;
+ALTER TABLE drug ALTER COLUMN date_of_manufacture TYPE DATE USING pg_temp.to_date_or_null(date_of_manufacture);
+ALTER TABLE drug ALTER COLUMN best_before TYPE DATE USING pg_temp.to_date_or_null(best_before);
+
+-- stock of a drug is kept in lots, drug.count is the sum of its lots
+CREATE TABLE IF NOT EXISTS drug_lot (
+    id UUID PRIMARY KEY,
+    drug_id UUID NOT NULL REFERENCES drug(id),
+    lot_number VARCHAR(50) NOT NULL,
+    quantity INT NOT NULL CHECK (quantity >= 0),
+    expiry_date DATE,
+    purchase_price NUMERIC(100,2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS drug_lot_drug_id_expiry_date_idx ON drug_lot (drug_id, expiry_date) WHERE deleted_at IS NULL;
+
+-- lots an order line was taken from, used to put the drugs back when the order is cancelled
+CREATE TABLE IF NOT EXISTS order_drug_lot (
+    id UUID PRIMARY KEY,
+    order_drug_id UUID NOT NULL REFERENCES order_drug(id),
+    drug_lot_id UUID NOT NULL REFERENCES drug_lot(id),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS order_drug_lot_order_drug_id_idx ON order_drug_lot (order_drug_id);
+
+INSERT INTO drug_lot (id, drug_id, lot_number, quantity, expiry_date, purchase_price)
+SELECT gen_random_uuid(), id, 'INITIAL', count, best_before, price
+FROM drug WHERE deleted_at IS NULL AND count > 0;
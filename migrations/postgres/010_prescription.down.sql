@@ -0,0 +1,7 @@
+ALTER TABLE order_drug DROP COLUMN IF EXISTS prescription_item_id;
+ALTER TABLE orders DROP COLUMN IF EXISTS prescription_id;
+
+DROP TABLE IF EXISTS prescription_item;
+DROP TABLE IF EXISTS prescription;
+
+ALTER TABLE drug DROP COLUMN IF EXISTS prescription_required;
@@ -0,0 +1,36 @@
+ALTER TABLE drug ADD COLUMN IF NOT EXISTS prescription_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS prescription (
+    id UUID PRIMARY KEY,
+    code VARCHAR(12) NOT NULL,
+    queue_id UUID NOT NULL REFERENCES queue(id),
+    doctor_id UUID NOT NULL REFERENCES doctor(id),
+    customer_id UUID NOT NULL REFERENCES customer(id),
+    note TEXT NOT NULL DEFAULT '',
+    valid_until DATE NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS prescription_code_idx ON prescription (code);
+CREATE INDEX IF NOT EXISTS prescription_customer_id_idx ON prescription (customer_id) WHERE deleted_at IS NULL;
+
+-- drugs are matched by name at the pharmacy, the same drug is a different row in every drug store branch
+CREATE TABLE IF NOT EXISTS prescription_item (
+    id UUID PRIMARY KEY,
+    prescription_id UUID NOT NULL REFERENCES prescription(id),
+    drug_name VARCHAR(50) NOT NULL,
+    dosage VARCHAR(100) NOT NULL,
+    duration_days INT NOT NULL CHECK (duration_days > 0),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    refills INT NOT NULL DEFAULT 0 CHECK (refills >= 0),
+    redeemed_count INT NOT NULL DEFAULT 0 CHECK (redeemed_count >= 0 AND redeemed_count <= refills + 1),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS prescription_item_prescription_id_idx ON prescription_item (prescription_id);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS prescription_id UUID REFERENCES prescription(id);
+ALTER TABLE order_drug ADD COLUMN IF NOT EXISTS prescription_item_id UUID REFERENCES prescription_item(id);
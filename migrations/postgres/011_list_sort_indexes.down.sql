@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS orders_created_at_id_idx;
+DROP INDEX IF EXISTS queue_start_time_id_idx;
+DROP INDEX IF EXISTS queue_created_at_id_idx;
@@ -0,0 +1,4 @@
+-- keyset pagination of the largest lists walks these indexes instead of counting skipped rows
+CREATE INDEX IF NOT EXISTS queue_created_at_id_idx ON queue (created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS queue_start_time_id_idx ON queue (start_time, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS orders_created_at_id_idx ON orders (created_at, id) WHERE deleted_at IS NULL;
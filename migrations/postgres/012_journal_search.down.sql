@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS journal_search_vector_idx;
+
+ALTER TABLE journal DROP COLUMN IF EXISTS search_vector;
+
+DROP FUNCTION IF EXISTS journal_search_query(TEXT, VARCHAR);
+DROP FUNCTION IF EXISTS journal_search_config(VARCHAR);
+
+ALTER TABLE journal DROP COLUMN IF EXISTS language;
@@ -0,0 +1,31 @@
+ALTER TABLE journal ADD COLUMN IF NOT EXISTS language VARCHAR(2) NOT NULL DEFAULT 'uz'
+    CHECK (language IN ('uz', 'ru', 'en'));
+
+-- postgres has no Uzbek dictionary, Uzbek articles are only lowercased and split into words
+CREATE OR REPLACE FUNCTION journal_search_config(lang VARCHAR) RETURNS regconfig
+    LANGUAGE sql IMMUTABLE AS $$
+    SELECT CASE lang
+        WHEN 'ru' THEN 'russian'::regconfig
+        WHEN 'en' THEN 'english'::regconfig
+        ELSE 'simple'::regconfig
+    END
+$$;
+
+-- without a language the text is looked up in every configuration so a word matches articles of any language
+CREATE OR REPLACE FUNCTION journal_search_query(search TEXT, lang VARCHAR) RETURNS tsquery
+    LANGUAGE sql IMMUTABLE AS $$
+    SELECT CASE WHEN lang = '' THEN
+        websearch_to_tsquery('simple', search) ||
+        websearch_to_tsquery('russian', search) ||
+        websearch_to_tsquery('english', search)
+    ELSE
+        websearch_to_tsquery(journal_search_config(lang), search)
+    END
+$$;
+
+ALTER TABLE journal ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector(journal_search_config(language), theme), 'A') ||
+    setweight(to_tsvector(journal_search_config(language), article), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS journal_search_vector_idx ON journal USING GIN (search_vector);
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS journal_revision;
+
+DROP INDEX IF EXISTS journal_published_at_idx;
+DROP INDEX IF EXISTS journal_slug_idx;
+
+ALTER TABLE journal DROP COLUMN IF EXISTS slug;
+ALTER TABLE journal DROP COLUMN IF EXISTS version;
+ALTER TABLE journal DROP COLUMN IF EXISTS review_note;
+ALTER TABLE journal DROP COLUMN IF EXISTS reviewed_by;
+ALTER TABLE journal DROP COLUMN IF EXISTS published_at;
+ALTER TABLE journal DROP COLUMN IF EXISTS status;
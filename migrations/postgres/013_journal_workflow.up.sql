@@ -0,0 +1,39 @@
+-- articles written before the workflow were already public, they stay published and new ones start as drafts
+ALTER TABLE journal ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published'
+    CHECK (status IN ('draft', 'in_review', 'published', 'archived'));
+ALTER TABLE journal ALTER COLUMN status SET DEFAULT 'draft';
+
+-- published_at is when the article becomes public, a future time schedules the release
+ALTER TABLE journal ADD COLUMN IF NOT EXISTS published_at TIMESTAMP;
+UPDATE journal SET published_at = created_at WHERE status = 'published' AND published_at IS NULL;
+
+ALTER TABLE journal ADD COLUMN IF NOT EXISTS reviewed_by UUID REFERENCES super_admin(id);
+ALTER TABLE journal ADD COLUMN IF NOT EXISTS review_note TEXT NOT NULL DEFAULT '';
+ALTER TABLE journal ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+
+ALTER TABLE journal ADD COLUMN IF NOT EXISTS slug VARCHAR(200);
+UPDATE journal SET slug = coalesce(nullif(trim(BOTH '-' FROM regexp_replace(lower(theme), '[^a-z0-9]+', '-', 'g')), ''), 'journal')
+    || '-' || left(id::text, 8)
+    WHERE slug IS NULL;
+ALTER TABLE journal ALTER COLUMN slug SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS journal_slug_idx ON journal (slug);
+CREATE INDEX IF NOT EXISTS journal_published_at_idx ON journal (published_at) WHERE deleted_at IS NULL AND status = 'published';
+
+-- every saved version of the theme, the article and the language, version 1 is the text it was created with
+CREATE TABLE IF NOT EXISTS journal_revision (
+    id UUID PRIMARY KEY,
+    journal_id UUID NOT NULL REFERENCES journal(id),
+    version INT NOT NULL,
+    theme VARCHAR(150) NOT NULL,
+    article TEXT NOT NULL,
+    language VARCHAR(2) NOT NULL,
+    edited_by UUID,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (journal_id, version)
+);
+
+INSERT INTO journal_revision (id, journal_id, version, theme, article, language, edited_by, created_at)
+SELECT gen_random_uuid(), id, version, theme, article, language, author_id, coalesce(updated_at, created_at)
+    FROM journal
+    ON CONFLICT (journal_id, version) DO NOTHING;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS journal_doctor_type;
+DROP TABLE IF EXISTS journal_tag;
+
+DROP INDEX IF EXISTS journal_category_id_idx;
+ALTER TABLE journal DROP COLUMN IF EXISTS category_id;
+
+DROP TABLE IF EXISTS journal_category;
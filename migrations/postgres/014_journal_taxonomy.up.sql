@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS journal_category (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS journal_category_name_idx ON journal_category (lower(name)) WHERE deleted_at IS NULL;
+
+ALTER TABLE journal ADD COLUMN IF NOT EXISTS category_id UUID REFERENCES journal_category(id);
+
+CREATE INDEX IF NOT EXISTS journal_category_id_idx ON journal (category_id) WHERE deleted_at IS NULL;
+
+-- tags are lowercase latin words joined by "-", so "Heart health" and "heart-health" are one tag
+CREATE TABLE IF NOT EXISTS journal_tag (
+    journal_id UUID NOT NULL REFERENCES journal(id),
+    tag VARCHAR(50) NOT NULL,
+    PRIMARY KEY (journal_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS journal_tag_tag_idx ON journal_tag (tag);
+
+-- specialties an article is relevant for, shown next to profiles of doctors of that type
+CREATE TABLE IF NOT EXISTS journal_doctor_type (
+    journal_id UUID NOT NULL REFERENCES journal(id),
+    doctor_type_id UUID NOT NULL REFERENCES doctor_type(id),
+    PRIMARY KEY (journal_id, doctor_type_id)
+);
+
+CREATE INDEX IF NOT EXISTS journal_doctor_type_doctor_type_id_idx ON journal_doctor_type (doctor_type_id);
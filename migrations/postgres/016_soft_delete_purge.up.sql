@@ -0,0 +1,37 @@
+-- restoring a soft deleted record is written to the audit log too
+ALTER TABLE audit_log DROP CONSTRAINT IF EXISTS audit_log_action_check;
+ALTER TABLE audit_log ADD CONSTRAINT audit_log_action_check CHECK (action IN ('create', 'update', 'delete', 'restore'));
+
+-- rows which have no deleted_at of their own go away with the record they belong to when it is purged
+ALTER TABLE journal_tag DROP CONSTRAINT IF EXISTS journal_tag_journal_id_fkey;
+ALTER TABLE journal_tag ADD CONSTRAINT journal_tag_journal_id_fkey
+    FOREIGN KEY (journal_id) REFERENCES journal(id) ON DELETE CASCADE;
+
+ALTER TABLE journal_doctor_type DROP CONSTRAINT IF EXISTS journal_doctor_type_journal_id_fkey;
+ALTER TABLE journal_doctor_type ADD CONSTRAINT journal_doctor_type_journal_id_fkey
+    FOREIGN KEY (journal_id) REFERENCES journal(id) ON DELETE CASCADE;
+
+ALTER TABLE journal_doctor_type DROP CONSTRAINT IF EXISTS journal_doctor_type_doctor_type_id_fkey;
+ALTER TABLE journal_doctor_type ADD CONSTRAINT journal_doctor_type_doctor_type_id_fkey
+    FOREIGN KEY (doctor_type_id) REFERENCES doctor_type(id) ON DELETE CASCADE;
+
+ALTER TABLE journal_revision DROP CONSTRAINT IF EXISTS journal_revision_journal_id_fkey;
+ALTER TABLE journal_revision ADD CONSTRAINT journal_revision_journal_id_fkey
+    FOREIGN KEY (journal_id) REFERENCES journal(id) ON DELETE CASCADE;
+
+ALTER TABLE prescription_item DROP CONSTRAINT IF EXISTS prescription_item_prescription_id_fkey;
+ALTER TABLE prescription_item ADD CONSTRAINT prescription_item_prescription_id_fkey
+    FOREIGN KEY (prescription_id) REFERENCES prescription(id) ON DELETE CASCADE;
+
+ALTER TABLE order_drug_lot DROP CONSTRAINT IF EXISTS order_drug_lot_order_drug_id_fkey;
+ALTER TABLE order_drug_lot ADD CONSTRAINT order_drug_lot_order_drug_id_fkey
+    FOREIGN KEY (order_drug_id) REFERENCES order_drug(id) ON DELETE CASCADE;
+
+ALTER TABLE queue_counter DROP CONSTRAINT IF EXISTS queue_counter_doctor_id_fkey;
+ALTER TABLE queue_counter ADD CONSTRAINT queue_counter_doctor_id_fkey
+    FOREIGN KEY (doctor_id) REFERENCES doctor(id) ON DELETE CASCADE;
+
+-- deleting a clinic soft deletes its branches, their doctor types and doctors
+CREATE INDEX IF NOT EXISTS clinic_branch_clinic_id_idx ON clinic_branch (clinic_id);
+CREATE INDEX IF NOT EXISTS doctor_type_clinic_branch_id_idx ON doctor_type (clinic_branch_id);
+CREATE INDEX IF NOT EXISTS doctor_doctor_type_id_idx ON doctor (doctor_type_id);
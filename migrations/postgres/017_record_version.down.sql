@@ -0,0 +1,18 @@
+ALTER TABLE author DROP COLUMN IF EXISTS version;
+ALTER TABLE clinic DROP COLUMN IF EXISTS version;
+ALTER TABLE clinic_admin DROP COLUMN IF EXISTS version;
+ALTER TABLE clinic_branch DROP COLUMN IF EXISTS version;
+ALTER TABLE customer DROP COLUMN IF EXISTS version;
+ALTER TABLE doctor DROP COLUMN IF EXISTS version;
+ALTER TABLE doctor_schedule DROP COLUMN IF EXISTS version;
+ALTER TABLE doctor_type DROP COLUMN IF EXISTS version;
+ALTER TABLE drug DROP COLUMN IF EXISTS version;
+ALTER TABLE drug_lot DROP COLUMN IF EXISTS version;
+ALTER TABLE drug_store DROP COLUMN IF EXISTS version;
+ALTER TABLE drug_store_branch DROP COLUMN IF EXISTS version;
+ALTER TABLE journal_category DROP COLUMN IF EXISTS version;
+ALTER TABLE order_drug DROP COLUMN IF EXISTS version;
+ALTER TABLE orders DROP COLUMN IF EXISTS version;
+ALTER TABLE pharmacist DROP COLUMN IF EXISTS version;
+ALTER TABLE queue DROP COLUMN IF EXISTS version;
+ALTER TABLE super_admin DROP COLUMN IF EXISTS version;
@@ -0,0 +1,2 @@
+ALTER TABLE prescription_item DROP CONSTRAINT IF EXISTS prescription_item_dispensed_quantity_check;
+ALTER TABLE prescription_item DROP COLUMN IF EXISTS dispensed_quantity;
@@ -0,0 +1,19 @@
+-- every fill of an item allows its quantity, dispensed_quantity counts units sold over all fills so a partial
+-- fill keeps the rest of its quantity for the next sale. redeemed_count is the fills begun, derived from it
+ALTER TABLE prescription_item ADD COLUMN IF NOT EXISTS dispensed_quantity INT NOT NULL DEFAULT 0;
+
+UPDATE prescription_item pi SET dispensed_quantity = LEAST(sold.quantity, pi.quantity * (pi.refills + 1))
+FROM (
+    SELECT od.prescription_item_id, SUM(od.quantity) AS quantity
+    FROM order_drug od
+    JOIN orders o ON o.id = od.orders_id
+    WHERE od.prescription_item_id IS NOT NULL AND o.status <> 'cancelled'
+    GROUP BY od.prescription_item_id
+) sold
+WHERE pi.id = sold.prescription_item_id;
+
+UPDATE prescription_item SET redeemed_count = CEIL(dispensed_quantity::numeric / quantity);
+
+ALTER TABLE prescription_item DROP CONSTRAINT IF EXISTS prescription_item_dispensed_quantity_check;
+ALTER TABLE prescription_item ADD CONSTRAINT prescription_item_dispensed_quantity_check
+    CHECK (dispensed_quantity >= 0 AND dispensed_quantity <= quantity * (refills + 1));
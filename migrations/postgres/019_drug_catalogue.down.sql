@@ -0,0 +1,9 @@
+ALTER TABLE prescription_item DROP COLUMN IF EXISTS drug_catalogue_id;
+
+DROP INDEX IF EXISTS drug_lot_drug_store_branch_id_expiry_date_idx;
+ALTER TABLE drug_lot DROP COLUMN IF EXISTS drug_store_branch_id;
+
+DROP INDEX IF EXISTS drug_drug_catalogue_id_idx;
+ALTER TABLE drug DROP COLUMN IF EXISTS drug_catalogue_id;
+
+DROP TABLE IF EXISTS drug_catalogue;
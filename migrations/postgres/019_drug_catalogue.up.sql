@@ -0,0 +1,56 @@
+-- drug_catalogue is the list of drugs shared by all drug stores, a drug row is the offer of a catalogue drug at a branch
+CREATE TABLE IF NOT EXISTS drug_catalogue (
+    id UUID PRIMARY KEY,
+    name VARCHAR(50) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    prescription_required BOOLEAN NOT NULL DEFAULT FALSE,
+    version INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS drug_catalogue_name_idx ON drug_catalogue (lower(name)) WHERE deleted_at IS NULL;
+
+-- drugs of the same name across branches become one catalogue entry, the oldest drug gives its details
+INSERT INTO drug_catalogue (id, name, description, prescription_required)
+SELECT gen_random_uuid(), name, description, prescription_required
+FROM (
+    SELECT DISTINCT ON (lower(trim(name))) trim(name) AS name, description, prescription_required
+    FROM drug WHERE deleted_at IS NULL
+    ORDER BY lower(trim(name)), created_at
+) drugs;
+
+ALTER TABLE drug ADD COLUMN IF NOT EXISTS drug_catalogue_id UUID REFERENCES drug_catalogue(id);
+
+UPDATE drug SET drug_catalogue_id = c.id,
+    name = c.name,
+    prescription_required = c.prescription_required
+FROM drug_catalogue c WHERE lower(c.name) = lower(trim(drug.name));
+
+-- soft deleted drugs whose name is no longer sold anywhere get a deleted catalogue entry of their own
+INSERT INTO drug_catalogue (id, name, description, prescription_required, deleted_at)
+SELECT DISTINCT ON (lower(trim(name))) gen_random_uuid(), trim(name), description, prescription_required, deleted_at
+FROM drug WHERE drug_catalogue_id IS NULL
+ORDER BY lower(trim(name)), deleted_at DESC;
+
+UPDATE drug SET drug_catalogue_id = c.id
+FROM drug_catalogue c WHERE drug.drug_catalogue_id IS NULL AND c.deleted_at IS NOT NULL AND lower(c.name) = lower(trim(drug.name));
+
+ALTER TABLE drug ALTER COLUMN drug_catalogue_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS drug_drug_catalogue_id_idx ON drug (drug_catalogue_id) WHERE deleted_at IS NULL;
+
+-- a lot is stock held by one branch, it stays with the branch of its drug.
+-- Lots of old drugs which were added without a branch stay without one
+ALTER TABLE drug_lot ADD COLUMN IF NOT EXISTS drug_store_branch_id UUID REFERENCES drug_store_branch(id);
+
+UPDATE drug_lot SET drug_store_branch_id = d.drug_store_branch_id FROM drug d WHERE d.id = drug_lot.drug_id;
+
+CREATE INDEX IF NOT EXISTS drug_lot_drug_store_branch_id_expiry_date_idx ON drug_lot (drug_store_branch_id, expiry_date) WHERE deleted_at IS NULL;
+
+-- prescriptions name a catalogue drug, items written before the catalogue keep only their drug name
+ALTER TABLE prescription_item ADD COLUMN IF NOT EXISTS drug_catalogue_id UUID REFERENCES drug_catalogue(id);
+
+UPDATE prescription_item SET drug_catalogue_id = c.id
+FROM drug_catalogue c WHERE c.deleted_at IS NULL AND lower(c.name) = lower(trim(prescription_item.drug_name));
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS author_email_idx;
+DROP INDEX IF EXISTS author_phone_idx;
+DROP INDEX IF EXISTS clinic_admin_email_idx;
+DROP INDEX IF EXISTS clinic_admin_phone_idx;
+DROP INDEX IF EXISTS customer_email_idx;
+DROP INDEX IF EXISTS customer_phone_idx;
+DROP INDEX IF EXISTS doctor_email_idx;
+DROP INDEX IF EXISTS doctor_phone_idx;
+DROP INDEX IF EXISTS pharmacist_email_idx;
+DROP INDEX IF EXISTS pharmacist_phone_idx;
+DROP INDEX IF EXISTS super_admin_email_idx;
+DROP INDEX IF EXISTS super_admin_phone_idx;
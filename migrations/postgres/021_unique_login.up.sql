@@ -0,0 +1,14 @@
+-- users log in by email or phone, both have to point to one user of the role. The migration stops with
+-- the duplicated key when live users share one, they have to be told apart by hand first
+CREATE UNIQUE INDEX IF NOT EXISTS author_email_idx ON author (email) WHERE deleted_at IS NULL AND email <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS author_phone_idx ON author (phone) WHERE deleted_at IS NULL AND phone <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS clinic_admin_email_idx ON clinic_admin (email) WHERE deleted_at IS NULL AND email <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS clinic_admin_phone_idx ON clinic_admin (phone) WHERE deleted_at IS NULL AND phone <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS customer_email_idx ON customer (email) WHERE deleted_at IS NULL AND email <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS customer_phone_idx ON customer (phone) WHERE deleted_at IS NULL AND phone <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS doctor_email_idx ON doctor (email) WHERE deleted_at IS NULL AND email <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS doctor_phone_idx ON doctor (phone) WHERE deleted_at IS NULL AND phone <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS pharmacist_email_idx ON pharmacist (email) WHERE deleted_at IS NULL AND email <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS pharmacist_phone_idx ON pharmacist (phone) WHERE deleted_at IS NULL AND phone <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS super_admin_email_idx ON super_admin (email) WHERE deleted_at IS NULL AND email <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS super_admin_phone_idx ON super_admin (phone) WHERE deleted_at IS NULL AND phone <> '';
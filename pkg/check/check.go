@@ -3,9 +3,13 @@ package check
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"time"
 )
 
+// uzPhone is +998, a two digit operator or city code and a seven digit number
+var uzPhone = regexp.MustCompile(`^\+998\d{9}$`)
+
 func CalculateAge(birthDate string) int {
 	layout := "2006-01-02"
 	birthday, err := time.Parse(layout, birthDate)
@@ -31,3 +35,8 @@ func ValidatePassword(password string) error {
 
 	return nil
 }
+
+// IsUzPhone tells if the phone is an Uzbek number in +998XXXXXXXXX format
+func IsUzPhone(phone string) bool {
+	return uzPhone.MatchString(phone)
+}
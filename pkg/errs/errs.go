@@ -0,0 +1,152 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Kind tells what went wrong in words the api turns into a http status
+type Kind string
+
+const (
+	KindNotFound   Kind = "not_found"
+	KindConflict   Kind = "conflict"
+	KindValidation Kind = "validation"
+	KindForbidden  Kind = "forbidden"
+	// KindPreconditionFailed is a write based on a version of the record which is not the current one
+	KindPreconditionFailed Kind = "precondition_failed"
+)
+
+// Error is a failure caused by the request, not by the server. Fields of a validation error tell
+// which request fields are wrong and why
+type Error struct {
+	Kind    Kind
+	Message string
+	Fields  map[string]string
+	err     error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// WithField returns the error with a detail about one field, the result still matches e with errors.Is
+func (e *Error) WithField(field, message string) *Error {
+	fields := make(map[string]string, len(e.Fields)+1)
+	for key, value := range e.Fields {
+		fields[key] = value
+	}
+	fields[field] = message
+
+	return &Error{
+		Kind:    e.Kind,
+		Message: fmt.Sprintf("%s: %s %s", e.Message, field, message),
+		Fields:  fields,
+		err:     e,
+	}
+}
+
+func NotFound(message string) *Error {
+	return &Error{Kind: KindNotFound, Message: message}
+}
+
+func Conflict(message string) *Error {
+	return &Error{Kind: KindConflict, Message: message}
+}
+
+func Validation(message string) *Error {
+	return &Error{Kind: KindValidation, Message: message}
+}
+
+func Forbidden(message string) *Error {
+	return &Error{Kind: KindForbidden, Message: message}
+}
+
+func PreconditionFailed(message string) *Error {
+	return &Error{Kind: KindPreconditionFailed, Message: message}
+}
+
+// As finds the domain error in the chain of err
+func As(err error) (*Error, bool) {
+	domainErr := &Error{}
+	if errors.As(err, &domainErr) {
+		return domainErr, true
+	}
+
+	return nil, false
+}
+
+var (
+	// keyDetail reads the column list out of details like `Key (email)=(a@b.uz) already exists.`
+	keyDetail = regexp.MustCompile(`^Key \((.+?)\)=\(`)
+	// keyExpression finds the column inside index expressions like lower(name::text)
+	keyExpression = regexp.MustCompile(`^\w+\((\w+)`)
+)
+
+// FromDB turns postgres errors a request can cause into domain errors, the original error stays
+// in the chain. Other errors are returned as they are
+func FromDB(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := As(err); ok {
+		return err
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &Error{Kind: KindNotFound, Message: "record not found", err: err}
+	}
+
+	pgErr := &pgconn.PgError{}
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	field := pgErr.ColumnName
+	if match := keyDetail.FindStringSubmatch(pgErr.Detail); match != nil {
+		field = match[1]
+		if match = keyExpression.FindStringSubmatch(field); match != nil {
+			field = match[1]
+		}
+	}
+
+	var domainErr *Error
+
+	switch {
+	case pgErr.Code == "23505":
+		domainErr = Conflict("record already exists")
+		if field != "" {
+			domainErr = domainErr.WithField(field, "is already used")
+		}
+	case pgErr.Code == "23503" && strings.Contains(pgErr.Detail, "is still referenced"):
+		domainErr = Conflict("record is still used by other records")
+	case pgErr.Code == "23503":
+		domainErr = Validation("referenced record not found")
+		if field != "" {
+			domainErr = domainErr.WithField(field, "points to a record which does not exist")
+		}
+	case pgErr.Code == "23502":
+		domainErr = Validation("data is not valid").WithField(field, "is required")
+	case pgErr.Code == "23514":
+		domainErr = Validation("data is not valid").WithField(pgErr.ConstraintName, "check failed")
+	case strings.HasPrefix(pgErr.Code, "22"):
+		// data exceptions: malformed uuids, dates and numbers, too long texts
+		domainErr = Validation("data is not valid: " + pgErr.Message)
+	default:
+		return err
+	}
+
+	domainErr.err = err
+
+	return domainErr
+}
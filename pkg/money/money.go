@@ -0,0 +1,54 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Amounts are kept in minor units (1/100 of the currency) so sums and products are exact
+
+var ErrInvalidAmount = errors.New("amount should be a decimal number with at most 2 fractional digits")
+
+// Parse converts "12.5", "12.50" or "12" to minor units
+func Parse(amount string) (int64, error) {
+
+	amount = strings.TrimSpace(amount)
+	if amount == "" {
+		return 0, nil
+	}
+
+	negative := strings.HasPrefix(amount, "-")
+	amount = strings.TrimPrefix(amount, "-")
+
+	whole, fraction, _ := strings.Cut(amount, ".")
+	if whole == "" || len(fraction) > 2 {
+		return 0, ErrInvalidAmount
+	}
+
+	fraction += strings.Repeat("0", 2-len(fraction))
+
+	value, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidAmount
+	}
+
+	if negative {
+		value = -value
+	}
+
+	return value, nil
+}
+
+// Format converts minor units back to a "12.50" like string
+func Format(amount int64) string {
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, amount/100, amount%100)
+}
@@ -0,0 +1,50 @@
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ErrNotObject is returned for a patch which is not a JSON object, only objects can be merged into records
+var ErrNotObject = errors.New("patch should be a JSON object")
+
+// Merge applies a JSON merge patch (RFC 7386) to the document. Members of the patch replace the members of
+// the document, null removes a member and nested objects are merged the same way
+func Merge(document, patch []byte) ([]byte, error) {
+
+	patchValue := map[string]interface{}{}
+	if err := json.Unmarshal(patch, &patchValue); err != nil || patchValue == nil {
+		return nil, ErrNotObject
+	}
+
+	documentValue := map[string]interface{}{}
+	if err := json.Unmarshal(document, &documentValue); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(mergeObject(documentValue, patchValue))
+}
+
+func mergeObject(document, patch map[string]interface{}) map[string]interface{} {
+	if document == nil {
+		document = map[string]interface{}{}
+	}
+
+	for key, value := range patch {
+		if value == nil {
+			delete(document, key)
+			continue
+		}
+
+		patchObject, ok := value.(map[string]interface{})
+		if !ok {
+			document[key] = value
+			continue
+		}
+
+		documentObject, _ := document[key].(map[string]interface{})
+		document[key] = mergeObject(documentObject, patchObject)
+	}
+
+	return document
+}
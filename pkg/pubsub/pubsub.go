@@ -0,0 +1,99 @@
+package pubsub
+
+import "sync"
+
+// bufferSize is how many messages a slow subscriber can fall behind before it is unsubscribed
+const bufferSize = 64
+
+type Message struct {
+	Topic string
+	Data  interface{}
+}
+
+// Broker is an in-process publish/subscribe hub, subscribers receive messages of their topic only
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan Message]struct{}
+	closed      bool
+}
+
+func New() *Broker {
+	return &Broker{
+		subscribers: make(map[string]map[chan Message]struct{}),
+	}
+}
+
+// Subscribe returns a channel with messages of the topic and a function which stops the subscription
+func (b *Broker) Subscribe(topic string) (<-chan Message, func()) {
+	ch := make(chan Message, bufferSize)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+
+	if b.subscribers[topic] == nil {
+		b.subscribers[topic] = make(map[chan Message]struct{})
+	}
+	b.subscribers[topic][ch] = struct{}{}
+
+	once := sync.Once{}
+
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			if _, ok := b.subscribers[topic][ch]; !ok {
+				return
+			}
+
+			delete(b.subscribers[topic], ch)
+			if len(b.subscribers[topic]) == 0 {
+				delete(b.subscribers, topic)
+			}
+			close(ch)
+		})
+	}
+}
+
+// Publish never blocks the publisher. A subscriber with a full buffer would silently miss the message,
+// so its channel is closed instead and the subscriber has to subscribe again
+func (b *Broker) Publish(topic string, data interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[topic] {
+		select {
+		case ch <- Message{Topic: topic, Data: data}:
+		default:
+			delete(b.subscribers[topic], ch)
+			close(ch)
+		}
+	}
+
+	if len(b.subscribers[topic]) == 0 {
+		delete(b.subscribers, topic)
+	}
+}
+
+// Close ends all subscriptions, their channels get closed
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+	b.closed = true
+
+	for topic, channels := range b.subscribers {
+		for ch := range channels {
+			close(ch)
+		}
+		delete(b.subscribers, topic)
+	}
+}
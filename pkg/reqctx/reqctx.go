@@ -0,0 +1,37 @@
+package reqctx
+
+import "context"
+
+type contextKey int
+
+const (
+	actorKey contextKey = iota
+	requestIDKey
+)
+
+// Actor is the signed in user a request is made by
+type Actor struct {
+	ID   string
+	Role string
+}
+
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ActorFrom returns the actor of the request, it is empty for guests
+func ActorFrom(ctx context.Context) Actor {
+	actor, _ := ctx.Value(actorKey).(Actor)
+
+	return actor
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+
+	return requestID
+}
@@ -0,0 +1,50 @@
+package security
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AccessToken  = "access"
+	RefreshToken = "refresh"
+)
+
+type Claims struct {
+	UserID    string `json:"user_id"`
+	UserRole  string `json:"user_role"`
+	BranchID  string `json:"branch_id,omitempty"`
+	TokenType string `json:"token_type"`
+	jwt.RegisteredClaims
+}
+
+func GenerateJWT(claims Claims, expireTime time.Duration, secretKey string) (string, error) {
+	now := time.Now()
+
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(expireTime))
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secretKey))
+}
+
+func ExtractClaims(tokenString, secretKey string) (Claims, error) {
+	claims := Claims{}
+
+	token, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return []byte(secretKey), nil
+	})
+	if err != nil {
+		return Claims{}, err
+	}
+
+	if !token.Valid {
+		return Claims{}, errors.New("invalid token")
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,118 @@
+package slot
+
+import (
+	"errors"
+	"shifolink/api/models"
+	"time"
+)
+
+const (
+	DateLayout     = "2006-01-02"
+	ClockLayout    = "15:04"
+	DateTimeLayout = "2006-01-02 15:04"
+)
+
+type Slot struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (s Slot) Overlaps(other Slot) bool {
+	return s.Start.Before(other.End) && other.Start.Before(s.End)
+}
+
+func (s Slot) ToModel() models.Slot {
+	return models.Slot{
+		StartTime: s.Start.Format(DateTimeLayout),
+		EndTime:   s.End.Format(DateTimeLayout),
+	}
+}
+
+// Generate splits working hours of the schedule on the given date into slots of
+// schedule.SlotDuration minutes, slots crossing the break or the end of the day are dropped
+func Generate(schedule models.DoctorSchedule, date time.Time) ([]Slot, error) {
+
+	if int(date.Weekday()) != schedule.Weekday {
+		return nil, nil
+	}
+
+	if schedule.SlotDuration <= 0 {
+		return nil, errors.New("slot duration should be positive")
+	}
+
+	start, err := atClock(date, schedule.StartTime)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := atClock(date, schedule.EndTime)
+	if err != nil {
+		return nil, err
+	}
+
+	var breakTime *Slot
+	if schedule.BreakStart != "" && schedule.BreakEnd != "" {
+		breakStart, err := atClock(date, schedule.BreakStart)
+		if err != nil {
+			return nil, err
+		}
+
+		breakEnd, err := atClock(date, schedule.BreakEnd)
+		if err != nil {
+			return nil, err
+		}
+
+		breakTime = &Slot{Start: breakStart, End: breakEnd}
+	}
+
+	var (
+		slots    []Slot
+		duration = time.Duration(schedule.SlotDuration) * time.Minute
+	)
+
+	for slotStart := start; !slotStart.Add(duration).After(end); slotStart = slotStart.Add(duration) {
+		s := Slot{Start: slotStart, End: slotStart.Add(duration)}
+
+		if breakTime != nil && s.Overlaps(*breakTime) {
+			continue
+		}
+
+		slots = append(slots, s)
+	}
+
+	return slots, nil
+}
+
+// Find returns the slot of the schedule which starts exactly at start
+func Find(schedule models.DoctorSchedule, start time.Time) (Slot, bool, error) {
+
+	slots, err := Generate(schedule, start)
+	if err != nil {
+		return Slot{}, false, err
+	}
+
+	for _, s := range slots {
+		if s.Start.Equal(start) {
+			return s, true, nil
+		}
+	}
+
+	return Slot{}, false, nil
+}
+
+func atClock(date time.Time, clock string) (time.Time, error) {
+	t, err := time.Parse(ClockLayout, clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), 0, 0, date.Location()), nil
+}
+
+// Now returns the current wall clock time in UTC location, the same way
+// booking times are parsed and stored in timestamp without time zone columns
+func Now() time.Time {
+	now := time.Now()
+
+	return time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), now.Second(), 0, time.UTC)
+}
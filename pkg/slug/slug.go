@@ -0,0 +1,62 @@
+package slug
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// MaxLength leaves room for the "-N" suffix added when the slug is taken
+const MaxLength = 180
+
+var validSlug = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// cyrillic spells Russian and Uzbek Cyrillic letters in Latin, so themes of every journal language give readable urls
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "j", 'з': "z", 'и': "i",
+	'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t",
+	'у': "u", 'ф': "f", 'х': "x", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "sch", 'ъ': "", 'ы': "i", 'ь': "",
+	'э': "e", 'ю': "yu", 'я': "ya", 'ў': "o", 'қ': "q", 'ғ': "g", 'ҳ': "h",
+}
+
+// Make turns the text into lowercase latin words joined by "-", an empty result means nothing was usable
+func Make(text string) string {
+
+	builder := strings.Builder{}
+	dash := false
+
+	for _, r := range strings.ToLower(text) {
+		var part string
+
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			part = string(r)
+		case cyrillic[r] != "":
+			part = cyrillic[r]
+		case r == '\'' || r == '‘' || r == '’' || r == 'ʻ' || r == 'ʼ' || r == 'ъ' || r == 'ь':
+			// o‘, g‘ and the hard and soft signs are parts of a word, not separators
+			continue
+		default:
+			dash = builder.Len() > 0
+			continue
+		}
+
+		if dash {
+			builder.WriteByte('-')
+			dash = false
+		}
+		builder.WriteString(part)
+	}
+
+	slug := builder.String()
+	if len(slug) > MaxLength {
+		slug = strings.TrimRight(slug[:MaxLength], "-")
+	}
+
+	return slug
+}
+
+// Valid tells whether the slug given by a client can be used as it is
+func Valid(slug string) bool {
+	return len(slug) <= MaxLength && validSlug.MatchString(slug)
+}
@@ -0,0 +1,75 @@
+package textdiff
+
+import "strings"
+
+const (
+	Equal  = "="
+	Insert = "+"
+	Delete = "-"
+)
+
+type Line struct {
+	Op   string
+	Text string
+}
+
+// Lines compares two texts line by line and returns the shortest edit from a to b, removed lines of a
+// change come before the added ones. It keeps an lcs table of len(a)*len(b), which is fine for articles
+func Lines(a, b string) []Line {
+
+	from, to := splitLines(a), splitLines(b)
+
+	// common[i][j] is the length of the longest common subsequence of from[i:] and to[j:]
+	common := make([][]int, len(from)+1)
+	for i := range common {
+		common[i] = make([]int, len(to)+1)
+	}
+
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]Line, 0, len(from)+len(to))
+
+	i, j := 0, 0
+	for i < len(from) && j < len(to) {
+		switch {
+		case from[i] == to[j]:
+			lines = append(lines, Line{Op: Equal, Text: from[i]})
+			i++
+			j++
+		case common[i+1][j] >= common[i][j+1]:
+			lines = append(lines, Line{Op: Delete, Text: from[i]})
+			i++
+		default:
+			lines = append(lines, Line{Op: Insert, Text: to[j]})
+			j++
+		}
+	}
+
+	for ; i < len(from); i++ {
+		lines = append(lines, Line{Op: Delete, Text: from[i]})
+	}
+
+	for ; j < len(to); j++ {
+		lines = append(lines, Line{Op: Insert, Text: to[j]})
+	}
+
+	return lines
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"shifolink/api/models"
+	"shifolink/config"
+	"shifolink/pkg/reqctx"
+	"shifolink/storage"
+)
+
+// auditIgnoredFields change on every update and tell nothing about what was changed
+var auditIgnoredFields = map[string]bool{
+	"updated_at": true,
+}
+
+// passwordChanged is logged for password changes, hashes are never written to the log
+var passwordChanged = map[string]string{"password": "changed"}
+
+type auditLogService struct {
+	storage storage.IStorage
+}
+
+func NewAuditLogService(storage storage.IStorage) auditLogService {
+	return auditLogService{
+		storage: storage,
+	}
+}
+
+func (a auditLogService) GetList(ctx context.Context, request models.GetAuditLogsListRequest) (models.AuditLogsResponse, error) {
+
+	logs, err := a.storage.AuditLog().GetList(ctx, request)
+	if err != nil {
+		fmt.Println("error in service layer while getting audit log list", err.Error())
+		return models.AuditLogsResponse{}, err
+	}
+
+	return logs, nil
+}
+
+// recordAudit writes down who made the change and which fields it changed, before is nil for created records
+// and after is nil for deleted ones. It is called in the transaction of the change, so a change is never saved
+// without its log
+func recordAudit(ctx context.Context, storage storage.IStorage, entity, entityID, action string, before, after interface{}) error {
+
+	beforeFields, afterFields, err := auditDiff(before, after)
+	if err != nil {
+		log.Println("error in service layer while making audit diff", err.Error())
+		return err
+	}
+
+	if action == config.AuditUpdate && beforeFields == nil && afterFields == nil {
+		return nil
+	}
+
+	actor := reqctx.ActorFrom(ctx)
+
+	if err = storage.AuditLog().Create(ctx, models.CreateAuditLog{
+		ActorID:   actor.ID,
+		ActorRole: actor.Role,
+		Entity:    entity,
+		EntityID:  entityID,
+		Action:    action,
+		RequestID: reqctx.RequestID(ctx),
+		Before:    beforeFields,
+		After:     afterFields,
+	}); err != nil {
+		log.Println("error in service layer while writing audit log", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// recordChildAudits writes down the rows a delete or restore took with the record, like recordAudit without
+// their fields
+func recordChildAudits(ctx context.Context, store storage.IStorage, action string, children []storage.ChildRecord) error {
+	for _, child := range children {
+		if err := recordAudit(ctx, store, child.Table, child.ID, action, nil, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// auditDiff keeps the fields whose values differ between the two records, a missing record keeps every field
+// of the other one
+func auditDiff(before, after interface{}) (json.RawMessage, json.RawMessage, error) {
+
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if beforeFields != nil && afterFields != nil {
+		for key, value := range beforeFields {
+			if afterValue, ok := afterFields[key]; ok && reflect.DeepEqual(value, afterValue) {
+				delete(beforeFields, key)
+				delete(afterFields, key)
+			}
+		}
+	}
+
+	return marshalAuditFields(beforeFields), marshalAuditFields(afterFields), nil
+}
+
+func auditFields(record interface{}) (map[string]interface{}, error) {
+	if record == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{}
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	for key := range auditIgnoredFields {
+		delete(fields, key)
+	}
+
+	return fields, nil
+}
+
+func marshalAuditFields(fields map[string]interface{}) json.RawMessage {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	// a map of json values always marshals
+	data, _ := json.Marshal(fields)
+
+	return data
+}
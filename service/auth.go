@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"shifolink/api/models"
+	"shifolink/config"
+	"shifolink/pkg/security"
+	"shifolink/storage"
+)
+
+var ErrInvalidCredentials = errors.New("login or password is incorrect")
+
+type authService struct {
+	cfg     config.Config
+	storage storage.IStorage
+}
+
+func NewAuthService(cfg config.Config, storage storage.IStorage) authService {
+	return authService{
+		cfg:     cfg,
+		storage: storage,
+	}
+}
+
+func (a authService) Login(ctx context.Context, role string, request models.LoginRequest) (models.LoginResponse, error) {
+
+	authInfo, err := a.getAuthInfo(ctx, role, request.Login)
+	if err != nil {
+		fmt.Println("error in service layer while getting user by login", err.Error())
+		return models.LoginResponse{}, ErrInvalidCredentials
+	}
+
+	password, err := a.getPassword(ctx, role, authInfo.UserID)
+	if err != nil {
+		fmt.Println("error in service layer while getting password", err.Error())
+		return models.LoginResponse{}, err
+	}
+
+	if !security.CompareHashAndPassword(password, request.Password) {
+		return models.LoginResponse{}, ErrInvalidCredentials
+	}
+
+	return a.generateTokens(authInfo)
+}
+
+func (a authService) RefreshToken(ctx context.Context, request models.RefreshTokenRequest) (models.LoginResponse, error) {
+
+	claims, err := security.ExtractClaims(request.RefreshToken, a.cfg.JWTSecretKey)
+	if err != nil {
+		fmt.Println("error in service layer while parsing refresh token", err.Error())
+		return models.LoginResponse{}, err
+	}
+
+	if claims.TokenType != security.RefreshToken {
+		return models.LoginResponse{}, errors.New("token is not a refresh token")
+	}
+
+	// the account could be deleted or moved to another branch after the token was issued
+	authInfo, err := a.getAuthInfoByID(ctx, claims.UserRole, claims.UserID)
+	if err != nil {
+		fmt.Println("error in service layer while getting user by id", err.Error())
+		return models.LoginResponse{}, err
+	}
+
+	return a.generateTokens(authInfo)
+}
+
+func (a authService) ParseAccessToken(token string) (models.AuthInfo, error) {
+
+	claims, err := security.ExtractClaims(token, a.cfg.JWTSecretKey)
+	if err != nil {
+		return models.AuthInfo{}, err
+	}
+
+	if claims.TokenType != security.AccessToken {
+		return models.AuthInfo{}, errors.New("token is not an access token")
+	}
+
+	return models.AuthInfo{
+		UserID:   claims.UserID,
+		UserRole: claims.UserRole,
+		BranchID: claims.BranchID,
+	}, nil
+}
+
+func (a authService) generateTokens(authInfo models.AuthInfo) (models.LoginResponse, error) {
+
+	claims := security.Claims{
+		UserID:   authInfo.UserID,
+		UserRole: authInfo.UserRole,
+		BranchID: authInfo.BranchID,
+	}
+
+	claims.TokenType = security.AccessToken
+	accessToken, err := security.GenerateJWT(claims, a.cfg.AccessTokenExpireTime, a.cfg.JWTSecretKey)
+	if err != nil {
+		fmt.Println("error in service layer while generating access token", err.Error())
+		return models.LoginResponse{}, err
+	}
+
+	claims.TokenType = security.RefreshToken
+	refreshToken, err := security.GenerateJWT(claims, a.cfg.RefreshTokenExpireTime, a.cfg.JWTSecretKey)
+	if err != nil {
+		fmt.Println("error in service layer while generating refresh token", err.Error())
+		return models.LoginResponse{}, err
+	}
+
+	return models.LoginResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		UserID:       authInfo.UserID,
+		UserRole:     authInfo.UserRole,
+	}, nil
+}
+
+func (a authService) getAuthInfo(ctx context.Context, role, login string) (models.AuthInfo, error) {
+
+	id := ""
+
+	switch role {
+	case config.AuthorRole:
+		author, err := a.storage.Author().GetByLogin(ctx, login)
+		if err != nil {
+			return models.AuthInfo{}, err
+		}
+		id = author.ID
+
+	case config.ClinicAdminRole:
+		clinicAdmin, err := a.storage.ClinicAdmin().GetByLogin(ctx, login)
+		if err != nil {
+			return models.AuthInfo{}, err
+		}
+		id = clinicAdmin.ID
+
+	case config.CustomerRole:
+		customer, err := a.storage.Customer().GetByLogin(ctx, login)
+		if err != nil {
+			return models.AuthInfo{}, err
+		}
+		id = customer.ID
+
+	case config.DoctorRole:
+		doctor, err := a.storage.Doctor().GetByLogin(ctx, login)
+		if err != nil {
+			return models.AuthInfo{}, err
+		}
+		id = doctor.ID
+
+	case config.PharmacistRole:
+		pharmacist, err := a.storage.Pharmacist().GetByLogin(ctx, login)
+		if err != nil {
+			return models.AuthInfo{}, err
+		}
+		id = pharmacist.ID
+
+	case config.SuperAdminRole:
+		superAdmin, err := a.storage.SuperAdmin().GetByLogin(ctx, login)
+		if err != nil {
+			return models.AuthInfo{}, err
+		}
+		id = superAdmin.ID
+
+	default:
+		return models.AuthInfo{}, fmt.Errorf("unknown role %q", role)
+	}
+
+	return a.getAuthInfoByID(ctx, role, id)
+}
+
+func (a authService) getAuthInfoByID(ctx context.Context, role, id string) (models.AuthInfo, error) {
+
+	authInfo := models.AuthInfo{
+		UserID:   id,
+		UserRole: role,
+	}
+
+	pKey := models.PrimaryKey{
+		ID: id,
+	}
+
+	switch role {
+	case config.AuthorRole:
+		if _, err := a.storage.Author().Get(ctx, pKey); err != nil {
+			return models.AuthInfo{}, err
+		}
+
+	case config.ClinicAdminRole:
+		clinicAdmin, err := a.storage.ClinicAdmin().Get(ctx, pKey)
+		if err != nil {
+			return models.AuthInfo{}, err
+		}
+		authInfo.BranchID = clinicAdmin.ClinicBranchID
+
+	case config.CustomerRole:
+		if _, err := a.storage.Customer().Get(ctx, pKey); err != nil {
+			return models.AuthInfo{}, err
+		}
+
+	case config.DoctorRole:
+		doctor, err := a.storage.Doctor().Get(ctx, pKey)
+		if err != nil {
+			return models.AuthInfo{}, err
+		}
+
+		doctorType, err := a.storage.DoctorType().Get(ctx, models.PrimaryKey{
+			ID: doctor.DoctorTypeID,
+		})
+		if err != nil {
+			return models.AuthInfo{}, err
+		}
+		authInfo.BranchID = doctorType.ClinicBranchID
+
+	case config.PharmacistRole:
+		pharmacist, err := a.storage.Pharmacist().Get(ctx, pKey)
+		if err != nil {
+			return models.AuthInfo{}, err
+		}
+		authInfo.BranchID = pharmacist.DrugStoreBranchID
+
+	case config.SuperAdminRole:
+		if _, err := a.storage.SuperAdmin().Get(ctx, pKey); err != nil {
+			return models.AuthInfo{}, err
+		}
+
+	default:
+		return models.AuthInfo{}, fmt.Errorf("unknown role %q", role)
+	}
+
+	return authInfo, nil
+}
+
+func (a authService) getPassword(ctx context.Context, role, id string) (string, error) {
+
+	switch role {
+	case config.AuthorRole:
+		return a.storage.Author().GetPassword(ctx, id)
+	case config.ClinicAdminRole:
+		return a.storage.ClinicAdmin().GetPassword(ctx, id)
+	case config.CustomerRole:
+		return a.storage.Customer().GetPassword(ctx, id)
+	case config.DoctorRole:
+		return a.storage.Doctor().GetPassword(ctx, id)
+	case config.PharmacistRole:
+		return a.storage.Pharmacist().GetPassword(ctx, id)
+	case config.SuperAdminRole:
+		return a.storage.SuperAdmin().GetPassword(ctx, id)
+	}
+
+	return "", fmt.Errorf("unknown role %q", role)
+}
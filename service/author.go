@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"log"
 	"shifolink/api/models"
+	"shifolink/config"
 	"shifolink/pkg/check"
+	"shifolink/pkg/security"
 	"shifolink/storage"
 
 	"github.com/jackc/pgx/v5"
@@ -24,17 +26,34 @@ func NewAuthorService(storage storage.IStorage) authorService {
 
 func (a authorService) Create(ctx context.Context, createAuthor models.CreateAuthor) (models.Author, error) {
 
-	pKey, err := a.storage.Author().Create(ctx, createAuthor)
-	if err != nil {
-		log.Println("error in service layer while creating author ", err.Error())
-		return models.Author{}, err
-	}
+	author := models.Author{}
+
+	err := a.storage.Transaction(ctx, func(ctx context.Context) error {
 
-	author, err := a.storage.Author().Get(ctx, models.PrimaryKey{
-		ID: pKey,
+		age, err := checkAccount(createAuthor.Password, createAuthor.BirthDate)
+		if err != nil {
+			return err
+		}
+		createAuthor.Age = age
+
+		pKey, err := a.storage.Author().Create(ctx, createAuthor)
+		if err != nil {
+			log.Println("error in service layer while creating author ", err.Error())
+			return err
+		}
+
+		author, err = a.storage.Author().Get(ctx, models.PrimaryKey{
+			ID: pKey,
+		})
+		if err != nil {
+			log.Println("error in service layer get author by id")
+			return err
+		}
+
+		return recordAudit(ctx, a.storage, "author", author.ID, config.AuditCreate, nil, author)
 	})
 	if err != nil {
-		log.Println("error in service layer get author by id")
+		return models.Author{}, err
 	}
 
 	return author, nil
@@ -46,8 +65,8 @@ func (a authorService) Get(ctx context.Context, pkey models.PrimaryKey) (models.
 	if err != nil {
 		if !errors.Is(err, pgx.ErrNoRows) {
 			fmt.Println("error in service layer while getting author by id", err.Error())
-			return models.Author{}, err
 		}
+		return models.Author{}, err
 	}
 
 	return author, nil
@@ -67,17 +86,32 @@ func (a authorService) GetList(ctx context.Context, request models.GetListReques
 
 func (a authorService) Update(ctx context.Context, updateAuthor models.UpdateAuthor) (models.Author, error) {
 
-	id, err := a.storage.Author().Update(ctx, updateAuthor)
-	if err != nil {
-		fmt.Println("error in servise layer updating author by id", err.Error())
-		return models.Author{}, err
-	}
+	author := models.Author{}
+
+	err := a.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := a.storage.Author().Get(ctx, models.PrimaryKey{ID: updateAuthor.ID})
+		if err != nil {
+			return err
+		}
+
+		id, err := a.storage.Author().Update(ctx, updateAuthor)
+		if err != nil {
+			fmt.Println("error in servise layer updating author by id", err.Error())
+			return err
+		}
 
-	author, err := a.storage.Author().Get(ctx, models.PrimaryKey{
-		ID: id,
+		author, err = a.storage.Author().Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			fmt.Println("error in service layer getting author after update", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, a.storage, "author", author.ID, config.AuditUpdate, before, author)
 	})
 	if err != nil {
-		fmt.Println("error in service layer getting author after update", err.Error())
 		return models.Author{}, err
 	}
 
@@ -86,33 +120,74 @@ func (a authorService) Update(ctx context.Context, updateAuthor models.UpdateAut
 
 func (a authorService) Delete(ctx context.Context, id string) error {
 
-	err := a.storage.Author().Delete(ctx, id)
+	return a.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := a.storage.Author().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			return err
+		}
+
+		if err = a.storage.Author().Delete(ctx, id); err != nil {
+			return err
+		}
 
-	return err
+		return recordAudit(ctx, a.storage, "author", id, config.AuditDelete, before, nil)
+	})
 }
 
 func (a authorService) UpdatePassword(ctx context.Context, request models.UpdateAuthorPassword) error {
 
-	oldPassword, err := a.storage.Author().GetPassword(ctx, request.ID)
-	if err != nil {
-		fmt.Println("error in service layer getting password by id", err.Error())
-		return err
-	}
+	return a.storage.Transaction(ctx, func(ctx context.Context) error {
 
-	if oldPassword != request.OldPassword {
-		fmt.Println("error in service layer old password is not correct")
-		return errors.New("old password did not match")
-	}
+		oldPassword, err := a.storage.Author().GetPassword(ctx, request.ID)
+		if err != nil {
+			fmt.Println("error in service layer getting password by id", err.Error())
+			return err
+		}
 
-	if err = check.ValidatePassword(request.NewPassword); err != nil {
-		fmt.Println("error in service layer new password validation failed", err.Error())
-		return err
-	}
+		if !security.CompareHashAndPassword(oldPassword, request.OldPassword) {
+			fmt.Println("error in service layer old password is not correct")
+			return ErrPasswordMismatch.WithField("old_password", "is not correct")
+		}
 
-	if err = a.storage.Author().UpdatePassword(context.Background(), request); err != nil {
-		fmt.Println("error in service layer while updating author password ", err.Error())
-		return err
+		if err = check.ValidatePassword(request.NewPassword); err != nil {
+			fmt.Println("error in service layer new password validation failed", err.Error())
+			return ErrInvalidData.WithField("new_password", err.Error())
+		}
+
+		if err = a.storage.Author().UpdatePassword(ctx, request); err != nil {
+			fmt.Println("error in service layer while updating author password ", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, a.storage, "author", request.ID, config.AuditUpdate, nil, passwordChanged)
+	})
+}
+
+// Restore brings back the soft deleted author
+func (a authorService) Restore(ctx context.Context, id string) (models.Author, error) {
+
+	author := models.Author{}
+
+	err := a.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		err := a.storage.Author().Restore(ctx, id)
+		if err != nil {
+			fmt.Println("error in service layer while restoring author", err.Error())
+			return err
+		}
+
+		author, err = a.storage.Author().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			fmt.Println("error in service layer getting author after restore", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, a.storage, "author", id, config.AuditRestore, nil, author)
+	})
+	if err != nil {
+		return models.Author{}, err
 	}
 
-	return nil
+	return author, nil
 }
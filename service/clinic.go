@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"shifolink/api/models"
+	"shifolink/config"
 	"shifolink/storage"
 
 	"github.com/jackc/pgx/v5"
@@ -23,17 +24,28 @@ func NewClinicService(storage storage.IStorage) clinicService {
 
 func (c clinicService) Create(ctx context.Context, createClinic models.CreateClinic) (models.Clinic, error) {
 
-	pKey, err := c.storage.Clinic().Create(ctx, createClinic)
-	if err != nil {
-		log.Println("error in service layer while creating clinic  ", err.Error())
-		return models.Clinic{}, err
-	}
+	clinic := models.Clinic{}
+
+	err := c.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		pKey, err := c.storage.Clinic().Create(ctx, createClinic)
+		if err != nil {
+			log.Println("error in service layer while creating clinic  ", err.Error())
+			return err
+		}
 
-	clinic, err := c.storage.Clinic().Get(ctx, models.PrimaryKey{
-		ID: pKey,
+		clinic, err = c.storage.Clinic().Get(ctx, models.PrimaryKey{
+			ID: pKey,
+		})
+		if err != nil {
+			log.Println("error in service layer get clinic by id")
+			return err
+		}
+
+		return recordAudit(ctx, c.storage, "clinic", clinic.ID, config.AuditCreate, nil, clinic)
 	})
 	if err != nil {
-		log.Println("error in service layer get clinic by id")
+		return models.Clinic{}, err
 	}
 
 	return clinic, nil
@@ -45,8 +57,8 @@ func (c clinicService) Get(ctx context.Context, pkey models.PrimaryKey) (models.
 	if err != nil {
 		if !errors.Is(err, pgx.ErrNoRows) {
 			fmt.Println("error in service layer while getting clinic branch by id", err.Error())
-			return models.Clinic{}, err
 		}
+		return models.Clinic{}, err
 	}
 
 	return clinic, nil
@@ -66,17 +78,32 @@ func (c clinicService) GetList(ctx context.Context, request models.GetListReques
 
 func (c clinicService) Update(ctx context.Context, updateClinic models.UpdateClinic) (models.Clinic, error) {
 
-	id, err := c.storage.Clinic().Update(ctx, updateClinic)
-	if err != nil {
-		fmt.Println("error in servise layer updating clinic by id", err.Error())
-		return models.Clinic{}, err
-	}
+	clinic := models.Clinic{}
+
+	err := c.storage.Transaction(ctx, func(ctx context.Context) error {
 
-	clinic, err := c.storage.Clinic().Get(ctx, models.PrimaryKey{
-		ID: id,
+		before, err := c.storage.Clinic().Get(ctx, models.PrimaryKey{ID: updateClinic.ID})
+		if err != nil {
+			return err
+		}
+
+		id, err := c.storage.Clinic().Update(ctx, updateClinic)
+		if err != nil {
+			fmt.Println("error in servise layer updating clinic by id", err.Error())
+			return err
+		}
+
+		clinic, err = c.storage.Clinic().Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			fmt.Println("error in service layer getting clinic after update", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, c.storage, "clinic", clinic.ID, config.AuditUpdate, before, clinic)
 	})
 	if err != nil {
-		fmt.Println("error in service layer getting clinic after update", err.Error())
 		return models.Clinic{}, err
 	}
 
@@ -85,7 +112,54 @@ func (c clinicService) Update(ctx context.Context, updateClinic models.UpdateCli
 
 func (c clinicService) Delete(ctx context.Context, id string) error {
 
-	err := c.storage.Clinic().Delete(ctx, id)
+	return c.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := c.storage.Clinic().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			return err
+		}
+
+		children, err := c.storage.Clinic().Delete(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err = recordAudit(ctx, c.storage, "clinic", id, config.AuditDelete, before, nil); err != nil {
+			return err
+		}
+
+		return recordChildAudits(ctx, c.storage, config.AuditDelete, children)
+	})
+}
+
+// Restore brings back the soft deleted clinic with its branches, doctor types and doctors
+func (c clinicService) Restore(ctx context.Context, id string) (models.Clinic, error) {
+
+	clinic := models.Clinic{}
+
+	err := c.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		children, err := c.storage.Clinic().Restore(ctx, id)
+		if err != nil {
+			fmt.Println("error in service layer while restoring clinic", err.Error())
+			return err
+		}
+
+		clinic, err = c.storage.Clinic().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			fmt.Println("error in service layer getting clinic after restore", err.Error())
+			return err
+		}
 
-	return err
+		if err = recordAudit(ctx, c.storage, "clinic", id, config.AuditRestore, nil, clinic); err != nil {
+			return err
+		}
+
+		return recordChildAudits(ctx, c.storage, config.AuditRestore, children)
+	})
+	if err != nil {
+		return models.Clinic{}, err
+	}
+
+	return clinic, nil
 }
@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"log"
 	"shifolink/api/models"
+	"shifolink/config"
+	"shifolink/pkg/check"
+	"shifolink/pkg/security"
 	"shifolink/storage"
 
 	"github.com/jackc/pgx/v5"
@@ -23,17 +26,44 @@ func NewClinicAdminService(storage storage.IStorage) clinicAdminService {
 
 func (c clinicAdminService) Create(ctx context.Context, createClinicAdmin models.CreateClinicAdmin) (models.ClinicAdmin, error) {
 
-	pKey, err := c.storage.ClinicAdmin().Create(ctx, createClinicAdmin)
-	if err != nil {
-		log.Println("error in service layer while creating clinic admin ", err.Error())
-		return models.ClinicAdmin{}, err
-	}
+	clinicAdmin := models.ClinicAdmin{}
+
+	err := c.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		age, err := checkAccount(createClinicAdmin.Password, createClinicAdmin.BirthDate)
+		if err != nil {
+			return err
+		}
+		createClinicAdmin.Age = age
+
+		if err := checkReference(ctx, "clinic_branch_id", createClinicAdmin.ClinicBranchID, c.storage.ClinicBranch().Get); err != nil {
+			return err
+		}
 
-	clinicAdmin, err := c.storage.ClinicAdmin().Get(ctx, models.PrimaryKey{
-		ID: pKey,
+		if createClinicAdmin.DoctorTypeID != "" {
+			if err := checkReference(ctx, "doctor_type_id", createClinicAdmin.DoctorTypeID, c.storage.DoctorType().Get); err != nil {
+				return err
+			}
+		}
+
+		pKey, err := c.storage.ClinicAdmin().Create(ctx, createClinicAdmin)
+		if err != nil {
+			log.Println("error in service layer while creating clinic admin ", err.Error())
+			return err
+		}
+
+		clinicAdmin, err = c.storage.ClinicAdmin().Get(ctx, models.PrimaryKey{
+			ID: pKey,
+		})
+		if err != nil {
+			log.Println("error in service layer get clinic admin by id")
+			return err
+		}
+
+		return recordAudit(ctx, c.storage, "clinic_admin", clinicAdmin.ID, config.AuditCreate, nil, clinicAdmin)
 	})
 	if err != nil {
-		log.Println("error in service layer get clinic admin by id")
+		return models.ClinicAdmin{}, err
 	}
 
 	return clinicAdmin, nil
@@ -45,14 +75,14 @@ func (c clinicAdminService) Get(ctx context.Context, pkey models.PrimaryKey) (mo
 	if err != nil {
 		if !errors.Is(err, pgx.ErrNoRows) {
 			fmt.Println("error in service layer while getting clinic admin by id", err.Error())
-			return models.ClinicAdmin{}, err
 		}
+		return models.ClinicAdmin{}, err
 	}
 
 	return clinicAdmin, nil
 }
 
-func (c clinicAdminService) GetList(ctx context.Context, request models.GetListRequest) (models.ClinicAdminsResponse, error) {
+func (c clinicAdminService) GetList(ctx context.Context, request models.GetClinicAdminsListRequest) (models.ClinicAdminsResponse, error) {
 
 	clinicAdmin, err := c.storage.ClinicAdmin().GetList(ctx, request)
 	if err != nil {
@@ -66,17 +96,42 @@ func (c clinicAdminService) GetList(ctx context.Context, request models.GetListR
 
 func (c clinicAdminService) Update(ctx context.Context, updateClinicAdmin models.UpdateClinicAdmin) (models.ClinicAdmin, error) {
 
-	id, err := c.storage.ClinicAdmin().Update(ctx, updateClinicAdmin)
-	if err != nil {
-		fmt.Println("error in servise layer updating clinic admin by id", err.Error())
-		return models.ClinicAdmin{}, err
-	}
+	clinicAdmin := models.ClinicAdmin{}
+
+	err := c.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := c.storage.ClinicAdmin().Get(ctx, models.PrimaryKey{ID: updateClinicAdmin.ID})
+		if err != nil {
+			return err
+		}
+
+		if err := checkReference(ctx, "clinic_branch_id", updateClinicAdmin.ClinicBranchID, c.storage.ClinicBranch().Get); err != nil {
+			return err
+		}
 
-	clinicAdmin, err := c.storage.ClinicAdmin().Get(ctx, models.PrimaryKey{
-		ID: id,
+		if updateClinicAdmin.DoctorTypeID != "" {
+			if err := checkReference(ctx, "doctor_type_id", updateClinicAdmin.DoctorTypeID, c.storage.DoctorType().Get); err != nil {
+				return err
+			}
+		}
+
+		id, err := c.storage.ClinicAdmin().Update(ctx, updateClinicAdmin)
+		if err != nil {
+			fmt.Println("error in servise layer updating clinic admin by id", err.Error())
+			return err
+		}
+
+		clinicAdmin, err = c.storage.ClinicAdmin().Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			fmt.Println("error in service layer getting clinic admin after update", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, c.storage, "clinic_admin", clinicAdmin.ID, config.AuditUpdate, before, clinicAdmin)
 	})
 	if err != nil {
-		fmt.Println("error in service layer getting clinic admin after update", err.Error())
 		return models.ClinicAdmin{}, err
 	}
 
@@ -85,7 +140,74 @@ func (c clinicAdminService) Update(ctx context.Context, updateClinicAdmin models
 
 func (c clinicAdminService) Delete(ctx context.Context, id string) error {
 
-	err := c.storage.ClinicAdmin().Delete(ctx, id)
+	return c.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := c.storage.ClinicAdmin().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			return err
+		}
 
-	return err
+		if err = c.storage.ClinicAdmin().Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, c.storage, "clinic_admin", id, config.AuditDelete, before, nil)
+	})
+}
+
+func (c clinicAdminService) UpdatePassword(ctx context.Context, request models.UpdateClinicAdminPassword) error {
+
+	return c.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		oldPassword, err := c.storage.ClinicAdmin().GetPassword(ctx, request.ID)
+		if err != nil {
+			log.Println("error in service layer getting password by id", err.Error())
+			return err
+		}
+
+		if !security.CompareHashAndPassword(oldPassword, request.OldPassword) {
+			fmt.Println("error in service layer old password is not correct")
+			return ErrPasswordMismatch.WithField("old_password", "is not correct")
+		}
+
+		if err = check.ValidatePassword(request.NewPassword); err != nil {
+			fmt.Println("error in service layer new password validation failed", err.Error())
+			return ErrInvalidData.WithField("new_password", err.Error())
+		}
+
+		if err = c.storage.ClinicAdmin().UpdatePassword(ctx, request); err != nil {
+			fmt.Println("error in service layer while updating clinic admin password ", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, c.storage, "clinic_admin", request.ID, config.AuditUpdate, nil, passwordChanged)
+	})
+}
+
+// Restore brings back the soft deleted clinic admin
+func (c clinicAdminService) Restore(ctx context.Context, id string) (models.ClinicAdmin, error) {
+
+	clinicAdmin := models.ClinicAdmin{}
+
+	err := c.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		err := c.storage.ClinicAdmin().Restore(ctx, id)
+		if err != nil {
+			fmt.Println("error in service layer while restoring clinic admin", err.Error())
+			return err
+		}
+
+		clinicAdmin, err = c.storage.ClinicAdmin().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			fmt.Println("error in service layer getting clinic admin after restore", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, c.storage, "clinic_admin", id, config.AuditRestore, nil, clinicAdmin)
+	})
+	if err != nil {
+		return models.ClinicAdmin{}, err
+	}
+
+	return clinicAdmin, nil
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"shifolink/api/models"
+	"shifolink/config"
 	"shifolink/storage"
 
 	"github.com/jackc/pgx/v5"
@@ -23,17 +24,32 @@ func NewClinicBranchService(storage storage.IStorage) clinicBranchService {
 
 func (c clinicBranchService) Create(ctx context.Context, createClinicBranch models.CreateClinicBranch) (models.ClinicBranch, error) {
 
-	pKey, err := c.storage.ClinicBranch().Create(ctx, createClinicBranch)
-	if err != nil {
-		log.Println("error in service layer while creating clinic branch ", err.Error())
-		return models.ClinicBranch{}, err
-	}
+	clinicBranch := models.ClinicBranch{}
+
+	err := c.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		if err := checkReference(ctx, "clinic_id", createClinicBranch.ClinicID, c.storage.Clinic().Get); err != nil {
+			return err
+		}
 
-	clinicBranch, err := c.storage.ClinicBranch().Get(ctx, models.PrimaryKey{
-		ID: pKey,
+		pKey, err := c.storage.ClinicBranch().Create(ctx, createClinicBranch)
+		if err != nil {
+			log.Println("error in service layer while creating clinic branch ", err.Error())
+			return err
+		}
+
+		clinicBranch, err = c.storage.ClinicBranch().Get(ctx, models.PrimaryKey{
+			ID: pKey,
+		})
+		if err != nil {
+			log.Println("error in service layer get clinic branch by id")
+			return err
+		}
+
+		return recordAudit(ctx, c.storage, "clinic_branch", clinicBranch.ID, config.AuditCreate, nil, clinicBranch)
 	})
 	if err != nil {
-		log.Println("error in service layer get clinic branch by id")
+		return models.ClinicBranch{}, err
 	}
 
 	return clinicBranch, nil
@@ -45,14 +61,14 @@ func (c clinicBranchService) Get(ctx context.Context, pkey models.PrimaryKey) (m
 	if err != nil {
 		if !errors.Is(err, pgx.ErrNoRows) {
 			fmt.Println("error in service layer while getting clinic branch by id", err.Error())
-			return models.ClinicBranch{}, err
 		}
+		return models.ClinicBranch{}, err
 	}
 
 	return clinicBranch, nil
 }
 
-func (c clinicBranchService) GetList(ctx context.Context, request models.GetListRequest) (models.ClinicBranchsResponse, error) {
+func (c clinicBranchService) GetList(ctx context.Context, request models.GetClinicBranchsListRequest) (models.ClinicBranchsResponse, error) {
 
 	clinicBranch, err := c.storage.ClinicBranch().GetList(ctx, request)
 	if err != nil {
@@ -66,17 +82,36 @@ func (c clinicBranchService) GetList(ctx context.Context, request models.GetList
 
 func (c clinicBranchService) Update(ctx context.Context, updateClinicBranch models.UpdateClinicBranch) (models.ClinicBranch, error) {
 
-	id, err := c.storage.ClinicBranch().Update(ctx, updateClinicBranch)
-	if err != nil {
-		fmt.Println("error in servise layer updating clinic branch by id", err.Error())
-		return models.ClinicBranch{}, err
-	}
+	clinicBranch := models.ClinicBranch{}
+
+	err := c.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := c.storage.ClinicBranch().Get(ctx, models.PrimaryKey{ID: updateClinicBranch.ID})
+		if err != nil {
+			return err
+		}
+
+		if err := checkReference(ctx, "clinic_id", updateClinicBranch.ClinicID, c.storage.Clinic().Get); err != nil {
+			return err
+		}
+
+		id, err := c.storage.ClinicBranch().Update(ctx, updateClinicBranch)
+		if err != nil {
+			fmt.Println("error in servise layer updating clinic branch by id", err.Error())
+			return err
+		}
+
+		clinicBranch, err = c.storage.ClinicBranch().Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			fmt.Println("error in service layer getting clinic branch after update", err.Error())
+			return err
+		}
 
-	clinicBranch, err := c.storage.ClinicBranch().Get(ctx, models.PrimaryKey{
-		ID: id,
+		return recordAudit(ctx, c.storage, "clinic_branch", clinicBranch.ID, config.AuditUpdate, before, clinicBranch)
 	})
 	if err != nil {
-		fmt.Println("error in service layer getting clinic branch after update", err.Error())
 		return models.ClinicBranch{}, err
 	}
 
@@ -85,7 +120,54 @@ func (c clinicBranchService) Update(ctx context.Context, updateClinicBranch mode
 
 func (c clinicBranchService) Delete(ctx context.Context, id string) error {
 
-	err := c.storage.ClinicBranch().Delete(ctx, id)
+	return c.storage.Transaction(ctx, func(ctx context.Context) error {
 
-	return err
+		before, err := c.storage.ClinicBranch().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			return err
+		}
+
+		children, err := c.storage.ClinicBranch().Delete(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err = recordAudit(ctx, c.storage, "clinic_branch", id, config.AuditDelete, before, nil); err != nil {
+			return err
+		}
+
+		return recordChildAudits(ctx, c.storage, config.AuditDelete, children)
+	})
+}
+
+// Restore brings back the soft deleted clinic branch with its doctor types and doctors
+func (c clinicBranchService) Restore(ctx context.Context, id string) (models.ClinicBranch, error) {
+
+	clinicBranch := models.ClinicBranch{}
+
+	err := c.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		children, err := c.storage.ClinicBranch().Restore(ctx, id)
+		if err != nil {
+			fmt.Println("error in service layer while restoring clinic branch", err.Error())
+			return err
+		}
+
+		clinicBranch, err = c.storage.ClinicBranch().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			fmt.Println("error in service layer getting clinic branch after restore", err.Error())
+			return err
+		}
+
+		if err = recordAudit(ctx, c.storage, "clinic_branch", id, config.AuditRestore, nil, clinicBranch); err != nil {
+			return err
+		}
+
+		return recordChildAudits(ctx, c.storage, config.AuditRestore, children)
+	})
+	if err != nil {
+		return models.ClinicBranch{}, err
+	}
+
+	return clinicBranch, nil
 }
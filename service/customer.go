@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"log"
 	"shifolink/api/models"
+	"shifolink/config"
 	"shifolink/pkg/check"
+	"shifolink/pkg/security"
 	"shifolink/storage"
 
 	"github.com/jackc/pgx/v5"
@@ -24,17 +26,34 @@ func NewCustomerService(storage storage.IStorage) customerService {
 
 func (c customerService) Create(ctx context.Context, CreateCustomer models.CreateCustomer) (models.Customer, error) {
 
-	pKey, err := c.storage.Customer().Create(ctx, CreateCustomer)
-	if err != nil {
-		log.Println("error in service layer while creating author ", err.Error())
-		return models.Customer{}, err
-	}
+	customer := models.Customer{}
+
+	err := c.storage.Transaction(ctx, func(ctx context.Context) error {
 
-	customer, err := c.storage.Customer().Get(ctx, models.PrimaryKey{
-		ID: pKey,
+		age, err := checkAccount(CreateCustomer.Password, CreateCustomer.BirthDate)
+		if err != nil {
+			return err
+		}
+		CreateCustomer.Age = age
+
+		pKey, err := c.storage.Customer().Create(ctx, CreateCustomer)
+		if err != nil {
+			log.Println("error in service layer while creating author ", err.Error())
+			return err
+		}
+
+		customer, err = c.storage.Customer().Get(ctx, models.PrimaryKey{
+			ID: pKey,
+		})
+		if err != nil {
+			log.Println("error in service layer get customer by id")
+			return err
+		}
+
+		return recordAudit(ctx, c.storage, "customer", customer.ID, config.AuditCreate, nil, customer)
 	})
 	if err != nil {
-		log.Println("error in service layer get customer by id")
+		return models.Customer{}, err
 	}
 
 	return customer, nil
@@ -46,8 +65,8 @@ func (c customerService) Get(ctx context.Context, pkey models.PrimaryKey) (model
 	if err != nil {
 		if !errors.Is(err, pgx.ErrNoRows) {
 			fmt.Println("error in service layer while getting customer by id", err.Error())
-			return models.Customer{}, err
 		}
+		return models.Customer{}, err
 	}
 
 	return customer, nil
@@ -67,17 +86,32 @@ func (c customerService) GetList(ctx context.Context, request models.GetListRequ
 
 func (c customerService) Update(ctx context.Context, updateCustomer models.UpdateCustomer) (models.Customer, error) {
 
-	id, err := c.storage.Customer().Update(ctx, updateCustomer)
-	if err != nil {
-		fmt.Println("error in servise layer updating customer by id", err.Error())
-		return models.Customer{}, err
-	}
+	customer := models.Customer{}
+
+	err := c.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := c.storage.Customer().Get(ctx, models.PrimaryKey{ID: updateCustomer.ID})
+		if err != nil {
+			return err
+		}
+
+		id, err := c.storage.Customer().Update(ctx, updateCustomer)
+		if err != nil {
+			fmt.Println("error in servise layer updating customer by id", err.Error())
+			return err
+		}
 
-	customer, err := c.storage.Customer().Get(ctx, models.PrimaryKey{
-		ID: id,
+		customer, err = c.storage.Customer().Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			fmt.Println("error in service layer getting customer after update", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, c.storage, "customer", customer.ID, config.AuditUpdate, before, customer)
 	})
 	if err != nil {
-		fmt.Println("error in service layer getting customer after update", err.Error())
 		return models.Customer{}, err
 	}
 
@@ -86,33 +120,74 @@ func (c customerService) Update(ctx context.Context, updateCustomer models.Updat
 
 func (c customerService) Delete(ctx context.Context, id string) error {
 
-	err := c.storage.Customer().Delete(ctx, id)
+	return c.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := c.storage.Customer().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			return err
+		}
+
+		if err = c.storage.Customer().Delete(ctx, id); err != nil {
+			return err
+		}
 
-	return err
+		return recordAudit(ctx, c.storage, "customer", id, config.AuditDelete, before, nil)
+	})
 }
 
 func (c customerService) UpdatePassword(ctx context.Context, request models.UpdateCustomerPassword) error {
 
-	oldPassword, err := c.storage.Customer().GetPassword(ctx, request.ID)
-	if err != nil {
-		fmt.Println("error in service layer getting password by id", err.Error())
-		return err
-	}
+	return c.storage.Transaction(ctx, func(ctx context.Context) error {
 
-	if oldPassword != request.OldPassword {
-		fmt.Println("error in service layer old password is not correct")
-		return errors.New("old password did not match")
-	}
+		oldPassword, err := c.storage.Customer().GetPassword(ctx, request.ID)
+		if err != nil {
+			fmt.Println("error in service layer getting password by id", err.Error())
+			return err
+		}
 
-	if err = check.ValidatePassword(request.NewPassword); err != nil {
-		fmt.Println("error in service layer new password validation failed", err.Error())
-		return err
-	}
+		if !security.CompareHashAndPassword(oldPassword, request.OldPassword) {
+			fmt.Println("error in service layer old password is not correct")
+			return ErrPasswordMismatch.WithField("old_password", "is not correct")
+		}
 
-	if err = c.storage.Customer().UpdatePassword(context.Background(), request); err != nil {
-		fmt.Println("error in service layer while updating customer password ", err.Error())
-		return err
+		if err = check.ValidatePassword(request.NewPassword); err != nil {
+			fmt.Println("error in service layer new password validation failed", err.Error())
+			return ErrInvalidData.WithField("new_password", err.Error())
+		}
+
+		if err = c.storage.Customer().UpdatePassword(ctx, request); err != nil {
+			fmt.Println("error in service layer while updating customer password ", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, c.storage, "customer", request.ID, config.AuditUpdate, nil, passwordChanged)
+	})
+}
+
+// Restore brings back the soft deleted customer
+func (c customerService) Restore(ctx context.Context, id string) (models.Customer, error) {
+
+	customer := models.Customer{}
+
+	err := c.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		err := c.storage.Customer().Restore(ctx, id)
+		if err != nil {
+			fmt.Println("error in service layer while restoring customer", err.Error())
+			return err
+		}
+
+		customer, err = c.storage.Customer().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			fmt.Println("error in service layer getting customer after restore", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, c.storage, "customer", id, config.AuditRestore, nil, customer)
+	})
+	if err != nil {
+		return models.Customer{}, err
 	}
 
-	return nil
+	return customer, nil
 }
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"log"
 	"shifolink/api/models"
+	"shifolink/config"
 	"shifolink/pkg/check"
+	"shifolink/pkg/security"
 	"shifolink/storage"
 
 	"github.com/jackc/pgx/v5"
@@ -24,17 +26,38 @@ func NewDoctorService(storage storage.IStorage) doctorService {
 
 func (d doctorService) Create(ctx context.Context, createDoctor models.CreateDoctor) (models.Doctor, error) {
 
-	pKey, err := d.storage.Doctor().Create(ctx, createDoctor)
-	if err != nil {
-		log.Println("error in service layer while creating doctor  ", err.Error())
-		return models.Doctor{}, err
-	}
+	doctor := models.Doctor{}
+
+	err := d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		age, err := checkAccount(createDoctor.Password, createDoctor.BirthDate)
+		if err != nil {
+			return err
+		}
+		createDoctor.Age = age
+
+		if err := checkReference(ctx, "doctor_type_id", createDoctor.DoctorTypeID, d.storage.DoctorType().Get); err != nil {
+			return err
+		}
+
+		pKey, err := d.storage.Doctor().Create(ctx, createDoctor)
+		if err != nil {
+			log.Println("error in service layer while creating doctor  ", err.Error())
+			return err
+		}
 
-	doctor, err := d.storage.Doctor().Get(ctx, models.PrimaryKey{
-		ID: pKey,
+		doctor, err = d.storage.Doctor().Get(ctx, models.PrimaryKey{
+			ID: pKey,
+		})
+		if err != nil {
+			log.Println("error in service layer get doctor by id")
+			return err
+		}
+
+		return recordAudit(ctx, d.storage, "doctor", doctor.ID, config.AuditCreate, nil, doctor)
 	})
 	if err != nil {
-		log.Println("error in service layer get doctor by id")
+		return models.Doctor{}, err
 	}
 
 	return doctor, nil
@@ -46,14 +69,14 @@ func (d doctorService) Get(ctx context.Context, pkey models.PrimaryKey) (models.
 	if err != nil {
 		if !errors.Is(err, pgx.ErrNoRows) {
 			fmt.Println("error in service layer while getting doctor by id", err.Error())
-			return models.Doctor{}, err
 		}
+		return models.Doctor{}, err
 	}
 
 	return doctor, nil
 }
 
-func (d doctorService) GetList(ctx context.Context, request models.GetListRequest) (models.DoctorsResponse, error) {
+func (d doctorService) GetList(ctx context.Context, request models.GetDoctorsListRequest) (models.DoctorsResponse, error) {
 
 	doctor, err := d.storage.Doctor().GetList(ctx, request)
 	if err != nil {
@@ -67,17 +90,36 @@ func (d doctorService) GetList(ctx context.Context, request models.GetListReques
 
 func (d doctorService) Update(ctx context.Context, updateDoctor models.UpdateDoctor) (models.Doctor, error) {
 
-	id, err := d.storage.Doctor().Update(ctx, updateDoctor)
-	if err != nil {
-		fmt.Println("error in servise layer updating doctor type by id", err.Error())
-		return models.Doctor{}, err
-	}
+	doctor := models.Doctor{}
+
+	err := d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := d.storage.Doctor().Get(ctx, models.PrimaryKey{ID: updateDoctor.ID})
+		if err != nil {
+			return err
+		}
+
+		if err := checkReference(ctx, "doctor_type_id", updateDoctor.DoctorTypeID, d.storage.DoctorType().Get); err != nil {
+			return err
+		}
+
+		id, err := d.storage.Doctor().Update(ctx, updateDoctor)
+		if err != nil {
+			fmt.Println("error in servise layer updating doctor type by id", err.Error())
+			return err
+		}
 
-	doctor, err := d.storage.Doctor().Get(ctx, models.PrimaryKey{
-		ID: id,
+		doctor, err = d.storage.Doctor().Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			log.Println("error in service layer getting doctor after update", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, d.storage, "doctor", doctor.ID, config.AuditUpdate, before, doctor)
 	})
 	if err != nil {
-		log.Println("error in service layer getting doctor after update", err.Error())
 		return models.Doctor{}, err
 	}
 
@@ -86,33 +128,74 @@ func (d doctorService) Update(ctx context.Context, updateDoctor models.UpdateDoc
 
 func (d doctorService) Delete(ctx context.Context, id string) error {
 
-	err := d.storage.Doctor().Delete(ctx, id)
+	return d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := d.storage.Doctor().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			return err
+		}
 
-	return err
+		if err = d.storage.Doctor().Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, d.storage, "doctor", id, config.AuditDelete, before, nil)
+	})
 }
 
 func (d doctorService) UpdatePassword(ctx context.Context, request models.UpdateDoctorPassword) error {
 
-	oldPassword, err := d.storage.Doctor().GetPassword(ctx, request.ID)
-	if err != nil {
-		log.Println("error in service layer getting password by id", err.Error())
-		return err
-	}
+	return d.storage.Transaction(ctx, func(ctx context.Context) error {
 
-	if oldPassword != request.OldPassword {
-		fmt.Println("error in service layer old password is not correct")
-		return errors.New("old password did not match")
-	}
+		oldPassword, err := d.storage.Doctor().GetPassword(ctx, request.ID)
+		if err != nil {
+			log.Println("error in service layer getting password by id", err.Error())
+			return err
+		}
 
-	if err = check.ValidatePassword(request.NewPassword); err != nil {
-		fmt.Println("error in service layer new password validation failed", err.Error())
-		return err
-	}
+		if !security.CompareHashAndPassword(oldPassword, request.OldPassword) {
+			fmt.Println("error in service layer old password is not correct")
+			return ErrPasswordMismatch.WithField("old_password", "is not correct")
+		}
+
+		if err = check.ValidatePassword(request.NewPassword); err != nil {
+			fmt.Println("error in service layer new password validation failed", err.Error())
+			return ErrInvalidData.WithField("new_password", err.Error())
+		}
 
-	if err = d.storage.Doctor().UpdatePassword(context.Background(), request); err != nil {
-		fmt.Println("error in service layer while updating doctor password ", err.Error())
-		return err
+		if err = d.storage.Doctor().UpdatePassword(ctx, request); err != nil {
+			fmt.Println("error in service layer while updating doctor password ", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, d.storage, "doctor", request.ID, config.AuditUpdate, nil, passwordChanged)
+	})
+}
+
+// Restore brings back the soft deleted doctor
+func (d doctorService) Restore(ctx context.Context, id string) (models.Doctor, error) {
+
+	doctor := models.Doctor{}
+
+	err := d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		err := d.storage.Doctor().Restore(ctx, id)
+		if err != nil {
+			fmt.Println("error in service layer while restoring doctor", err.Error())
+			return err
+		}
+
+		doctor, err = d.storage.Doctor().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			fmt.Println("error in service layer getting doctor after restore", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, d.storage, "doctor", id, config.AuditRestore, nil, doctor)
+	})
+	if err != nil {
+		return models.Doctor{}, err
 	}
 
-	return nil
+	return doctor, nil
 }
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"shifolink/api/models"
+	"shifolink/config"
+	"shifolink/pkg/slot"
+	"shifolink/storage"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type doctorScheduleService struct {
+	storage storage.IStorage
+}
+
+func NewDoctorScheduleService(storage storage.IStorage) doctorScheduleService {
+	return doctorScheduleService{
+		storage: storage,
+	}
+}
+
+func (d doctorScheduleService) Create(ctx context.Context, createSchedule models.CreateDoctorSchedule) (models.DoctorSchedule, error) {
+
+	schedule := models.DoctorSchedule{}
+
+	err := d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		if _, err := d.storage.Doctor().Get(ctx, models.PrimaryKey{
+			ID: createSchedule.DoctorID,
+		}); err != nil {
+			log.Println("error in service layer while getting doctor for schedule", err.Error())
+			return err
+		}
+
+		pKey, err := d.storage.DoctorSchedule().Create(ctx, createSchedule)
+		if err != nil {
+			log.Println("error in service layer while creating doctor schedule", err.Error())
+			return err
+		}
+
+		schedule, err = d.storage.DoctorSchedule().Get(ctx, models.PrimaryKey{
+			ID: pKey,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, d.storage, "doctor_schedule", schedule.ID, config.AuditCreate, nil, schedule)
+	})
+	if err != nil {
+		return models.DoctorSchedule{}, err
+	}
+
+	return schedule, nil
+}
+
+func (d doctorScheduleService) Get(ctx context.Context, pKey models.PrimaryKey) (models.DoctorSchedule, error) {
+
+	schedule, err := d.storage.DoctorSchedule().Get(ctx, pKey)
+	if err != nil {
+		fmt.Println("error in service layer while getting doctor schedule by id", err.Error())
+		return models.DoctorSchedule{}, err
+	}
+
+	return schedule, nil
+}
+
+func (d doctorScheduleService) GetByDoctor(ctx context.Context, doctorID string) (models.DoctorSchedulesResponse, error) {
+
+	schedules, err := d.storage.DoctorSchedule().GetByDoctor(ctx, doctorID)
+	if err != nil {
+		fmt.Println("error in service layer while getting doctor schedules", err.Error())
+		return models.DoctorSchedulesResponse{}, err
+	}
+
+	return schedules, nil
+}
+
+func (d doctorScheduleService) Update(ctx context.Context, updateSchedule models.UpdateDoctorSchedule) (models.DoctorSchedule, error) {
+
+	schedule := models.DoctorSchedule{}
+
+	err := d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := d.storage.DoctorSchedule().Get(ctx, models.PrimaryKey{ID: updateSchedule.ID})
+		if err != nil {
+			return err
+		}
+
+		id, err := d.storage.DoctorSchedule().Update(ctx, updateSchedule)
+		if err != nil {
+			fmt.Println("error in service layer while updating doctor schedule", err.Error())
+			return err
+		}
+
+		schedule, err = d.storage.DoctorSchedule().Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, d.storage, "doctor_schedule", schedule.ID, config.AuditUpdate, before, schedule)
+	})
+	if err != nil {
+		return models.DoctorSchedule{}, err
+	}
+
+	return schedule, nil
+}
+
+func (d doctorScheduleService) Delete(ctx context.Context, id string) error {
+
+	return d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := d.storage.DoctorSchedule().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			return err
+		}
+
+		if err = d.storage.DoctorSchedule().Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, d.storage, "doctor_schedule", id, config.AuditDelete, before, nil)
+	})
+}
+
+// GetSlots returns slots of the doctor's working day which are not booked yet and not in the past
+func (d doctorScheduleService) GetSlots(ctx context.Context, doctorID, date string) (models.DoctorSlots, error) {
+
+	doctorSlots := models.DoctorSlots{
+		DoctorID: doctorID,
+		Date:     date,
+		Slots:    []models.Slot{},
+	}
+
+	day, err := time.Parse(slot.DateLayout, date)
+	if err != nil {
+		return models.DoctorSlots{}, fmt.Errorf("date should be in %q format", slot.DateLayout)
+	}
+
+	schedule, err := d.storage.DoctorSchedule().GetByWeekday(ctx, doctorID, int(day.Weekday()))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return doctorSlots, nil
+		}
+		fmt.Println("error in service layer while getting doctor schedule", err.Error())
+		return models.DoctorSlots{}, err
+	}
+
+	slots, err := slot.Generate(schedule, day)
+	if err != nil {
+		return models.DoctorSlots{}, err
+	}
+
+	bookedSlots, err := d.storage.Queue().GetBookedSlots(ctx, doctorID, date)
+	if err != nil {
+		fmt.Println("error in service layer while getting booked slots", err.Error())
+		return models.DoctorSlots{}, err
+	}
+
+	booked := make([]slot.Slot, 0, len(bookedSlots))
+	for _, b := range bookedSlots {
+		start, err := time.Parse(slot.DateTimeLayout, b.StartTime)
+		if err != nil {
+			return models.DoctorSlots{}, err
+		}
+
+		end, err := time.Parse(slot.DateTimeLayout, b.EndTime)
+		if err != nil {
+			return models.DoctorSlots{}, err
+		}
+
+		booked = append(booked, slot.Slot{Start: start, End: end})
+	}
+
+	now := slot.Now()
+
+	for _, s := range slots {
+		if s.Start.Before(now) || isBooked(s, booked) {
+			continue
+		}
+
+		doctorSlots.Slots = append(doctorSlots.Slots, s.ToModel())
+	}
+
+	return doctorSlots, nil
+}
+
+func isBooked(s slot.Slot, booked []slot.Slot) bool {
+	for _, b := range booked {
+		if s.Overlaps(b) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"shifolink/api/models"
+	"shifolink/config"
 	"shifolink/storage"
 
 	"github.com/jackc/pgx/v5"
@@ -23,17 +24,32 @@ func NewDoctorTypeService(storage storage.IStorage) doctorTypeService {
 
 func (d doctorTypeService) Create(ctx context.Context, createDoctorType models.CreateDoctorType) (models.DoctorType, error) {
 
-	pKey, err := d.storage.DoctorType().Create(ctx, createDoctorType)
-	if err != nil {
-		log.Println("error in service layer while creating clinic  ", err.Error())
-		return models.DoctorType{}, err
-	}
+	doctorType := models.DoctorType{}
+
+	err := d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		if err := checkReference(ctx, "clinic_branch_id", createDoctorType.ClinicBranchID, d.storage.ClinicBranch().Get); err != nil {
+			return err
+		}
 
-	doctorType, err := d.storage.DoctorType().Get(ctx, models.PrimaryKey{
-		ID: pKey,
+		pKey, err := d.storage.DoctorType().Create(ctx, createDoctorType)
+		if err != nil {
+			log.Println("error in service layer while creating clinic  ", err.Error())
+			return err
+		}
+
+		doctorType, err = d.storage.DoctorType().Get(ctx, models.PrimaryKey{
+			ID: pKey,
+		})
+		if err != nil {
+			log.Println("error in service layer get doctor type by id")
+			return err
+		}
+
+		return recordAudit(ctx, d.storage, "doctor_type", doctorType.ID, config.AuditCreate, nil, doctorType)
 	})
 	if err != nil {
-		log.Println("error in service layer get doctor type by id")
+		return models.DoctorType{}, err
 	}
 
 	return doctorType, nil
@@ -45,14 +61,14 @@ func (d doctorTypeService) Get(ctx context.Context, pkey models.PrimaryKey) (mod
 	if err != nil {
 		if !errors.Is(err, pgx.ErrNoRows) {
 			fmt.Println("error in service layer while getting doctor type by id", err.Error())
-			return models.DoctorType{}, err
 		}
+		return models.DoctorType{}, err
 	}
 
 	return doctorType, nil
 }
 
-func (d doctorTypeService) GetList(ctx context.Context, request models.GetListRequest) (models.DoctorTypesResponse, error) {
+func (d doctorTypeService) GetList(ctx context.Context, request models.GetDoctorTypesListRequest) (models.DoctorTypesResponse, error) {
 
 	doctorType, err := d.storage.DoctorType().GetList(ctx, request)
 	if err != nil {
@@ -66,17 +82,36 @@ func (d doctorTypeService) GetList(ctx context.Context, request models.GetListRe
 
 func (d doctorTypeService) Update(ctx context.Context, updateDoctorType models.UpdateDoctorType) (models.DoctorType, error) {
 
-	id, err := d.storage.DoctorType().Update(ctx, updateDoctorType)
-	if err != nil {
-		fmt.Println("error in servise layer updating doctor type by id", err.Error())
-		return models.DoctorType{}, err
-	}
+	doctorType := models.DoctorType{}
+
+	err := d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := d.storage.DoctorType().Get(ctx, models.PrimaryKey{ID: updateDoctorType.ID})
+		if err != nil {
+			return err
+		}
+
+		if err := checkReference(ctx, "clinic_branch_id", updateDoctorType.ClinicBranchID, d.storage.ClinicBranch().Get); err != nil {
+			return err
+		}
+
+		id, err := d.storage.DoctorType().Update(ctx, updateDoctorType)
+		if err != nil {
+			fmt.Println("error in servise layer updating doctor type by id", err.Error())
+			return err
+		}
+
+		doctorType, err = d.storage.DoctorType().Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			fmt.Println("error in service layer getting doctor type after update", err.Error())
+			return err
+		}
 
-	doctorType, err := d.storage.DoctorType().Get(ctx, models.PrimaryKey{
-		ID: id,
+		return recordAudit(ctx, d.storage, "doctor_type", doctorType.ID, config.AuditUpdate, before, doctorType)
 	})
 	if err != nil {
-		fmt.Println("error in service layer getting doctor type after update", err.Error())
 		return models.DoctorType{}, err
 	}
 
@@ -85,7 +120,54 @@ func (d doctorTypeService) Update(ctx context.Context, updateDoctorType models.U
 
 func (d doctorTypeService) Delete(ctx context.Context, id string) error {
 
-	err := d.storage.DoctorType().Delete(ctx, id)
+	return d.storage.Transaction(ctx, func(ctx context.Context) error {
 
-	return err
+		before, err := d.storage.DoctorType().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			return err
+		}
+
+		children, err := d.storage.DoctorType().Delete(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err = recordAudit(ctx, d.storage, "doctor_type", id, config.AuditDelete, before, nil); err != nil {
+			return err
+		}
+
+		return recordChildAudits(ctx, d.storage, config.AuditDelete, children)
+	})
+}
+
+// Restore brings back the soft deleted doctor type with its doctors
+func (d doctorTypeService) Restore(ctx context.Context, id string) (models.DoctorType, error) {
+
+	doctorType := models.DoctorType{}
+
+	err := d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		children, err := d.storage.DoctorType().Restore(ctx, id)
+		if err != nil {
+			fmt.Println("error in service layer while restoring doctor type", err.Error())
+			return err
+		}
+
+		doctorType, err = d.storage.DoctorType().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			fmt.Println("error in service layer getting doctor type after restore", err.Error())
+			return err
+		}
+
+		if err = recordAudit(ctx, d.storage, "doctor_type", id, config.AuditRestore, nil, doctorType); err != nil {
+			return err
+		}
+
+		return recordChildAudits(ctx, d.storage, config.AuditRestore, children)
+	})
+	if err != nil {
+		return models.DoctorType{}, err
+	}
+
+	return doctorType, nil
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"shifolink/api/models"
+	"shifolink/config"
 	"shifolink/storage"
 
 	"github.com/jackc/pgx/v5"
@@ -23,17 +24,36 @@ func NewDrugService(storage storage.IStorage) drugService {
 
 func (d drugService) Create(ctx context.Context, createDrug models.CreateDrug) (models.Drug, error) {
 
-	pKey, err := d.storage.Drug().Create(ctx, createDrug)
-	if err != nil {
-		log.Println("error in service layer while creating drug ", err.Error())
-		return models.Drug{}, err
-	}
+	drug := models.Drug{}
+
+	err := d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		if err := checkReference(ctx, "drug_store_branch_id", createDrug.DrugStoreBranchID, d.storage.DrugStoreBranch().Get); err != nil {
+			return err
+		}
+
+		if err := checkReference(ctx, "drug_catalogue_id", createDrug.DrugCatalogueID, d.storage.DrugCatalogue().Get); err != nil {
+			return err
+		}
+
+		pKey, err := d.storage.Drug().Create(ctx, createDrug)
+		if err != nil {
+			log.Println("error in service layer while creating drug ", err.Error())
+			return err
+		}
+
+		drug, err = d.storage.Drug().Get(ctx, models.PrimaryKey{
+			ID: pKey,
+		})
+		if err != nil {
+			log.Println("error in service layer get drug by id")
+			return err
+		}
 
-	drug, err := d.storage.Drug().Get(ctx, models.PrimaryKey{
-		ID: pKey,
+		return recordAudit(ctx, d.storage, "drug", drug.ID, config.AuditCreate, nil, drug)
 	})
 	if err != nil {
-		log.Println("error in service layer get drug by id")
+		return models.Drug{}, err
 	}
 
 	return drug, nil
@@ -45,14 +65,14 @@ func (d drugService) Get(ctx context.Context, pkey models.PrimaryKey) (models.Dr
 	if err != nil {
 		if !errors.Is(err, pgx.ErrNoRows) {
 			fmt.Println("error in service layer while getting drug by id", err.Error())
-			return models.Drug{}, err
 		}
+		return models.Drug{}, err
 	}
 
 	return drug, nil
 }
 
-func (d drugService) GetList(ctx context.Context, request models.GetListRequest) (models.DrugsResponse, error) {
+func (d drugService) GetList(ctx context.Context, request models.GetDrugsListRequest) (models.DrugsResponse, error) {
 
 	drug, err := d.storage.Drug().GetList(ctx, request)
 	if err != nil {
@@ -66,17 +86,32 @@ func (d drugService) GetList(ctx context.Context, request models.GetListRequest)
 
 func (d drugService) Update(ctx context.Context, updateDrug models.UpdateDrug) (models.Drug, error) {
 
-	id, err := d.storage.Drug().Update(ctx, updateDrug)
-	if err != nil {
-		fmt.Println("error in servise layer updating drug  by id", err.Error())
-		return models.Drug{}, err
-	}
+	drug := models.Drug{}
+
+	err := d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := d.storage.Drug().Get(ctx, models.PrimaryKey{ID: updateDrug.ID})
+		if err != nil {
+			return err
+		}
+
+		id, err := d.storage.Drug().Update(ctx, updateDrug)
+		if err != nil {
+			fmt.Println("error in servise layer updating drug  by id", err.Error())
+			return err
+		}
 
-	drug, err := d.storage.Drug().Get(ctx, models.PrimaryKey{
-		ID: id,
+		drug, err = d.storage.Drug().Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			fmt.Println("error in service layer getting drug after update", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, d.storage, "drug", drug.ID, config.AuditUpdate, before, drug)
 	})
 	if err != nil {
-		fmt.Println("error in service layer getting drug after update", err.Error())
 		return models.Drug{}, err
 	}
 
@@ -85,7 +120,45 @@ func (d drugService) Update(ctx context.Context, updateDrug models.UpdateDrug) (
 
 func (d drugService) Delete(ctx context.Context, id string) error {
 
-	err := d.storage.Drug().Delete(ctx, id)
+	return d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := d.storage.Drug().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			return err
+		}
+
+		if err = d.storage.Drug().Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, d.storage, "drug", id, config.AuditDelete, before, nil)
+	})
+}
+
+// Restore brings back the soft deleted drug
+func (d drugService) Restore(ctx context.Context, id string) (models.Drug, error) {
+
+	drug := models.Drug{}
 
-	return err
+	err := d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		err := d.storage.Drug().Restore(ctx, id)
+		if err != nil {
+			fmt.Println("error in service layer while restoring drug", err.Error())
+			return err
+		}
+
+		drug, err = d.storage.Drug().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			fmt.Println("error in service layer getting drug after restore", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, d.storage, "drug", id, config.AuditRestore, nil, drug)
+	})
+	if err != nil {
+		return models.Drug{}, err
+	}
+
+	return drug, nil
 }
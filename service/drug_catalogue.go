@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"shifolink/api/models"
+	"shifolink/config"
+	"shifolink/storage"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type drugCatalogueService struct {
+	storage storage.IStorage
+}
+
+func NewDrugCatalogueService(storage storage.IStorage) drugCatalogueService {
+	return drugCatalogueService{
+		storage: storage,
+	}
+}
+
+func (d drugCatalogueService) Create(ctx context.Context, createDrugCatalogue models.CreateDrugCatalogue) (models.DrugCatalogue, error) {
+
+	drugCatalogue := models.DrugCatalogue{}
+
+	err := d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		pKey, err := d.storage.DrugCatalogue().Create(ctx, createDrugCatalogue)
+		if err != nil {
+			log.Println("error in service layer while creating drug catalogue   ", err.Error())
+			return err
+		}
+
+		drugCatalogue, err = d.storage.DrugCatalogue().Get(ctx, models.PrimaryKey{
+			ID: pKey,
+		})
+		if err != nil {
+			log.Println("error in service layer get drug catalogue by id")
+			return err
+		}
+
+		return recordAudit(ctx, d.storage, "drug_catalogue", drugCatalogue.ID, config.AuditCreate, nil, drugCatalogue)
+	})
+	if err != nil {
+		return models.DrugCatalogue{}, err
+	}
+
+	return drugCatalogue, nil
+}
+
+func (d drugCatalogueService) Get(ctx context.Context, pkey models.PrimaryKey) (models.DrugCatalogue, error) {
+
+	drugCatalogue, err := d.storage.DrugCatalogue().Get(ctx, pkey)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			fmt.Println("error in service layer while getting drug catalogue by id", err.Error())
+		}
+		return models.DrugCatalogue{}, err
+	}
+
+	return drugCatalogue, nil
+}
+
+func (d drugCatalogueService) GetList(ctx context.Context, request models.GetListRequest) (models.DrugCataloguesResponse, error) {
+
+	drugCatalogue, err := d.storage.DrugCatalogue().GetList(ctx, request)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			fmt.Println("error in service layer while getting drug catalogue list", err.Error())
+			return models.DrugCataloguesResponse{}, err
+		}
+	}
+	return drugCatalogue, nil
+}
+
+func (d drugCatalogueService) Update(ctx context.Context, updateDrugCatalogue models.UpdateDrugCatalogue) (models.DrugCatalogue, error) {
+
+	drugCatalogue := models.DrugCatalogue{}
+
+	err := d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := d.storage.DrugCatalogue().Get(ctx, models.PrimaryKey{ID: updateDrugCatalogue.ID})
+		if err != nil {
+			return err
+		}
+
+		id, err := d.storage.DrugCatalogue().Update(ctx, updateDrugCatalogue)
+		if err != nil {
+			fmt.Println("error in servise layer updating drug catalogue by id", err.Error())
+			return err
+		}
+
+		drugCatalogue, err = d.storage.DrugCatalogue().Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			fmt.Println("error in service layer getting drug catalogue after update", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, d.storage, "drug_catalogue", drugCatalogue.ID, config.AuditUpdate, before, drugCatalogue)
+	})
+	if err != nil {
+		return models.DrugCatalogue{}, err
+	}
+
+	return drugCatalogue, nil
+}
+
+// Delete removes the drug from the catalogue, every branch stops selling it
+func (d drugCatalogueService) Delete(ctx context.Context, id string) error {
+
+	return d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := d.storage.DrugCatalogue().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			return err
+		}
+
+		children, err := d.storage.DrugCatalogue().Delete(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err = recordAudit(ctx, d.storage, "drug_catalogue", id, config.AuditDelete, before, nil); err != nil {
+			return err
+		}
+
+		return recordChildAudits(ctx, d.storage, config.AuditDelete, children)
+	})
+}
+
+// Restore brings back the soft deleted catalogue drug with the branch drugs its delete took
+func (d drugCatalogueService) Restore(ctx context.Context, id string) (models.DrugCatalogue, error) {
+
+	drugCatalogue := models.DrugCatalogue{}
+
+	err := d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		children, err := d.storage.DrugCatalogue().Restore(ctx, id)
+		if err != nil {
+			fmt.Println("error in service layer while restoring drug catalogue", err.Error())
+			return err
+		}
+
+		drugCatalogue, err = d.storage.DrugCatalogue().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			fmt.Println("error in service layer getting drug catalogue after restore", err.Error())
+			return err
+		}
+
+		if err = recordAudit(ctx, d.storage, "drug_catalogue", id, config.AuditRestore, nil, drugCatalogue); err != nil {
+			return err
+		}
+
+		return recordChildAudits(ctx, d.storage, config.AuditRestore, children)
+	})
+	if err != nil {
+		return models.DrugCatalogue{}, err
+	}
+
+	return drugCatalogue, nil
+}
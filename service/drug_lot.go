@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"shifolink/api/models"
+	"shifolink/config"
+	"shifolink/pkg/errs"
+	"shifolink/pkg/money"
+	"shifolink/pkg/slot"
+	"shifolink/storage"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidDrugLot = errs.Validation("drug lot is not valid")
+
+// defaultExpiringWithin is the number of days the expiring report looks ahead by default
+const defaultExpiringWithin = 30
+
+type drugLotService struct {
+	storage storage.IStorage
+}
+
+func NewDrugLotService(storage storage.IStorage) drugLotService {
+	return drugLotService{
+		storage: storage,
+	}
+}
+
+func (d drugLotService) Create(ctx context.Context, createLot models.CreateDrugLot) (models.DrugLot, error) {
+
+	lot := models.DrugLot{}
+
+	err := d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		if err := validateDrugLot(createLot.LotNumber, createLot.Quantity, createLot.ExpiryDate, createLot.PurchasePrice); err != nil {
+			return err
+		}
+
+		if _, err := d.storage.Drug().Get(ctx, models.PrimaryKey{
+			ID: createLot.DrugID,
+		}); err != nil {
+			log.Println("error in service layer while getting drug for lot", err.Error())
+			return err
+		}
+
+		pKey, err := d.storage.DrugLot().Create(ctx, createLot)
+		if err != nil {
+			log.Println("error in service layer while creating drug lot", err.Error())
+			return err
+		}
+
+		lot, err = d.storage.DrugLot().Get(ctx, models.PrimaryKey{
+			ID: pKey,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, d.storage, "drug_lot", lot.ID, config.AuditCreate, nil, lot)
+	})
+	if err != nil {
+		return models.DrugLot{}, err
+	}
+
+	return lot, nil
+}
+
+func (d drugLotService) Get(ctx context.Context, pKey models.PrimaryKey) (models.DrugLot, error) {
+
+	lot, err := d.storage.DrugLot().Get(ctx, pKey)
+	if err != nil {
+		fmt.Println("error in service layer while getting drug lot by id", err.Error())
+		return models.DrugLot{}, err
+	}
+
+	return lot, nil
+}
+
+func (d drugLotService) GetByDrug(ctx context.Context, drugID string) (models.DrugLotsResponse, error) {
+
+	lots, err := d.storage.DrugLot().GetByDrug(ctx, drugID)
+	if err != nil {
+		fmt.Println("error in service layer while getting drug lots", err.Error())
+		return models.DrugLotsResponse{}, err
+	}
+
+	return lots, nil
+}
+
+func (d drugLotService) Update(ctx context.Context, updateLot models.UpdateDrugLot) (models.DrugLot, error) {
+
+	lot := models.DrugLot{}
+
+	err := d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		if err := validateDrugLot(updateLot.LotNumber, updateLot.Quantity, updateLot.ExpiryDate, updateLot.PurchasePrice); err != nil {
+			return err
+		}
+
+		before, err := d.storage.DrugLot().Get(ctx, models.PrimaryKey{ID: updateLot.ID})
+		if err != nil {
+			return err
+		}
+
+		id, err := d.storage.DrugLot().Update(ctx, updateLot)
+		if err != nil {
+			fmt.Println("error in service layer while updating drug lot", err.Error())
+			return err
+		}
+
+		lot, err = d.storage.DrugLot().Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, d.storage, "drug_lot", lot.ID, config.AuditUpdate, before, lot)
+	})
+	if err != nil {
+		return models.DrugLot{}, err
+	}
+
+	return lot, nil
+}
+
+func (d drugLotService) Delete(ctx context.Context, id string) error {
+
+	return d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := d.storage.DrugLot().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			return err
+		}
+
+		if err = d.storage.DrugLot().Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, d.storage, "drug_lot", id, config.AuditDelete, before, nil)
+	})
+}
+
+// GetExpiring reports lots of the branch expiring within the period, e.g. "30d", "2w" or "30" days
+func (d drugLotService) GetExpiring(ctx context.Context, drugStoreBranchID, within string) (models.ExpiringLotsResponse, error) {
+
+	days, err := parseDays(within)
+	if err != nil {
+		return models.ExpiringLotsResponse{}, err
+	}
+
+	lots, err := d.storage.DrugLot().GetExpiring(ctx, models.ExpiringLotsRequest{
+		DrugStoreBranchID: drugStoreBranchID,
+		Within:            days,
+	})
+	if err != nil {
+		fmt.Println("error in service layer while getting expiring drug lots", err.Error())
+		return models.ExpiringLotsResponse{}, err
+	}
+
+	return lots, nil
+}
+
+func validateDrugLot(lotNumber string, quantity int, expiryDate, purchasePrice string) error {
+
+	if strings.TrimSpace(lotNumber) == "" {
+		return fmt.Errorf("%w: lot_number is required", ErrInvalidDrugLot)
+	}
+
+	if quantity < 0 {
+		return fmt.Errorf("%w: quantity can not be negative", ErrInvalidDrugLot)
+	}
+
+	if _, err := time.Parse(slot.DateLayout, expiryDate); err != nil {
+		return fmt.Errorf("%w: expiry_date should be in YYYY-MM-DD format", ErrInvalidDrugLot)
+	}
+
+	if price, err := money.Parse(purchasePrice); err != nil || price < 0 {
+		return fmt.Errorf("%w: purchase_price should be a non negative amount", ErrInvalidDrugLot)
+	}
+
+	return nil
+}
+
+func parseDays(within string) (int, error) {
+
+	if within == "" {
+		return defaultExpiringWithin, nil
+	}
+
+	multiplier := 1
+	switch {
+	case strings.HasSuffix(within, "d"):
+		within = strings.TrimSuffix(within, "d")
+	case strings.HasSuffix(within, "w"):
+		within = strings.TrimSuffix(within, "w")
+		multiplier = 7
+	}
+
+	days, err := strconv.Atoi(within)
+	if err != nil || days < 0 {
+		return 0, fmt.Errorf("%w: within should look like 30d, 2w or 30", ErrInvalidDrugLot)
+	}
+
+	return days * multiplier, nil
+}
+
+// recordStockMoves logs what the checkout of the order took from each lot, or gave back to it when returned is
+// set, as an update of the lot quantity
+func recordStockMoves(ctx context.Context, store storage.IStorage, ordersID string, returned bool) error {
+
+	moves, err := store.DrugLot().GetOrderMoves(ctx, ordersID)
+	if err != nil {
+		log.Println("error in service layer while getting lots of order", err.Error())
+		return err
+	}
+
+	for _, move := range moves {
+		before := move.DrugLot
+		if returned {
+			before.Quantity -= move.Moved
+		} else {
+			before.Quantity += move.Moved
+		}
+
+		if err = recordAudit(ctx, store, "drug_lot", move.ID, config.AuditUpdate, before, move.DrugLot); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
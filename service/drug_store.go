@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"shifolink/api/models"
+	"shifolink/config"
 	"shifolink/storage"
 
 	"github.com/jackc/pgx/v5"
@@ -23,17 +24,28 @@ func NewDrugStoreService(storage storage.IStorage) drugStoreService {
 
 func (d drugStoreService) Create(ctx context.Context, createDrugStore models.CreateDrugStore) (models.DrugStore, error) {
 
-	pKey, err := d.storage.DrugStore().Create(ctx, createDrugStore)
-	if err != nil {
-		log.Println("error in service layer while creating drug store   ", err.Error())
-		return models.DrugStore{}, err
-	}
+	drugStore := models.DrugStore{}
+
+	err := d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		pKey, err := d.storage.DrugStore().Create(ctx, createDrugStore)
+		if err != nil {
+			log.Println("error in service layer while creating drug store   ", err.Error())
+			return err
+		}
+
+		drugStore, err = d.storage.DrugStore().Get(ctx, models.PrimaryKey{
+			ID: pKey,
+		})
+		if err != nil {
+			log.Println("error in service layer get drug store by id")
+			return err
+		}
 
-	drugStore, err := d.storage.DrugStore().Get(ctx, models.PrimaryKey{
-		ID: pKey,
+		return recordAudit(ctx, d.storage, "drug_store", drugStore.ID, config.AuditCreate, nil, drugStore)
 	})
 	if err != nil {
-		log.Println("error in service layer get drug store by id")
+		return models.DrugStore{}, err
 	}
 
 	return drugStore, nil
@@ -45,8 +57,8 @@ func (d drugStoreService) Get(ctx context.Context, pkey models.PrimaryKey) (mode
 	if err != nil {
 		if !errors.Is(err, pgx.ErrNoRows) {
 			fmt.Println("error in service layer while getting drug store by id", err.Error())
-			return models.DrugStore{}, err
 		}
+		return models.DrugStore{}, err
 	}
 
 	return drugStore, nil
@@ -66,17 +78,32 @@ func (d drugStoreService) GetList(ctx context.Context, request models.GetListReq
 
 func (d drugStoreService) Update(ctx context.Context, updateDrugStore models.UpdateDrugStore) (models.DrugStore, error) {
 
-	id, err := d.storage.DrugStore().Update(ctx, updateDrugStore)
-	if err != nil {
-		fmt.Println("error in servise layer updating drug store by id", err.Error())
-		return models.DrugStore{}, err
-	}
+	drugStore := models.DrugStore{}
+
+	err := d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := d.storage.DrugStore().Get(ctx, models.PrimaryKey{ID: updateDrugStore.ID})
+		if err != nil {
+			return err
+		}
+
+		id, err := d.storage.DrugStore().Update(ctx, updateDrugStore)
+		if err != nil {
+			fmt.Println("error in servise layer updating drug store by id", err.Error())
+			return err
+		}
+
+		drugStore, err = d.storage.DrugStore().Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			fmt.Println("error in service layer getting drug store after update", err.Error())
+			return err
+		}
 
-	drugStore, err := d.storage.DrugStore().Get(ctx, models.PrimaryKey{
-		ID: id,
+		return recordAudit(ctx, d.storage, "drug_store", drugStore.ID, config.AuditUpdate, before, drugStore)
 	})
 	if err != nil {
-		fmt.Println("error in service layer getting drug store after update", err.Error())
 		return models.DrugStore{}, err
 	}
 
@@ -85,7 +112,45 @@ func (d drugStoreService) Update(ctx context.Context, updateDrugStore models.Upd
 
 func (d drugStoreService) Delete(ctx context.Context, id string) error {
 
-	err := d.storage.DrugStore().Delete(ctx, id)
+	return d.storage.Transaction(ctx, func(ctx context.Context) error {
 
-	return err
+		before, err := d.storage.DrugStore().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			return err
+		}
+
+		if err = d.storage.DrugStore().Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, d.storage, "drug_store", id, config.AuditDelete, before, nil)
+	})
+}
+
+// Restore brings back the soft deleted drug store
+func (d drugStoreService) Restore(ctx context.Context, id string) (models.DrugStore, error) {
+
+	drugStore := models.DrugStore{}
+
+	err := d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		err := d.storage.DrugStore().Restore(ctx, id)
+		if err != nil {
+			fmt.Println("error in service layer while restoring drug store", err.Error())
+			return err
+		}
+
+		drugStore, err = d.storage.DrugStore().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			fmt.Println("error in service layer getting drug store after restore", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, d.storage, "drug_store", id, config.AuditRestore, nil, drugStore)
+	})
+	if err != nil {
+		return models.DrugStore{}, err
+	}
+
+	return drugStore, nil
 }
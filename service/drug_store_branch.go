@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"shifolink/api/models"
+	"shifolink/config"
 	"shifolink/storage"
 
 	"github.com/jackc/pgx/v5"
@@ -23,17 +24,32 @@ func NewDrugStoreBranchService(storage storage.IStorage) drugStoreBranchService
 
 func (d drugStoreBranchService) Create(ctx context.Context, createDrugStoreBranch models.CreateDrugStoreBranch) (models.DrugStoreBranch, error) {
 
-	pKey, err := d.storage.DrugStoreBranch().Create(ctx, createDrugStoreBranch)
-	if err != nil {
-		log.Println("error in service layer while creating drug store branch  ", err.Error())
-		return models.DrugStoreBranch{}, err
-	}
+	drugStoreBranch := models.DrugStoreBranch{}
+
+	err := d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		if err := checkReference(ctx, "drug_store_id", createDrugStoreBranch.DrugStoreID, d.storage.DrugStore().Get); err != nil {
+			return err
+		}
 
-	drugStoreBranch, err := d.storage.DrugStoreBranch().Get(ctx, models.PrimaryKey{
-		ID: pKey,
+		pKey, err := d.storage.DrugStoreBranch().Create(ctx, createDrugStoreBranch)
+		if err != nil {
+			log.Println("error in service layer while creating drug store branch  ", err.Error())
+			return err
+		}
+
+		drugStoreBranch, err = d.storage.DrugStoreBranch().Get(ctx, models.PrimaryKey{
+			ID: pKey,
+		})
+		if err != nil {
+			log.Println("error in service layer get drug store branch by id")
+			return err
+		}
+
+		return recordAudit(ctx, d.storage, "drug_store_branch", drugStoreBranch.ID, config.AuditCreate, nil, drugStoreBranch)
 	})
 	if err != nil {
-		log.Println("error in service layer get drug store branch by id")
+		return models.DrugStoreBranch{}, err
 	}
 
 	return drugStoreBranch, nil
@@ -45,14 +61,14 @@ func (d drugStoreBranchService) Get(ctx context.Context, pkey models.PrimaryKey)
 	if err != nil {
 		if !errors.Is(err, pgx.ErrNoRows) {
 			fmt.Println("error in service layer while getting drug store branch by id", err.Error())
-			return models.DrugStoreBranch{}, err
 		}
+		return models.DrugStoreBranch{}, err
 	}
 
 	return drugStoreBranch, nil
 }
 
-func (d drugStoreBranchService) GetList(ctx context.Context, request models.GetListRequest) (models.DrugStoreBranchsResponse, error) {
+func (d drugStoreBranchService) GetList(ctx context.Context, request models.GetDrugStoreBranchsListRequest) (models.DrugStoreBranchsResponse, error) {
 
 	drugStoreBranch, err := d.storage.DrugStoreBranch().GetList(ctx, request)
 	if err != nil {
@@ -66,17 +82,36 @@ func (d drugStoreBranchService) GetList(ctx context.Context, request models.GetL
 
 func (d drugStoreBranchService) Update(ctx context.Context, updateDrugStoreBranch models.UpdateDrugStoreBranch) (models.DrugStoreBranch, error) {
 
-	id, err := d.storage.DrugStoreBranch().Update(ctx, updateDrugStoreBranch)
-	if err != nil {
-		fmt.Println("error in servise layer updating drug store branch by id", err.Error())
-		return models.DrugStoreBranch{}, err
-	}
+	drugStoreBranch := models.DrugStoreBranch{}
+
+	err := d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := d.storage.DrugStoreBranch().Get(ctx, models.PrimaryKey{ID: updateDrugStoreBranch.ID})
+		if err != nil {
+			return err
+		}
+
+		if err := checkReference(ctx, "drug_store_id", updateDrugStoreBranch.DrugStoreID, d.storage.DrugStore().Get); err != nil {
+			return err
+		}
+
+		id, err := d.storage.DrugStoreBranch().Update(ctx, updateDrugStoreBranch)
+		if err != nil {
+			fmt.Println("error in servise layer updating drug store branch by id", err.Error())
+			return err
+		}
 
-	drugStoreBranch, err := d.storage.DrugStoreBranch().Get(ctx, models.PrimaryKey{
-		ID: id,
+		drugStoreBranch, err = d.storage.DrugStoreBranch().Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			fmt.Println("error in service layer getting doctor type after update", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, d.storage, "drug_store_branch", drugStoreBranch.ID, config.AuditUpdate, before, drugStoreBranch)
 	})
 	if err != nil {
-		fmt.Println("error in service layer getting doctor type after update", err.Error())
 		return models.DrugStoreBranch{}, err
 	}
 
@@ -85,7 +120,45 @@ func (d drugStoreBranchService) Update(ctx context.Context, updateDrugStoreBranc
 
 func (d drugStoreBranchService) Delete(ctx context.Context, id string) error {
 
-	err := d.storage.DrugStoreBranch().Delete(ctx, id)
+	return d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := d.storage.DrugStoreBranch().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			return err
+		}
+
+		if err = d.storage.DrugStoreBranch().Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, d.storage, "drug_store_branch", id, config.AuditDelete, before, nil)
+	})
+}
+
+// Restore brings back the soft deleted drug store branch
+func (d drugStoreBranchService) Restore(ctx context.Context, id string) (models.DrugStoreBranch, error) {
+
+	drugStoreBranch := models.DrugStoreBranch{}
 
-	return err
+	err := d.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		err := d.storage.DrugStoreBranch().Restore(ctx, id)
+		if err != nil {
+			fmt.Println("error in service layer while restoring drug store branch", err.Error())
+			return err
+		}
+
+		drugStoreBranch, err = d.storage.DrugStoreBranch().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			fmt.Println("error in service layer getting drug store branch after restore", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, d.storage, "drug_store_branch", id, config.AuditRestore, nil, drugStoreBranch)
+	})
+	if err != nil {
+		return models.DrugStoreBranch{}, err
+	}
+
+	return drugStoreBranch, nil
 }
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"shifolink/api/models"
+	"shifolink/storage"
+)
+
+var ErrNotReady = errors.New("service is not ready")
+
+type healthService struct {
+	storage storage.IStorage
+}
+
+func NewHealthService(storage storage.IStorage) healthService {
+	return healthService{
+		storage: storage,
+	}
+}
+
+// Ready checks the database answers and its schema is migrated, ErrNotReady is returned when a check fails
+func (h healthService) Ready(ctx context.Context) (models.Readiness, error) {
+
+	readiness := models.Readiness{
+		Database:   "ok",
+		Migrations: "ok",
+	}
+
+	if err := h.storage.Ping(ctx); err != nil {
+		log.Println("error in service layer while pinging database", err.Error())
+		readiness.Database = err.Error()
+		readiness.Migrations = "not checked"
+		return readiness, ErrNotReady
+	}
+
+	if err := h.storage.CheckMigrations(ctx); err != nil {
+		log.Println("error in service layer while checking migrations", err.Error())
+		readiness.Migrations = err.Error()
+		return readiness, ErrNotReady
+	}
+
+	return readiness, nil
+}
@@ -6,11 +6,38 @@ import (
 	"fmt"
 	"log"
 	"shifolink/api/models"
+	"shifolink/config"
+	"shifolink/pkg/errs"
+	"shifolink/pkg/slug"
+	"shifolink/pkg/textdiff"
 	"shifolink/storage"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
+var (
+	ErrInvalidJournal   = errs.Validation("journal is not valid")
+	ErrJournalNotDraft  = errs.Conflict("only drafts can be edited")
+	ErrJournalSlugTaken = errs.Conflict("slug is used by another journal")
+)
+
+// maxJournalTags keeps the tag list short enough to be shown on an article card
+const maxJournalTags = 10
+
+// journalSlugAttempts is how many numbered slugs are tried when the one made from the theme is taken
+const journalSlugAttempts = 5
+
+// journalTransitions lists statuses an article can move to from its current status
+var journalTransitions = map[string][]string{
+	config.JournalDraft:     {config.JournalInReview},
+	config.JournalInReview:  {config.JournalDraft, config.JournalPublished},
+	config.JournalPublished: {config.JournalArchived, config.JournalDraft},
+	config.JournalArchived:  {config.JournalDraft},
+}
+
 type journalService struct {
 	storage storage.IStorage
 }
@@ -21,19 +48,69 @@ func NewJournalService(storage storage.IStorage) journalService {
 	}
 }
 
+// Create saves the article as a draft, it is seen only by its author and super admins until it is approved
 func (j journalService) Create(ctx context.Context, createJournal models.CreateJournal) (models.Journal, error) {
 
-	pKey, err := j.storage.Journal().Create(ctx, createJournal)
-	if err != nil {
-		log.Println("error in service layer while creating journal ", err.Error())
-		return models.Journal{}, err
-	}
+	journal := models.Journal{}
+
+	err := j.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		if err := validateJournalText(createJournal.Theme, createJournal.Article, createJournal.Slug); err != nil {
+			return err
+		}
+
+		if err := checkReference(ctx, "author_id", createJournal.AuthorID, j.storage.Author().Get); err != nil {
+			return err
+		}
+
+		var err error
 
-	journal, err := j.storage.Journal().Get(ctx, models.PrimaryKey{
-		ID: pKey,
+		createJournal.Tags, createJournal.DoctorTypeIDs, err = j.checkLinks(ctx, createJournal.CategoryID, createJournal.Tags, createJournal.DoctorTypeIDs)
+		if err != nil {
+			return err
+		}
+
+		given := createJournal.Slug != ""
+
+		base := createJournal.Slug
+		if !given {
+			if base = slug.Make(createJournal.Theme); base == "" {
+				base = "journal"
+			}
+		}
+
+		pKey := ""
+
+		for attempt := 1; attempt <= journalSlugAttempts; attempt++ {
+			createJournal.Slug = base
+			if attempt > 1 {
+				createJournal.Slug = fmt.Sprintf("%s-%d", base, attempt)
+			}
+
+			pKey, err = j.storage.Journal().Create(ctx, createJournal)
+			if given || !isSlugTaken(err) {
+				break
+			}
+		}
+		if isSlugTaken(err) {
+			return ErrJournalSlugTaken
+		}
+		if err != nil {
+			log.Println("error in service layer while creating journal ", err.Error())
+			return err
+		}
+
+		journal, err = j.Get(ctx, models.PrimaryKey{
+			ID: pKey,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, j.storage, "journal", journal.ID, config.AuditCreate, nil, journal)
 	})
 	if err != nil {
-		log.Println("error in service layer get journal by id")
+		return models.Journal{}, err
 	}
 
 	return journal, nil
@@ -45,14 +122,27 @@ func (j journalService) Get(ctx context.Context, pkey models.PrimaryKey) (models
 	if err != nil {
 		if !errors.Is(err, pgx.ErrNoRows) {
 			fmt.Println("error in service layer while getting journal by id", err.Error())
-			return models.Journal{}, err
 		}
+		return models.Journal{}, err
 	}
 
 	return journal, nil
 }
 
-func (j journalService) GetList(ctx context.Context, request models.GetListRequest) (models.JournalsResponse, error) {
+func (j journalService) GetBySlug(ctx context.Context, slug string) (models.Journal, error) {
+
+	journal, err := j.storage.Journal().GetBySlug(ctx, slug)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			fmt.Println("error in service layer while getting journal by slug", err.Error())
+		}
+		return models.Journal{}, err
+	}
+
+	return journal, nil
+}
+
+func (j journalService) GetList(ctx context.Context, request models.GetJournalsListRequest) (models.JournalsResponse, error) {
 
 	journal, err := j.storage.Journal().GetList(ctx, request)
 	if err != nil {
@@ -64,28 +154,463 @@ func (j journalService) GetList(ctx context.Context, request models.GetListReque
 	return journal, nil
 }
 
+// GetForDoctor lists published articles about the specialty of the doctor, to be read next to their profile
+func (j journalService) GetForDoctor(ctx context.Context, doctorID string, request models.GetListRequest) (models.JournalsResponse, error) {
+
+	doctor, err := j.storage.Doctor().Get(ctx, models.PrimaryKey{
+		ID: doctorID,
+	})
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			fmt.Println("error in service layer while getting doctor of journals", err.Error())
+		}
+		return models.JournalsResponse{}, err
+	}
+
+	if doctor.DoctorTypeID == "" {
+		return models.JournalsResponse{
+			Journals: []models.Journal{},
+		}, nil
+	}
+
+	return j.GetList(ctx, models.GetJournalsListRequest{
+		GetListRequest: request,
+		DoctorTypeID:   doctor.DoctorTypeID,
+		PublishedOnly:  true,
+	})
+}
+
+func (j journalService) Search(ctx context.Context, request models.SearchJournalsRequest) (models.JournalSearchResponse, error) {
+
+	response, err := j.storage.Journal().Search(ctx, request)
+	if err != nil {
+		fmt.Println("error in service layer while searching journals", err.Error())
+		return models.JournalSearchResponse{}, err
+	}
+
+	return response, nil
+}
+
+// Update saves the edited draft as its next version
 func (j journalService) Update(ctx context.Context, updateJournal models.UpdateJournal) (models.Journal, error) {
 
-	id, err := j.storage.Journal().Update(ctx, updateJournal)
+	updated := models.Journal{}
+
+	err := j.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		if err := validateJournalText(updateJournal.Theme, updateJournal.Article, updateJournal.Slug); err != nil {
+			return err
+		}
+
+		if updateJournal.AuthorID != "" {
+			if err := checkReference(ctx, "author_id", updateJournal.AuthorID, j.storage.Author().Get); err != nil {
+				return err
+			}
+		}
+
+		journal, err := j.Get(ctx, models.PrimaryKey{
+			ID: updateJournal.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if journal.Status != config.JournalDraft {
+			return fmt.Errorf("%w: journal is %s", ErrJournalNotDraft, journal.Status)
+		}
+
+		id, err := j.storage.Journal().Update(ctx, updateJournal)
+		if err != nil {
+			if isSlugTaken(err) {
+				return ErrJournalSlugTaken
+			}
+			// the journal left draft after it was read above, the update matched no draft row
+			if errors.Is(err, storage.ErrStatusChanged) {
+				return fmt.Errorf("%w: journal is no longer a draft", ErrJournalNotDraft)
+			}
+			fmt.Println("error in servise layer updating journal  by id", err.Error())
+			return err
+		}
+
+		updated, err = j.Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, j.storage, "journal", updated.ID, config.AuditUpdate, journal, updated)
+	})
 	if err != nil {
-		fmt.Println("error in servise layer updating journal  by id", err.Error())
 		return models.Journal{}, err
 	}
 
-	journal, err := j.storage.Journal().Get(ctx, models.PrimaryKey{
+	return updated, nil
+}
+
+func (j journalService) Delete(ctx context.Context, id string) error {
+
+	return j.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := j.storage.Journal().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			return err
+		}
+
+		if err = j.storage.Journal().Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, j.storage, "journal", id, config.AuditDelete, before, nil)
+	})
+}
+
+// Submit sends the draft to super admins for review
+func (j journalService) Submit(ctx context.Context, id string) (models.Journal, error) {
+	return j.changeStatus(ctx, models.UpdateJournalStatus{
+		ID:       id,
+		ToStatus: config.JournalInReview,
+	})
+}
+
+// Approve publishes the reviewed article at publishAt, a zero or past time publishes it right away
+func (j journalService) Approve(ctx context.Context, id, reviewerID string, publishAt time.Time) (models.Journal, error) {
+
+	if now := time.Now(); publishAt.Before(now) {
+		publishAt = now
+	}
+
+	return j.changeStatus(ctx, models.UpdateJournalStatus{
+		ID:          id,
+		ToStatus:    config.JournalPublished,
+		PublishedAt: &publishAt,
+		ReviewedBy:  reviewerID,
+	})
+}
+
+// Reject returns the reviewed article to its author as a draft with the reason
+func (j journalService) Reject(ctx context.Context, id, reviewerID, note string) (models.Journal, error) {
+
+	if strings.TrimSpace(note) == "" {
+		return models.Journal{}, fmt.Errorf("%w: note should tell the author why the journal is rejected", ErrInvalidJournal)
+	}
+
+	journal, err := j.Get(ctx, models.PrimaryKey{
 		ID: id,
 	})
 	if err != nil {
-		fmt.Println("error in service layer getting journal after update", err.Error())
+		return models.Journal{}, err
+	}
+
+	if journal.Status != config.JournalInReview {
+		return models.Journal{}, fmt.Errorf("%w: only journals in review can be rejected", ErrInvalidStatusTransition)
+	}
+
+	return j.changeStatus(ctx, models.UpdateJournalStatus{
+		ID:         id,
+		ToStatus:   config.JournalDraft,
+		ReviewedBy: reviewerID,
+		ReviewNote: note,
+	})
+}
+
+// Archive takes the published article down, it can be brought back only through a new review
+func (j journalService) Archive(ctx context.Context, id string) (models.Journal, error) {
+	return j.changeStatus(ctx, models.UpdateJournalStatus{
+		ID:       id,
+		ToStatus: config.JournalArchived,
+	})
+}
+
+// Withdraw turns the article back into a draft so it can be edited
+func (j journalService) Withdraw(ctx context.Context, id string) (models.Journal, error) {
+	return j.changeStatus(ctx, models.UpdateJournalStatus{
+		ID:       id,
+		ToStatus: config.JournalDraft,
+	})
+}
+
+func (j journalService) changeStatus(ctx context.Context, request models.UpdateJournalStatus) (models.Journal, error) {
+
+	updated := models.Journal{}
+
+	err := j.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		journal, err := j.Get(ctx, models.PrimaryKey{
+			ID: request.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if !canTransition(journalTransitions, journal.Status, request.ToStatus) {
+			return fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, journal.Status, request.ToStatus)
+		}
+
+		request.FromStatus = journal.Status
+
+		if err = j.storage.Journal().UpdateStatus(ctx, request); err != nil {
+			fmt.Println("error in service layer while updating journal status", err.Error())
+			return err
+		}
+
+		updated, err = j.Get(ctx, models.PrimaryKey{
+			ID: request.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, j.storage, "journal", updated.ID, config.AuditUpdate, journal, updated)
+	})
+	if err != nil {
+		return models.Journal{}, err
+	}
+
+	return updated, nil
+}
+
+// Classify sets the category, the tags and the specialties of the article, it does not make a new version
+func (j journalService) Classify(ctx context.Context, request models.ClassifyJournal) (models.Journal, error) {
+
+	journal := models.Journal{}
+
+	err := j.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		var err error
+
+		request.Tags, request.DoctorTypeIDs, err = j.checkLinks(ctx, request.CategoryID, request.Tags, request.DoctorTypeIDs)
+		if err != nil {
+			return err
+		}
+
+		before, err := j.Get(ctx, models.PrimaryKey{
+			ID: request.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err = j.storage.Journal().Classify(ctx, request); err != nil {
+			if !errors.Is(err, pgx.ErrNoRows) {
+				fmt.Println("error in service layer while classifying journal", err.Error())
+			}
+			return err
+		}
+
+		journal, err = j.Get(ctx, models.PrimaryKey{
+			ID: request.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, j.storage, "journal", journal.ID, config.AuditUpdate, before, journal)
+	})
+	if err != nil {
 		return models.Journal{}, err
 	}
 
 	return journal, nil
 }
 
-func (j journalService) Delete(ctx context.Context, id string) error {
+func (j journalService) GetTags(ctx context.Context) (models.JournalTagsResponse, error) {
 
-	err := j.storage.Journal().Delete(ctx, id)
+	tags, err := j.storage.Journal().GetTags(ctx)
+	if err != nil {
+		fmt.Println("error in service layer while getting journal tags", err.Error())
+		return models.JournalTagsResponse{}, err
+	}
+
+	return tags, nil
+}
+
+// checkLinks makes tags lowercase words joined by "-" without repeats and checks the category and
+// the doctor types exist
+func (j journalService) checkLinks(ctx context.Context, categoryID string, tags, doctorTypeIDs []string) ([]string, []string, error) {
+
+	if categoryID != "" {
+		if _, err := j.storage.JournalCategory().Get(ctx, models.PrimaryKey{ID: categoryID}); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, nil, fmt.Errorf("%w: category %s not found", ErrInvalidJournal, categoryID)
+			}
+			return nil, nil, err
+		}
+	}
 
-	return err
+	var (
+		normalTags = []string{}
+		typeIDs    = []string{}
+		seen       = map[string]bool{}
+	)
+
+	for _, tag := range tags {
+		normal := slug.Make(tag)
+		if normal == "" || len(normal) > 50 {
+			return nil, nil, fmt.Errorf("%w: tag %q should have letters or digits and be at most 50 characters", ErrInvalidJournal, tag)
+		}
+
+		if !seen[normal] {
+			seen[normal] = true
+			normalTags = append(normalTags, normal)
+		}
+	}
+
+	if len(normalTags) > maxJournalTags {
+		return nil, nil, fmt.Errorf("%w: journal can have at most %d tags", ErrInvalidJournal, maxJournalTags)
+	}
+
+	for _, id := range doctorTypeIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if _, err := j.storage.DoctorType().Get(ctx, models.PrimaryKey{ID: id}); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, nil, fmt.Errorf("%w: doctor type %s not found", ErrInvalidJournal, id)
+			}
+			return nil, nil, err
+		}
+
+		typeIDs = append(typeIDs, id)
+	}
+
+	return normalTags, typeIDs, nil
+}
+
+func (j journalService) GetRevisions(ctx context.Context, journalID string) (models.JournalRevisionsResponse, error) {
+
+	revisions, err := j.storage.Journal().GetRevisions(ctx, journalID)
+	if err != nil {
+		fmt.Println("error in service layer while getting journal revisions", err.Error())
+		return models.JournalRevisionsResponse{}, err
+	}
+
+	return revisions, nil
+}
+
+func (j journalService) GetRevision(ctx context.Context, journalID string, version int) (models.JournalRevision, error) {
+
+	revision, err := j.storage.Journal().GetRevision(ctx, journalID, version)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			fmt.Println("error in service layer while getting journal revision", err.Error())
+		}
+		return models.JournalRevision{}, err
+	}
+
+	return revision, nil
+}
+
+// Diff compares two versions of the article line by line. Without to the current version is used,
+// without from the version before to
+func (j journalService) Diff(ctx context.Context, journal models.Journal, from, to int) (models.JournalDiff, error) {
+
+	if to == 0 {
+		to = journal.Version
+	}
+
+	if from == 0 {
+		from = to - 1
+	}
+
+	if from < 1 || to < 1 {
+		return models.JournalDiff{}, fmt.Errorf("%w: version %d has nothing to be compared with", ErrInvalidJournal, to)
+	}
+
+	fromRevision, err := j.GetRevision(ctx, journal.ID, from)
+	if err != nil {
+		return models.JournalDiff{}, err
+	}
+
+	toRevision, err := j.GetRevision(ctx, journal.ID, to)
+	if err != nil {
+		return models.JournalDiff{}, err
+	}
+
+	return models.JournalDiff{
+		JournalID: journal.ID,
+		From:      from,
+		To:        to,
+		Theme:     diffLines(fromRevision.Theme, toRevision.Theme),
+		Article:   diffLines(fromRevision.Article, toRevision.Article),
+	}, nil
+}
+
+// Rollback saves the text of an older version as the next version of the draft, history is never rewritten
+func (j journalService) Rollback(ctx context.Context, id string, version int, editorID string) (models.Journal, error) {
+
+	revision, err := j.GetRevision(ctx, id, version)
+	if err != nil {
+		return models.Journal{}, err
+	}
+
+	return j.Update(ctx, models.UpdateJournal{
+		ID:       id,
+		Theme:    revision.Theme,
+		Article:  revision.Article,
+		Language: revision.Language,
+		EditorID: editorID,
+	})
+}
+
+func validateJournalText(theme, article, journalSlug string) error {
+	switch {
+	case strings.TrimSpace(theme) == "" || strings.TrimSpace(article) == "":
+		return fmt.Errorf("%w: theme and article are required", ErrInvalidJournal)
+	case len([]rune(theme)) > 150:
+		return fmt.Errorf("%w: theme should be at most 150 characters", ErrInvalidJournal)
+	case journalSlug != "" && !slug.Valid(journalSlug):
+		return fmt.Errorf("%w: slug should be lowercase latin letters and digits joined by -", ErrInvalidJournal)
+	}
+
+	return nil
+}
+
+func isSlugTaken(err error) bool {
+	pgErr := &pgconn.PgError{}
+
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "journal_slug_idx"
+}
+
+func diffLines(from, to string) []models.DiffLine {
+
+	lines := []models.DiffLine{}
+	for _, line := range textdiff.Lines(from, to) {
+		lines = append(lines, models.DiffLine{
+			Op:   line.Op,
+			Text: line.Text,
+		})
+	}
+
+	return lines
+}
+
+// Restore brings back the soft deleted journal
+func (j journalService) Restore(ctx context.Context, id string) (models.Journal, error) {
+
+	journal := models.Journal{}
+
+	err := j.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		err := j.storage.Journal().Restore(ctx, id)
+		if err != nil {
+			fmt.Println("error in service layer while restoring journal", err.Error())
+			return err
+		}
+
+		journal, err = j.storage.Journal().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			fmt.Println("error in service layer getting journal after restore", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, j.storage, "journal", id, config.AuditRestore, nil, journal)
+	})
+	if err != nil {
+		return models.Journal{}, err
+	}
+
+	return journal, nil
 }
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"shifolink/api/models"
+	"shifolink/config"
+	"shifolink/storage"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type journalCategoryService struct {
+	storage storage.IStorage
+}
+
+func NewJournalCategoryService(storage storage.IStorage) journalCategoryService {
+	return journalCategoryService{
+		storage: storage,
+	}
+}
+
+func (j journalCategoryService) Create(ctx context.Context, createCategory models.CreateJournalCategory) (models.JournalCategory, error) {
+
+	category := models.JournalCategory{}
+
+	err := j.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		if createCategory.Name = strings.TrimSpace(createCategory.Name); createCategory.Name == "" {
+			return fmt.Errorf("%w: name should not be empty", ErrInvalidData)
+		}
+
+		pKey, err := j.storage.JournalCategory().Create(ctx, createCategory)
+		if err != nil {
+			log.Println("error in service layer while creating journal category", err.Error())
+			return err
+		}
+
+		category, err = j.storage.JournalCategory().Get(ctx, models.PrimaryKey{
+			ID: pKey,
+		})
+		if err != nil {
+			log.Println("error in service layer get journal category by id", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, j.storage, "journal_category", category.ID, config.AuditCreate, nil, category)
+	})
+	if err != nil {
+		return models.JournalCategory{}, err
+	}
+
+	return category, nil
+}
+
+func (j journalCategoryService) Get(ctx context.Context, pkey models.PrimaryKey) (models.JournalCategory, error) {
+
+	category, err := j.storage.JournalCategory().Get(ctx, pkey)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			fmt.Println("error in service layer while getting journal category by id", err.Error())
+		}
+		return models.JournalCategory{}, err
+	}
+
+	return category, nil
+}
+
+func (j journalCategoryService) GetList(ctx context.Context, request models.GetListRequest) (models.JournalCategoriesResponse, error) {
+
+	categories, err := j.storage.JournalCategory().GetList(ctx, request)
+	if err != nil {
+		fmt.Println("error in service layer while getting journal categories list", err.Error())
+		return models.JournalCategoriesResponse{}, err
+	}
+
+	return categories, nil
+}
+
+func (j journalCategoryService) Update(ctx context.Context, updateCategory models.UpdateJournalCategory) (models.JournalCategory, error) {
+
+	category := models.JournalCategory{}
+
+	err := j.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := j.storage.JournalCategory().Get(ctx, models.PrimaryKey{ID: updateCategory.ID})
+		if err != nil {
+			return err
+		}
+
+		if updateCategory.Name = strings.TrimSpace(updateCategory.Name); updateCategory.Name == "" {
+			return fmt.Errorf("%w: name should not be empty", ErrInvalidData)
+		}
+
+		id, err := j.storage.JournalCategory().Update(ctx, updateCategory)
+		if err != nil {
+			fmt.Println("error in service layer updating journal category by id", err.Error())
+			return err
+		}
+
+		category, err = j.storage.JournalCategory().Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			fmt.Println("error in service layer getting journal category after update", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, j.storage, "journal_category", category.ID, config.AuditUpdate, before, category)
+	})
+	if err != nil {
+		return models.JournalCategory{}, err
+	}
+
+	return category, nil
+}
+
+func (j journalCategoryService) Delete(ctx context.Context, id string) error {
+
+	return j.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := j.storage.JournalCategory().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			return err
+		}
+
+		if err = j.storage.JournalCategory().Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, j.storage, "journal_category", id, config.AuditDelete, before, nil)
+	})
+}
+
+// Restore brings back the soft deleted journal category
+func (j journalCategoryService) Restore(ctx context.Context, id string) (models.JournalCategory, error) {
+
+	journalCategory := models.JournalCategory{}
+
+	err := j.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		err := j.storage.JournalCategory().Restore(ctx, id)
+		if err != nil {
+			fmt.Println("error in service layer while restoring journal category", err.Error())
+			return err
+		}
+
+		journalCategory, err = j.storage.JournalCategory().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			fmt.Println("error in service layer getting journal category after restore", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, j.storage, "journal_category", id, config.AuditRestore, nil, journalCategory)
+	})
+	if err != nil {
+		return models.JournalCategory{}, err
+	}
+
+	return journalCategory, nil
+}
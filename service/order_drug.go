@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"shifolink/api/models"
+	"shifolink/config"
 	"shifolink/storage"
 
 	"github.com/jackc/pgx/v5"
@@ -23,17 +24,36 @@ func NewOrderDrugService(storage storage.IStorage) orderDrugService {
 
 func (o orderDrugService) Create(ctx context.Context, createOrderDrug models.CreateOrderDrug) (models.OrderDrug, error) {
 
-	pKey, err := o.storage.OrderDrug().Create(ctx, createOrderDrug)
-	if err != nil {
-		log.Println("error in service layer while creating order drug ", err.Error())
-		return models.OrderDrug{}, err
-	}
+	orderDrug := models.OrderDrug{}
+
+	err := o.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		if err := checkReference(ctx, "drug_id", createOrderDrug.DrugID, o.storage.Drug().Get); err != nil {
+			return err
+		}
+
+		if err := checkReference(ctx, "orders_id", createOrderDrug.OrdersID, o.storage.Orders().Get); err != nil {
+			return err
+		}
+
+		pKey, err := o.storage.OrderDrug().Create(ctx, createOrderDrug)
+		if err != nil {
+			log.Println("error in service layer while creating order drug ", err.Error())
+			return err
+		}
+
+		orderDrug, err = o.storage.OrderDrug().Get(ctx, models.PrimaryKey{
+			ID: pKey,
+		})
+		if err != nil {
+			log.Println("error in service layer get order drug by id")
+			return err
+		}
 
-	orderDrug, err := o.storage.OrderDrug().Get(ctx, models.PrimaryKey{
-		ID: pKey,
+		return recordAudit(ctx, o.storage, "order_drug", orderDrug.ID, config.AuditCreate, nil, orderDrug)
 	})
 	if err != nil {
-		log.Println("error in service layer get order drug by id")
+		return models.OrderDrug{}, err
 	}
 
 	return orderDrug, nil
@@ -45,14 +65,14 @@ func (o orderDrugService) Get(ctx context.Context, pkey models.PrimaryKey) (mode
 	if err != nil {
 		if !errors.Is(err, pgx.ErrNoRows) {
 			fmt.Println("error in service layer while getting order drug by id", err.Error())
-			return models.OrderDrug{}, err
 		}
+		return models.OrderDrug{}, err
 	}
 
 	return orderDrug, nil
 }
 
-func (o orderDrugService) GetList(ctx context.Context, request models.GetListRequest) (models.OrderDrugsResponse, error) {
+func (o orderDrugService) GetList(ctx context.Context, request models.GetOrderDrugsListRequest) (models.OrderDrugsResponse, error) {
 
 	orderDrug, err := o.storage.OrderDrug().GetList(ctx, request)
 	if err != nil {
@@ -66,17 +86,40 @@ func (o orderDrugService) GetList(ctx context.Context, request models.GetListReq
 
 func (o orderDrugService) Update(ctx context.Context, updateOrderDrug models.UpdateOrderDrug) (models.OrderDrug, error) {
 
-	id, err := o.storage.OrderDrug().Update(ctx, updateOrderDrug)
-	if err != nil {
-		fmt.Println("error in servise layer updating order drug  by id", err.Error())
-		return models.OrderDrug{}, err
-	}
+	orderDrug := models.OrderDrug{}
+
+	err := o.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := o.storage.OrderDrug().Get(ctx, models.PrimaryKey{ID: updateOrderDrug.ID})
+		if err != nil {
+			return err
+		}
+
+		if err := checkReference(ctx, "drug_id", updateOrderDrug.DrugID, o.storage.Drug().Get); err != nil {
+			return err
+		}
+
+		if err := checkReference(ctx, "orders_id", updateOrderDrug.OrdersID, o.storage.Orders().Get); err != nil {
+			return err
+		}
 
-	orderDrug, err := o.storage.OrderDrug().Get(ctx, models.PrimaryKey{
-		ID: id,
+		id, err := o.storage.OrderDrug().Update(ctx, updateOrderDrug)
+		if err != nil {
+			fmt.Println("error in servise layer updating order drug  by id", err.Error())
+			return err
+		}
+
+		orderDrug, err = o.storage.OrderDrug().Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			fmt.Println("error in service layer getting order drug after update", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, o.storage, "order_drug", orderDrug.ID, config.AuditUpdate, before, orderDrug)
 	})
 	if err != nil {
-		fmt.Println("error in service layer getting order drug after update", err.Error())
 		return models.OrderDrug{}, err
 	}
 
@@ -85,7 +128,45 @@ func (o orderDrugService) Update(ctx context.Context, updateOrderDrug models.Upd
 
 func (o orderDrugService) Delete(ctx context.Context, id string) error {
 
-	err := o.storage.OrderDrug().Delete(ctx, id)
+	return o.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := o.storage.OrderDrug().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			return err
+		}
+
+		if err = o.storage.OrderDrug().Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, o.storage, "order_drug", id, config.AuditDelete, before, nil)
+	})
+}
+
+// Restore brings back the soft deleted order drug
+func (o orderDrugService) Restore(ctx context.Context, id string) (models.OrderDrug, error) {
 
-	return err
+	orderDrug := models.OrderDrug{}
+
+	err := o.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		err := o.storage.OrderDrug().Restore(ctx, id)
+		if err != nil {
+			fmt.Println("error in service layer while restoring order drug", err.Error())
+			return err
+		}
+
+		orderDrug, err = o.storage.OrderDrug().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			fmt.Println("error in service layer getting order drug after restore", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, o.storage, "order_drug", id, config.AuditRestore, nil, orderDrug)
+	})
+	if err != nil {
+		return models.OrderDrug{}, err
+	}
+
+	return orderDrug, nil
 }
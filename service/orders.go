@@ -6,34 +6,72 @@ import (
 	"fmt"
 	"log"
 	"shifolink/api/models"
+	"shifolink/config"
+	"shifolink/pkg/errs"
+	"shifolink/pkg/metrics"
+	"shifolink/pkg/money"
 	"shifolink/storage"
 
 	"github.com/jackc/pgx/v5"
 )
 
+var ErrInvalidCheckout = errs.Validation("checkout request is not valid")
+
+// orderTransitions lists statuses an order can move to from its current status
+var orderTransitions = map[string][]string{
+	config.OrderPending:        {config.OrderConfirmed, config.OrderCancelled},
+	config.OrderConfirmed:      {config.OrderPreparing, config.OrderCancelled},
+	config.OrderPreparing:      {config.OrderReadyForPickup, config.OrderCancelled},
+	config.OrderReadyForPickup: {config.OrderDelivered, config.OrderCancelled},
+	config.OrderDelivered:      {config.OrderRefunded},
+}
+
 type ordersService struct {
-	storage storage.IStorage
+	storage  storage.IStorage
+	currency string
 }
 
-func NewOrdersService(storage storage.IStorage) ordersService {
+func NewOrdersService(storage storage.IStorage, currency string) ordersService {
 	return ordersService{
-		storage: storage,
+		storage:  storage,
+		currency: currency,
 	}
 }
 
 func (o ordersService) Create(ctx context.Context, createOrders models.CreateOrders) (models.Orders, error) {
 
-	pKey, err := o.storage.Orders().Create(ctx, createOrders)
-	if err != nil {
-		log.Println("error in service layer while creating orders ", err.Error())
-		return models.Orders{}, err
-	}
+	orders := models.Orders{}
+
+	err := o.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		if err := checkReference(ctx, "customer_id", createOrders.CustomerID, o.storage.Customer().Get); err != nil {
+			return err
+		}
+
+		if createOrders.PharmacistID != "" {
+			if err := checkReference(ctx, "pharmacist_id", createOrders.PharmacistID, o.storage.Pharmacist().Get); err != nil {
+				return err
+			}
+		}
+
+		pKey, err := o.storage.Orders().Create(ctx, createOrders)
+		if err != nil {
+			log.Println("error in service layer while creating orders ", err.Error())
+			return err
+		}
+
+		orders, err = o.storage.Orders().Get(ctx, models.PrimaryKey{
+			ID: pKey,
+		})
+		if err != nil {
+			log.Println("error in service layer get orders by id")
+			return err
+		}
 
-	orders, err := o.storage.Orders().Get(ctx, models.PrimaryKey{
-		ID: pKey,
+		return recordAudit(ctx, o.storage, "orders", orders.ID, config.AuditCreate, nil, orders)
 	})
 	if err != nil {
-		log.Println("error in service layer get orders by id")
+		return models.Orders{}, err
 	}
 
 	return orders, nil
@@ -45,14 +83,19 @@ func (o ordersService) Get(ctx context.Context, pkey models.PrimaryKey) (models.
 	if err != nil {
 		if !errors.Is(err, pgx.ErrNoRows) {
 			fmt.Println("error in service layer while getting orders by id", err.Error())
-			return models.Orders{}, err
 		}
+		return models.Orders{}, err
+	}
+
+	if orders.Lines, err = o.storage.OrderDrug().GetByOrder(ctx, orders.ID); err != nil {
+		fmt.Println("error in service layer while getting order lines", err.Error())
+		return models.Orders{}, err
 	}
 
 	return orders, nil
 }
 
-func (o ordersService) GetList(ctx context.Context, request models.GetListRequest) (models.OrdersResponse, error) {
+func (o ordersService) GetList(ctx context.Context, request models.GetOrdersListRequest) (models.OrdersResponse, error) {
 
 	orders, err := o.storage.Orders().GetList(ctx, request)
 	if err != nil {
@@ -66,26 +109,246 @@ func (o ordersService) GetList(ctx context.Context, request models.GetListReques
 
 func (o ordersService) Update(ctx context.Context, updateOrders models.UpdateOrders) (models.Orders, error) {
 
-	id, err := o.storage.Orders().Update(ctx, updateOrders)
+	orders := models.Orders{}
+
+	err := o.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := o.storage.Orders().Get(ctx, models.PrimaryKey{ID: updateOrders.ID})
+		if err != nil {
+			return err
+		}
+
+		if err := checkReference(ctx, "customer_id", updateOrders.CustomerID, o.storage.Customer().Get); err != nil {
+			return err
+		}
+
+		if updateOrders.PharmacistID != "" {
+			if err := checkReference(ctx, "pharmacist_id", updateOrders.PharmacistID, o.storage.Pharmacist().Get); err != nil {
+				return err
+			}
+		}
+
+		id, err := o.storage.Orders().Update(ctx, updateOrders)
+		if err != nil {
+			fmt.Println("error in servise layer updating orders  by id", err.Error())
+			return err
+		}
+
+		orders, err = o.storage.Orders().Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			fmt.Println("error in service layer getting orders after update", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, o.storage, "orders", orders.ID, config.AuditUpdate, before, orders)
+	})
 	if err != nil {
-		fmt.Println("error in servise layer updating orders  by id", err.Error())
 		return models.Orders{}, err
 	}
 
-	orders, err := o.storage.Orders().Get(ctx, models.PrimaryKey{
-		ID: id,
+	return orders, nil
+}
+
+func (o ordersService) Delete(ctx context.Context, id string) error {
+
+	return o.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := o.storage.Orders().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			return err
+		}
+
+		if err = o.storage.Orders().Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, o.storage, "orders", id, config.AuditDelete, before, nil)
+	})
+}
+
+// Checkout places the order and takes its drugs from stock at once, lines of the same drug are merged.
+// Prices are taken from the drugs now and kept on the lines, the repository checks they did not change meanwhile
+func (o ordersService) Checkout(ctx context.Context, checkout models.CheckoutOrder) (models.Orders, error) {
+
+	orders := models.Orders{}
+
+	err := o.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		if checkout.CustomerID == "" || checkout.DrugStoreBranchID == "" {
+			return fmt.Errorf("%w: customer_id and drug_store_branch_id are required", ErrInvalidCheckout)
+		}
+
+		if len(checkout.Items) == 0 {
+			return fmt.Errorf("%w: order should have at least one item", ErrInvalidCheckout)
+		}
+
+		items := []models.CheckoutItem{}
+		positions := map[string]int{}
+
+		for _, item := range checkout.Items {
+			if item.Quantity <= 0 {
+				return fmt.Errorf("%w: quantity of drug %s should be positive", ErrInvalidCheckout, item.DrugID)
+			}
+
+			if i, ok := positions[item.DrugID]; ok {
+				items[i].Quantity += item.Quantity
+				continue
+			}
+
+			positions[item.DrugID] = len(items)
+			items = append(items, item)
+		}
+
+		discount, err := money.Parse(checkout.Discount)
+		if err != nil || discount < 0 {
+			return fmt.Errorf("%w: discount should be a non negative amount", ErrInvalidCheckout)
+		}
+
+		subtotal := int64(0)
+
+		for i, item := range items {
+			drug, err := o.storage.Drug().Get(ctx, models.PrimaryKey{
+				ID: item.DrugID,
+			})
+			if err != nil {
+				// an unknown drug is reported together with other rejected lines by the repository
+				if errors.Is(err, pgx.ErrNoRows) {
+					continue
+				}
+				fmt.Println("error in service layer while getting drug by id", err.Error())
+				return err
+			}
+
+			unitPrice, err := money.Parse(drug.Price)
+			if err != nil {
+				return err
+			}
+
+			lineTotal := unitPrice * int64(item.Quantity)
+			subtotal += lineTotal
+
+			items[i].UnitPrice = money.Format(unitPrice)
+			items[i].LineTotal = money.Format(lineTotal)
+		}
+
+		if discount > subtotal {
+			return fmt.Errorf("%w: discount is bigger than the subtotal", ErrInvalidCheckout)
+		}
+
+		checkout.Items = items
+		checkout.Subtotal = money.Format(subtotal)
+		checkout.Discount = money.Format(discount)
+		checkout.Total = money.Format(subtotal - discount)
+		checkout.Currency = o.currency
+
+		id, err := o.storage.Orders().Checkout(ctx, checkout)
+		if err != nil {
+			log.Println("error in service layer while checking out order ", err.Error())
+			return err
+		}
+
+		orders, err = o.Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err = recordAudit(ctx, o.storage, "orders", orders.ID, config.AuditCreate, nil, orders); err != nil {
+			return err
+		}
+
+		return recordStockMoves(ctx, o.storage, orders.ID, false)
 	})
 	if err != nil {
-		fmt.Println("error in service layer getting orders after update", err.Error())
 		return models.Orders{}, err
 	}
 
+	metrics.OrdersPlaced.Inc()
+
 	return orders, nil
 }
 
-func (o ordersService) Delete(ctx context.Context, id string) error {
+// ChangeStatus moves the order to the given status, drugs of a cancelled order go back to stock and the lots
+// they return to are logged
+func (o ordersService) ChangeStatus(ctx context.Context, id, status string) (models.Orders, error) {
 
-	err := o.storage.Orders().Delete(ctx, id)
+	updated := models.Orders{}
 
-	return err
+	err := o.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		orders, err := o.storage.Orders().Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			fmt.Println("error in service layer while getting orders by id", err.Error())
+			return err
+		}
+
+		if !canTransition(orderTransitions, orders.Status, status) {
+			return fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, orders.Status, status)
+		}
+
+		if err = o.storage.Orders().UpdateStatus(ctx, models.UpdateOrderStatus{
+			ID:           id,
+			FromStatus:   orders.Status,
+			ToStatus:     status,
+			RestoreStock: status == config.OrderCancelled,
+		}); err != nil {
+			fmt.Println("error in service layer while updating order status", err.Error())
+			return err
+		}
+
+		updated, err = o.Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err = recordAudit(ctx, o.storage, "orders", updated.ID, config.AuditUpdate, orders, updated); err != nil {
+			return err
+		}
+
+		if status != config.OrderCancelled {
+			return nil
+		}
+
+		return recordStockMoves(ctx, o.storage, updated.ID, true)
+	})
+	if err != nil {
+		return models.Orders{}, err
+	}
+
+	return updated, nil
+}
+
+// Restore brings back the soft deleted orders
+func (o ordersService) Restore(ctx context.Context, id string) (models.Orders, error) {
+
+	orders := models.Orders{}
+
+	err := o.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		err := o.storage.Orders().Restore(ctx, id)
+		if err != nil {
+			fmt.Println("error in service layer while restoring orders", err.Error())
+			return err
+		}
+
+		orders, err = o.storage.Orders().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			fmt.Println("error in service layer getting orders after restore", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, o.storage, "orders", id, config.AuditRestore, nil, orders)
+	})
+	if err != nil {
+		return models.Orders{}, err
+	}
+
+	return orders, nil
 }
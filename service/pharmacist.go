@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"log"
 	"shifolink/api/models"
+	"shifolink/config"
 	"shifolink/pkg/check"
+	"shifolink/pkg/security"
 	"shifolink/storage"
 
 	"github.com/jackc/pgx/v5"
@@ -24,17 +26,38 @@ func NewPharmacistService(storage storage.IStorage) pharmacistService {
 
 func (p pharmacistService) Create(ctx context.Context, createPharmacist models.CreatePharmacist) (models.Pharmacist, error) {
 
-	pKey, err := p.storage.Pharmacist().Create(ctx, createPharmacist)
-	if err != nil {
-		log.Println("error in service layer while creating pharmacist ", err.Error())
-		return models.Pharmacist{}, err
-	}
+	pharmacist := models.Pharmacist{}
+
+	err := p.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		age, err := checkAccount(createPharmacist.Password, createPharmacist.BirthDate)
+		if err != nil {
+			return err
+		}
+		createPharmacist.Age = age
+
+		if err := checkReference(ctx, "drug_store_branch_id", createPharmacist.DrugStoreBranchID, p.storage.DrugStoreBranch().Get); err != nil {
+			return err
+		}
+
+		pKey, err := p.storage.Pharmacist().Create(ctx, createPharmacist)
+		if err != nil {
+			log.Println("error in service layer while creating pharmacist ", err.Error())
+			return err
+		}
 
-	pharmacist, err := p.storage.Pharmacist().Get(ctx, models.PrimaryKey{
-		ID: pKey,
+		pharmacist, err = p.storage.Pharmacist().Get(ctx, models.PrimaryKey{
+			ID: pKey,
+		})
+		if err != nil {
+			log.Println("error in service layer get pharmacist by id")
+			return err
+		}
+
+		return recordAudit(ctx, p.storage, "pharmacist", pharmacist.ID, config.AuditCreate, nil, pharmacist)
 	})
 	if err != nil {
-		log.Println("error in service layer get pharmacist by id")
+		return models.Pharmacist{}, err
 	}
 
 	return pharmacist, nil
@@ -46,14 +69,14 @@ func (p pharmacistService) Get(ctx context.Context, pkey models.PrimaryKey) (mod
 	if err != nil {
 		if !errors.Is(err, pgx.ErrNoRows) {
 			fmt.Println("error in service layer while getting pharmacist by id", err.Error())
-			return models.Pharmacist{}, err
 		}
+		return models.Pharmacist{}, err
 	}
 
 	return pharmacist, nil
 }
 
-func (p pharmacistService) GetList(ctx context.Context, request models.GetListRequest) (models.PharmacistsResponse, error) {
+func (p pharmacistService) GetList(ctx context.Context, request models.GetPharmacistsListRequest) (models.PharmacistsResponse, error) {
 
 	customers, err := p.storage.Pharmacist().GetList(ctx, request)
 	if err != nil {
@@ -67,17 +90,36 @@ func (p pharmacistService) GetList(ctx context.Context, request models.GetListRe
 
 func (p pharmacistService) Update(ctx context.Context, updatePharmacist models.UpdatePharmacist) (models.Pharmacist, error) {
 
-	id, err := p.storage.Pharmacist().Update(ctx, updatePharmacist)
-	if err != nil {
-		fmt.Println("error in servise layer updating pharmacist by id", err.Error())
-		return models.Pharmacist{}, err
-	}
+	pharmacist := models.Pharmacist{}
+
+	err := p.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := p.storage.Pharmacist().Get(ctx, models.PrimaryKey{ID: updatePharmacist.ID})
+		if err != nil {
+			return err
+		}
+
+		if err := checkReference(ctx, "drug_store_branch_id", updatePharmacist.DrugStoreBranchID, p.storage.DrugStoreBranch().Get); err != nil {
+			return err
+		}
+
+		id, err := p.storage.Pharmacist().Update(ctx, updatePharmacist)
+		if err != nil {
+			fmt.Println("error in servise layer updating pharmacist by id", err.Error())
+			return err
+		}
 
-	pharmacist, err := p.storage.Pharmacist().Get(ctx, models.PrimaryKey{
-		ID: id,
+		pharmacist, err = p.storage.Pharmacist().Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			fmt.Println("error in service layer getting pharmacist after update", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, p.storage, "pharmacist", pharmacist.ID, config.AuditUpdate, before, pharmacist)
 	})
 	if err != nil {
-		fmt.Println("error in service layer getting pharmacist after update", err.Error())
 		return models.Pharmacist{}, err
 	}
 
@@ -86,33 +128,74 @@ func (p pharmacistService) Update(ctx context.Context, updatePharmacist models.U
 
 func (p pharmacistService) Delete(ctx context.Context, id string) error {
 
-	err := p.storage.Pharmacist().Delete(ctx, id)
+	return p.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := p.storage.Pharmacist().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			return err
+		}
 
-	return err
+		if err = p.storage.Pharmacist().Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, p.storage, "pharmacist", id, config.AuditDelete, before, nil)
+	})
 }
 
 func (p pharmacistService) UpdatePassword(ctx context.Context, request models.UpdatePharmacistPassword) error {
 
-	oldPassword, err := p.storage.Pharmacist().GetPassword(ctx, request.ID)
-	if err != nil {
-		fmt.Println("error in service layer getting password by id", err.Error())
-		return err
-	}
+	return p.storage.Transaction(ctx, func(ctx context.Context) error {
 
-	if oldPassword != request.OldPassword {
-		fmt.Println("error in service layer old password is not correct")
-		return errors.New("old password did not match")
-	}
+		oldPassword, err := p.storage.Pharmacist().GetPassword(ctx, request.ID)
+		if err != nil {
+			fmt.Println("error in service layer getting password by id", err.Error())
+			return err
+		}
 
-	if err = check.ValidatePassword(request.NewPassword); err != nil {
-		fmt.Println("error in service layer new password validation failed", err.Error())
-		return err
-	}
+		if !security.CompareHashAndPassword(oldPassword, request.OldPassword) {
+			fmt.Println("error in service layer old password is not correct")
+			return ErrPasswordMismatch.WithField("old_password", "is not correct")
+		}
+
+		if err = check.ValidatePassword(request.NewPassword); err != nil {
+			fmt.Println("error in service layer new password validation failed", err.Error())
+			return ErrInvalidData.WithField("new_password", err.Error())
+		}
 
-	if err = p.storage.Pharmacist().UpdatePassword(context.Background(), request); err != nil {
-		fmt.Println("error in service layer while updating pharmacist password ", err.Error())
-		return err
+		if err = p.storage.Pharmacist().UpdatePassword(ctx, request); err != nil {
+			fmt.Println("error in service layer while updating pharmacist password ", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, p.storage, "pharmacist", request.ID, config.AuditUpdate, nil, passwordChanged)
+	})
+}
+
+// Restore brings back the soft deleted pharmacist
+func (p pharmacistService) Restore(ctx context.Context, id string) (models.Pharmacist, error) {
+
+	pharmacist := models.Pharmacist{}
+
+	err := p.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		err := p.storage.Pharmacist().Restore(ctx, id)
+		if err != nil {
+			fmt.Println("error in service layer while restoring pharmacist", err.Error())
+			return err
+		}
+
+		pharmacist, err = p.storage.Pharmacist().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			fmt.Println("error in service layer getting pharmacist after restore", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, p.storage, "pharmacist", id, config.AuditRestore, nil, pharmacist)
+	})
+	if err != nil {
+		return models.Pharmacist{}, err
 	}
 
-	return nil
+	return pharmacist, nil
 }
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"shifolink/api/models"
+	"shifolink/config"
+	"shifolink/pkg/errs"
+	"shifolink/pkg/metrics"
+	"shifolink/storage"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var ErrInvalidPrescription = errs.Validation("prescription is not valid")
+
+const (
+	// defaultPrescriptionValidity is the number of days a prescription can be redeemed when the doctor gives no date
+	defaultPrescriptionValidity = 30
+
+	prescriptionCodeLength = 8
+	// prescriptionCodeAlphabet leaves out characters which are easy to misread when the code is dictated
+	prescriptionCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	// prescriptionCodeAttempts is how many codes are tried before giving up on a unique one
+	prescriptionCodeAttempts = 5
+)
+
+type prescriptionService struct {
+	storage       storage.IStorage
+	ordersService ordersService
+}
+
+func NewPrescriptionService(storage storage.IStorage, ordersService ordersService) prescriptionService {
+	return prescriptionService{
+		storage:       storage,
+		ordersService: ordersService,
+	}
+}
+
+// Create issues a prescription for the customer of a visit the doctor has started or finished
+func (p prescriptionService) Create(ctx context.Context, createPrescription models.CreatePrescription) (models.Prescription, error) {
+
+	prescription := models.Prescription{}
+
+	err := p.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		if len(createPrescription.Items) == 0 {
+			return fmt.Errorf("%w: prescription should have at least one item", ErrInvalidPrescription)
+		}
+
+		for i, item := range createPrescription.Items {
+			drug, err := p.storage.DrugCatalogue().Get(ctx, models.PrimaryKey{ID: item.DrugCatalogueID})
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("%w: drug %s is not in the catalogue", ErrInvalidPrescription, item.DrugCatalogueID)
+			}
+			if err != nil {
+				return err
+			}
+
+			item.DrugName = drug.Name
+			createPrescription.Items[i].DrugName = drug.Name
+
+			switch {
+			case item.Quantity <= 0 || item.DurationDays <= 0:
+				return fmt.Errorf("%w: quantity and duration_days of %s should be positive", ErrInvalidPrescription, item.DrugName)
+			case item.Refills < 0:
+				return fmt.Errorf("%w: refills of %s can not be negative", ErrInvalidPrescription, item.DrugName)
+			}
+		}
+
+		today := time.Now().Truncate(24 * time.Hour)
+
+		if createPrescription.ValidUntil == "" {
+			createPrescription.ValidUntil = today.AddDate(0, 0, defaultPrescriptionValidity).Format("2006-01-02")
+		} else {
+			validUntil, err := time.Parse("2006-01-02", createPrescription.ValidUntil)
+			if err != nil || validUntil.Before(today) {
+				return fmt.Errorf("%w: valid_until should be a date (YYYY-MM-DD) not in the past", ErrInvalidPrescription)
+			}
+		}
+
+		queue, err := p.storage.Queue().Get(ctx, models.PrimaryKey{
+			ID: createPrescription.QueueID,
+		})
+		if err != nil {
+			log.Println("error in service layer while getting queue for prescription", err.Error())
+			return err
+		}
+
+		if queue.DoctorID != createPrescription.DoctorID {
+			return fmt.Errorf("%w: queue belongs to another doctor", ErrInvalidPrescription)
+		}
+
+		if queue.Status != config.QueueInConsultation && queue.Status != config.QueueCompleted {
+			return fmt.Errorf("%w: prescription can be issued only during or after the visit", ErrInvalidPrescription)
+		}
+
+		createPrescription.CustomerID = queue.CustomerID
+
+		pKey := ""
+
+		for attempt := 0; attempt < prescriptionCodeAttempts; attempt++ {
+			if createPrescription.Code, err = newPrescriptionCode(); err != nil {
+				return err
+			}
+
+			pKey, err = p.storage.Prescription().Create(ctx, createPrescription)
+
+			pgErr := &pgconn.PgError{}
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "prescription_code_idx" {
+				continue
+			}
+			break
+		}
+		if err != nil {
+			log.Println("error in service layer while creating prescription", err.Error())
+			return err
+		}
+
+		prescription, err = p.storage.Prescription().Get(ctx, models.PrimaryKey{
+			ID: pKey,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, p.storage, "prescription", prescription.ID, config.AuditCreate, nil, prescription)
+	})
+	if err != nil {
+		return models.Prescription{}, err
+	}
+
+	metrics.PrescriptionsIssued.Inc()
+
+	return prescription, nil
+}
+
+func (p prescriptionService) Get(ctx context.Context, pKey models.PrimaryKey) (models.Prescription, error) {
+
+	prescription, err := p.storage.Prescription().Get(ctx, pKey)
+	if err != nil {
+		fmt.Println("error in service layer while getting prescription by id", err.Error())
+		return models.Prescription{}, err
+	}
+
+	return prescription, nil
+}
+
+func (p prescriptionService) GetByCode(ctx context.Context, code string) (models.Prescription, error) {
+
+	prescription, err := p.storage.Prescription().GetByCode(ctx, code)
+	if err != nil {
+		fmt.Println("error in service layer while getting prescription by code", err.Error())
+		return models.Prescription{}, err
+	}
+
+	return prescription, nil
+}
+
+func (p prescriptionService) GetByCustomer(ctx context.Context, customerID string) (models.PrescriptionsResponse, error) {
+
+	prescriptions, err := p.storage.Prescription().GetByCustomer(ctx, customerID)
+	if err != nil {
+		fmt.Println("error in service layer while getting customer prescriptions", err.Error())
+		return models.PrescriptionsResponse{}, err
+	}
+
+	return prescriptions, nil
+}
+
+func (p prescriptionService) Delete(ctx context.Context, id string) error {
+
+	return p.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := p.storage.Prescription().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			return err
+		}
+
+		if err = p.storage.Prescription().Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, p.storage, "prescription", id, config.AuditDelete, before, nil)
+	})
+}
+
+// Redeem sells drugs of the pharmacy for some or all items of the prescription as one order.
+// A redeemed item sells at most one fill, items left out can be redeemed later
+func (p prescriptionService) Redeem(ctx context.Context, redeem models.RedeemPrescription) (models.Orders, error) {
+
+	orders := models.Orders{}
+
+	err := p.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		if len(redeem.Items) == 0 {
+			return fmt.Errorf("%w: at least one item should be redeemed", ErrInvalidPrescription)
+		}
+
+		prescription, err := p.storage.Prescription().GetByCode(ctx, redeem.Code)
+		if err != nil {
+			log.Println("error in service layer while getting prescription by code", err.Error())
+			return err
+		}
+
+		if prescription.ValidUntil < time.Now().Format("2006-01-02") {
+			return fmt.Errorf("%w: prescription is expired", ErrInvalidPrescription)
+		}
+
+		prescribed := map[string]models.PrescriptionItem{}
+		for _, item := range prescription.Items {
+			prescribed[item.ID] = item
+		}
+
+		checkout := models.CheckoutOrder{
+			CustomerID:        prescription.CustomerID,
+			PharmacistID:      redeem.PharmacistID,
+			DrugStoreBranchID: redeem.DrugStoreBranchID,
+			PrescriptionID:    prescription.ID,
+		}
+
+		drugs, redeemedItems := map[string]bool{}, map[string]bool{}
+
+		for _, item := range redeem.Items {
+			prescribedItem, ok := prescribed[item.PrescriptionItemID]
+			if !ok {
+				return fmt.Errorf("%w: item %s is not in the prescription", ErrInvalidPrescription, item.PrescriptionItemID)
+			}
+
+			if drugs[item.DrugID] || redeemedItems[item.PrescriptionItemID] {
+				return fmt.Errorf("%w: drug %s or its prescription item is redeemed twice", ErrInvalidPrescription, item.DrugID)
+			}
+			drugs[item.DrugID], redeemedItems[item.PrescriptionItemID] = true, true
+
+			// by default a whole fill is sold, or what is left of the prescription when less than a fill is left
+			if item.Quantity == 0 {
+				item.Quantity = min(prescribedItem.Quantity,
+					prescribedItem.Quantity*(prescribedItem.Refills+1)-prescribedItem.DispensedQuantity)
+			}
+
+			checkout.Items = append(checkout.Items, models.CheckoutItem{
+				DrugID:             item.DrugID,
+				Quantity:           item.Quantity,
+				PrescriptionItemID: item.PrescriptionItemID,
+			})
+		}
+
+		orders, err = p.ordersService.Checkout(ctx, checkout)
+		if err != nil {
+			return err
+		}
+
+		// the order is logged by the checkout, the prescription logs its used fills
+		redeemed, err := p.storage.Prescription().Get(ctx, models.PrimaryKey{ID: prescription.ID})
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, p.storage, "prescription", prescription.ID, config.AuditUpdate, prescription, redeemed)
+	})
+	if err != nil {
+		return models.Orders{}, err
+	}
+
+	return orders, nil
+}
+
+// newPrescriptionCode returns a random code the customer can tell the pharmacist
+func newPrescriptionCode() (string, error) {
+
+	random := make([]byte, prescriptionCodeLength)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	code := make([]byte, prescriptionCodeLength)
+	for i, b := range random {
+		code[i] = prescriptionCodeAlphabet[int(b)%len(prescriptionCodeAlphabet)]
+	}
+
+	return string(code), nil
+}
@@ -6,36 +6,73 @@ import (
 	"fmt"
 	"log"
 	"shifolink/api/models"
+	"shifolink/config"
+	"shifolink/pkg/errs"
+	"shifolink/pkg/metrics"
+	"shifolink/pkg/pubsub"
 	"shifolink/storage"
 
 	"github.com/jackc/pgx/v5"
 )
 
+var ErrInvalidStatusTransition = errs.Conflict("status transition is not allowed")
+
+// queueTransitions lists statuses a queue can move to from its current status
+var queueTransitions = map[string][]string{
+	config.QueueBooked:         {config.QueueCheckedIn, config.QueueCancelled, config.QueueNoShow},
+	config.QueueCheckedIn:      {config.QueueCalled, config.QueueCancelled, config.QueueNoShow},
+	config.QueueCalled:         {config.QueueInConsultation, config.QueueNoShow},
+	config.QueueInConsultation: {config.QueueCompleted},
+}
+
 type queueService struct {
 	storage storage.IStorage
+	broker  *pubsub.Broker
 }
 
-func NewQueueService(storage storage.IStorage) queueService {
+func NewQueueService(storage storage.IStorage, broker *pubsub.Broker) queueService {
 	return queueService{
 		storage: storage,
+		broker:  broker,
 	}
 }
 
 func (q queueService) Create(ctx context.Context, createQueue models.CreateQueue) (models.Queue, error) {
 
-	pKey, err := q.storage.Queue().Create(ctx, createQueue)
-	if err != nil {
-		log.Println("error in service layer while creating queue ", err.Error())
-		return models.Queue{}, err
-	}
+	queue := models.Queue{}
+
+	err := q.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		if err := checkReference(ctx, "customer_id", createQueue.CustomerID, q.storage.Customer().Get); err != nil {
+			return err
+		}
+
+		if err := checkReference(ctx, "doctor_id", createQueue.DoctorID, q.storage.Doctor().Get); err != nil {
+			return err
+		}
+
+		pKey, err := q.storage.Queue().Create(ctx, createQueue)
+		if err != nil {
+			log.Println("error in service layer while creating queue ", err.Error())
+			return err
+		}
+
+		queue, err = q.storage.Queue().Get(ctx, models.PrimaryKey{
+			ID: pKey,
+		})
+		if err != nil {
+			log.Println("error in service layer get queue by id")
+			return err
+		}
 
-	queue, err := q.storage.Queue().Get(ctx, models.PrimaryKey{
-		ID: pKey,
+		return recordAudit(ctx, q.storage, "queue", queue.ID, config.AuditCreate, nil, queue)
 	})
 	if err != nil {
-		log.Println("error in service layer get queue by id")
+		return models.Queue{}, err
 	}
 
+	metrics.BookingsCreated.Inc()
+
 	return queue, nil
 }
 
@@ -45,14 +82,14 @@ func (q queueService) Get(ctx context.Context, pkey models.PrimaryKey) (models.Q
 	if err != nil {
 		if !errors.Is(err, pgx.ErrNoRows) {
 			fmt.Println("error in service layer while getting queue by id", err.Error())
-			return models.Queue{}, err
 		}
+		return models.Queue{}, err
 	}
 
 	return queue, nil
 }
 
-func (q queueService) GetList(ctx context.Context, request models.GetListRequest) (models.QueuesResponse, error) {
+func (q queueService) GetList(ctx context.Context, request models.GetQueuesListRequest) (models.QueuesResponse, error) {
 
 	queue, err := q.storage.Queue().GetList(ctx, request)
 	if err != nil {
@@ -66,26 +103,194 @@ func (q queueService) GetList(ctx context.Context, request models.GetListRequest
 
 func (q queueService) Update(ctx context.Context, updateQueue models.UpdateQueue) (models.Queue, error) {
 
-	id, err := q.storage.Queue().Update(ctx, updateQueue)
+	queue := models.Queue{}
+
+	err := q.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := q.storage.Queue().Get(ctx, models.PrimaryKey{ID: updateQueue.ID})
+		if err != nil {
+			return err
+		}
+
+		if err := checkReference(ctx, "customer_id", updateQueue.CustomerID, q.storage.Customer().Get); err != nil {
+			return err
+		}
+
+		if err := checkReference(ctx, "doctor_id", updateQueue.DoctorID, q.storage.Doctor().Get); err != nil {
+			return err
+		}
+
+		id, err := q.storage.Queue().Update(ctx, updateQueue)
+		if err != nil {
+			fmt.Println("error in servise layer updating queue  by id", err.Error())
+			return err
+		}
+
+		queue, err = q.storage.Queue().Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			fmt.Println("error in service layer getting queue after update", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, q.storage, "queue", queue.ID, config.AuditUpdate, before, queue)
+	})
 	if err != nil {
-		fmt.Println("error in servise layer updating queue  by id", err.Error())
 		return models.Queue{}, err
 	}
 
-	queue, err := q.storage.Queue().Get(ctx, models.PrimaryKey{
-		ID: id,
+	return queue, nil
+}
+
+func (q queueService) Delete(ctx context.Context, id string) error {
+
+	return q.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := q.storage.Queue().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			return err
+		}
+
+		if err = q.storage.Queue().Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, q.storage, "queue", id, config.AuditDelete, before, nil)
+	})
+}
+
+// ChangeStatus moves the queue to the given status, when a consultation starts or ends
+// the doctor becomes busy or empty in the same transaction
+func (q queueService) ChangeStatus(ctx context.Context, id, status string) (models.Queue, error) {
+
+	updated := models.Queue{}
+
+	err := q.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		queue, err := q.storage.Queue().Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			fmt.Println("error in service layer while getting queue by id", err.Error())
+			return err
+		}
+
+		if !canTransition(queueTransitions, queue.Status, status) {
+			return fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, queue.Status, status)
+		}
+
+		doctorStatus := ""
+
+		switch status {
+		case config.QueueInConsultation:
+			doctorStatus = config.DoctorBusy
+		case config.QueueCompleted:
+			doctorStatus = config.DoctorEmpty
+		}
+
+		doctor := models.Doctor{}
+
+		if doctorStatus != "" {
+			doctor, err = q.storage.Doctor().Get(ctx, models.PrimaryKey{
+				ID: queue.DoctorID,
+			})
+			if err != nil {
+				fmt.Println("error in service layer while getting doctor by id", err.Error())
+				return err
+			}
+		}
+
+		// a busy doctor is refused by the storage once it locked the doctor row
+
+		if err = q.storage.Queue().UpdateStatus(ctx, models.UpdateQueueStatus{
+			ID:           id,
+			FromStatus:   queue.Status,
+			ToStatus:     status,
+			DoctorStatus: doctorStatus,
+		}); err != nil {
+			fmt.Println("error in service layer while updating queue status", err.Error())
+			return err
+		}
+
+		updated, err = q.storage.Queue().Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err = recordAudit(ctx, q.storage, "queue", updated.ID, config.AuditUpdate, queue, updated); err != nil {
+			return err
+		}
+
+		if doctorStatus == "" {
+			return nil
+		}
+
+		changed := doctor
+		changed.Status = doctorStatus
+
+		return recordAudit(ctx, q.storage, "doctor", doctor.ID, config.AuditUpdate, doctor, changed)
 	})
 	if err != nil {
-		fmt.Println("error in service layer getting queue after update", err.Error())
 		return models.Queue{}, err
 	}
 
-	return queue, nil
+	return updated, nil
 }
 
-func (q queueService) Delete(ctx context.Context, id string) error {
+func (q queueService) GetBoard(ctx context.Context, clinicBranchID, date string) ([]models.QueueBoardItem, error) {
 
-	err := q.storage.Queue().Delete(ctx, id)
+	items, err := q.storage.Queue().GetBoard(ctx, clinicBranchID, date)
+	if err != nil {
+		fmt.Println("error in service layer while getting queue board", err.Error())
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// SubscribeBoard streams changes of the clinic branch queues, the returned function stops the stream.
+// Subscribe before taking the snapshot with GetBoard so no change made in between is lost
+func (q queueService) SubscribeBoard(clinicBranchID string) (<-chan pubsub.Message, func()) {
+	return q.broker.Subscribe(clinicBranchID)
+}
+
+func canTransition(transitions map[string][]string, from, to string) bool {
+	for _, status := range transitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Restore brings back the soft deleted queue
+func (q queueService) Restore(ctx context.Context, id string) (models.Queue, error) {
+
+	queue := models.Queue{}
 
-	return err
+	err := q.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		err := q.storage.Queue().Restore(ctx, id)
+		if err != nil {
+			fmt.Println("error in service layer while restoring queue", err.Error())
+			return err
+		}
+
+		queue, err = q.storage.Queue().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			fmt.Println("error in service layer getting queue after restore", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, q.storage, "queue", id, config.AuditRestore, nil, queue)
+	})
+	if err != nil {
+		return models.Queue{}, err
+	}
+
+	return queue, nil
 }
@@ -1,27 +1,195 @@
 package service
 
-import "shifolink/storage"
+import (
+	"shifolink/config"
+	"shifolink/pkg/pubsub"
+	"shifolink/storage"
+)
 
 type IServiceManager interface {
+	AuditLog() auditLogService
 	Author() authorService
-	//other structs
-
+	Auth() authService
+	Clinic() clinicService
+	ClinicAdmin() clinicAdminService
+	ClinicBranch() clinicBranchService
+	Customer() customerService
+	Doctor() doctorService
+	DoctorSchedule() doctorScheduleService
+	DoctorType() doctorTypeService
+	Drug() drugService
+	DrugCatalogue() drugCatalogueService
+	DrugLot() drugLotService
+	DrugStore() drugStoreService
+	DrugStoreBranch() drugStoreBranchService
+	Health() healthService
+	Journal() journalService
+	JournalCategory() journalCategoryService
+	OrderDrug() orderDrugService
+	Orders() ordersService
+	Pharmacist() pharmacistService
+	Prescription() prescriptionService
+	Queue() queueService
+	SuperAdmin() superAdminService
+	Trash() trashService
 }
 
 type Service struct {
-	authorService authorService
-	// other structs
+	auditLogService        auditLogService
+	authorService          authorService
+	authService            authService
+	clinicService          clinicService
+	clinicAdminService     clinicAdminService
+	clinicBranchService    clinicBranchService
+	customerService        customerService
+	doctorService          doctorService
+	doctorScheduleService  doctorScheduleService
+	doctorTypeService      doctorTypeService
+	drugService            drugService
+	drugCatalogueService   drugCatalogueService
+	drugLotService         drugLotService
+	drugStoreService       drugStoreService
+	drugStoreBranchService drugStoreBranchService
+	healthService          healthService
+	journalService         journalService
+	journalCategoryService journalCategoryService
+	orderDrugService       orderDrugService
+	ordersService          ordersService
+	pharmacistService      pharmacistService
+	prescriptionService    prescriptionService
+	queueService           queueService
+	superAdminService      superAdminService
+	trashService           trashService
 }
 
-func New(storage storage.IStorage) Service {
+func New(cfg config.Config, storage storage.IStorage, broker *pubsub.Broker) Service {
 	services := Service{}
 
+	services.auditLogService = NewAuditLogService(storage)
 	services.authorService = NewAuthorService(storage)
-	// other services
+	services.authService = NewAuthService(cfg, storage)
+	services.clinicService = NewClinicService(storage)
+	services.clinicAdminService = NewClinicAdminService(storage)
+	services.clinicBranchService = NewClinicBranchService(storage)
+	services.customerService = NewCustomerService(storage)
+	services.doctorService = NewDoctorService(storage)
+	services.doctorScheduleService = NewDoctorScheduleService(storage)
+	services.doctorTypeService = NewDoctorTypeService(storage)
+	services.drugService = NewDrugService(storage)
+	services.drugCatalogueService = NewDrugCatalogueService(storage)
+	services.drugLotService = NewDrugLotService(storage)
+	services.drugStoreService = NewDrugStoreService(storage)
+	services.drugStoreBranchService = NewDrugStoreBranchService(storage)
+	services.healthService = NewHealthService(storage)
+	services.journalService = NewJournalService(storage)
+	services.journalCategoryService = NewJournalCategoryService(storage)
+	services.orderDrugService = NewOrderDrugService(storage)
+	services.ordersService = NewOrdersService(storage, cfg.Currency)
+	services.pharmacistService = NewPharmacistService(storage)
+	services.prescriptionService = NewPrescriptionService(storage, services.ordersService)
+	services.queueService = NewQueueService(storage, broker)
+	services.superAdminService = NewSuperAdminService(storage)
+	services.trashService = NewTrashService(storage, cfg.PurgeRetention)
 
 	return services
 }
 
+func (s Service) AuditLog() auditLogService {
+	return s.auditLogService
+}
+
 func (s Service) Author() authorService {
 	return s.authorService
 }
+
+func (s Service) Auth() authService {
+	return s.authService
+}
+
+func (s Service) Clinic() clinicService {
+	return s.clinicService
+}
+
+func (s Service) ClinicAdmin() clinicAdminService {
+	return s.clinicAdminService
+}
+
+func (s Service) ClinicBranch() clinicBranchService {
+	return s.clinicBranchService
+}
+
+func (s Service) Customer() customerService {
+	return s.customerService
+}
+
+func (s Service) Doctor() doctorService {
+	return s.doctorService
+}
+
+func (s Service) DoctorSchedule() doctorScheduleService {
+	return s.doctorScheduleService
+}
+
+func (s Service) DoctorType() doctorTypeService {
+	return s.doctorTypeService
+}
+
+func (s Service) Drug() drugService {
+	return s.drugService
+}
+
+func (s Service) DrugLot() drugLotService {
+	return s.drugLotService
+}
+
+func (s Service) DrugCatalogue() drugCatalogueService {
+	return s.drugCatalogueService
+}
+
+func (s Service) DrugStore() drugStoreService {
+	return s.drugStoreService
+}
+
+func (s Service) DrugStoreBranch() drugStoreBranchService {
+	return s.drugStoreBranchService
+}
+
+func (s Service) Health() healthService {
+	return s.healthService
+}
+
+func (s Service) Journal() journalService {
+	return s.journalService
+}
+
+func (s Service) JournalCategory() journalCategoryService {
+	return s.journalCategoryService
+}
+
+func (s Service) OrderDrug() orderDrugService {
+	return s.orderDrugService
+}
+
+func (s Service) Orders() ordersService {
+	return s.ordersService
+}
+
+func (s Service) Pharmacist() pharmacistService {
+	return s.pharmacistService
+}
+
+func (s Service) Prescription() prescriptionService {
+	return s.prescriptionService
+}
+
+func (s Service) Queue() queueService {
+	return s.queueService
+}
+
+func (s Service) SuperAdmin() superAdminService {
+	return s.superAdminService
+}
+
+func (s Service) Trash() trashService {
+	return s.trashService
+}
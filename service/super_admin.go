@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"log"
 	"shifolink/api/models"
+	"shifolink/config"
 	"shifolink/pkg/check"
+	"shifolink/pkg/security"
 	"shifolink/storage"
 
 	"github.com/jackc/pgx/v5"
@@ -24,17 +26,52 @@ func NewSuperAdminService(storage storage.IStorage) superAdminService {
 
 func (s superAdminService) Create(ctx context.Context, createSuperAdmin models.CreateSuperAdmin) (models.SuperAdmin, error) {
 
-	pKey, err := s.storage.SuperAdmin().Create(ctx, createSuperAdmin)
-	if err != nil {
-		log.Println("error in service layer while creating superAdmin ", err.Error())
-		return models.SuperAdmin{}, err
-	}
+	superAdmin := models.SuperAdmin{}
+
+	err := s.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		age, err := checkAccount(createSuperAdmin.Password, createSuperAdmin.BirthDate)
+		if err != nil {
+			return err
+		}
+		createSuperAdmin.Age = age
+
+		if createSuperAdmin.ClinicID != "" {
+			if err := checkReference(ctx, "clinic_id", createSuperAdmin.ClinicID, s.storage.Clinic().Get); err != nil {
+				return err
+			}
+		}
+
+		if createSuperAdmin.DrugStoreID != "" {
+			if err := checkReference(ctx, "drug_store_id", createSuperAdmin.DrugStoreID, s.storage.DrugStore().Get); err != nil {
+				return err
+			}
+		}
+
+		if createSuperAdmin.AuthorID != "" {
+			if err := checkReference(ctx, "author_id", createSuperAdmin.AuthorID, s.storage.Author().Get); err != nil {
+				return err
+			}
+		}
+
+		pKey, err := s.storage.SuperAdmin().Create(ctx, createSuperAdmin)
+		if err != nil {
+			log.Println("error in service layer while creating superAdmin ", err.Error())
+			return err
+		}
+
+		superAdmin, err = s.storage.SuperAdmin().Get(ctx, models.PrimaryKey{
+			ID: pKey,
+		})
+		if err != nil {
+			log.Println("error in service layer get superAdmin by id")
+			return err
+		}
 
-	superAdmin, err := s.storage.SuperAdmin().Get(ctx, models.PrimaryKey{
-		ID: pKey,
+		return recordAudit(ctx, s.storage, "super_admin", superAdmin.ID, config.AuditCreate, nil, superAdmin)
 	})
 	if err != nil {
-		log.Println("error in service layer get superAdmin by id")
+		return models.SuperAdmin{}, err
 	}
 
 	return superAdmin, nil
@@ -46,8 +83,8 @@ func (s superAdminService) Get(ctx context.Context, pkey models.PrimaryKey) (mod
 	if err != nil {
 		if !errors.Is(err, pgx.ErrNoRows) {
 			fmt.Println("error in service layer while getting superAdmin by id", err.Error())
-			return models.SuperAdmin{}, err
 		}
+		return models.SuperAdmin{}, err
 	}
 
 	return superAdmin, nil
@@ -67,17 +104,50 @@ func (s superAdminService) GetList(ctx context.Context, request models.GetListRe
 
 func (s superAdminService) Update(ctx context.Context, updateSuperAdmin models.UpdateSuperAdmin) (models.SuperAdmin, error) {
 
-	id, err := s.storage.SuperAdmin().Update(ctx, updateSuperAdmin)
-	if err != nil {
-		fmt.Println("error in servise layer updating superAdmin by id", err.Error())
-		return models.SuperAdmin{}, err
-	}
+	superAdmin := models.SuperAdmin{}
+
+	err := s.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := s.storage.SuperAdmin().Get(ctx, models.PrimaryKey{ID: updateSuperAdmin.ID})
+		if err != nil {
+			return err
+		}
+
+		if updateSuperAdmin.ClinicID != "" {
+			if err := checkReference(ctx, "clinic_id", updateSuperAdmin.ClinicID, s.storage.Clinic().Get); err != nil {
+				return err
+			}
+		}
+
+		if updateSuperAdmin.DrugStoreID != "" {
+			if err := checkReference(ctx, "drug_store_id", updateSuperAdmin.DrugStoreID, s.storage.DrugStore().Get); err != nil {
+				return err
+			}
+		}
+
+		if updateSuperAdmin.AuthorID != "" {
+			if err := checkReference(ctx, "author_id", updateSuperAdmin.AuthorID, s.storage.Author().Get); err != nil {
+				return err
+			}
+		}
+
+		id, err := s.storage.SuperAdmin().Update(ctx, updateSuperAdmin)
+		if err != nil {
+			fmt.Println("error in servise layer updating superAdmin by id", err.Error())
+			return err
+		}
 
-	superAdmin, err := s.storage.SuperAdmin().Get(ctx, models.PrimaryKey{
-		ID: id,
+		superAdmin, err = s.storage.SuperAdmin().Get(ctx, models.PrimaryKey{
+			ID: id,
+		})
+		if err != nil {
+			fmt.Println("error in service layer getting superAdmin after update", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, s.storage, "super_admin", superAdmin.ID, config.AuditUpdate, before, superAdmin)
 	})
 	if err != nil {
-		fmt.Println("error in service layer getting superAdmin after update", err.Error())
 		return models.SuperAdmin{}, err
 	}
 
@@ -86,33 +156,74 @@ func (s superAdminService) Update(ctx context.Context, updateSuperAdmin models.U
 
 func (s superAdminService) Delete(ctx context.Context, id string) error {
 
-	err := s.storage.SuperAdmin().Delete(ctx, id)
+	return s.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		before, err := s.storage.SuperAdmin().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			return err
+		}
+
+		if err = s.storage.SuperAdmin().Delete(ctx, id); err != nil {
+			return err
+		}
 
-	return err
+		return recordAudit(ctx, s.storage, "super_admin", id, config.AuditDelete, before, nil)
+	})
 }
 
 func (s superAdminService) UpdatePassword(ctx context.Context, request models.UpdateSuperAdminPassword) error {
 
-	oldPassword, err := s.storage.SuperAdmin().GetPassword(ctx, request.ID)
-	if err != nil {
-		fmt.Println("error in service layer getting password by id", err.Error())
-		return err
-	}
+	return s.storage.Transaction(ctx, func(ctx context.Context) error {
 
-	if oldPassword != request.OldPassword {
-		fmt.Println("error in service layer old password is not correct")
-		return errors.New("old password did not match")
-	}
+		oldPassword, err := s.storage.SuperAdmin().GetPassword(ctx, request.ID)
+		if err != nil {
+			fmt.Println("error in service layer getting password by id", err.Error())
+			return err
+		}
 
-	if err = check.ValidatePassword(request.NewPassword); err != nil {
-		fmt.Println("error in service layer new password validation failed", err.Error())
-		return err
-	}
+		if !security.CompareHashAndPassword(oldPassword, request.OldPassword) {
+			fmt.Println("error in service layer old password is not correct")
+			return ErrPasswordMismatch.WithField("old_password", "is not correct")
+		}
+
+		if err = check.ValidatePassword(request.NewPassword); err != nil {
+			fmt.Println("error in service layer new password validation failed", err.Error())
+			return ErrInvalidData.WithField("new_password", err.Error())
+		}
 
-	if err = s.storage.SuperAdmin().UpdatePassword(context.Background(), request); err != nil {
-		fmt.Println("error in service layer while updating pharmacist password ", err.Error())
-		return err
+		if err = s.storage.SuperAdmin().UpdatePassword(ctx, request); err != nil {
+			fmt.Println("error in service layer while updating pharmacist password ", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, s.storage, "super_admin", request.ID, config.AuditUpdate, nil, passwordChanged)
+	})
+}
+
+// Restore brings back the soft deleted super admin
+func (s superAdminService) Restore(ctx context.Context, id string) (models.SuperAdmin, error) {
+
+	superAdmin := models.SuperAdmin{}
+
+	err := s.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		err := s.storage.SuperAdmin().Restore(ctx, id)
+		if err != nil {
+			fmt.Println("error in service layer while restoring super admin", err.Error())
+			return err
+		}
+
+		superAdmin, err = s.storage.SuperAdmin().Get(ctx, models.PrimaryKey{ID: id})
+		if err != nil {
+			fmt.Println("error in service layer getting super admin after restore", err.Error())
+			return err
+		}
+
+		return recordAudit(ctx, s.storage, "super_admin", id, config.AuditRestore, nil, superAdmin)
+	})
+	if err != nil {
+		return models.SuperAdmin{}, err
 	}
 
-	return nil
+	return superAdmin, nil
 }
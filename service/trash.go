@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"log"
+	"shifolink/config"
+	"shifolink/storage"
+	"time"
+)
+
+type trashService struct {
+	storage   storage.IStorage
+	retention time.Duration
+}
+
+func NewTrashService(storage storage.IStorage, retention time.Duration) trashService {
+	return trashService{
+		storage:   storage,
+		retention: retention,
+	}
+}
+
+// Purge removes for good the records which were soft deleted longer than the retention ago, each of them
+// is written to the audit log in the same transaction, so nothing is purged when a step fails
+func (t trashService) Purge(ctx context.Context) error {
+
+	return t.storage.Transaction(ctx, func(ctx context.Context) error {
+
+		purged, err := t.storage.Trash().Purge(ctx, time.Now().Add(-t.retention))
+		if err != nil {
+			log.Println("error in service layer while purging deleted records", err.Error())
+			return err
+		}
+
+		for table, ids := range purged {
+			log.Println("purged", len(ids), "deleted records of", table)
+
+			for _, id := range ids {
+				if err = recordAudit(ctx, t.storage, table, id, config.AuditPurge, nil, nil); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// RunPurge purges deleted records right away and then every interval until the context is done,
+// the interval should be positive
+func (t trashService) RunPurge(ctx context.Context, interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// a failed purge is logged and tried again on the next tick
+		_ = t.Purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
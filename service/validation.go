@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"shifolink/api/models"
+	"shifolink/pkg/check"
+	"shifolink/pkg/errs"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrInvalidData is returned when a request breaks a rule of the entity, the wrapped message tells which one
+	ErrInvalidData = errs.Validation("data is not valid")
+	// ErrReferenceNotFound is returned when an id of the request points to a record which does not exist
+	ErrReferenceNotFound = errs.Validation("referenced record not found")
+	// ErrPasswordMismatch is returned when the old password given for a password change is wrong
+	ErrPasswordMismatch = errs.Validation("old password did not match")
+)
+
+// checkReference makes sure the id of the field points to an existing record, get is the Get of its repo
+func checkReference[T any](ctx context.Context, field, id string, get func(context.Context, models.PrimaryKey) (T, error)) error {
+
+	if id == "" {
+		return ErrInvalidData.WithField(field, "is required")
+	}
+
+	if _, err := uuid.Parse(id); err != nil {
+		return ErrInvalidData.WithField(field, "is not a valid uuid")
+	}
+
+	if _, err := get(ctx, models.PrimaryKey{ID: id}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrReferenceNotFound.WithField(field, "points to a record which does not exist")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// checkAccount validates the password and the birth date of a new account and returns its age
+func checkAccount(password, birthDate string) (int, error) {
+
+	if err := check.ValidatePassword(password); err != nil {
+		return 0, ErrInvalidData.WithField("password", err.Error())
+	}
+
+	birthday, err := time.Parse("2006-01-02", birthDate)
+	if err != nil || birthday.After(time.Now()) {
+		return 0, ErrInvalidData.WithField("birth_date", "should be a date in YYYY-MM-DD format not in the future")
+	}
+
+	return check.CalculateAge(birthDate), nil
+}
@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"shifolink/api/models"
+	"shifolink/storage"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type auditLogRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuditLogRepo(pool *pgxpool.Pool) storage.IAuditLogRepo {
+	return &auditLogRepo{
+		pool: pool,
+	}
+}
+
+func (a *auditLogRepo) Create(ctx context.Context, request models.CreateAuditLog) error {
+
+	query := `insert into audit_log
+	 (id,
+	  actor_id,
+	  actor_role,
+	  entity,
+	  entity_id,
+	  action,
+	  request_id,
+	  before,
+	  after)
+	  values ($1, nullif($2, '')::uuid, $3, $4, $5, $6, $7, $8, $9)`
+
+	if _, err := conn(ctx, a.pool).Exec(ctx, query,
+		uuid.New(),
+		request.ActorID,
+		request.ActorRole,
+		request.Entity,
+		request.EntityID,
+		request.Action,
+		request.RequestID,
+		[]byte(request.Before),
+		[]byte(request.After),
+	); err != nil {
+		log.Println("error while inserting audit log", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (a *auditLogRepo) GetList(ctx context.Context, request models.GetAuditLogsListRequest) (models.AuditLogsResponse, error) {
+
+	var (
+		logs              = []models.AuditLog{}
+		count             = 0
+		query, countQuery string
+		sortKey           = sql.NullString{}
+	)
+
+	// audit logs are never deleted
+	request.IncludeDeleted = true
+
+	filter := newListFilter(request.GetListRequest)
+	filter.Equal("entity", request.Entity)
+	filter.Equal("entity_id", request.EntityID)
+	filter.Equal("actor_id", request.ActorID)
+	filter.Equal("action", request.Action)
+
+	if err := filter.Sort(request.GetListRequest, sortColumns{}); err != nil {
+		return models.AuditLogsResponse{}, err
+	}
+
+	countQuery = `select count(1) from audit_log` + filter.Where()
+
+	if err := conn(ctx, a.pool).QueryRow(ctx, countQuery, filter.Args()...).Scan(&count); err != nil {
+		fmt.Println("error is while selecting count", err.Error())
+		return models.AuditLogsResponse{}, err
+	}
+
+	query = `select
+	 id,
+	 coalesce(actor_id::text, ''),
+	 actor_role,
+	 entity,
+	 entity_id,
+	 action,
+	 request_id,
+	 before,
+	 after,
+	 created_at,
+	 ` + filter.SortKey() + ` from audit_log` + filter.Where()
+
+	pagination, args := filter.Page(request.Page, request.Limit)
+	rows, err := conn(ctx, a.pool).Query(ctx, query+pagination, args...)
+	if err != nil {
+		fmt.Println("error is while selecting audit logs", err.Error())
+		return models.AuditLogsResponse{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			auditLog      = models.AuditLog{}
+			before, after []byte
+		)
+
+		if err = rows.Scan(
+			&auditLog.ID,
+			&auditLog.ActorID,
+			&auditLog.ActorRole,
+			&auditLog.Entity,
+			&auditLog.EntityID,
+			&auditLog.Action,
+			&auditLog.RequestID,
+			&before,
+			&after,
+			&auditLog.CreatedAt,
+			&sortKey,
+		); err != nil {
+			fmt.Println("error is while scanning audit log data", err.Error())
+			return models.AuditLogsResponse{}, err
+		}
+
+		auditLog.Before, auditLog.After = before, after
+
+		logs = append(logs, auditLog)
+	}
+
+	response := models.AuditLogsResponse{
+		AuditLogs: logs,
+		Count:     count,
+	}
+
+	if len(logs) > 0 {
+		response.NextCursor = filter.NextCursor(len(logs), request.Limit, sortKey.String, logs[len(logs)-1].ID)
+	}
+
+	return response, nil
+}
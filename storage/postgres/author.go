@@ -6,11 +6,12 @@ import (
 	"fmt"
 	"log"
 	"shifolink/api/models"
-	"shifolink/pkg/check"
+	"shifolink/pkg/security"
 	"shifolink/storage"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -28,6 +29,12 @@ func (a *authorRepo) Create(ctx context.Context, request models.CreateAuthor) (s
 
 	id := uuid.New()
 
+	hashedPassword, err := security.HashPassword(request.Password)
+	if err != nil {
+		log.Println("error while hashing author password", err.Error())
+		return "", err
+	}
+
 	query := `insert into author (
 		id, 
 		first_name, 
@@ -40,16 +47,16 @@ func (a *authorRepo) Create(ctx context.Context, request models.CreateAuthor) (s
 		age, 
 		address) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
 
-	_, err := a.pool.Exec(ctx, query,
+	_, err = conn(ctx, a.pool).Exec(ctx, query,
 		id,
 		request.FirstName,
 		request.LastName,
 		request.Email,
-		request.Password,
+		hashedPassword,
 		request.Phone,
 		request.Gender,
 		request.BirthDate,
-		check.CalculateAge(request.BirthDate),
+		request.Age,
 		request.Address,
 	)
 
@@ -73,29 +80,29 @@ func (a *authorRepo) Get(ctx context.Context, request models.PrimaryKey) (models
 	 first_name, 
 	 last_name, 
 	 email, 
-	 password, 
 	 phone, 
 	 gender, 
 	 birth_date::text, 
 	 age, 
 	 address, 
+	 version,
 	 created_at, 
 	 updated_at 
 	 from author where deleted_at is null and id = $1`
 
-	row := a.pool.QueryRow(ctx, query, request.ID)
+	row := conn(ctx, a.pool).QueryRow(ctx, query, request.ID)
 
 	err := row.Scan(
 		&author.ID,
 		&author.FirstName,
 		&author.LastName,
 		&author.Email,
-		&author.Password,
 		&author.Phone,
 		&author.Gender,
 		&author.BirthDate,
 		&author.Age,
 		&author.Address,
+		&author.Version,
 		&author.CreatedAt,
 		&updatedAt,
 	)
@@ -112,24 +119,32 @@ func (a *authorRepo) Get(ctx context.Context, request models.PrimaryKey) (models
 	return author, nil
 }
 
+// authorSortColumns are columns the author list can be sorted by, besides created_at
+var authorSortColumns = sortColumns{
+	"first_name": "text",
+	"last_name":  "text",
+	"birth_date": "date",
+}
+
 func (a *authorRepo) GetList(ctx context.Context, request models.GetListRequest) (models.AuthorsResponse, error) {
 
 	var (
 		updatedAt         = sql.NullTime{}
+		deletedAt         = sql.NullTime{}
 		authors           = []models.Author{}
 		count             = 0
 		query, countQuery string
-		page              = request.Page
-		offset            = (page - 1) * request.Limit
-		search            = request.Search
 	)
 
-	countQuery = `select count(1) from author where deleted_at is null`
+	filter := newListFilter(request, "first_name", "last_name")
 
-	if search != "" {
-		countQuery += fmt.Sprintf(` and (first_name ilike '%%%s%%' or last_name ilike '%%%s%%')`, search, search)
+	if err := filter.Sort(request, authorSortColumns); err != nil {
+		return models.AuthorsResponse{}, err
 	}
-	if err := a.pool.QueryRow(ctx, countQuery).Scan(&count); err != nil {
+
+	countQuery = `select count(1) from author` + filter.Where()
+
+	if err := conn(ctx, a.pool).QueryRow(ctx, countQuery, filter.Args()...).Scan(&count); err != nil {
 		fmt.Println("error is while selecting count", err.Error())
 		return models.AuthorsResponse{}, err
 	}
@@ -139,21 +154,20 @@ func (a *authorRepo) GetList(ctx context.Context, request models.GetListRequest)
 	 first_name, 
 	 last_name, 
 	 email, 
-	 password, 
 	 phone, 
 	 gender, 
 	 birth_date::text, 
 	 age, 
 	 address, 
+	 version,
 	 created_at, 
-	 updated_at from author where deleted_at is null`
+	 updated_at,
+	 deleted_at from author`
 
-	if search != "" {
-		query += fmt.Sprintf(` and (first_name ilike '%%%s%%' or last_name ilike '%%%s%%')`, search, search)
-	}
+	query += filter.Where()
 
-	query += ` LIMIT $1 OFFSET $2`
-	rows, err := a.pool.Query(ctx, query, request.Limit, offset)
+	pagination, args := filter.Page(request.Page, request.Limit)
+	rows, err := conn(ctx, a.pool).Query(ctx, query+pagination, args...)
 	if err != nil {
 		fmt.Println("error is while selecting author", err.Error())
 		return models.AuthorsResponse{}, err
@@ -166,14 +180,15 @@ func (a *authorRepo) GetList(ctx context.Context, request models.GetListRequest)
 			&author.FirstName,
 			&author.LastName,
 			&author.Email,
-			&author.Password,
 			&author.Phone,
 			&author.Gender,
 			&author.BirthDate,
 			&author.Age,
 			&author.Address,
+			&author.Version,
 			&author.CreatedAt,
 			&updatedAt,
+			&deletedAt,
 		); err != nil {
 			fmt.Println("error is while scanning author data", err.Error())
 			return models.AuthorsResponse{}, err
@@ -183,6 +198,10 @@ func (a *authorRepo) GetList(ctx context.Context, request models.GetListRequest)
 			author.UpdatedAt = updatedAt.Time
 		}
 
+		if deletedAt.Valid {
+			author.DeletedAt = deletedAt.Time
+		}
+
 		authors = append(authors, author)
 
 	}
@@ -201,29 +220,30 @@ func (a *authorRepo) Update(ctx context.Context, request models.UpdateAuthor) (s
 	email = $3,
 	phone = $4,
     address = $5,
+	version = version + 1,
 	updated_at = $6
-   where id = $7  
+   where id = $7 and deleted_at is null and ($8 = 0 or version = $8)
    `
 
-	rowsAffected, err := a.pool.Exec(ctx, query,
+	rowsAffected, err := conn(ctx, a.pool).Exec(ctx, query,
 		request.FirstName,
 		request.LastName,
 		request.Email,
 		request.Phone,
 		request.Address,
 		time.Now(),
-		request.ID)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return "", err
-	}
+		request.ID,
+		request.Version)
 
 	if err != nil {
 		log.Println("error while updating author data...", err.Error())
 		return "", err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return "", missedUpdate(ctx, conn(ctx, a.pool), "author", request.ID, request.Version)
+	}
+
 	return request.ID, nil
 }
 
@@ -232,21 +252,20 @@ func (a *authorRepo) Delete(ctx context.Context, id string) error {
 	query := `
 	update author
 	 set deleted_at = $1
-	  where id = $2
+	  where id = $2 and deleted_at is null
 	`
 
-	rowsAffected, err := a.pool.Exec(ctx, query, time.Now(), id)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return err
-	}
+	rowsAffected, err := conn(ctx, a.pool).Exec(ctx, query, time.Now(), id)
 
 	if err != nil {
 		log.Println("error while deleting author by id", err.Error())
 		return err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
 	return nil
 }
 
@@ -255,9 +274,9 @@ func (a *authorRepo) GetPassword(ctx context.Context, id string) (string, error)
 
 	query := `
 		select password from author 
-		                where id = $1`
+		                where id = $1 and deleted_at is null`
 
-	if err := a.pool.QueryRow(ctx, query, id).Scan(&password); err != nil {
+	if err := conn(ctx, a.pool).QueryRow(ctx, query, id).Scan(&password); err != nil {
 		fmt.Println("Error while scanning password from author", err.Error())
 		return "", err
 	}
@@ -267,22 +286,43 @@ func (a *authorRepo) GetPassword(ctx context.Context, id string) (string, error)
 
 func (a *authorRepo) UpdatePassword(ctx context.Context, request models.UpdateAuthorPassword) error {
 
+	hashedPassword, err := security.HashPassword(request.NewPassword)
+	if err != nil {
+		log.Println("error while hashing author password", err.Error())
+		return err
+	}
+
 	query := `
 		update author 
 				set password = $1, updated_at = now()
-					where id = $2`
+					where id = $2 and deleted_at is null`
 
-	rowsAffected, err := a.pool.Exec(ctx, query, request.NewPassword, request.ID)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return err
-	}
+	rowsAffected, err := conn(ctx, a.pool).Exec(ctx, query, hashedPassword, request.ID)
 
 	if err != nil {
 		log.Println("error while updating password for author", err.Error())
 		return err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
 	return nil
 }
+
+func (a *authorRepo) GetByLogin(ctx context.Context, login string) (models.Author, error) {
+	id, err := loginID(ctx, conn(ctx, a.pool), "author", login)
+	if err != nil {
+		fmt.Println("error while selecting author by login", err.Error())
+		return models.Author{}, err
+	}
+
+	return a.Get(ctx, models.PrimaryKey{
+		ID: id,
+	})
+}
+
+func (a *authorRepo) Restore(ctx context.Context, id string) error {
+	return restore(ctx, conn(ctx, a.pool), "author", id)
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -33,22 +34,21 @@ func (c *clinicRepo) Create(ctx context.Context, request models.CreateClinic) (s
 	  description) 
 	  values ($1, $2, $3)`
 
-	rowsAffected, err := c.pool.Exec(ctx, query,
+	rowsAffected, err := conn(ctx, c.pool).Exec(ctx, query,
 		id,
 		request.Name,
 		request.Description,
 	)
 
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return "", err
-	}
-
 	if err != nil {
 		log.Println("error while inserting clinic", err.Error())
 		return "", err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return "", pgx.ErrNoRows
+	}
+
 	return id.String(), nil
 }
 
@@ -62,16 +62,18 @@ func (c *clinicRepo) Get(ctx context.Context, request models.PrimaryKey) (models
 	 id,
 	 name,
 	 description,
+	 version,
 	 created_at,
 	 updated_at
 	 from clinic where deleted_at is null and id = $1`
 
-	row := c.pool.QueryRow(ctx, query, request.ID)
+	row := conn(ctx, c.pool).QueryRow(ctx, query, request.ID)
 
 	err := row.Scan(
 		&clinic.ID,
 		&clinic.Name,
 		&clinic.Description,
+		&clinic.Version,
 		&clinic.CreatedAt,
 		&updatedAt,
 	)
@@ -88,24 +90,30 @@ func (c *clinicRepo) Get(ctx context.Context, request models.PrimaryKey) (models
 	return clinic, nil
 }
 
+// clinicSortColumns are columns the clinic list can be sorted by, besides created_at
+var clinicSortColumns = sortColumns{
+	"name": "text",
+}
+
 func (c *clinicRepo) GetList(ctx context.Context, request models.GetListRequest) (models.ClinicsResponse, error) {
 
 	var (
 		updatedAt         = sql.NullTime{}
+		deletedAt         = sql.NullTime{}
 		clinics           = []models.Clinic{}
 		count             = 0
 		query, countQuery string
-		page              = request.Page
-		offset            = (page - 1) * request.Limit
-		search            = request.Search
 	)
 
-	countQuery = `select count(1) from clinic where deleted_at is null`
+	filter := newListFilter(request, "name")
 
-	if search != "" {
-		countQuery += fmt.Sprintf(` and (name ilike '%%%s%%')`, search)
+	if err := filter.Sort(request, clinicSortColumns); err != nil {
+		return models.ClinicsResponse{}, err
 	}
-	if err := c.pool.QueryRow(ctx, countQuery).Scan(&count); err != nil {
+
+	countQuery = `select count(1) from clinic` + filter.Where()
+
+	if err := conn(ctx, c.pool).QueryRow(ctx, countQuery, filter.Args()...).Scan(&count); err != nil {
 		fmt.Println("error is while selecting count", err.Error())
 		return models.ClinicsResponse{}, err
 	}
@@ -114,15 +122,15 @@ func (c *clinicRepo) GetList(ctx context.Context, request models.GetListRequest)
 	 id,
 	 name,
 	 description,
+	 version,
 	 created_at, 
-	 updated_at from clinic where deleted_at is null`
+	 updated_at,
+	 deleted_at from clinic`
 
-	if search != "" {
-		query += fmt.Sprintf(` and (name ilike '%%%s%%')`, search)
-	}
+	query += filter.Where()
 
-	query += ` LIMIT $1 OFFSET $2`
-	rows, err := c.pool.Query(ctx, query, request.Limit, offset)
+	pagination, args := filter.Page(request.Page, request.Limit)
+	rows, err := conn(ctx, c.pool).Query(ctx, query+pagination, args...)
 	if err != nil {
 		fmt.Println("error is while selecting clinic ", err.Error())
 		return models.ClinicsResponse{}, err
@@ -134,8 +142,10 @@ func (c *clinicRepo) GetList(ctx context.Context, request models.GetListRequest)
 			&clinic.ID,
 			&clinic.Name,
 			&clinic.Description,
+			&clinic.Version,
 			&clinic.CreatedAt,
 			&updatedAt,
+			&deletedAt,
 		); err != nil {
 			fmt.Println("error is while scanning clinic data", err.Error())
 			return models.ClinicsResponse{}, err
@@ -145,6 +155,10 @@ func (c *clinicRepo) GetList(ctx context.Context, request models.GetListRequest)
 			clinic.UpdatedAt = updatedAt.Time
 		}
 
+		if deletedAt.Valid {
+			clinic.DeletedAt = deletedAt.Time
+		}
+
 		clinics = append(clinics, clinic)
 
 	}
@@ -160,48 +174,35 @@ func (c *clinicRepo) Update(ctx context.Context, request models.UpdateClinic) (s
 	query := `update clinic set
 	name = $1,
 	description = $2,
+    version = version + 1,
     updated_at = $3 
-	 where id = $4  
+	 where id = $4 and deleted_at is null and ($5 = 0 or version = $5)
    `
 
-	rowsAffected, err := c.pool.Exec(ctx, query,
+	rowsAffected, err := conn(ctx, c.pool).Exec(ctx, query,
 		request.Name,
 		request.Description,
 		time.Now(),
-		request.ID)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return "", err
-	}
+		request.ID,
+		request.Version)
 
 	if err != nil {
 		log.Println("error while updating clinic data...", err.Error())
 		return "", err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return "", missedUpdate(ctx, conn(ctx, c.pool), "clinic", request.ID, request.Version)
+	}
+
 	return request.ID, nil
 }
 
-func (c *clinicRepo) Delete(ctx context.Context, id string) error {
-
-	query := `
-	update clinic
-	 set deleted_at = $1
-	  where id = $2
-	`
-
-	rowsAffected, err := c.pool.Exec(ctx, query, time.Now(), id)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return err
-	}
-
-	if err != nil {
-		log.Println("error while deleting clinic by id", err.Error())
-		return err
-	}
+// Delete soft deletes the clinic with its branches, their doctor types and doctors
+func (c *clinicRepo) Delete(ctx context.Context, id string) ([]storage.ChildRecord, error) {
+	return softDelete(ctx, conn(ctx, c.pool), "clinic", id)
+}
 
-	return nil
+func (c *clinicRepo) Restore(ctx context.Context, id string) ([]storage.ChildRecord, error) {
+	return restoreWithChildren(ctx, conn(ctx, c.pool), "clinic", id)
 }
@@ -6,11 +6,12 @@ import (
 	"fmt"
 	"log"
 	"shifolink/api/models"
-	"shifolink/pkg/check"
+	"shifolink/pkg/security"
 	"shifolink/storage"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -28,6 +29,12 @@ func (c *clinicAdminRepo) Create(ctx context.Context, request models.CreateClini
 
 	id := uuid.New()
 
+	hashedPassword, err := security.HashPassword(request.Password)
+	if err != nil {
+		log.Println("error while hashing clinic_admin password", err.Error())
+		return "", err
+	}
+
 	query := `insert into clinic_admin
 	 (id, 
 	  clinic_branch_id,
@@ -42,31 +49,30 @@ func (c *clinicAdminRepo) Create(ctx context.Context, request models.CreateClini
 	  age, 
 	  address) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
 
-	rowsAffected, err := c.pool.Exec(ctx, query,
+	rowsAffected, err := conn(ctx, c.pool).Exec(ctx, query,
 		id,
 		request.ClinicBranchID,
 		request.DoctorTypeID,
 		request.FirstName,
 		request.LastName,
 		request.Email,
-		request.Password,
+		hashedPassword,
 		request.Phone,
 		request.Gender,
 		request.BirthDate,
-		check.CalculateAge(request.BirthDate),
+		request.Age,
 		request.Address,
 	)
 
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return "", err
-	}
-
 	if err != nil {
 		log.Println("error while inserting clinic_admin", err.Error())
 		return "", err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return "", pgx.ErrNoRows
+	}
+
 	return id.String(), nil
 }
 
@@ -83,17 +89,17 @@ func (c *clinicAdminRepo) Get(ctx context.Context, request models.PrimaryKey) (m
 	 first_name, 
 	 last_name, 
 	 email, 
-	 password, 
 	 phone, 
 	 gender, 
 	 birth_date::text, 
 	 age, 
 	 address, 
+	 version,
 	 created_at, 
 	 updated_at 
 	 from clinic_admin where deleted_at is null and id = $1`
 
-	row := c.pool.QueryRow(ctx, query, request.ID)
+	row := conn(ctx, c.pool).QueryRow(ctx, query, request.ID)
 
 	err := row.Scan(
 		&clinicAdmin.ID,
@@ -102,12 +108,12 @@ func (c *clinicAdminRepo) Get(ctx context.Context, request models.PrimaryKey) (m
 		&clinicAdmin.FirstName,
 		&clinicAdmin.LastName,
 		&clinicAdmin.Email,
-		&clinicAdmin.Password,
 		&clinicAdmin.Phone,
 		&clinicAdmin.Gender,
 		&clinicAdmin.BirthDate,
 		&clinicAdmin.Age,
 		&clinicAdmin.Address,
+		&clinicAdmin.Version,
 		&clinicAdmin.CreatedAt,
 		&updatedAt,
 	)
@@ -124,24 +130,33 @@ func (c *clinicAdminRepo) Get(ctx context.Context, request models.PrimaryKey) (m
 	return clinicAdmin, nil
 }
 
-func (c *clinicAdminRepo) GetList(ctx context.Context, request models.GetListRequest) (models.ClinicAdminsResponse, error) {
+// clinicAdminSortColumns are columns the clinic admin list can be sorted by, besides created_at
+var clinicAdminSortColumns = sortColumns{
+	"first_name": "text",
+	"last_name":  "text",
+}
+
+func (c *clinicAdminRepo) GetList(ctx context.Context, request models.GetClinicAdminsListRequest) (models.ClinicAdminsResponse, error) {
 
 	var (
 		updatedAt         = sql.NullTime{}
+		deletedAt         = sql.NullTime{}
 		clinicAdmins      = []models.ClinicAdmin{}
 		count             = 0
 		query, countQuery string
-		page              = request.Page
-		offset            = (page - 1) * request.Limit
-		search            = request.Search
 	)
 
-	countQuery = `select count(1) from clinic_admin where deleted_at is null`
+	filter := newListFilter(request.GetListRequest, "first_name", "last_name")
+	filter.Equal("clinic_branch_id", request.ClinicBranchID)
+	filter.Equal("doctor_type_id", request.DoctorTypeID)
 
-	if search != "" {
-		countQuery += fmt.Sprintf(` and (first_name ilike '%%%s%%' or last_name ilike '%%%s%%')`, search, search)
+	if err := filter.Sort(request.GetListRequest, clinicAdminSortColumns); err != nil {
+		return models.ClinicAdminsResponse{}, err
 	}
-	if err := c.pool.QueryRow(ctx, countQuery).Scan(&count); err != nil {
+
+	countQuery = `select count(1) from clinic_admin` + filter.Where()
+
+	if err := conn(ctx, c.pool).QueryRow(ctx, countQuery, filter.Args()...).Scan(&count); err != nil {
 		fmt.Println("error is while selecting count", err.Error())
 		return models.ClinicAdminsResponse{}, err
 	}
@@ -153,21 +168,20 @@ func (c *clinicAdminRepo) GetList(ctx context.Context, request models.GetListReq
 	 first_name, 
 	 last_name, 
 	 email, 
-	 password, 
 	 phone, 
 	 gender, 
 	 birth_date::text, 
 	 age, 
 	 address, 
+	 version,
 	 created_at, 
-	 updated_at from clinic_admin where deleted_at is null`
+	 updated_at,
+	 deleted_at from clinic_admin`
 
-	if search != "" {
-		query += fmt.Sprintf(` and (first_name ilike '%%%s%%' or last_name ilike '%%%s%%')`, search, search)
-	}
+	query += filter.Where()
 
-	query += ` LIMIT $1 OFFSET $2`
-	rows, err := c.pool.Query(ctx, query, request.Limit, offset)
+	pagination, args := filter.Page(request.Page, request.Limit)
+	rows, err := conn(ctx, c.pool).Query(ctx, query+pagination, args...)
 	if err != nil {
 		fmt.Println("error is while selecting clinic admin", err.Error())
 		return models.ClinicAdminsResponse{}, err
@@ -182,14 +196,15 @@ func (c *clinicAdminRepo) GetList(ctx context.Context, request models.GetListReq
 			&clinicAdmin.FirstName,
 			&clinicAdmin.LastName,
 			&clinicAdmin.Email,
-			&clinicAdmin.Password,
 			&clinicAdmin.Phone,
 			&clinicAdmin.Gender,
 			&clinicAdmin.BirthDate,
 			&clinicAdmin.Age,
 			&clinicAdmin.Address,
+			&clinicAdmin.Version,
 			&clinicAdmin.CreatedAt,
 			&updatedAt,
+			&deletedAt,
 		); err != nil {
 			fmt.Println("error is while scanning clinic admin data", err.Error())
 			return models.ClinicAdminsResponse{}, err
@@ -199,6 +214,10 @@ func (c *clinicAdminRepo) GetList(ctx context.Context, request models.GetListReq
 			clinicAdmin.UpdatedAt = updatedAt.Time
 		}
 
+		if deletedAt.Valid {
+			clinicAdmin.DeletedAt = deletedAt.Time
+		}
+
 		clinicAdmins = append(clinicAdmins, clinicAdmin)
 
 	}
@@ -219,11 +238,12 @@ func (c *clinicAdminRepo) Update(ctx context.Context, request models.UpdateClini
 	email = $5,
 	phone = $6,
     address = $7,
+	version = version + 1,
 	updated_at = $8
-   where id = $9  
+   where id = $9 and deleted_at is null and ($10 = 0 or version = $10)
    `
 
-	rowsAffected, err := c.pool.Exec(ctx, query,
+	rowsAffected, err := conn(ctx, c.pool).Exec(ctx, query,
 		request.ClinicBranchID,
 		request.DoctorTypeID,
 		request.FirstName,
@@ -232,18 +252,18 @@ func (c *clinicAdminRepo) Update(ctx context.Context, request models.UpdateClini
 		request.Phone,
 		request.Address,
 		time.Now(),
-		request.ID)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return "", err
-	}
+		request.ID,
+		request.Version)
 
 	if err != nil {
 		log.Println("error while updating clinic admin data...", err.Error())
 		return "", err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return "", missedUpdate(ctx, conn(ctx, c.pool), "clinic_admin", request.ID, request.Version)
+	}
+
 	return request.ID, nil
 }
 
@@ -252,21 +272,20 @@ func (c *clinicAdminRepo) Delete(ctx context.Context, id string) error {
 	query := `
 	update clinic_admin
 	 set deleted_at = $1
-	  where id = $2
+	  where id = $2 and deleted_at is null
 	`
 
-	rowsAffected, err := c.pool.Exec(ctx, query, time.Now(), id)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return err
-	}
+	rowsAffected, err := conn(ctx, c.pool).Exec(ctx, query, time.Now(), id)
 
 	if err != nil {
 		log.Println("error while deleting clinic admin by id", err.Error())
 		return err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
 	return nil
 }
 
@@ -275,9 +294,9 @@ func (c *clinicAdminRepo) GetPassword(ctx context.Context, id string) (string, e
 
 	query := `
 		select password from clinic_admin 
-						where id = $1`
+						where id = $1 and deleted_at is null`
 
-	if err := c.pool.QueryRow(ctx, query, id).Scan(&password); err != nil {
+	if err := conn(ctx, c.pool).QueryRow(ctx, query, id).Scan(&password); err != nil {
 		fmt.Println("Error while scanning password from clinic_admin", err.Error())
 		return "", err
 	}
@@ -287,22 +306,43 @@ func (c *clinicAdminRepo) GetPassword(ctx context.Context, id string) (string, e
 
 func (c *clinicAdminRepo) UpdatePassword(ctx context.Context, request models.UpdateClinicAdminPassword) error {
 
+	hashedPassword, err := security.HashPassword(request.NewPassword)
+	if err != nil {
+		log.Println("error while hashing clinic_admin password", err.Error())
+		return err
+	}
+
 	query := `
 		update clinic_admin 
 				set password = $1, updated_at = now()
-					where id = $2`
+					where id = $2 and deleted_at is null`
 
-	rowsAffected, err := c.pool.Exec(ctx, query, request.NewPassword, request.ID)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return err
-	}
+	rowsAffected, err := conn(ctx, c.pool).Exec(ctx, query, hashedPassword, request.ID)
 
 	if err != nil {
 		fmt.Println("error while updating password for clinic admin", err.Error())
 		return err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
 	return nil
 }
+
+func (c *clinicAdminRepo) GetByLogin(ctx context.Context, login string) (models.ClinicAdmin, error) {
+	id, err := loginID(ctx, conn(ctx, c.pool), "clinic_admin", login)
+	if err != nil {
+		fmt.Println("error while selecting clinic admin by login", err.Error())
+		return models.ClinicAdmin{}, err
+	}
+
+	return c.Get(ctx, models.PrimaryKey{
+		ID: id,
+	})
+}
+
+func (c *clinicAdminRepo) Restore(ctx context.Context, id string) error {
+	return restore(ctx, conn(ctx, c.pool), "clinic_admin", id)
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -35,7 +36,7 @@ func (c *clinicBranchRepo) Create(ctx context.Context, request models.CreateClin
 	  working_time) 
 	  values ($1, $2, $3, $4, $5)`
 
-	rowsAffected, err := c.pool.Exec(ctx, query,
+	rowsAffected, err := conn(ctx, c.pool).Exec(ctx, query,
 		id,
 		request.ClinicID,
 		request.Address,
@@ -43,16 +44,15 @@ func (c *clinicBranchRepo) Create(ctx context.Context, request models.CreateClin
 		request.WorkingTime,
 	)
 
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return "", err
-	}
-
 	if err != nil {
 		log.Println("error while inserting clinic_admin", err.Error())
 		return "", err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return "", pgx.ErrNoRows
+	}
+
 	return id.String(), nil
 }
 
@@ -68,11 +68,12 @@ func (c *clinicBranchRepo) Get(ctx context.Context, request models.PrimaryKey) (
 	 address,
 	 phone,
 	 working_time,
+	 version,
 	 created_at,
 	 updated_at
 	 from clinic_branch where deleted_at is null and id = $1`
 
-	row := c.pool.QueryRow(ctx, query, request.ID)
+	row := conn(ctx, c.pool).QueryRow(ctx, query, request.ID)
 
 	err := row.Scan(
 		&clinicBranch.ID,
@@ -80,6 +81,7 @@ func (c *clinicBranchRepo) Get(ctx context.Context, request models.PrimaryKey) (
 		&clinicBranch.Address,
 		&clinicBranch.Phone,
 		&clinicBranch.WorkingTime,
+		&clinicBranch.Version,
 		&clinicBranch.CreatedAt,
 		&updatedAt,
 	)
@@ -96,24 +98,31 @@ func (c *clinicBranchRepo) Get(ctx context.Context, request models.PrimaryKey) (
 	return clinicBranch, nil
 }
 
-func (c *clinicBranchRepo) GetList(ctx context.Context, request models.GetListRequest) (models.ClinicBranchsResponse, error) {
+// clinicBranchSortColumns are columns the clinic branch list can be sorted by, besides created_at
+var clinicBranchSortColumns = sortColumns{
+	"address": "text",
+}
+
+func (c *clinicBranchRepo) GetList(ctx context.Context, request models.GetClinicBranchsListRequest) (models.ClinicBranchsResponse, error) {
 
 	var (
 		updatedAt         = sql.NullTime{}
+		deletedAt         = sql.NullTime{}
 		clinicBranchs     = []models.ClinicBranch{}
 		count             = 0
 		query, countQuery string
-		page              = request.Page
-		offset            = (page - 1) * request.Limit
-		search            = request.Search
 	)
 
-	countQuery = `select count(1) from clinic_branch where deleted_at is null`
+	filter := newListFilter(request.GetListRequest, "address", "phone")
+	filter.Equal("clinic_id", request.ClinicID)
 
-	if search != "" {
-		countQuery += fmt.Sprintf(` and (address ilike '%%%s%%' or phone ilike '%%%s%%')`, search, search)
+	if err := filter.Sort(request.GetListRequest, clinicBranchSortColumns); err != nil {
+		return models.ClinicBranchsResponse{}, err
 	}
-	if err := c.pool.QueryRow(ctx, countQuery).Scan(&count); err != nil {
+
+	countQuery = `select count(1) from clinic_branch` + filter.Where()
+
+	if err := conn(ctx, c.pool).QueryRow(ctx, countQuery, filter.Args()...).Scan(&count); err != nil {
 		fmt.Println("error is while selecting count", err.Error())
 		return models.ClinicBranchsResponse{}, err
 	}
@@ -124,15 +133,15 @@ func (c *clinicBranchRepo) GetList(ctx context.Context, request models.GetListRe
 	 address,
 	 phone,
 	 working_time,
+	 version,
 	 created_at, 
-	 updated_at from clinic_branch where deleted_at is null`
+	 updated_at,
+	 deleted_at from clinic_branch`
 
-	if search != "" {
-		query += fmt.Sprintf(` and (address ilike '%%%s%%' or phone ilike '%%%s%%')`, search, search)
-	}
+	query += filter.Where()
 
-	query += ` LIMIT $1 OFFSET $2`
-	rows, err := c.pool.Query(ctx, query, request.Limit, offset)
+	pagination, args := filter.Page(request.Page, request.Limit)
+	rows, err := conn(ctx, c.pool).Query(ctx, query+pagination, args...)
 	if err != nil {
 		fmt.Println("error is while selecting clinic branch", err.Error())
 		return models.ClinicBranchsResponse{}, err
@@ -146,8 +155,10 @@ func (c *clinicBranchRepo) GetList(ctx context.Context, request models.GetListRe
 			&clinicBranch.Address,
 			&clinicBranch.Phone,
 			&clinicBranch.WorkingTime,
+			&clinicBranch.Version,
 			&clinicBranch.CreatedAt,
 			&updatedAt,
+			&deletedAt,
 		); err != nil {
 			fmt.Println("error is while scanning clinic branch data", err.Error())
 			return models.ClinicBranchsResponse{}, err
@@ -157,6 +168,10 @@ func (c *clinicBranchRepo) GetList(ctx context.Context, request models.GetListRe
 			clinicBranch.UpdatedAt = updatedAt.Time
 		}
 
+		if deletedAt.Valid {
+			clinicBranch.DeletedAt = deletedAt.Time
+		}
+
 		clinicBranchs = append(clinicBranchs, clinicBranch)
 
 	}
@@ -174,50 +189,37 @@ func (c *clinicBranchRepo) Update(ctx context.Context, request models.UpdateClin
 	address = $2,
     phone = $3,
 	working_time = $4,
+    version = version + 1,
     updated_at = $5 
-	 where id = $6  
+	 where id = $6 and deleted_at is null and ($7 = 0 or version = $7)
    `
 
-	rowsAffected, err := c.pool.Exec(ctx, query,
+	rowsAffected, err := conn(ctx, c.pool).Exec(ctx, query,
 		request.ClinicID,
 		request.Address,
 		request.Phone,
 		request.WorkingTime,
 		time.Now(),
-		request.ID)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return "", err
-	}
+		request.ID,
+		request.Version)
 
 	if err != nil {
 		log.Println("error while updating clinic branch data...", err.Error())
 		return "", err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return "", missedUpdate(ctx, conn(ctx, c.pool), "clinic_branch", request.ID, request.Version)
+	}
+
 	return request.ID, nil
 }
 
-func (c *clinicBranchRepo) Delete(ctx context.Context, id string) error {
-
-	query := `
-	update clinic_branch
-	 set deleted_at = $1
-	  where id = $2
-	`
-
-	rowsAffected, err := c.pool.Exec(ctx, query, time.Now(), id)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return err
-	}
-
-	if err != nil {
-		log.Println("error while deleting clinic branch by id", err.Error())
-		return err
-	}
+// Delete soft deletes the clinic branch with its doctor types and their doctors
+func (c *clinicBranchRepo) Delete(ctx context.Context, id string) ([]storage.ChildRecord, error) {
+	return softDelete(ctx, conn(ctx, c.pool), "clinic_branch", id)
+}
 
-	return nil
+func (c *clinicBranchRepo) Restore(ctx context.Context, id string) ([]storage.ChildRecord, error) {
+	return restoreWithChildren(ctx, conn(ctx, c.pool), "clinic_branch", id)
 }
@@ -6,11 +6,12 @@ import (
 	"fmt"
 	"log"
 	"shifolink/api/models"
-	"shifolink/pkg/check"
+	"shifolink/pkg/security"
 	"shifolink/storage"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -28,31 +29,36 @@ func (c *customerRepo) Create(ctx context.Context, request models.CreateCustomer
 
 	id := uuid.New()
 
+	hashedPassword, err := security.HashPassword(request.Password)
+	if err != nil {
+		log.Println("error while hashing customer password", err.Error())
+		return "", err
+	}
+
 	query := `insert into customer (id, first_name, last_name, email, password, phone, gender, birth_date, age, address) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
 
-	rowsAffected, err := c.pool.Exec(ctx, query,
+	rowsAffected, err := conn(ctx, c.pool).Exec(ctx, query,
 		id,
 		request.FirstName,
 		request.LastName,
 		request.Email,
-		request.Password,
+		hashedPassword,
 		request.Phone,
 		request.Gender,
 		request.BirthDate,
-		check.CalculateAge(request.BirthDate),
+		request.Age,
 		request.Address,
 	)
 
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return "", err
-	}
-
 	if err != nil {
 		log.Println("error while inserting customer", err.Error())
 		return "", err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return "", pgx.ErrNoRows
+	}
+
 	return id.String(), nil
 
 }
@@ -68,29 +74,29 @@ func (c *customerRepo) Get(ctx context.Context, request models.PrimaryKey) (mode
 	 first_name, 
 	 last_name, 
 	 email, 
-	 password, 
 	 phone, 
 	 gender, 
 	 birth_date::text, 
 	 age, 
 	 address, 
+	 version,
 	 created_at, 
 	 updated_at 
 	 from customer where deleted_at is null and id = $1`
 
-	row := c.pool.QueryRow(ctx, query, request.ID)
+	row := conn(ctx, c.pool).QueryRow(ctx, query, request.ID)
 
 	err := row.Scan(
 		&customer.ID,
 		&customer.FirstName,
 		&customer.LastName,
 		&customer.Email,
-		&customer.Password,
 		&customer.Phone,
 		&customer.Gender,
 		&customer.BirthDate,
 		&customer.Age,
 		&customer.Address,
+		&customer.Version,
 		&customer.CreatedAt,
 		&updatedAt,
 	)
@@ -108,24 +114,32 @@ func (c *customerRepo) Get(ctx context.Context, request models.PrimaryKey) (mode
 
 }
 
+// customerSortColumns are columns the customer list can be sorted by, besides created_at
+var customerSortColumns = sortColumns{
+	"first_name": "text",
+	"last_name":  "text",
+	"birth_date": "date",
+}
+
 func (c *customerRepo) GetList(ctx context.Context, request models.GetListRequest) (models.CustomersResponse, error) {
 
 	var (
 		updatedAt         = sql.NullTime{}
+		deletedAt         = sql.NullTime{}
 		customers         = []models.Customer{}
 		count             = 0
 		query, countQuery string
-		page              = request.Page
-		offset            = (page - 1) * request.Limit
-		search            = request.Search
 	)
 
-	countQuery = `select count(1) from customer where deleted_at is null`
+	filter := newListFilter(request, "first_name", "last_name")
 
-	if search != "" {
-		countQuery += fmt.Sprintf(` and (first_name ilike '%%%s%%' or last_name ilike '%%%s%%')`, search, search)
+	if err := filter.Sort(request, customerSortColumns); err != nil {
+		return models.CustomersResponse{}, err
 	}
-	if err := c.pool.QueryRow(ctx, countQuery).Scan(&count); err != nil {
+
+	countQuery = `select count(1) from customer` + filter.Where()
+
+	if err := conn(ctx, c.pool).QueryRow(ctx, countQuery, filter.Args()...).Scan(&count); err != nil {
 		fmt.Println("error is while selecting count", err.Error())
 		return models.CustomersResponse{}, err
 	}
@@ -135,21 +149,20 @@ func (c *customerRepo) GetList(ctx context.Context, request models.GetListReques
 	 first_name, 
 	 last_name, 
 	 email, 
-	 password, 
 	 phone, 
 	 gender, 
 	 birth_date::text, 
 	 age, 
 	 address, 
+	 version,
 	 created_at, 
-	 updated_at from customer where deleted_at is null`
+	 updated_at,
+	 deleted_at from customer`
 
-	if search != "" {
-		query += fmt.Sprintf(` and (first_name ilike '%%%s%%' or last_name ilike '%%%s%%')`, search, search)
-	}
+	query += filter.Where()
 
-	query += ` LIMIT $1 OFFSET $2`
-	rows, err := c.pool.Query(ctx, query, request.Limit, offset)
+	pagination, args := filter.Page(request.Page, request.Limit)
+	rows, err := conn(ctx, c.pool).Query(ctx, query+pagination, args...)
 	if err != nil {
 		fmt.Println("error is while selecting customer", err.Error())
 		return models.CustomersResponse{}, err
@@ -162,14 +175,15 @@ func (c *customerRepo) GetList(ctx context.Context, request models.GetListReques
 			&customer.FirstName,
 			&customer.LastName,
 			&customer.Email,
-			&customer.Password,
 			&customer.Phone,
 			&customer.Gender,
 			&customer.BirthDate,
 			&customer.Age,
 			&customer.Address,
+			&customer.Version,
 			&customer.CreatedAt,
 			&updatedAt,
+			&deletedAt,
 		); err != nil {
 			fmt.Println("error is while scanning customer data", err.Error())
 			return models.CustomersResponse{}, err
@@ -179,6 +193,10 @@ func (c *customerRepo) GetList(ctx context.Context, request models.GetListReques
 			customer.UpdatedAt = updatedAt.Time
 		}
 
+		if deletedAt.Valid {
+			customer.DeletedAt = deletedAt.Time
+		}
+
 		customers = append(customers, customer)
 
 	}
@@ -197,29 +215,30 @@ func (c *customerRepo) Update(ctx context.Context, request models.UpdateCustomer
 	email = $3,
 	phone = $4,
     address = $5,
+	version = version + 1,
 	updated_at = $6
-   where id = $7  
+   where id = $7 and deleted_at is null and ($8 = 0 or version = $8)
    `
 
-	rowsAffected, err := c.pool.Exec(ctx, query,
+	rowsAffected, err := conn(ctx, c.pool).Exec(ctx, query,
 		request.FirstName,
 		request.LastName,
 		request.Email,
 		request.Phone,
 		request.Address,
 		time.Now(),
-		request.ID)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return "", err
-	}
+		request.ID,
+		request.Version)
 
 	if err != nil {
 		log.Println("error while updating customer data...", err.Error())
 		return "", err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return "", missedUpdate(ctx, conn(ctx, c.pool), "customer", request.ID, request.Version)
+	}
+
 	return request.ID, nil
 
 }
@@ -229,21 +248,20 @@ func (c *customerRepo) Delete(ctx context.Context, id string) error {
 	query := `
 	update customer
 	 set deleted_at = $1
-	  where id = $2
+	  where id = $2 and deleted_at is null
 	`
 
-	rowsAffected, err := c.pool.Exec(ctx, query, time.Now(), id)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return err
-	}
+	rowsAffected, err := conn(ctx, c.pool).Exec(ctx, query, time.Now(), id)
 
 	if err != nil {
 		log.Println("error while deleting customer by id", err.Error())
 		return err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
 	return nil
 }
 
@@ -252,9 +270,9 @@ func (c *customerRepo) GetPassword(ctx context.Context, id string) (string, erro
 
 	query := `
 		select password from customer 
-						where id = $1`
+						where id = $1 and deleted_at is null`
 
-	if err := c.pool.QueryRow(ctx, query, id).Scan(&password); err != nil {
+	if err := conn(ctx, c.pool).QueryRow(ctx, query, id).Scan(&password); err != nil {
 		fmt.Println("Error while scanning password from customer", err.Error())
 		return "", err
 	}
@@ -264,22 +282,43 @@ func (c *customerRepo) GetPassword(ctx context.Context, id string) (string, erro
 
 func (c *customerRepo) UpdatePassword(ctx context.Context, request models.UpdateCustomerPassword) error {
 
+	hashedPassword, err := security.HashPassword(request.NewPassword)
+	if err != nil {
+		log.Println("error while hashing customer password", err.Error())
+		return err
+	}
+
 	query := `
 		update customer
 				set password = $1, updated_at = now()
-					where id = $2`
+					where id = $2 and deleted_at is null`
 
-	rowsAffected, err := c.pool.Exec(ctx, query, request.NewPassword, request.ID)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return err
-	}
+	rowsAffected, err := conn(ctx, c.pool).Exec(ctx, query, hashedPassword, request.ID)
 
 	if err != nil {
 		fmt.Println("error while updating password for customer", err.Error())
 		return err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
 	return nil
 }
+
+func (c *customerRepo) GetByLogin(ctx context.Context, login string) (models.Customer, error) {
+	id, err := loginID(ctx, conn(ctx, c.pool), "customer", login)
+	if err != nil {
+		fmt.Println("error while selecting customer by login", err.Error())
+		return models.Customer{}, err
+	}
+
+	return c.Get(ctx, models.PrimaryKey{
+		ID: id,
+	})
+}
+
+func (c *customerRepo) Restore(ctx context.Context, id string) error {
+	return restore(ctx, conn(ctx, c.pool), "customer", id)
+}
@@ -6,11 +6,12 @@ import (
 	"fmt"
 	"log"
 	"shifolink/api/models"
-	"shifolink/pkg/check"
+	"shifolink/pkg/security"
 	"shifolink/storage"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -28,6 +29,12 @@ func (d *doctorRepo) Create(ctx context.Context, request models.CreateDoctor) (s
 
 	id := uuid.New()
 
+	hashedPassword, err := security.HashPassword(request.Password)
+	if err != nil {
+		log.Println("error while hashing doctor password", err.Error())
+		return "", err
+	}
+
 	query := `insert into doctor (
 		id, 
 		doctor_type_id, 
@@ -41,34 +48,33 @@ func (d *doctorRepo) Create(ctx context.Context, request models.CreateDoctor) (s
 		age, 
 		address,
 		working_time,
-		status) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+		status) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, coalesce(nullif($13, ''), 'empty'))`
 
-	rowsAffected, err := d.pool.Exec(ctx, query,
+	rowsAffected, err := conn(ctx, d.pool).Exec(ctx, query,
 		id,
 		request.DoctorTypeID,
 		request.FirstName,
 		request.LastName,
 		request.Email,
-		request.Password,
+		hashedPassword,
 		request.Phone,
 		request.Gender,
 		request.BirthDate,
-		check.CalculateAge(request.BirthDate),
+		request.Age,
 		request.Address,
 		request.WorkingTime,
 		request.Status,
 	)
 
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return "", err
-	}
-
 	if err != nil {
 		log.Println("error while inserting doctor", err.Error())
 		return "", err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return "", pgx.ErrNoRows
+	}
+
 	return id.String(), nil
 }
 
@@ -84,7 +90,6 @@ func (d *doctorRepo) Get(ctx context.Context, request models.PrimaryKey) (models
 	 first_name, 
 	 last_name, 
 	 email, 
-	 password, 
 	 phone, 
 	 gender, 
 	 birth_date::text, 
@@ -92,11 +97,12 @@ func (d *doctorRepo) Get(ctx context.Context, request models.PrimaryKey) (models
 	 address, 
 	 working_time,
 	 status,
+	 version,
 	 created_at, 
 	 updated_at 
 	 from doctor where deleted_at is null and id = $1`
 
-	row := d.pool.QueryRow(ctx, query, request.ID)
+	row := conn(ctx, d.pool).QueryRow(ctx, query, request.ID)
 
 	err := row.Scan(
 		&doctor.ID,
@@ -104,7 +110,6 @@ func (d *doctorRepo) Get(ctx context.Context, request models.PrimaryKey) (models
 		&doctor.FirstName,
 		&doctor.LastName,
 		&doctor.Email,
-		&doctor.Password,
 		&doctor.Phone,
 		&doctor.Gender,
 		&doctor.BirthDate,
@@ -112,6 +117,7 @@ func (d *doctorRepo) Get(ctx context.Context, request models.PrimaryKey) (models
 		&doctor.Address,
 		&doctor.WorkingTime,
 		&doctor.Status,
+		&doctor.Version,
 		&doctor.CreatedAt,
 		&updatedAt,
 	)
@@ -129,24 +135,35 @@ func (d *doctorRepo) Get(ctx context.Context, request models.PrimaryKey) (models
 
 }
 
-func (d *doctorRepo) GetList(ctx context.Context, request models.GetListRequest) (models.DoctorsResponse, error) {
+// doctorSortColumns are columns the doctor list can be sorted by, besides created_at
+var doctorSortColumns = sortColumns{
+	"first_name": "text",
+	"last_name":  "text",
+	"status":     "text",
+}
+
+func (d *doctorRepo) GetList(ctx context.Context, request models.GetDoctorsListRequest) (models.DoctorsResponse, error) {
 
 	var (
 		updatedAt         = sql.NullTime{}
+		deletedAt         = sql.NullTime{}
 		doctors           = []models.Doctor{}
 		count             = 0
 		query, countQuery string
-		page              = request.Page
-		offset            = (page - 1) * request.Limit
-		search            = request.Search
 	)
 
-	countQuery = `select count(1) from doctor where deleted_at is null`
+	filter := newListFilter(request.GetListRequest, "first_name", "last_name")
+	filter.Equal("doctor_type_id", request.DoctorTypeID)
+	filter.Filter("doctor_type_id in (select id from doctor_type where clinic_branch_id = %s)", request.ClinicBranchID)
+	filter.Equal("status", request.Status)
 
-	if search != "" {
-		countQuery += fmt.Sprintf(` and (first_name ilike '%%%s%%' or last_name ilike '%%%s%%')`, search, search)
+	if err := filter.Sort(request.GetListRequest, doctorSortColumns); err != nil {
+		return models.DoctorsResponse{}, err
 	}
-	if err := d.pool.QueryRow(ctx, countQuery).Scan(&count); err != nil {
+
+	countQuery = `select count(1) from doctor` + filter.Where()
+
+	if err := conn(ctx, d.pool).QueryRow(ctx, countQuery, filter.Args()...).Scan(&count); err != nil {
 		fmt.Println("error is while selecting count", err.Error())
 		return models.DoctorsResponse{}, err
 	}
@@ -157,7 +174,6 @@ func (d *doctorRepo) GetList(ctx context.Context, request models.GetListRequest)
 	 first_name, 
 	 last_name, 
 	 email, 
-	 password, 
 	 phone, 
 	 gender, 
 	 birth_date::text, 
@@ -165,15 +181,15 @@ func (d *doctorRepo) GetList(ctx context.Context, request models.GetListRequest)
 	 address, 
 	 working_time,
 	 status,
+	 version,
 	 created_at, 
-	 updated_at from doctor where deleted_at is null`
+	 updated_at,
+	 deleted_at from doctor`
 
-	if search != "" {
-		query += fmt.Sprintf(` and (first_name ilike '%%%s%%' or last_name ilike '%%%s%%')`, search, search)
-	}
+	query += filter.Where()
 
-	query += ` LIMIT $1 OFFSET $2`
-	rows, err := d.pool.Query(ctx, query, request.Limit, offset)
+	pagination, args := filter.Page(request.Page, request.Limit)
+	rows, err := conn(ctx, d.pool).Query(ctx, query+pagination, args...)
 	if err != nil {
 		fmt.Println("error is while selecting doctor", err.Error())
 		return models.DoctorsResponse{}, err
@@ -187,7 +203,6 @@ func (d *doctorRepo) GetList(ctx context.Context, request models.GetListRequest)
 			&doctor.FirstName,
 			&doctor.LastName,
 			&doctor.Email,
-			&doctor.Password,
 			&doctor.Phone,
 			&doctor.Gender,
 			&doctor.BirthDate,
@@ -195,8 +210,10 @@ func (d *doctorRepo) GetList(ctx context.Context, request models.GetListRequest)
 			&doctor.Address,
 			&doctor.WorkingTime,
 			&doctor.Status,
+			&doctor.Version,
 			&doctor.CreatedAt,
 			&updatedAt,
+			&deletedAt,
 		); err != nil {
 			fmt.Println("error is while scanning doctor data", err.Error())
 			return models.DoctorsResponse{}, err
@@ -206,6 +223,10 @@ func (d *doctorRepo) GetList(ctx context.Context, request models.GetListRequest)
 			doctor.UpdatedAt = updatedAt.Time
 		}
 
+		if deletedAt.Valid {
+			doctor.DeletedAt = deletedAt.Time
+		}
+
 		doctors = append(doctors, doctor)
 	}
 
@@ -225,12 +246,13 @@ func (d *doctorRepo) Update(ctx context.Context, request models.UpdateDoctor) (s
 	phone = $5,
     address = $6,
 	working_time = $7,
-	status = $8,
+	status = coalesce(nullif($8, ''), status),
+	version = version + 1,
 	updated_at = $9
-   where id = $10
+   where id = $10 and deleted_at is null and ($11 = 0 or version = $11)
    `
 
-	rowsAffected, err := d.pool.Exec(ctx, query,
+	rowsAffected, err := conn(ctx, d.pool).Exec(ctx, query,
 		request.DoctorTypeID,
 		request.FirstName,
 		request.LastName,
@@ -240,18 +262,18 @@ func (d *doctorRepo) Update(ctx context.Context, request models.UpdateDoctor) (s
 		request.WorkingTime,
 		request.Status,
 		time.Now(),
-		request.ID)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return "", err
-	}
+		request.ID,
+		request.Version)
 
 	if err != nil {
 		log.Println("error while updating doctor data...", err.Error())
 		return "", err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return "", missedUpdate(ctx, conn(ctx, d.pool), "doctor", request.ID, request.Version)
+	}
+
 	return request.ID, nil
 
 }
@@ -261,21 +283,20 @@ func (d *doctorRepo) Delete(ctx context.Context, id string) error {
 	query := `
 	update doctor
 	 set deleted_at = $1
-	  where id = $2
+	  where id = $2 and deleted_at is null
 	`
 
-	rowsAffected, err := d.pool.Exec(ctx, query, time.Now(), id)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return err
-	}
+	rowsAffected, err := conn(ctx, d.pool).Exec(ctx, query, time.Now(), id)
 
 	if err != nil {
 		log.Println("error while deleting doctor by id", err.Error())
 		return err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
 	return nil
 }
 
@@ -285,9 +306,9 @@ func (c *doctorRepo) GetPassword(ctx context.Context, id string) (string, error)
 
 	query := `
 		select password from doctor 
-						where id = $1`
+						where id = $1 and deleted_at is null`
 
-	if err := c.pool.QueryRow(ctx, query, id).Scan(&password); err != nil {
+	if err := conn(ctx, c.pool).QueryRow(ctx, query, id).Scan(&password); err != nil {
 		fmt.Println("Error while scanning password from doctor", err.Error())
 		return "", err
 	}
@@ -298,22 +319,43 @@ func (c *doctorRepo) GetPassword(ctx context.Context, id string) (string, error)
 
 func (d *doctorRepo) UpdatePassword(ctx context.Context, request models.UpdateDoctorPassword) error {
 
+	hashedPassword, err := security.HashPassword(request.NewPassword)
+	if err != nil {
+		log.Println("error while hashing doctor password", err.Error())
+		return err
+	}
+
 	query := `
 		update doctor
 				set password = $1, updated_at = now()
-					where id = $2`
+					where id = $2 and deleted_at is null`
 
-	rowsAffected, err := d.pool.Exec(ctx, query, request.NewPassword, request.ID)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return err
-	}
+	rowsAffected, err := conn(ctx, d.pool).Exec(ctx, query, hashedPassword, request.ID)
 
 	if err != nil {
 		fmt.Println("error while updating password for doctor", err.Error())
 		return err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
 	return nil
 }
+
+func (c *doctorRepo) GetByLogin(ctx context.Context, login string) (models.Doctor, error) {
+	id, err := loginID(ctx, conn(ctx, c.pool), "doctor", login)
+	if err != nil {
+		fmt.Println("error while selecting doctor by login", err.Error())
+		return models.Doctor{}, err
+	}
+
+	return c.Get(ctx, models.PrimaryKey{
+		ID: id,
+	})
+}
+
+func (d *doctorRepo) Restore(ctx context.Context, id string) error {
+	return restore(ctx, conn(ctx, d.pool), "doctor", id)
+}
@@ -0,0 +1,220 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"shifolink/api/models"
+	"shifolink/storage"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type doctorScheduleRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewDoctorScheduleRepo(pool *pgxpool.Pool) storage.IDoctorScheduleRepo {
+	return &doctorScheduleRepo{
+		pool: pool,
+	}
+}
+
+const doctorScheduleColumns = `
+	 id,
+	 doctor_id,
+	 weekday,
+	 to_char(start_time, 'HH24:MI'),
+	 to_char(end_time, 'HH24:MI'),
+	 coalesce(to_char(break_start, 'HH24:MI'), ''),
+	 coalesce(to_char(break_end, 'HH24:MI'), ''),
+	 slot_duration,
+	 version,
+	 created_at,
+	 updated_at`
+
+func scanDoctorSchedule(row pgx.Row) (models.DoctorSchedule, error) {
+
+	var updatedAt = sql.NullTime{}
+
+	schedule := models.DoctorSchedule{}
+
+	if err := row.Scan(
+		&schedule.ID,
+		&schedule.DoctorID,
+		&schedule.Weekday,
+		&schedule.StartTime,
+		&schedule.EndTime,
+		&schedule.BreakStart,
+		&schedule.BreakEnd,
+		&schedule.SlotDuration,
+		&schedule.Version,
+		&schedule.CreatedAt,
+		&updatedAt,
+	); err != nil {
+		return models.DoctorSchedule{}, err
+	}
+
+	if updatedAt.Valid {
+		schedule.UpdatedAt = updatedAt.Time
+	}
+
+	return schedule, nil
+}
+
+func (d *doctorScheduleRepo) Create(ctx context.Context, request models.CreateDoctorSchedule) (string, error) {
+
+	id := uuid.New()
+
+	query := `insert into doctor_schedule
+	 (id,
+	  doctor_id,
+	  weekday,
+	  start_time,
+	  end_time,
+	  break_start,
+	  break_end,
+	  slot_duration)
+	  values ($1, $2, $3, $4, $5, nullif($6, '')::time, nullif($7, '')::time, $8)`
+
+	if _, err := conn(ctx, d.pool).Exec(ctx, query,
+		id,
+		request.DoctorID,
+		request.Weekday,
+		request.StartTime,
+		request.EndTime,
+		request.BreakStart,
+		request.BreakEnd,
+		request.SlotDuration,
+	); err != nil {
+		log.Println("error while inserting doctor schedule", err.Error())
+		return "", err
+	}
+
+	return id.String(), nil
+}
+
+func (d *doctorScheduleRepo) Get(ctx context.Context, request models.PrimaryKey) (models.DoctorSchedule, error) {
+
+	query := `select ` + doctorScheduleColumns + `
+	 from doctor_schedule where deleted_at is null and id = $1`
+
+	schedule, err := scanDoctorSchedule(conn(ctx, d.pool).QueryRow(ctx, query, request.ID))
+	if err != nil {
+		log.Println("error while selecting doctor schedule", err.Error())
+		return models.DoctorSchedule{}, err
+	}
+
+	return schedule, nil
+}
+
+func (d *doctorScheduleRepo) GetByDoctor(ctx context.Context, doctorID string) (models.DoctorSchedulesResponse, error) {
+
+	schedules := []models.DoctorSchedule{}
+
+	query := `select ` + doctorScheduleColumns + `
+	 from doctor_schedule where deleted_at is null and doctor_id = $1 order by weekday`
+
+	rows, err := conn(ctx, d.pool).Query(ctx, query, doctorID)
+	if err != nil {
+		fmt.Println("error is while selecting doctor schedules", err.Error())
+		return models.DoctorSchedulesResponse{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		schedule, err := scanDoctorSchedule(rows)
+		if err != nil {
+			fmt.Println("error is while scanning doctor schedule data", err.Error())
+			return models.DoctorSchedulesResponse{}, err
+		}
+
+		schedules = append(schedules, schedule)
+	}
+
+	return models.DoctorSchedulesResponse{
+		DoctorSchedules: schedules,
+		Count:           len(schedules),
+	}, nil
+}
+
+func (d *doctorScheduleRepo) GetByWeekday(ctx context.Context, doctorID string, weekday int) (models.DoctorSchedule, error) {
+	return getDoctorScheduleByWeekday(ctx, conn(ctx, d.pool), doctorID, weekday)
+}
+
+func (d *doctorScheduleRepo) Update(ctx context.Context, request models.UpdateDoctorSchedule) (string, error) {
+
+	query := `update doctor_schedule set
+	start_time = $1,
+	end_time = $2,
+	break_start = nullif($3, '')::time,
+	break_end = nullif($4, '')::time,
+	slot_duration = $5,
+	version = version + 1,
+	updated_at = $6
+	 where id = $7 and deleted_at is null and ($8 = 0 or version = $8)
+   `
+
+	rowsAffected, err := conn(ctx, d.pool).Exec(ctx, query,
+		request.StartTime,
+		request.EndTime,
+		request.BreakStart,
+		request.BreakEnd,
+		request.SlotDuration,
+		time.Now(),
+		request.ID,
+		request.Version)
+	if err != nil {
+		log.Println("error while updating doctor schedule data...", err.Error())
+		return "", err
+	}
+
+	if rowsAffected.RowsAffected() == 0 {
+		return "", missedUpdate(ctx, conn(ctx, d.pool), "doctor_schedule", request.ID, request.Version)
+	}
+
+	return request.ID, nil
+}
+
+func (d *doctorScheduleRepo) Delete(ctx context.Context, id string) error {
+
+	query := `
+	update doctor_schedule
+	 set deleted_at = $1
+	  where id = $2 and deleted_at is null
+	`
+
+	rowsAffected, err := conn(ctx, d.pool).Exec(ctx, query, time.Now(), id)
+	if err != nil {
+		log.Println("error while deleting doctor schedule by id", err.Error())
+		return err
+	}
+
+	if rowsAffected.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// getDoctorScheduleByWeekday is shared with queueRepo which reads the schedule inside its booking transaction
+func getDoctorScheduleByWeekday(ctx context.Context, q rowQuerier, doctorID string, weekday int) (models.DoctorSchedule, error) {
+
+	query := `select ` + doctorScheduleColumns + `
+	 from doctor_schedule where deleted_at is null and doctor_id = $1 and weekday = $2`
+
+	schedule, err := scanDoctorSchedule(q.QueryRow(ctx, query, doctorID, weekday))
+	if err != nil {
+		return models.DoctorSchedule{}, err
+	}
+
+	return schedule, nil
+}
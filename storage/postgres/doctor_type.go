@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -34,23 +35,22 @@ func (d *doctorTypeRepo) Create(ctx context.Context, request models.CreateDoctor
 	  clinic_branch_id ) 
 	  values ($1, $2, $3, $4)`
 
-	rowsAffected, err := d.pool.Exec(ctx, query,
+	rowsAffected, err := conn(ctx, d.pool).Exec(ctx, query,
 		id,
 		request.Name,
 		request.Description,
 		request.ClinicBranchID,
 	)
 
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return "", err
-	}
-
 	if err != nil {
 		log.Println("error while inserting doctor type", err.Error())
 		return "", err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return "", pgx.ErrNoRows
+	}
+
 	return id.String(), nil
 
 }
@@ -66,17 +66,19 @@ func (d *doctorTypeRepo) Get(ctx context.Context, request models.PrimaryKey) (mo
 	 name,
 	 description,
 	 clinic_branch_id,
+	 version,
 	 created_at,
 	 updated_at
 	 from doctor_type where deleted_at is null and id = $1`
 
-	row := d.pool.QueryRow(ctx, query, request.ID)
+	row := conn(ctx, d.pool).QueryRow(ctx, query, request.ID)
 
 	err := row.Scan(
 		&doctorType.ID,
 		&doctorType.Name,
 		&doctorType.Description,
 		&doctorType.ClinicBranchID,
+		&doctorType.Version,
 		&doctorType.CreatedAt,
 		&updatedAt,
 	)
@@ -93,24 +95,31 @@ func (d *doctorTypeRepo) Get(ctx context.Context, request models.PrimaryKey) (mo
 	return doctorType, nil
 }
 
-func (d *doctorTypeRepo) GetList(ctx context.Context, request models.GetListRequest) (models.DoctorTypesResponse, error) {
+// doctorTypeSortColumns are columns the doctor type list can be sorted by, besides created_at
+var doctorTypeSortColumns = sortColumns{
+	"name": "text",
+}
+
+func (d *doctorTypeRepo) GetList(ctx context.Context, request models.GetDoctorTypesListRequest) (models.DoctorTypesResponse, error) {
 
 	var (
 		updatedAt         = sql.NullTime{}
+		deletedAt         = sql.NullTime{}
 		doctorTypes       = []models.DoctorType{}
 		count             = 0
 		query, countQuery string
-		page              = request.Page
-		offset            = (page - 1) * request.Limit
-		search            = request.Search
 	)
 
-	countQuery = `select count(1) from doctor_type where deleted_at is null`
+	filter := newListFilter(request.GetListRequest, "name")
+	filter.Equal("clinic_branch_id", request.ClinicBranchID)
 
-	if search != "" {
-		countQuery += fmt.Sprintf(` and (name ilike '%%%s%%')`, search)
+	if err := filter.Sort(request.GetListRequest, doctorTypeSortColumns); err != nil {
+		return models.DoctorTypesResponse{}, err
 	}
-	if err := d.pool.QueryRow(ctx, countQuery).Scan(&count); err != nil {
+
+	countQuery = `select count(1) from doctor_type` + filter.Where()
+
+	if err := conn(ctx, d.pool).QueryRow(ctx, countQuery, filter.Args()...).Scan(&count); err != nil {
 		fmt.Println("error is while selecting count", err.Error())
 		return models.DoctorTypesResponse{}, err
 	}
@@ -120,15 +129,15 @@ func (d *doctorTypeRepo) GetList(ctx context.Context, request models.GetListRequ
 	 name,
 	 description,
 	 clinic_branch_id,
+	 version,
 	 created_at, 
-	 updated_at from doctor_type where deleted_at is null`
+	 updated_at,
+	 deleted_at from doctor_type`
 
-	if search != "" {
-		query += fmt.Sprintf(` and (name ilike '%%%s%%')`, search)
-	}
+	query += filter.Where()
 
-	query += ` LIMIT $1 OFFSET $2`
-	rows, err := d.pool.Query(ctx, query, request.Limit, offset)
+	pagination, args := filter.Page(request.Page, request.Limit)
+	rows, err := conn(ctx, d.pool).Query(ctx, query+pagination, args...)
 	if err != nil {
 		fmt.Println("error is while selecting doctor type ", err.Error())
 		return models.DoctorTypesResponse{}, err
@@ -141,8 +150,10 @@ func (d *doctorTypeRepo) GetList(ctx context.Context, request models.GetListRequ
 			&doctorType.Name,
 			&doctorType.Description,
 			&doctorType.ClinicBranchID,
+			&doctorType.Version,
 			&doctorType.CreatedAt,
 			&updatedAt,
+			&deletedAt,
 		); err != nil {
 			fmt.Println("error is while scanning doctor_type data", err.Error())
 			return models.DoctorTypesResponse{}, err
@@ -152,6 +163,10 @@ func (d *doctorTypeRepo) GetList(ctx context.Context, request models.GetListRequ
 			doctorType.UpdatedAt = updatedAt.Time
 		}
 
+		if deletedAt.Valid {
+			doctorType.DeletedAt = deletedAt.Time
+		}
+
 		doctorTypes = append(doctorTypes, doctorType)
 
 	}
@@ -168,50 +183,37 @@ func (d *doctorTypeRepo) Update(ctx context.Context, request models.UpdateDoctor
 	name = $1,
 	description = $2,
 	clinic_branch_id = $3,
+    version = version + 1,
     updated_at = $4 
-	 where id = $5  
+	 where id = $5 and deleted_at is null and ($6 = 0 or version = $6)
    `
 
-	rowsAffected, err := d.pool.Exec(ctx, query,
+	rowsAffected, err := conn(ctx, d.pool).Exec(ctx, query,
 		request.Name,
 		request.Description,
 		request.ClinicBranchID,
 		time.Now(),
-		request.ID)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return "", err
-	}
+		request.ID,
+		request.Version)
 
 	if err != nil {
 		log.Println("error while updating doctor type data...", err.Error())
 		return "", err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return "", missedUpdate(ctx, conn(ctx, d.pool), "doctor_type", request.ID, request.Version)
+	}
+
 	return request.ID, nil
 
 }
 
-func (d *doctorTypeRepo) Delete(ctx context.Context, id string) error {
-
-	query := `
-	update doctor_type
-	 set deleted_at = $1
-	  where id = $2
-	`
-
-	rowsAffected, err := d.pool.Exec(ctx, query, time.Now(), id)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return err
-	}
-
-	if err != nil {
-		log.Println("error while deleting doctor type by id", err.Error())
-		return err
-	}
+// Delete soft deletes the doctor type with its doctors
+func (d *doctorTypeRepo) Delete(ctx context.Context, id string) ([]storage.ChildRecord, error) {
+	return softDelete(ctx, conn(ctx, d.pool), "doctor_type", id)
+}
 
-	return nil
+func (d *doctorTypeRepo) Restore(ctx context.Context, id string) ([]storage.ChildRecord, error) {
+	return restoreWithChildren(ctx, conn(ctx, d.pool), "doctor_type", id)
 }
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -27,35 +28,71 @@ func (d *drugRepo) Create(ctx context.Context, request models.CreateDrug) (strin
 
 	id := uuid.New()
 
+	tx, err := conn(ctx, d.pool).Begin(ctx)
+	if err != nil {
+		log.Println("error while starting transaction", err.Error())
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	// name and prescription rule are the ones of the catalogue drug
 	query := `insert into drug
 	 (id, 
 	  drug_store_branch_id,
+	  drug_catalogue_id,
 	  name,
 	  description,
 	  count,
 	  price,
 	  date_of_manufacture,
-	  best_before) 
-	  values ($1, $2, $3, $4, $5, $6, $7, $8)`
+	  best_before,
+	  prescription_required) 
+	  select $1::uuid, $2::uuid, id, name, $4::text, 0, $5::numeric, nullif($6, '')::date, nullif($7, '')::date, prescription_required
+	  from drug_catalogue where id = $3 and deleted_at is null`
 
-	rowsAffected, err := d.pool.Exec(ctx, query,
+	rowsAffected, err := tx.Exec(ctx, query,
 		id,
 		request.DrugStoreBranchID,
-		request.Name,
+		request.DrugCatalogueID,
 		request.Description,
-		request.Count,
 		request.Price,
 		request.DateOfManufacture,
 		request.BestBefore,
 	)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
+	if err != nil {
+		log.Println("error while inserting drug ", err.Error())
 		return "", err
 	}
 
-	if err != nil {
-		log.Println("error while inserting drug ", err.Error())
+	if rowsAffected.RowsAffected() == 0 {
+		return "", pgx.ErrNoRows
+	}
+
+	// the count of a new drug becomes its first lot
+	if request.Count > 0 {
+		query = `insert into drug_lot
+		 (id,
+		  drug_id,
+		  drug_store_branch_id,
+		  lot_number,
+		  quantity,
+		  expiry_date,
+		  purchase_price)
+		  values ($1, $2, $3, 'INITIAL', $4, nullif($5, '')::date, $6)`
+
+		if _, err = tx.Exec(ctx, query, uuid.New(), id, request.DrugStoreBranchID, request.Count, request.BestBefore, request.Price); err != nil {
+			log.Println("error while inserting initial drug lot ", err.Error())
+			return "", err
+		}
+
+		if err = syncDrugCount(ctx, tx, id.String()); err != nil {
+			log.Println("error while updating drug count ", err.Error())
+			return "", err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Println("error while committing drug ", err.Error())
 		return "", err
 	}
 
@@ -72,27 +109,33 @@ func (d *drugRepo) Get(ctx context.Context, request models.PrimaryKey) (models.D
 	query := `select 
 	 id,
 	 drug_store_branch_id,
+	 drug_catalogue_id,
 	 name,
 	 description,
 	 count,
 	 price,
-	 date_of_manufacture,
-	 best_before,
+	 coalesce(to_char(date_of_manufacture, 'YYYY-MM-DD'), ''),
+	 coalesce(to_char(best_before, 'YYYY-MM-DD'), ''),
+	 prescription_required,
+	 version,
 	 created_at,
 	 updated_at
 	 from drug where deleted_at is null and id = $1`
 
-	row := d.pool.QueryRow(ctx, query, request.ID)
+	row := conn(ctx, d.pool).QueryRow(ctx, query, request.ID)
 
 	err := row.Scan(
 		&drug.ID,
 		&drug.DrugStoreBranchID,
+		&drug.DrugCatalogueID,
 		&drug.Name,
 		&drug.Description,
 		&drug.Count,
 		&drug.Price,
 		&drug.DateOfManufacture,
 		&drug.BestBefore,
+		&drug.PrescriptionRequired,
+		&drug.Version,
 		&drug.CreatedAt,
 		&updatedAt,
 	)
@@ -109,24 +152,38 @@ func (d *drugRepo) Get(ctx context.Context, request models.PrimaryKey) (models.D
 
 }
 
-func (d *drugRepo) GetList(ctx context.Context, request models.GetListRequest) (models.DrugsResponse, error) {
+// drugSortColumns are columns the drug list can be sorted by, besides created_at
+var drugSortColumns = sortColumns{
+	"name":        "text",
+	"price":       "numeric",
+	"count":       "int",
+	"best_before": "date",
+}
+
+func (d *drugRepo) GetList(ctx context.Context, request models.GetDrugsListRequest) (models.DrugsResponse, error) {
 
 	var (
 		updatedAt         = sql.NullTime{}
+		deletedAt         = sql.NullTime{}
 		drugs             = []models.Drug{}
 		count             = 0
 		query, countQuery string
-		page              = request.Page
-		offset            = (page - 1) * request.Limit
-		search            = request.Search
 	)
 
-	countQuery = `select count(1) from drug where deleted_at is null`
+	filter := newListFilter(request.GetListRequest, "name", "description")
+	filter.Equal("drug_store_branch_id", request.DrugStoreBranchID)
+	filter.Equal("drug_catalogue_id", request.DrugCatalogueID)
+	filter.Filter("price >= %s::numeric", request.MinPrice)
+	filter.Filter("price <= %s::numeric", request.MaxPrice)
+	filter.Filter("prescription_required = %s::boolean", request.PrescriptionRequired)
 
-	if search != "" {
-		countQuery += fmt.Sprintf(` and (name ilike '%%%s%%' or description ilike '%%%s%%')`, search, search)
+	if err := filter.Sort(request.GetListRequest, drugSortColumns); err != nil {
+		return models.DrugsResponse{}, err
 	}
-	if err := d.pool.QueryRow(ctx, countQuery).Scan(&count); err != nil {
+
+	countQuery = `select count(1) from drug` + filter.Where()
+
+	if err := conn(ctx, d.pool).QueryRow(ctx, countQuery, filter.Args()...).Scan(&count); err != nil {
 		fmt.Println("error is while selecting count", err.Error())
 		return models.DrugsResponse{}, err
 	}
@@ -134,22 +191,24 @@ func (d *drugRepo) GetList(ctx context.Context, request models.GetListRequest) (
 	query = `select 
 	 id,
 	 drug_store_branch_id,
+	 drug_catalogue_id,
 	 name,
 	 description,
 	 count,
 	 price,
-	 date_of_manufacture,
-	 best_before,
+	 coalesce(to_char(date_of_manufacture, 'YYYY-MM-DD'), ''),
+	 coalesce(to_char(best_before, 'YYYY-MM-DD'), ''),
+	 prescription_required,
+	 version,
 	 created_at,
-	 updated_at
-	 from drug where deleted_at is null`
+	 updated_at,
+	 deleted_at
+	 from drug`
 
-	if search != "" {
-		query += fmt.Sprintf(` and (name ilike '%%%s%%' or description ilike '%%%s%%')`, search, search)
-	}
+	query += filter.Where()
 
-	query += ` LIMIT $1 OFFSET $2`
-	rows, err := d.pool.Query(ctx, query, request.Limit, offset)
+	pagination, args := filter.Page(request.Page, request.Limit)
+	rows, err := conn(ctx, d.pool).Query(ctx, query+pagination, args...)
 	if err != nil {
 		fmt.Println("error is while selecting drug ", err.Error())
 		return models.DrugsResponse{}, err
@@ -160,14 +219,18 @@ func (d *drugRepo) GetList(ctx context.Context, request models.GetListRequest) (
 		if err = rows.Scan(
 			&drug.ID,
 			&drug.DrugStoreBranchID,
+			&drug.DrugCatalogueID,
 			&drug.Name,
 			&drug.Description,
 			&drug.Count,
 			&drug.Price,
 			&drug.DateOfManufacture,
 			&drug.BestBefore,
+			&drug.PrescriptionRequired,
+			&drug.Version,
 			&drug.CreatedAt,
 			&updatedAt,
+			&deletedAt,
 		); err != nil {
 			fmt.Println("error is while scanning drug data", err.Error())
 			return models.DrugsResponse{}, err
@@ -177,6 +240,10 @@ func (d *drugRepo) GetList(ctx context.Context, request models.GetListRequest) (
 			drug.UpdatedAt = updatedAt.Time
 		}
 
+		if deletedAt.Valid {
+			drug.DeletedAt = deletedAt.Time
+		}
+
 		drugs = append(drugs, drug)
 
 	}
@@ -190,34 +257,33 @@ func (d *drugRepo) GetList(ctx context.Context, request models.GetListRequest) (
 func (d *drugRepo) Update(ctx context.Context, request models.UpdateDrug) (string, error) {
 
 	query := `update drug set
-	drug_store_branch_id = $1,
-	name = $2,
-	description = $3,
-	count = $4,
-	price = $5,
-    updated_at = $6 
-	 where id = $7  
+	description = $1,
+	price = $2,
+	date_of_manufacture = nullif($3, '')::date,
+	best_before = nullif($4, '')::date,
+    version = version + 1,
+    updated_at = $5 
+	 where id = $6 and deleted_at is null and ($7 = 0 or version = $7)
    `
 
-	rowsAffected, err := d.pool.Exec(ctx, query,
-		request.DrugStoreBranchID,
-		request.Name,
+	rowsAffected, err := conn(ctx, d.pool).Exec(ctx, query,
 		request.Description,
-		request.Count,
-		request.Count,
+		request.Price,
+		request.DateOfManufacture,
+		request.BestBefore,
 		time.Now(),
-		request.ID)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return "", err
-	}
+		request.ID,
+		request.Version)
 
 	if err != nil {
 		log.Println("error while updating drug data...", err.Error())
 		return "", err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return "", missedUpdate(ctx, conn(ctx, d.pool), "drug", request.ID, request.Version)
+	}
+
 	return request.ID, nil
 
 }
@@ -227,21 +293,24 @@ func (d *drugRepo) Delete(ctx context.Context, id string) error {
 	query := `
 	update drug
 	 set deleted_at = $1
-	  where id = $2
+	  where id = $2 and deleted_at is null
 	`
 
-	rowsAffected, err := d.pool.Exec(ctx, query, time.Now(), id)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return err
-	}
+	rowsAffected, err := conn(ctx, d.pool).Exec(ctx, query, time.Now(), id)
 
 	if err != nil {
 		log.Println("error while deleting drug by id", err.Error())
 		return err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
 	return nil
 
 }
+
+func (d *drugRepo) Restore(ctx context.Context, id string) error {
+	return restore(ctx, conn(ctx, d.pool), "drug", id)
+}
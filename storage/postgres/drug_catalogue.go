@@ -0,0 +1,244 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"shifolink/api/models"
+	"shifolink/storage"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type drugCatalogueRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewDrugCatalogueRepo(pool *pgxpool.Pool) storage.IDrugCatalogueRepo {
+	return &drugCatalogueRepo{
+		pool: pool,
+	}
+}
+
+func (d *drugCatalogueRepo) Create(ctx context.Context, request models.CreateDrugCatalogue) (string, error) {
+
+	id := uuid.New()
+
+	query := `insert into drug_catalogue
+	 (id,
+	  name,
+	  description,
+	  prescription_required)
+	  values ($1, $2, $3, $4)`
+
+	rowsAffected, err := conn(ctx, d.pool).Exec(ctx, query,
+		id,
+		request.Name,
+		request.Description,
+		request.PrescriptionRequired,
+	)
+
+	if err != nil {
+		log.Println("error while inserting drug catalogue ", err.Error())
+		return "", err
+	}
+
+	if rowsAffected.RowsAffected() == 0 {
+		return "", pgx.ErrNoRows
+	}
+
+	return id.String(), nil
+
+}
+
+func (d *drugCatalogueRepo) Get(ctx context.Context, request models.PrimaryKey) (models.DrugCatalogue, error) {
+
+	var updatedAt = sql.NullTime{}
+
+	drugCatalogue := models.DrugCatalogue{}
+
+	query := `select
+	 id,
+	 name,
+	 description,
+	 prescription_required,
+	 version,
+	 created_at,
+	 updated_at
+	 from drug_catalogue where deleted_at is null and id = $1`
+
+	row := conn(ctx, d.pool).QueryRow(ctx, query, request.ID)
+
+	err := row.Scan(
+		&drugCatalogue.ID,
+		&drugCatalogue.Name,
+		&drugCatalogue.Description,
+		&drugCatalogue.PrescriptionRequired,
+		&drugCatalogue.Version,
+		&drugCatalogue.CreatedAt,
+		&updatedAt,
+	)
+
+	if err != nil {
+		log.Println("error while selecting drug catalogue ", err.Error())
+		return models.DrugCatalogue{}, err
+	}
+
+	if updatedAt.Valid {
+		drugCatalogue.UpdatedAt = updatedAt.Time
+	}
+
+	return drugCatalogue, nil
+
+}
+
+// drugCatalogueSortColumns are columns the drug catalogue list can be sorted by, besides created_at
+var drugCatalogueSortColumns = sortColumns{
+	"name": "text",
+}
+
+func (d *drugCatalogueRepo) GetList(ctx context.Context, request models.GetListRequest) (models.DrugCataloguesResponse, error) {
+
+	var (
+		updatedAt         = sql.NullTime{}
+		deletedAt         = sql.NullTime{}
+		drugCatalogues    = []models.DrugCatalogue{}
+		count             = 0
+		query, countQuery string
+	)
+
+	filter := newListFilter(request, "name", "description")
+
+	if err := filter.Sort(request, drugCatalogueSortColumns); err != nil {
+		return models.DrugCataloguesResponse{}, err
+	}
+
+	countQuery = `select count(1) from drug_catalogue` + filter.Where()
+
+	if err := conn(ctx, d.pool).QueryRow(ctx, countQuery, filter.Args()...).Scan(&count); err != nil {
+		fmt.Println("error is while selecting count", err.Error())
+		return models.DrugCataloguesResponse{}, err
+	}
+
+	query = `select
+	 id,
+	 name,
+	 description,
+	 prescription_required,
+	 version,
+	 created_at,
+	 updated_at,
+	 deleted_at from drug_catalogue`
+
+	query += filter.Where()
+
+	pagination, args := filter.Page(request.Page, request.Limit)
+	rows, err := conn(ctx, d.pool).Query(ctx, query+pagination, args...)
+	if err != nil {
+		fmt.Println("error is while selecting drug catalogue ", err.Error())
+		return models.DrugCataloguesResponse{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		drugCatalogue := models.DrugCatalogue{}
+		if err = rows.Scan(
+			&drugCatalogue.ID,
+			&drugCatalogue.Name,
+			&drugCatalogue.Description,
+			&drugCatalogue.PrescriptionRequired,
+			&drugCatalogue.Version,
+			&drugCatalogue.CreatedAt,
+			&updatedAt,
+			&deletedAt,
+		); err != nil {
+			fmt.Println("error is while scanning drug catalogue data", err.Error())
+			return models.DrugCataloguesResponse{}, err
+		}
+
+		if updatedAt.Valid {
+			drugCatalogue.UpdatedAt = updatedAt.Time
+		}
+
+		if deletedAt.Valid {
+			drugCatalogue.DeletedAt = deletedAt.Time
+		}
+
+		drugCatalogues = append(drugCatalogues, drugCatalogue)
+
+	}
+
+	return models.DrugCataloguesResponse{
+		DrugCatalogues: drugCatalogues,
+		Count:          count,
+	}, nil
+}
+
+// Update changes the catalogue entry and the name and prescription rule of every drug which sells it
+func (d *drugCatalogueRepo) Update(ctx context.Context, request models.UpdateDrugCatalogue) (string, error) {
+
+	tx, err := conn(ctx, d.pool).Begin(ctx)
+	if err != nil {
+		log.Println("error while starting transaction", err.Error())
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `update drug_catalogue set
+	name = $1,
+	description = $2,
+	prescription_required = $3,
+	version = version + 1,
+	updated_at = $4
+	 where id = $5 and deleted_at is null and ($6 = 0 or version = $6)
+   `
+
+	rowsAffected, err := tx.Exec(ctx, query,
+		request.Name,
+		request.Description,
+		request.PrescriptionRequired,
+		time.Now(),
+		request.ID,
+		request.Version)
+
+	if err != nil {
+		log.Println("error while updating drug catalogue data...", err.Error())
+		return "", err
+	}
+
+	if rowsAffected.RowsAffected() == 0 {
+		return "", missedUpdate(ctx, conn(ctx, d.pool), "drug_catalogue", request.ID, request.Version)
+	}
+
+	query = `update drug set
+	name = $1,
+	prescription_required = $2,
+	version = version + 1,
+	updated_at = $3
+	 where drug_catalogue_id = $4 and (name <> $1 or prescription_required <> $2)`
+
+	if _, err = tx.Exec(ctx, query, request.Name, request.PrescriptionRequired, time.Now(), request.ID); err != nil {
+		log.Println("error while updating drugs of drug catalogue...", err.Error())
+		return "", err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Println("error while committing drug catalogue", err.Error())
+		return "", err
+	}
+
+	return request.ID, nil
+
+}
+
+func (d *drugCatalogueRepo) Delete(ctx context.Context, id string) ([]storage.ChildRecord, error) {
+	return softDelete(ctx, conn(ctx, d.pool), "drug_catalogue", id)
+}
+
+func (d *drugCatalogueRepo) Restore(ctx context.Context, id string) ([]storage.ChildRecord, error) {
+	return restoreWithChildren(ctx, conn(ctx, d.pool), "drug_catalogue", id)
+}
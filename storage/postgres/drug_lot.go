@@ -0,0 +1,500 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"shifolink/api/models"
+	"shifolink/storage"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type drugLotRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewDrugLotRepo(pool *pgxpool.Pool) storage.IDrugLotRepo {
+	return &drugLotRepo{
+		pool: pool,
+	}
+}
+
+const drugLotColumns = `
+	 id,
+	 drug_id,
+	 coalesce(drug_store_branch_id::text, ''),
+	 lot_number,
+	 quantity,
+	 coalesce(to_char(expiry_date, 'YYYY-MM-DD'), ''),
+	 purchase_price::text,
+	 version,
+	 created_at,
+	 updated_at`
+
+func scanDrugLot(row pgx.Row, extra ...any) (models.DrugLot, error) {
+
+	var updatedAt = sql.NullTime{}
+
+	lot := models.DrugLot{}
+
+	if err := row.Scan(append([]any{
+		&lot.ID,
+		&lot.DrugID,
+		&lot.DrugStoreBranchID,
+		&lot.LotNumber,
+		&lot.Quantity,
+		&lot.ExpiryDate,
+		&lot.PurchasePrice,
+		&lot.Version,
+		&lot.CreatedAt,
+		&updatedAt,
+	}, extra...)...); err != nil {
+		return models.DrugLot{}, err
+	}
+
+	if updatedAt.Valid {
+		lot.UpdatedAt = updatedAt.Time
+	}
+
+	return lot, nil
+}
+
+func (d *drugLotRepo) Create(ctx context.Context, request models.CreateDrugLot) (string, error) {
+
+	id := uuid.New()
+
+	tx, err := conn(ctx, d.pool).Begin(ctx)
+	if err != nil {
+		log.Println("error while starting transaction", err.Error())
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	if err = lockDrugs(ctx, tx, request.DrugID); err != nil {
+		log.Println("error while locking drug", err.Error())
+		return "", err
+	}
+
+	// the lot is kept by the branch of the drug
+	query := `insert into drug_lot
+	 (id,
+	  drug_id,
+	  drug_store_branch_id,
+	  lot_number,
+	  quantity,
+	  expiry_date,
+	  purchase_price)
+	  select $1::uuid, id, drug_store_branch_id, $3::text, $4::int, $5::date, $6::numeric
+	  from drug where id = $2 and deleted_at is null`
+
+	rowsAffected, err := tx.Exec(ctx, query,
+		id,
+		request.DrugID,
+		request.LotNumber,
+		request.Quantity,
+		request.ExpiryDate,
+		request.PurchasePrice,
+	)
+	if err != nil {
+		log.Println("error while inserting drug lot", err.Error())
+		return "", err
+	}
+
+	if rowsAffected.RowsAffected() == 0 {
+		return "", pgx.ErrNoRows
+	}
+
+	if err = syncDrugCount(ctx, tx, request.DrugID); err != nil {
+		log.Println("error while updating drug count", err.Error())
+		return "", err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Println("error while committing drug lot", err.Error())
+		return "", err
+	}
+
+	return id.String(), nil
+}
+
+func (d *drugLotRepo) Get(ctx context.Context, request models.PrimaryKey) (models.DrugLot, error) {
+
+	query := `select ` + drugLotColumns + `
+	 from drug_lot where deleted_at is null and id = $1`
+
+	lot, err := scanDrugLot(conn(ctx, d.pool).QueryRow(ctx, query, request.ID))
+	if err != nil {
+		log.Println("error while selecting drug lot", err.Error())
+		return models.DrugLot{}, err
+	}
+
+	return lot, nil
+}
+
+func (d *drugLotRepo) GetByDrug(ctx context.Context, drugID string) (models.DrugLotsResponse, error) {
+
+	lots := []models.DrugLot{}
+
+	query := `select ` + drugLotColumns + `
+	 from drug_lot where deleted_at is null and drug_id = $1
+	 order by expiry_date nulls last, created_at`
+
+	rows, err := conn(ctx, d.pool).Query(ctx, query, drugID)
+	if err != nil {
+		fmt.Println("error is while selecting drug lots", err.Error())
+		return models.DrugLotsResponse{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		lot, err := scanDrugLot(rows)
+		if err != nil {
+			fmt.Println("error is while scanning drug lot data", err.Error())
+			return models.DrugLotsResponse{}, err
+		}
+
+		lots = append(lots, lot)
+	}
+
+	return models.DrugLotsResponse{
+		DrugLots: lots,
+		Count:    len(lots),
+	}, nil
+}
+
+// GetOrderMoves returns the lots the checkout of the order took drugs from, deleted ones too, with the quantity
+// taken from each
+func (d *drugLotRepo) GetOrderMoves(ctx context.Context, ordersID string) ([]models.DrugLotMove, error) {
+
+	moves := []models.DrugLotMove{}
+
+	query := `select ` + drugLotColumns + `, m.moved
+	 from drug_lot
+	 join (select a.drug_lot_id, sum(a.quantity) as moved from order_drug_lot a
+	        join order_drug od on od.id = a.order_drug_id
+	        where od.orders_id = $1
+	        group by a.drug_lot_id) m on m.drug_lot_id = drug_lot.id
+	 order by drug_lot.id`
+
+	rows, err := conn(ctx, d.pool).Query(ctx, query, ordersID)
+	if err != nil {
+		log.Println("error while selecting lots of order", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		move := models.DrugLotMove{}
+
+		if move.DrugLot, err = scanDrugLot(rows, &move.Moved); err != nil {
+			log.Println("error while scanning lot of order", err.Error())
+			return nil, err
+		}
+
+		moves = append(moves, move)
+	}
+
+	return moves, rows.Err()
+}
+
+func (d *drugLotRepo) Update(ctx context.Context, request models.UpdateDrugLot) (string, error) {
+
+	drugID := ""
+
+	tx, err := conn(ctx, d.pool).Begin(ctx)
+	if err != nil {
+		log.Println("error while starting transaction", err.Error())
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	if err = tx.QueryRow(ctx, `select drug_id from drug_lot where id = $1 and deleted_at is null`,
+		request.ID).Scan(&drugID); err != nil {
+		log.Println("error while selecting drug lot", err.Error())
+		return "", err
+	}
+
+	// the drug is locked before its lots, the same order checkout uses
+	if err = lockDrugs(ctx, tx, drugID); err != nil {
+		log.Println("error while locking drug", err.Error())
+		return "", err
+	}
+
+	query := `update drug_lot set
+	lot_number = $1,
+	quantity = $2,
+	expiry_date = $3::date,
+	purchase_price = $4,
+	version = version + 1,
+	updated_at = $5
+	 where id = $6 and deleted_at is null and ($7 = 0 or version = $7)
+   `
+
+	rowsAffected, err := tx.Exec(ctx, query,
+		request.LotNumber,
+		request.Quantity,
+		request.ExpiryDate,
+		request.PurchasePrice,
+		time.Now(),
+		request.ID,
+		request.Version)
+	if err != nil {
+		log.Println("error while updating drug lot data...", err.Error())
+		return "", err
+	}
+
+	if rowsAffected.RowsAffected() == 0 {
+		return "", missedUpdate(ctx, tx, "drug_lot", request.ID, request.Version)
+	}
+
+	if err = syncDrugCount(ctx, tx, drugID); err != nil {
+		log.Println("error while updating drug count", err.Error())
+		return "", err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Println("error while committing drug lot", err.Error())
+		return "", err
+	}
+
+	return request.ID, nil
+}
+
+func (d *drugLotRepo) Delete(ctx context.Context, id string) error {
+
+	drugID := ""
+
+	tx, err := conn(ctx, d.pool).Begin(ctx)
+	if err != nil {
+		log.Println("error while starting transaction", err.Error())
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err = tx.QueryRow(ctx, `select drug_id from drug_lot where id = $1 and deleted_at is null`, id).Scan(&drugID); err != nil {
+		log.Println("error while selecting drug lot", err.Error())
+		return err
+	}
+
+	if err = lockDrugs(ctx, tx, drugID); err != nil {
+		log.Println("error while locking drug", err.Error())
+		return err
+	}
+
+	query := `
+	update drug_lot
+	 set deleted_at = $1
+	  where id = $2 and deleted_at is null
+	`
+
+	rowsAffected, err := tx.Exec(ctx, query, time.Now(), id)
+	if err != nil {
+		log.Println("error while deleting drug lot by id", err.Error())
+		return err
+	}
+
+	if rowsAffected.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	if err = syncDrugCount(ctx, tx, drugID); err != nil {
+		log.Println("error while updating drug count", err.Error())
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Println("error while committing drug lot", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// GetExpiring returns not empty lots of the branch which are expired or expire in the next request.Within days
+func (d *drugLotRepo) GetExpiring(ctx context.Context, request models.ExpiringLotsRequest) (models.ExpiringLotsResponse, error) {
+
+	lots := []models.ExpiringLot{}
+
+	query := `select
+	 l.id,
+	 l.drug_id,
+	 l.drug_store_branch_id::text,
+	 l.lot_number,
+	 l.quantity,
+	 to_char(l.expiry_date, 'YYYY-MM-DD'),
+	 l.purchase_price::text,
+	 l.version,
+	 l.created_at,
+	 l.updated_at,
+	 d.name,
+	 l.expiry_date - current_date
+	 from drug_lot l
+	 join drug d on d.id = l.drug_id
+	 where l.deleted_at is null and d.deleted_at is null and l.quantity > 0
+	  and l.drug_store_branch_id = $1
+	  and l.expiry_date <= current_date + $2::int
+	 order by l.expiry_date, d.name`
+
+	rows, err := conn(ctx, d.pool).Query(ctx, query, request.DrugStoreBranchID, request.Within)
+	if err != nil {
+		fmt.Println("error is while selecting expiring drug lots", err.Error())
+		return models.ExpiringLotsResponse{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			updatedAt = sql.NullTime{}
+			lot       = models.ExpiringLot{}
+		)
+
+		if err = rows.Scan(
+			&lot.ID,
+			&lot.DrugID,
+			&lot.DrugStoreBranchID,
+			&lot.LotNumber,
+			&lot.Quantity,
+			&lot.ExpiryDate,
+			&lot.PurchasePrice,
+			&lot.Version,
+			&lot.CreatedAt,
+			&updatedAt,
+			&lot.DrugName,
+			&lot.DaysLeft,
+		); err != nil {
+			fmt.Println("error is while scanning expiring drug lot", err.Error())
+			return models.ExpiringLotsResponse{}, err
+		}
+
+		if updatedAt.Valid {
+			lot.UpdatedAt = updatedAt.Time
+		}
+
+		lots = append(lots, lot)
+	}
+
+	return models.ExpiringLotsResponse{
+		Lots:  lots,
+		Count: len(lots),
+	}, nil
+}
+
+// lockDrugs locks drug rows in id order, every change of stock starts with it so parallel changes
+// of the same drugs wait for each other instead of deadlocking
+func lockDrugs(ctx context.Context, tx pgx.Tx, drugIDs ...string) error {
+
+	rows, err := tx.Query(ctx, `select id from drug where id::text = any($1) order by id for update`, drugIDs)
+	if err != nil {
+		return err
+	}
+	rows.Close()
+
+	return rows.Err()
+}
+
+// syncDrugCount makes drug.count the sum of its lots again
+func syncDrugCount(ctx context.Context, db execer, drugIDs ...string) error {
+
+	query := `update drug set
+	 count = (select coalesce(sum(quantity), 0) from drug_lot where drug_id = drug.id and deleted_at is null),
+	 version = version + 1,
+	 updated_at = now()
+	 where id::text = any($1)`
+
+	_, err := db.Exec(ctx, query, drugIDs)
+
+	return err
+}
+
+// allocateLots takes quantity of the drug from its lots first-expired-first-out, expired lots and lots held
+// by another branch are never sold. The drug should be locked by the caller, taken parts are remembered in order_drug_lot
+func allocateLots(ctx context.Context, tx pgx.Tx, drugID, orderDrugID string, quantity int) error {
+
+	type part struct {
+		lotID    string
+		quantity int
+	}
+
+	parts := []part{}
+
+	query := `select l.id, l.quantity from drug_lot l
+	 join drug d on d.id = l.drug_id
+	 where l.drug_id = $1 and l.deleted_at is null and l.quantity > 0
+	  and l.drug_store_branch_id is not distinct from d.drug_store_branch_id
+	  and (l.expiry_date is null or l.expiry_date >= current_date)
+	 order by l.expiry_date nulls last, l.created_at`
+
+	rows, err := tx.Query(ctx, query, drugID)
+	if err != nil {
+		return err
+	}
+
+	for quantity > 0 && rows.Next() {
+		p := part{}
+		if err = rows.Scan(&p.lotID, &p.quantity); err != nil {
+			rows.Close()
+			return err
+		}
+
+		if p.quantity > quantity {
+			p.quantity = quantity
+		}
+		quantity -= p.quantity
+
+		parts = append(parts, p)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	if quantity > 0 {
+		return fmt.Errorf("%w: not enough unexpired lots of drug %s", storage.ErrOutOfStock, drugID)
+	}
+
+	for _, p := range parts {
+		if _, err = tx.Exec(ctx, `update drug_lot set quantity = quantity - $1, version = version + 1, updated_at = now() where id = $2`,
+			p.quantity, p.lotID); err != nil {
+			return err
+		}
+
+		query = `insert into order_drug_lot
+		 (id,
+		  order_drug_id,
+		  drug_lot_id,
+		  quantity)
+		  values ($1, $2, $3, $4)`
+
+		if _, err = tx.Exec(ctx, query, uuid.New(), orderDrugID, p.lotID, p.quantity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restoreLots puts drugs of the order back to the lots they were taken from. Only quantities recorded in
+// order_drug_lot come back, a line without an allocation never took stock
+func restoreLots(ctx context.Context, tx pgx.Tx, ordersID string) error {
+
+	query := `update drug_lot set
+	 quantity = drug_lot.quantity + a.quantity,
+	 version = drug_lot.version + 1,
+	 updated_at = now()
+	 from (select a.drug_lot_id, sum(a.quantity) as quantity
+	        from order_drug_lot a
+	        join order_drug od on od.id = a.order_drug_id
+	        where od.orders_id = $1
+	        group by a.drug_lot_id) a
+	 where drug_lot.id = a.drug_lot_id`
+
+	_, err := tx.Exec(ctx, query, ordersID)
+
+	return err
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -33,22 +34,21 @@ func (d *drugStoreRepo) Create(ctx context.Context, request models.CreateDrugSto
 	  description) 
 	  values ($1, $2, $3)`
 
-	rowsAffected, err := d.pool.Exec(ctx, query,
+	rowsAffected, err := conn(ctx, d.pool).Exec(ctx, query,
 		id,
 		request.Name,
 		request.Description,
 	)
 
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return "", err
-	}
-
 	if err != nil {
 		log.Println("error while inserting drug store ", err.Error())
 		return "", err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return "", pgx.ErrNoRows
+	}
+
 	return id.String(), nil
 
 }
@@ -63,16 +63,18 @@ func (d *drugStoreRepo) Get(ctx context.Context, request models.PrimaryKey) (mod
 	 id,
 	 name,
 	 description,
+	 version,
 	 created_at,
 	 updated_at
 	 from drug_store where deleted_at is null and id = $1`
 
-	row := d.pool.QueryRow(ctx, query, request.ID)
+	row := conn(ctx, d.pool).QueryRow(ctx, query, request.ID)
 
 	err := row.Scan(
 		&drugStore.ID,
 		&drugStore.Name,
 		&drugStore.Description,
+		&drugStore.Version,
 		&drugStore.CreatedAt,
 		&updatedAt,
 	)
@@ -90,24 +92,30 @@ func (d *drugStoreRepo) Get(ctx context.Context, request models.PrimaryKey) (mod
 
 }
 
+// drugStoreSortColumns are columns the drug store list can be sorted by, besides created_at
+var drugStoreSortColumns = sortColumns{
+	"name": "text",
+}
+
 func (d *drugStoreRepo) GetList(ctx context.Context, request models.GetListRequest) (models.DrugStoresResponse, error) {
 
 	var (
 		updatedAt         = sql.NullTime{}
+		deletedAt         = sql.NullTime{}
 		drugStores        = []models.DrugStore{}
 		count             = 0
 		query, countQuery string
-		page              = request.Page
-		offset            = (page - 1) * request.Limit
-		search            = request.Search
 	)
 
-	countQuery = `select count(1) from drug_store where deleted_at is null`
+	filter := newListFilter(request, "name", "description")
 
-	if search != "" {
-		countQuery += fmt.Sprintf(` and (name ilike '%%%s%%' or description ilike '%%%s%%')`, search, search)
+	if err := filter.Sort(request, drugStoreSortColumns); err != nil {
+		return models.DrugStoresResponse{}, err
 	}
-	if err := d.pool.QueryRow(ctx, countQuery).Scan(&count); err != nil {
+
+	countQuery = `select count(1) from drug_store` + filter.Where()
+
+	if err := conn(ctx, d.pool).QueryRow(ctx, countQuery, filter.Args()...).Scan(&count); err != nil {
 		fmt.Println("error is while selecting count", err.Error())
 		return models.DrugStoresResponse{}, err
 	}
@@ -116,15 +124,15 @@ func (d *drugStoreRepo) GetList(ctx context.Context, request models.GetListReque
 	 id,
 	 name,
 	 description,
+	 version,
 	 created_at, 
-	 updated_at from drug_store where deleted_at is null`
+	 updated_at,
+	 deleted_at from drug_store`
 
-	if search != "" {
-		query += fmt.Sprintf(` and (name ilike '%%%s%%' or description ilike '%%%s%%')`, search, search)
-	}
+	query += filter.Where()
 
-	query += ` LIMIT $1 OFFSET $2`
-	rows, err := d.pool.Query(ctx, query, request.Limit, offset)
+	pagination, args := filter.Page(request.Page, request.Limit)
+	rows, err := conn(ctx, d.pool).Query(ctx, query+pagination, args...)
 	if err != nil {
 		fmt.Println("error is while selecting drug store ", err.Error())
 		return models.DrugStoresResponse{}, err
@@ -136,8 +144,10 @@ func (d *drugStoreRepo) GetList(ctx context.Context, request models.GetListReque
 			&drugStore.ID,
 			&drugStore.Name,
 			&drugStore.Description,
+			&drugStore.Version,
 			&drugStore.CreatedAt,
 			&updatedAt,
+			&deletedAt,
 		); err != nil {
 			fmt.Println("error is while scanning drug store data", err.Error())
 			return models.DrugStoresResponse{}, err
@@ -147,6 +157,10 @@ func (d *drugStoreRepo) GetList(ctx context.Context, request models.GetListReque
 			drugStore.UpdatedAt = updatedAt.Time
 		}
 
+		if deletedAt.Valid {
+			drugStore.DeletedAt = deletedAt.Time
+		}
+
 		drugStores = append(drugStores, drugStore)
 
 	}
@@ -162,26 +176,27 @@ func (d *drugStoreRepo) Update(ctx context.Context, request models.UpdateDrugSto
 	query := `update drug_store set
 	name = $1,
 	description = $2,
+    version = version + 1,
     updated_at = $3 
-	 where id = $4  
+	 where id = $4 and deleted_at is null and ($5 = 0 or version = $5)
    `
 
-	rowsAffected, err := d.pool.Exec(ctx, query,
+	rowsAffected, err := conn(ctx, d.pool).Exec(ctx, query,
 		request.Name,
 		request.Description,
 		time.Now(),
-		request.ID)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return "", err
-	}
+		request.ID,
+		request.Version)
 
 	if err != nil {
 		log.Println("error while updating drug store data...", err.Error())
 		return "", err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return "", missedUpdate(ctx, conn(ctx, d.pool), "drug_store", request.ID, request.Version)
+	}
+
 	return request.ID, nil
 
 }
@@ -191,20 +206,23 @@ func (d *drugStoreRepo) Delete(ctx context.Context, id string) error {
 	query := `
 	update drug_store
 	 set deleted_at = $1
-	  where id = $2
+	  where id = $2 and deleted_at is null
 	`
 
-	rowsAffected, err := d.pool.Exec(ctx, query, time.Now(), id)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return err
-	}
+	rowsAffected, err := conn(ctx, d.pool).Exec(ctx, query, time.Now(), id)
 
 	if err != nil {
 		log.Println("error while deleting drug store  by id", err.Error())
 		return err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
 	return nil
 }
+
+func (d *drugStoreRepo) Restore(ctx context.Context, id string) error {
+	return restore(ctx, conn(ctx, d.pool), "drug_store", id)
+}
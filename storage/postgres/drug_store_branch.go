@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -35,7 +36,7 @@ func (d *drugStoreBranchRepo) Create(ctx context.Context, request models.CreateD
 	  working_time) 
 	  values ($1, $2, $3, $4, $5)`
 
-	rowsAffected, err := d.pool.Exec(ctx, query,
+	rowsAffected, err := conn(ctx, d.pool).Exec(ctx, query,
 		id,
 		request.DrugStoreID,
 		request.Address,
@@ -43,16 +44,15 @@ func (d *drugStoreBranchRepo) Create(ctx context.Context, request models.CreateD
 		request.WorkingTime,
 	)
 
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return "", err
-	}
-
 	if err != nil {
 		log.Println("error while inserting drug store branch", err.Error())
 		return "", err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return "", pgx.ErrNoRows
+	}
+
 	return id.String(), nil
 
 }
@@ -69,11 +69,12 @@ func (d *drugStoreBranchRepo) Get(ctx context.Context, request models.PrimaryKey
 	 address,
 	 phone,
 	 working_time,
+	 version,
 	 created_at,
 	 updated_at
 	 from drug_store_branch where deleted_at is null and id = $1`
 
-	row := d.pool.QueryRow(ctx, query, request.ID)
+	row := conn(ctx, d.pool).QueryRow(ctx, query, request.ID)
 
 	err := row.Scan(
 		&drugStoreBranch.ID,
@@ -81,6 +82,7 @@ func (d *drugStoreBranchRepo) Get(ctx context.Context, request models.PrimaryKey
 		&drugStoreBranch.Address,
 		&drugStoreBranch.Phone,
 		&drugStoreBranch.WorkingTime,
+		&drugStoreBranch.Version,
 		&drugStoreBranch.CreatedAt,
 		&updatedAt,
 	)
@@ -98,24 +100,31 @@ func (d *drugStoreBranchRepo) Get(ctx context.Context, request models.PrimaryKey
 
 }
 
-func (d *drugStoreBranchRepo) GetList(ctx context.Context, request models.GetListRequest) (models.DrugStoreBranchsResponse, error) {
+// drugStoreBranchSortColumns are columns the drug store branch list can be sorted by, besides created_at
+var drugStoreBranchSortColumns = sortColumns{
+	"address": "text",
+}
+
+func (d *drugStoreBranchRepo) GetList(ctx context.Context, request models.GetDrugStoreBranchsListRequest) (models.DrugStoreBranchsResponse, error) {
 
 	var (
 		updatedAt         = sql.NullTime{}
+		deletedAt         = sql.NullTime{}
 		drugStoreBranchs  = []models.DrugStoreBranch{}
 		count             = 0
 		query, countQuery string
-		page              = request.Page
-		offset            = (page - 1) * request.Limit
-		search            = request.Search
 	)
 
-	countQuery = `select count(1) from drug_store_branch where deleted_at is null`
+	filter := newListFilter(request.GetListRequest, "address", "phone")
+	filter.Equal("drug_store_id", request.DrugStoreID)
 
-	if search != "" {
-		countQuery += fmt.Sprintf(` and (address ilike '%%%s%%' or phone ilike '%%%s%%')`, search, search)
+	if err := filter.Sort(request.GetListRequest, drugStoreBranchSortColumns); err != nil {
+		return models.DrugStoreBranchsResponse{}, err
 	}
-	if err := d.pool.QueryRow(ctx, countQuery).Scan(&count); err != nil {
+
+	countQuery = `select count(1) from drug_store_branch` + filter.Where()
+
+	if err := conn(ctx, d.pool).QueryRow(ctx, countQuery, filter.Args()...).Scan(&count); err != nil {
 		fmt.Println("error is while selecting count", err.Error())
 		return models.DrugStoreBranchsResponse{}, err
 	}
@@ -126,15 +135,15 @@ func (d *drugStoreBranchRepo) GetList(ctx context.Context, request models.GetLis
 	 address,
 	 phone,
 	 working_time,
+	 version,
 	 created_at, 
-	 updated_at from drug_store_branch where deleted_at is null`
+	 updated_at,
+	 deleted_at from drug_store_branch`
 
-	if search != "" {
-		query += fmt.Sprintf(` and (address ilike '%%%s%%' or phone ilike '%%%s%%')`, search, search)
-	}
+	query += filter.Where()
 
-	query += ` LIMIT $1 OFFSET $2`
-	rows, err := d.pool.Query(ctx, query, request.Limit, offset)
+	pagination, args := filter.Page(request.Page, request.Limit)
+	rows, err := conn(ctx, d.pool).Query(ctx, query+pagination, args...)
 	if err != nil {
 		fmt.Println("error is while selecting drug store branch ", err.Error())
 		return models.DrugStoreBranchsResponse{}, err
@@ -148,8 +157,10 @@ func (d *drugStoreBranchRepo) GetList(ctx context.Context, request models.GetLis
 			&drugStoreBranch.Address,
 			&drugStoreBranch.Phone,
 			&drugStoreBranch.WorkingTime,
+			&drugStoreBranch.Version,
 			&drugStoreBranch.CreatedAt,
 			&updatedAt,
+			&deletedAt,
 		); err != nil {
 			fmt.Println("error is while scanning drug store branch data", err.Error())
 			return models.DrugStoreBranchsResponse{}, err
@@ -159,6 +170,10 @@ func (d *drugStoreBranchRepo) GetList(ctx context.Context, request models.GetLis
 			drugStoreBranch.UpdatedAt = updatedAt.Time
 		}
 
+		if deletedAt.Valid {
+			drugStoreBranch.DeletedAt = deletedAt.Time
+		}
+
 		drugStoreBranchs = append(drugStoreBranchs, drugStoreBranch)
 
 	}
@@ -177,28 +192,29 @@ func (d *drugStoreBranchRepo) Update(ctx context.Context, request models.UpdateD
 	address = $2,
 	phone = $3,
 	working_time = $4,
+    version = version + 1,
     updated_at = $5 
-	 where id = $6  
+	 where id = $6 and deleted_at is null and ($7 = 0 or version = $7)
    `
 
-	rowsAffected, err := d.pool.Exec(ctx, query,
+	rowsAffected, err := conn(ctx, d.pool).Exec(ctx, query,
 		request.DrugStoreID,
 		request.Address,
 		request.Phone,
 		request.WorkingTime,
 		time.Now(),
-		request.ID)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return "", err
-	}
+		request.ID,
+		request.Version)
 
 	if err != nil {
 		log.Println("error while updating drug store branch data...", err.Error())
 		return "", err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return "", missedUpdate(ctx, conn(ctx, d.pool), "drug_store_branch", request.ID, request.Version)
+	}
+
 	return request.ID, nil
 }
 
@@ -207,20 +223,23 @@ func (d *drugStoreBranchRepo) Delete(ctx context.Context, id string) error {
 	query := `
 	update drug_store_branch
 	 set deleted_at = $1
-	  where id = $2
+	  where id = $2 and deleted_at is null
 	`
 
-	rowsAffected, err := d.pool.Exec(ctx, query, time.Now(), id)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return err
-	}
+	rowsAffected, err := conn(ctx, d.pool).Exec(ctx, query, time.Now(), id)
 
 	if err != nil {
 		log.Println("error while deleting drug store branch by id", err.Error())
 		return err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
 	return nil
 }
+
+func (d *drugStoreBranchRepo) Restore(ctx context.Context, id string) error {
+	return restore(ctx, conn(ctx, d.pool), "drug_store_branch", id)
+}
@@ -0,0 +1,233 @@
+package postgres
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"shifolink/api/models"
+	"shifolink/storage"
+	"strings"
+)
+
+// sortColumns whitelists columns a list can be sorted by, with their SQL types for comparing cursor values
+type sortColumns map[string]string
+
+// nullSortValues stand in for NULL in sort columns of the type, so such rows are ordered first and cursors can
+// point at them. Columns of other types should be NOT NULL
+var nullSortValues = map[string]string{
+	"text":      "''",
+	"date":      "'-infinity'",
+	"timestamp": "'-infinity'",
+}
+
+// listCursor points after the last row of a page, it is only valid for the sorting it was made with
+type listCursor struct {
+	SortBy string `json:"s"`
+	Order  string `json:"o"`
+	Value  string `json:"v"`
+	ID     string `json:"id"`
+}
+
+// listFilter builds the where clause, the ordering and the pagination of GetList queries.
+// Values given by clients are never put into the query text, they are bound as parameters
+type listFilter struct {
+	conditions []string
+	args       []interface{}
+	sortBy     string
+	sortType   string
+	order      string
+	cursor     *listCursor
+}
+
+// newListFilter starts a filter with the search over the given text columns, the created_at range and
+// the soft delete condition, which every list supports
+func newListFilter(request models.GetListRequest, searchColumns ...string) *listFilter {
+	f := &listFilter{
+		sortBy:   "created_at",
+		sortType: "timestamp",
+		order:    "desc",
+	}
+
+	if request.Search != "" && len(searchColumns) > 0 {
+		matches := make([]string, 0, len(searchColumns))
+		for _, column := range searchColumns {
+			matches = append(matches, column+" ilike %[1]s")
+		}
+
+		f.Filter("("+strings.Join(matches, " or ")+")", likePattern(request.Search))
+	}
+
+	f.Filter("created_at >= %s::date", request.CreatedFrom)
+	f.Filter("created_at < %s::date + 1", request.CreatedTo)
+
+	if !request.IncludeDeleted {
+		f.Condition("deleted_at is null")
+	}
+
+	return f
+}
+
+// Filter adds the condition when the value is not empty, %s in the condition is replaced by the value placeholder
+// (%[1]s to use it more than once)
+func (f *listFilter) Filter(condition string, value string) {
+	if value == "" {
+		return
+	}
+
+	f.conditions = append(f.conditions, fmt.Sprintf(condition, f.Bind(value)))
+}
+
+// Bind adds the value to the arguments and returns its placeholder, for conditions with more than one value
+func (f *listFilter) Bind(value string) string {
+	f.args = append(f.args, value)
+
+	return fmt.Sprintf("$%d", len(f.args))
+}
+
+// Condition adds a condition which has no values given by the client
+func (f *listFilter) Condition(condition string) {
+	f.conditions = append(f.conditions, condition)
+}
+
+// Equal keeps rows where the column is the value, it is skipped for an empty value
+func (f *listFilter) Equal(column string, value string) {
+	f.Filter(column+" = %s", value)
+}
+
+// Where returns the where clause of the conditions, it is empty when there are none
+func (f *listFilter) Where() string {
+	if len(f.conditions) == 0 {
+		return ""
+	}
+
+	return " where " + strings.Join(f.conditions, " and ")
+}
+
+// Args returns the values of the conditions, for the count query
+func (f *listFilter) Args() []interface{} {
+	return f.args
+}
+
+// Sort orders the list by a whitelisted column, newest first by default. Rows are also ordered by id so equal
+// values do not move between pages. A cursor of the request continues after the row it was made for
+func (f *listFilter) Sort(request models.GetListRequest, columns sortColumns) error {
+
+	sortBy, order := request.SortBy, strings.ToLower(request.Order)
+
+	if sortBy == "" {
+		sortBy = "created_at"
+		if order == "" {
+			order = "desc"
+		}
+	}
+
+	if order == "" {
+		order = "asc"
+	}
+
+	if order != "asc" && order != "desc" {
+		return fmt.Errorf("%w: order should be asc or desc", storage.ErrInvalidListRequest)
+	}
+
+	sortType, ok := columns[sortBy]
+	if sortBy == "created_at" {
+		sortType, ok = "timestamp", true
+	}
+	if !ok {
+		return fmt.Errorf("%w: list can not be sorted by %s", storage.ErrInvalidListRequest, sortBy)
+	}
+
+	f.sortBy, f.sortType, f.order = sortBy, sortType, order
+
+	if request.Cursor == "" {
+		return nil
+	}
+
+	cursor := listCursor{}
+
+	data, err := base64.RawURLEncoding.DecodeString(request.Cursor)
+	if err == nil {
+		err = json.Unmarshal(data, &cursor)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: cursor is not valid", storage.ErrInvalidListRequest)
+	}
+
+	if cursor.SortBy != sortBy || cursor.Order != order {
+		return fmt.Errorf("%w: cursor was made for another sorting", storage.ErrInvalidListRequest)
+	}
+
+	f.cursor = &cursor
+
+	return nil
+}
+
+// sortExpr is the sort column with NULL replaced by the value of nullSortValues. created_at is never NULL and
+// is left alone for its indexes
+func (f *listFilter) sortExpr() string {
+	value, ok := nullSortValues[f.sortType]
+	if !ok || f.sortBy == "created_at" {
+		return f.sortBy
+	}
+
+	return fmt.Sprintf("coalesce(%s, %s::%s)", f.sortBy, value, f.sortType)
+}
+
+// SortKey is the sort column as text, lists with cursors select it to make the next cursor
+func (f *listFilter) SortKey() string {
+	return f.sortExpr() + "::text"
+}
+
+// Page returns the ordering with the LIMIT/OFFSET clause and the values of the whole list query.
+// With a cursor the page number is ignored and the rows after the cursor are returned
+func (f *listFilter) Page(page, limit int) (string, []interface{}) {
+	if page < 1 || f.cursor != nil {
+		page = 1
+	}
+
+	var (
+		args   = append([]interface{}{}, f.args...)
+		clause string
+	)
+
+	if f.cursor != nil {
+		comparison := ">"
+		if f.order == "desc" {
+			comparison = "<"
+		}
+
+		args = append(args, f.cursor.Value, f.cursor.ID)
+		clause = fmt.Sprintf(` and (%s, id) %s ($%d::%s, $%d::uuid)`, f.sortExpr(), comparison, len(args)-1, f.sortType, len(args))
+	}
+
+	args = append(args, limit, (page-1)*limit)
+	clause += fmt.Sprintf(` order by %[1]s %[2]s, id %[2]s LIMIT $%[3]d OFFSET $%[4]d`, f.sortExpr(), f.order, len(args)-1, len(args))
+
+	return clause, args
+}
+
+// NextCursor returns the cursor following the last row of a page, there is none after a page which is not full
+func (f *listFilter) NextCursor(rows, limit int, sortKey, id string) string {
+	if rows < limit {
+		return ""
+	}
+
+	data, err := json.Marshal(listCursor{
+		SortBy: f.sortBy,
+		Order:  f.order,
+		Value:  sortKey,
+		ID:     id,
+	})
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// likePattern matches the text anywhere, wildcards typed by the client are taken literally
+func likePattern(text string) string {
+	text = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(text)
+
+	return "%" + text + "%"
+}
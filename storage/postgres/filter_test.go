@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"shifolink/api/models"
+	"strings"
+	"testing"
+)
+
+func TestListFilterNullableSortColumn(t *testing.T) {
+
+	request := models.GetListRequest{SortBy: "start_time"}
+
+	first := newListFilter(request)
+	if err := first.Sort(request, queueSortColumns); err != nil {
+		t.Fatalf("error while sorting: %v", err)
+	}
+
+	// the last row of the first page had no start_time
+	request.Cursor = first.NextCursor(10, 10, "-infinity", "6f2a4c1e-52d4-4f0e-9d5c-0a6f2c1b7e01")
+
+	next := newListFilter(request)
+	if err := next.Sort(request, queueSortColumns); err != nil {
+		t.Fatalf("error while reading cursor: %v", err)
+	}
+
+	clause, args := next.Page(1, 10)
+
+	sortExpr := "coalesce(start_time, '-infinity'::timestamp)"
+	if !strings.Contains(clause, "("+sortExpr+", id) >") || !strings.Contains(clause, "order by "+sortExpr+" asc") {
+		t.Fatalf("NULL start_time is not ordered and compared the same way: %s", clause)
+	}
+
+	if args[0] != "-infinity" {
+		t.Fatalf("cursor value is %v, want -infinity", args[0])
+	}
+
+	if key := next.SortKey(); key != sortExpr+"::text" {
+		t.Fatalf("sort key is %s, want %s::text", key, sortExpr)
+	}
+}
@@ -3,13 +3,16 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"shifolink/api/models"
+	"shifolink/config"
 	"shifolink/storage"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -23,31 +26,124 @@ func NewJournalRepo(pool *pgxpool.Pool) storage.IJournalRepo {
 	}
 }
 
+// journalReleased keeps articles readers can see, a scheduled article shows up when its time comes
+const journalReleased = `status = 'published' and published_at <= now()`
+
+const journalColumns = `
+	 id,
+	 author_id,
+	 theme,
+	 article,
+	 language,
+	 slug,
+	 status,
+	 version,
+	 published_at,
+	 coalesce(reviewed_by::text, ''),
+	 review_note,
+	 coalesce(category_id::text, ''),
+	 array(select tag from journal_tag where journal_id = journal.id order by tag),
+	 array(select doctor_type_id::text from journal_doctor_type where journal_id = journal.id order by doctor_type_id),
+	 created_at,
+	 updated_at,
+	 deleted_at`
+
+func scanJournal(row pgx.Row) (models.Journal, error) {
+
+	var (
+		publishedAt = sql.NullTime{}
+		updatedAt   = sql.NullTime{}
+		deletedAt   = sql.NullTime{}
+	)
+
+	journal := models.Journal{}
+
+	if err := row.Scan(
+		&journal.ID,
+		&journal.AuthorID,
+		&journal.Theme,
+		&journal.Article,
+		&journal.Language,
+		&journal.Slug,
+		&journal.Status,
+		&journal.Version,
+		&publishedAt,
+		&journal.ReviewedBy,
+		&journal.ReviewNote,
+		&journal.CategoryID,
+		&journal.Tags,
+		&journal.DoctorTypeIDs,
+		&journal.CreatedAt,
+		&updatedAt,
+		&deletedAt,
+	); err != nil {
+		return models.Journal{}, err
+	}
+
+	if publishedAt.Valid {
+		journal.PublishedAt = &publishedAt.Time
+	}
+
+	if updatedAt.Valid {
+		journal.UpdatedAt = updatedAt.Time
+	}
+
+	if deletedAt.Valid {
+		journal.DeletedAt = deletedAt.Time
+	}
+
+	return journal, nil
+}
+
+// Create saves the article as a draft together with its first revision, tags and specialties
 func (j *journalRepo) Create(ctx context.Context, request models.CreateJournal) (string, error) {
 
 	id := uuid.New()
 
+	tx, err := conn(ctx, j.pool).Begin(ctx)
+	if err != nil {
+		log.Println("error while starting transaction", err.Error())
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
 	query := `insert into journal
 	 (id, 
 	  author_id,
 	  theme,
-	  article) 
-	  values ($1, $2, $3, $4)`
-
-	rowsAffected, err := j.pool.Exec(ctx, query,
+	  article,
+	  language,
+	  slug,
+	  status,
+	  category_id) 
+	  values ($1, $2, $3, $4, coalesce(nullif($5, ''), 'uz'), $6, $7, nullif($8, '')::uuid)`
+
+	if _, err = tx.Exec(ctx, query,
 		id,
 		request.AuthorID,
 		request.Theme,
 		request.Article,
-	)
+		request.Language,
+		request.Slug,
+		config.JournalDraft,
+		request.CategoryID,
+	); err != nil {
+		log.Println("error while inserting journal ", err.Error())
+		return "", err
+	}
 
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
+	if err = setJournalLinks(ctx, tx, id.String(), request.Tags, request.DoctorTypeIDs); err != nil {
+		log.Println("error while inserting journal tags and doctor types", err.Error())
 		return "", err
 	}
 
-	if err != nil {
-		log.Println("error while inserting journal ", err.Error())
+	if err = insertJournalRevision(ctx, tx, id.String(), request.EditorID); err != nil {
+		log.Println("error while inserting journal revision", err.Error())
+		return "", err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Println("error while committing journal", err.Error())
 		return "", err
 	}
 
@@ -57,101 +153,92 @@ func (j *journalRepo) Create(ctx context.Context, request models.CreateJournal)
 
 func (j *journalRepo) Get(ctx context.Context, request models.PrimaryKey) (models.Journal, error) {
 
-	var updatedAt = sql.NullTime{}
-
-	journal := models.Journal{}
-
-	query := `select 
-	 id,
-	 author_id,
-	 theme,
-	 article,
-	 created_at,
-	 updated_at
+	query := `select ` + journalColumns + `
 	 from journal where deleted_at is null and id = $1`
 
-	row := j.pool.QueryRow(ctx, query, request.ID)
-
-	err := row.Scan(
-		&journal.ID,
-		&journal.AuthorID,
-		&journal.Theme,
-		&journal.Article,
-		&journal.CreatedAt,
-		&updatedAt,
-	)
-
+	journal, err := scanJournal(conn(ctx, j.pool).QueryRow(ctx, query, request.ID))
 	if err != nil {
 		log.Println("error while selecting journal", err.Error())
 		return models.Journal{}, err
 	}
 
-	if updatedAt.Valid {
-		journal.UpdatedAt = updatedAt.Time
+	return journal, nil
+}
+
+func (j *journalRepo) GetBySlug(ctx context.Context, slug string) (models.Journal, error) {
+
+	query := `select ` + journalColumns + `
+	 from journal where deleted_at is null and slug = $1`
+
+	journal, err := scanJournal(conn(ctx, j.pool).QueryRow(ctx, query, slug))
+	if err != nil {
+		log.Println("error while selecting journal by slug", err.Error())
+		return models.Journal{}, err
 	}
 
 	return journal, nil
 }
 
-func (j *journalRepo) GetList(ctx context.Context, request models.GetListRequest) (models.JournalsResponse, error) {
+// journalSortColumns are columns the journal list can be sorted by, besides created_at
+var journalSortColumns = sortColumns{
+	"theme": "text",
+}
+
+func (j *journalRepo) GetList(ctx context.Context, request models.GetJournalsListRequest) (models.JournalsResponse, error) {
 
 	var (
-		updatedAt         = sql.NullTime{}
 		journals          = []models.Journal{}
 		count             = 0
 		query, countQuery string
-		page              = request.Page
-		offset            = (page - 1) * request.Limit
-		search            = request.Search
 	)
 
-	countQuery = `select count(1) from journal where deleted_at is null`
+	filter := newListFilter(request.GetListRequest)
+	filter.Filter("search_vector @@ journal_search_query(%s, '')", request.Search)
+	filter.Equal("author_id", request.AuthorID)
+	filter.Equal("status", request.Status)
+	filter.Equal("category_id", request.CategoryID)
+	filter.Filter("id in (select journal_id from journal_tag where tag = %s)", request.Tag)
+	filter.Filter("id in (select journal_id from journal_doctor_type where doctor_type_id = %s)", request.DoctorTypeID)
+
+	if request.PublishedOnly {
+		if request.OwnerID != "" {
+			filter.Filter("(author_id = %s or "+journalReleased+")", request.OwnerID)
+		} else {
+			filter.Condition(journalReleased)
+		}
+	}
 
-	if search != "" {
-		countQuery += fmt.Sprintf(` and (theme ilike '%%%s%%' or article ilike '%%%s%%')`, search, search)
+	if err := filter.Sort(request.GetListRequest, journalSortColumns); err != nil {
+		return models.JournalsResponse{}, err
 	}
-	if err := j.pool.QueryRow(ctx, countQuery).Scan(&count); err != nil {
+
+	countQuery = `select count(1) from journal` + filter.Where()
+
+	if err := conn(ctx, j.pool).QueryRow(ctx, countQuery, filter.Args()...).Scan(&count); err != nil {
 		fmt.Println("error is while selecting count", err.Error())
 		return models.JournalsResponse{}, err
 	}
 
-	query = `select 
-	 id,
-	 author_id,
-	 theme,
-	 article,
-	 created_at, 
-	 updated_at from journal where deleted_at is null`
+	query = `select ` + journalColumns + ` from journal`
 
-	if search != "" {
-		query += fmt.Sprintf(` and (theme ilike '%%%s%%' or article ilike '%%%s%%')`, search, search)
-	}
+	query += filter.Where()
 
-	query += ` LIMIT $1 OFFSET $2`
-	rows, err := j.pool.Query(ctx, query, request.Limit, offset)
+	pagination, args := filter.Page(request.Page, request.Limit)
+	rows, err := conn(ctx, j.pool).Query(ctx, query+pagination, args...)
 	if err != nil {
 		fmt.Println("error is while selecting journal ", err.Error())
 		return models.JournalsResponse{}, err
 	}
 
+	defer rows.Close()
+
 	for rows.Next() {
-		journal := models.Journal{}
-		if err = rows.Scan(
-			&journal.ID,
-			&journal.AuthorID,
-			&journal.Theme,
-			&journal.Article,
-			&journal.CreatedAt,
-			&updatedAt,
-		); err != nil {
+		journal, err := scanJournal(rows)
+		if err != nil {
 			fmt.Println("error is while scanning journal data", err.Error())
 			return models.JournalsResponse{}, err
 		}
 
-		if updatedAt.Valid {
-			journal.UpdatedAt = updatedAt.Time
-		}
-
 		journals = append(journals, journal)
 
 	}
@@ -162,30 +249,134 @@ func (j *journalRepo) GetList(ctx context.Context, request models.GetListRequest
 	}, nil
 }
 
+// escapedHTML is the sql expression of the column with its HTML special characters escaped, the text authors
+// wrote is escaped before ts_headline adds its marks so the marks are the only tags of a snippet
+func escapedHTML(column string) string {
+	return `replace(replace(replace(replace(replace(` + column +
+		`, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`
+}
+
+// Search ranks released articles by how well the theme (weighted higher) and the article match the text,
+// snippets are made only for the returned page
+func (j *journalRepo) Search(ctx context.Context, request models.SearchJournalsRequest) (models.JournalSearchResponse, error) {
+
+	var (
+		results = []models.JournalSearchResult{}
+		count   = 0
+		offset  = (request.Page - 1) * request.Limit
+	)
+
+	countQuery := `select count(1) from journal
+	 where deleted_at is null and ` + journalReleased + ` and search_vector @@ journal_search_query($1, $2)`
+
+	if err := conn(ctx, j.pool).QueryRow(ctx, countQuery, request.Query, request.Language).Scan(&count); err != nil {
+		fmt.Println("error is while selecting journal search count", err.Error())
+		return models.JournalSearchResponse{}, err
+	}
+
+	query := `select
+	 p.id,
+	 p.author_id,
+	 ts_headline(journal_search_config(p.language), ` + escapedHTML("p.theme") + `, p.query, 'HighlightAll=true, StartSel=<mark>, StopSel=</mark>'),
+	 ts_headline(journal_search_config(p.language), ` + escapedHTML("p.article") + `, p.query,
+	  'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=" ... "'),
+	 p.language,
+	 p.rank,
+	 p.created_at
+	 from (
+	  select j.id, j.author_id, j.theme, j.article, j.language, j.created_at, q.query,
+	   ts_rank_cd(j.search_vector, q.query) as rank
+	   from journal j, journal_search_query($1, $2) q(query)
+	   where j.deleted_at is null and j.status = 'published' and j.published_at <= now() and j.search_vector @@ q.query
+	   order by rank desc, j.created_at desc, j.id
+	   limit $3 offset $4
+	 ) p
+	 order by p.rank desc, p.created_at desc, p.id`
+
+	rows, err := conn(ctx, j.pool).Query(ctx, query, request.Query, request.Language, request.Limit, offset)
+	if err != nil {
+		fmt.Println("error is while searching journal ", err.Error())
+		return models.JournalSearchResponse{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		result := models.JournalSearchResult{}
+		if err = rows.Scan(
+			&result.ID,
+			&result.AuthorID,
+			&result.Theme,
+			&result.Snippet,
+			&result.Language,
+			&result.Rank,
+			&result.CreatedAt,
+		); err != nil {
+			fmt.Println("error is while scanning journal search data", err.Error())
+			return models.JournalSearchResponse{}, err
+		}
+
+		results = append(results, result)
+	}
+
+	return models.JournalSearchResponse{
+		Results: results,
+		Count:   count,
+	}, nil
+}
+
+// Update saves a new version of a draft and its revision, ErrStatusChanged is returned when the article
+// is not a draft anymore and ErrVersionChanged when a newer version than the given one was saved
 func (j *journalRepo) Update(ctx context.Context, request models.UpdateJournal) (string, error) {
 
+	tx, err := conn(ctx, j.pool).Begin(ctx)
+	if err != nil {
+		log.Println("error while starting transaction", err.Error())
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
 	query := `update journal set
-	author_id = $1,
+	author_id = coalesce(nullif($1, '')::uuid, author_id),
 	theme = $2,
 	article = $3,
-    updated_at = $4 
-	 where id = $5  
+	language = coalesce(nullif($4, ''), language),
+	slug = coalesce(nullif($5, ''), slug),
+	version = version + 1,
+    updated_at = $6 
+	 where id = $7 and status = $8 and deleted_at is null and ($9 = 0 or version = $9)
    `
 
-	rowsAffected, err := j.pool.Exec(ctx, query,
+	rowsAffected, err := tx.Exec(ctx, query,
 		request.AuthorID,
 		request.Theme,
 		request.Article,
+		request.Language,
+		request.Slug,
 		time.Now(),
-		request.ID)
+		request.ID,
+		config.JournalDraft,
+		request.Version)
+	if err != nil {
+		log.Println("error while updating journal data...", err.Error())
+		return "", err
+	}
+
+	if rowsAffected.RowsAffected() == 0 {
+		if err = missedUpdate(ctx, tx, "journal", request.ID, request.Version); errors.Is(err, storage.ErrVersionChanged) {
+			return "", err
+		}
 
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
+		// only a draft is updated, the journal was sent to review or published meanwhile
+		return "", storage.ErrStatusChanged
+	}
+
+	if err = insertJournalRevision(ctx, tx, request.ID, request.EditorID); err != nil {
+		log.Println("error while inserting journal revision", err.Error())
 		return "", err
 	}
 
-	if err != nil {
-		log.Println("error while updating journal data...", err.Error())
+	if err = tx.Commit(ctx); err != nil {
+		log.Println("error while committing journal", err.Error())
 		return "", err
 	}
 
@@ -193,25 +384,242 @@ func (j *journalRepo) Update(ctx context.Context, request models.UpdateJournal)
 
 }
 
+// UpdateStatus moves the article on only if it is still in FromStatus, so two reviewers can not both act on it
+func (j *journalRepo) UpdateStatus(ctx context.Context, request models.UpdateJournalStatus) error {
+
+	query := `update journal set
+	 status = $1,
+	 published_at = coalesce($2, published_at),
+	 reviewed_by = coalesce(nullif($3, '')::uuid, reviewed_by),
+	 review_note = $4,
+	 updated_at = now()
+	  where id = $5 and status = $6 and deleted_at is null`
+
+	rowsAffected, err := conn(ctx, j.pool).Exec(ctx, query,
+		request.ToStatus,
+		request.PublishedAt,
+		request.ReviewedBy,
+		request.ReviewNote,
+		request.ID,
+		request.FromStatus,
+	)
+	if err != nil {
+		log.Println("error while updating journal status", err.Error())
+		return err
+	}
+
+	if rowsAffected.RowsAffected() == 0 {
+		return storage.ErrStatusChanged
+	}
+
+	return nil
+}
+
+// Classify replaces the category, the tags and the specialties of the article in any status
+func (j *journalRepo) Classify(ctx context.Context, request models.ClassifyJournal) error {
+
+	tx, err := conn(ctx, j.pool).Begin(ctx)
+	if err != nil {
+		log.Println("error while starting transaction", err.Error())
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `update journal set
+	 category_id = nullif($1, '')::uuid,
+	 updated_at = now()
+	  where id = $2 and deleted_at is null`
+
+	rowsAffected, err := tx.Exec(ctx, query, request.CategoryID, request.ID)
+	if err != nil {
+		log.Println("error while updating journal category", err.Error())
+		return err
+	}
+
+	if rowsAffected.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	for _, query = range []string{
+		`delete from journal_tag where journal_id = $1`,
+		`delete from journal_doctor_type where journal_id = $1`,
+	} {
+		if _, err = tx.Exec(ctx, query, request.ID); err != nil {
+			log.Println("error while deleting journal tags and doctor types", err.Error())
+			return err
+		}
+	}
+
+	if err = setJournalLinks(ctx, tx, request.ID, request.Tags, request.DoctorTypeIDs); err != nil {
+		log.Println("error while inserting journal tags and doctor types", err.Error())
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Println("error while committing journal classification", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// GetTags counts released articles of every tag, the most used tags first
+func (j *journalRepo) GetTags(ctx context.Context) (models.JournalTagsResponse, error) {
+
+	tags := []models.JournalTag{}
+
+	query := `select t.tag, count(1)
+	 from journal_tag t
+	 join journal on journal.id = t.journal_id
+	 where journal.deleted_at is null and ` + journalReleased + `
+	 group by t.tag
+	 order by count(1) desc, t.tag`
+
+	rows, err := conn(ctx, j.pool).Query(ctx, query)
+	if err != nil {
+		fmt.Println("error is while selecting journal tags", err.Error())
+		return models.JournalTagsResponse{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		tag := models.JournalTag{}
+		if err = rows.Scan(&tag.Tag, &tag.Count); err != nil {
+			fmt.Println("error is while scanning journal tag", err.Error())
+			return models.JournalTagsResponse{}, err
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return models.JournalTagsResponse{
+		Tags:  tags,
+		Count: len(tags),
+	}, nil
+}
+
+func (j *journalRepo) GetRevisions(ctx context.Context, journalID string) (models.JournalRevisionsResponse, error) {
+
+	revisions := []models.JournalRevision{}
+
+	query := `select ` + journalRevisionColumns + `
+	 from journal_revision where journal_id = $1
+	 order by version desc`
+
+	rows, err := conn(ctx, j.pool).Query(ctx, query, journalID)
+	if err != nil {
+		fmt.Println("error is while selecting journal revisions", err.Error())
+		return models.JournalRevisionsResponse{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		revision, err := scanJournalRevision(rows)
+		if err != nil {
+			fmt.Println("error is while scanning journal revision", err.Error())
+			return models.JournalRevisionsResponse{}, err
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	return models.JournalRevisionsResponse{
+		Revisions: revisions,
+		Count:     len(revisions),
+	}, nil
+}
+
+func (j *journalRepo) GetRevision(ctx context.Context, journalID string, version int) (models.JournalRevision, error) {
+
+	query := `select ` + journalRevisionColumns + `
+	 from journal_revision where journal_id = $1 and version = $2`
+
+	revision, err := scanJournalRevision(conn(ctx, j.pool).QueryRow(ctx, query, journalID, version))
+	if err != nil {
+		log.Println("error while selecting journal revision", err.Error())
+		return models.JournalRevision{}, err
+	}
+
+	return revision, nil
+}
+
 func (j *journalRepo) Delete(ctx context.Context, id string) error {
 
 	query := `
 	update journal
 	 set deleted_at = $1
-	  where id = $2
+	  where id = $2 and deleted_at is null
 	`
 
-	rowsAffected, err := j.pool.Exec(ctx, query, time.Now(), id)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return err
-	}
+	rowsAffected, err := conn(ctx, j.pool).Exec(ctx, query, time.Now(), id)
 
 	if err != nil {
 		log.Println("error while deleting journal  by id", err.Error())
 		return err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
 	return nil
 }
+
+const journalRevisionColumns = `
+	 id,
+	 journal_id,
+	 version,
+	 theme,
+	 article,
+	 language,
+	 coalesce(edited_by::text, ''),
+	 created_at`
+
+func scanJournalRevision(row pgx.Row) (models.JournalRevision, error) {
+
+	revision := models.JournalRevision{}
+
+	err := row.Scan(
+		&revision.ID,
+		&revision.JournalID,
+		&revision.Version,
+		&revision.Theme,
+		&revision.Article,
+		&revision.Language,
+		&revision.EditedBy,
+		&revision.CreatedAt,
+	)
+
+	return revision, err
+}
+
+// insertJournalRevision copies the current text of the article as the revision of its current version
+func insertJournalRevision(ctx context.Context, tx pgx.Tx, journalID, editorID string) error {
+
+	query := `insert into journal_revision
+	 (id, journal_id, version, theme, article, language, edited_by)
+	 select $1, id, version, theme, article, language, nullif($2, '')::uuid
+	  from journal where id = $3`
+
+	_, err := tx.Exec(ctx, query, uuid.New(), editorID, journalID)
+
+	return err
+}
+
+// setJournalLinks adds the tags and the doctor types of the article
+func setJournalLinks(ctx context.Context, tx pgx.Tx, journalID string, tags, doctorTypeIDs []string) error {
+
+	if _, err := tx.Exec(ctx, `insert into journal_tag (journal_id, tag)
+	 select $1, unnest($2::text[])`, journalID, tags); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec(ctx, `insert into journal_doctor_type (journal_id, doctor_type_id)
+	 select $1, unnest($2::uuid[])`, journalID, doctorTypeIDs)
+
+	return err
+}
+
+func (j *journalRepo) Restore(ctx context.Context, id string) error {
+	return restore(ctx, conn(ctx, j.pool), "journal", id)
+}
@@ -0,0 +1,201 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"shifolink/api/models"
+	"shifolink/storage"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type journalCategoryRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewJournalCategoryRepo(pool *pgxpool.Pool) storage.IJournalCategoryRepo {
+	return &journalCategoryRepo{
+		pool: pool,
+	}
+}
+
+const journalCategoryColumns = `
+	 id,
+	 name,
+	 description,
+	 version,
+	 created_at,
+	 updated_at,
+	 deleted_at`
+
+func scanJournalCategory(row pgx.Row) (models.JournalCategory, error) {
+
+	var updatedAt, deletedAt = sql.NullTime{}, sql.NullTime{}
+
+	category := models.JournalCategory{}
+
+	if err := row.Scan(
+		&category.ID,
+		&category.Name,
+		&category.Description,
+		&category.Version,
+		&category.CreatedAt,
+		&updatedAt,
+		&deletedAt,
+	); err != nil {
+		return models.JournalCategory{}, err
+	}
+
+	if updatedAt.Valid {
+		category.UpdatedAt = updatedAt.Time
+	}
+
+	if deletedAt.Valid {
+		category.DeletedAt = deletedAt.Time
+	}
+
+	return category, nil
+}
+
+func (j *journalCategoryRepo) Create(ctx context.Context, request models.CreateJournalCategory) (string, error) {
+
+	id := uuid.New()
+
+	query := `insert into journal_category
+	 (id,
+	  name,
+	  description)
+	  values ($1, $2, $3)`
+
+	if _, err := conn(ctx, j.pool).Exec(ctx, query, id, request.Name, request.Description); err != nil {
+		log.Println("error while inserting journal category", err.Error())
+		return "", err
+	}
+
+	return id.String(), nil
+}
+
+func (j *journalCategoryRepo) Get(ctx context.Context, request models.PrimaryKey) (models.JournalCategory, error) {
+
+	query := `select ` + journalCategoryColumns + `
+	 from journal_category where deleted_at is null and id = $1`
+
+	category, err := scanJournalCategory(conn(ctx, j.pool).QueryRow(ctx, query, request.ID))
+	if err != nil {
+		log.Println("error while selecting journal category", err.Error())
+		return models.JournalCategory{}, err
+	}
+
+	return category, nil
+}
+
+// journalCategorySortColumns are columns the category list can be sorted by, besides created_at
+var journalCategorySortColumns = sortColumns{
+	"name": "text",
+}
+
+func (j *journalCategoryRepo) GetList(ctx context.Context, request models.GetListRequest) (models.JournalCategoriesResponse, error) {
+
+	var (
+		categories        = []models.JournalCategory{}
+		count             = 0
+		query, countQuery string
+	)
+
+	filter := newListFilter(request, "name")
+
+	if err := filter.Sort(request, journalCategorySortColumns); err != nil {
+		return models.JournalCategoriesResponse{}, err
+	}
+
+	countQuery = `select count(1) from journal_category` + filter.Where()
+
+	if err := conn(ctx, j.pool).QueryRow(ctx, countQuery, filter.Args()...).Scan(&count); err != nil {
+		fmt.Println("error is while selecting count", err.Error())
+		return models.JournalCategoriesResponse{}, err
+	}
+
+	query = `select ` + journalCategoryColumns + ` from journal_category` + filter.Where()
+
+	pagination, args := filter.Page(request.Page, request.Limit)
+	rows, err := conn(ctx, j.pool).Query(ctx, query+pagination, args...)
+	if err != nil {
+		fmt.Println("error is while selecting journal categories", err.Error())
+		return models.JournalCategoriesResponse{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		category, err := scanJournalCategory(rows)
+		if err != nil {
+			fmt.Println("error is while scanning journal category data", err.Error())
+			return models.JournalCategoriesResponse{}, err
+		}
+
+		categories = append(categories, category)
+	}
+
+	return models.JournalCategoriesResponse{
+		JournalCategories: categories,
+		Count:             count,
+	}, nil
+}
+
+func (j *journalCategoryRepo) Update(ctx context.Context, request models.UpdateJournalCategory) (string, error) {
+
+	query := `update journal_category set
+	 name = $1,
+	 description = $2,
+	 version = version + 1,
+	 updated_at = $3
+	  where id = $4 and deleted_at is null and ($5 = 0 or version = $5)`
+
+	rowsAffected, err := conn(ctx, j.pool).Exec(ctx, query, request.Name, request.Description, time.Now(), request.ID, request.Version)
+	if err != nil {
+		log.Println("error while updating journal category", err.Error())
+		return "", err
+	}
+
+	if rowsAffected.RowsAffected() == 0 {
+		return "", missedUpdate(ctx, conn(ctx, j.pool), "journal_category", request.ID, request.Version)
+	}
+
+	return request.ID, nil
+}
+
+// Delete removes the category, its articles are left without a category
+func (j *journalCategoryRepo) Delete(ctx context.Context, id string) error {
+
+	tx, err := conn(ctx, j.pool).Begin(ctx)
+	if err != nil {
+		log.Println("error while starting transaction", err.Error())
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	rowsAffected, err := tx.Exec(ctx, `update journal_category set deleted_at = $1 where id = $2 and deleted_at is null`, time.Now(), id)
+	if err != nil {
+		log.Println("error while deleting journal category by id", err.Error())
+		return err
+	}
+
+	if rowsAffected.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	if _, err = tx.Exec(ctx, `update journal set category_id = null where category_id = $1`, id); err != nil {
+		log.Println("error while unlinking journals of the category", err.Error())
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (j *journalCategoryRepo) Restore(ctx context.Context, id string) error {
+	return restore(ctx, conn(ctx, j.pool), "journal_category", id)
+}
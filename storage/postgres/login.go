@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"context"
+	"log"
+	"shifolink/storage"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// loginID returns the id of the live user of the table whose email or phone is the login. Unique indexes keep
+// each of them to one user, but an email of one user may still be the phone of another, such a login is refused
+func loginID(ctx context.Context, db dbtx, table, login string) (string, error) {
+
+	rows, err := db.Query(ctx, `select id from `+table+` where deleted_at is null and (email = $1 or phone = $1) limit 2`, login)
+	if err != nil {
+		log.Println("error while selecting", table, "by login", err.Error())
+		return "", err
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		log.Println("error while scanning", table, "by login", err.Error())
+		return "", err
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", pgx.ErrNoRows
+	case 1:
+		return ids[0], nil
+	}
+
+	return "", storage.ErrAmbiguousLogin
+}
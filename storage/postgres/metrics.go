@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"errors"
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	poolAcquiredConns = prometheus.NewDesc("shifolink_pgxpool_acquired_conns",
+		"Connections of the pool currently in use.", nil, nil)
+	poolIdleConns = prometheus.NewDesc("shifolink_pgxpool_idle_conns",
+		"Connections of the pool waiting to be used.", nil, nil)
+	poolTotalConns = prometheus.NewDesc("shifolink_pgxpool_total_conns",
+		"Connections of the pool, constructing ones included.", nil, nil)
+	poolMaxConns = prometheus.NewDesc("shifolink_pgxpool_max_conns",
+		"Most connections the pool may open.", nil, nil)
+	poolAcquires = prometheus.NewDesc("shifolink_pgxpool_acquires_total",
+		"Connections taken from the pool.", nil, nil)
+	poolEmptyAcquires = prometheus.NewDesc("shifolink_pgxpool_empty_acquires_total",
+		"Connections taken from the pool after waiting because none was idle.", nil, nil)
+	poolAcquireWait = prometheus.NewDesc("shifolink_pgxpool_acquire_wait_seconds_total",
+		"Time spent waiting for connections of the pool.", nil, nil)
+)
+
+// poolMetrics is registered once, every new store makes it read its own pool
+var poolMetrics = &poolCollector{}
+
+// poolCollector reads the stats of the pool every time the metrics are scraped
+type poolCollector struct {
+	pool atomic.Pointer[pgxpool.Pool]
+}
+
+// observePool makes the pool metrics read the pool, the collector is registered by the first store
+func observePool(pool *pgxpool.Pool) error {
+
+	poolMetrics.pool.Store(pool)
+
+	err := prometheus.Register(poolMetrics)
+	if errors.As(err, &prometheus.AlreadyRegisteredError{}) {
+		return nil
+	}
+
+	return err
+}
+
+func (p *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolAcquiredConns
+	ch <- poolIdleConns
+	ch <- poolTotalConns
+	ch <- poolMaxConns
+	ch <- poolAcquires
+	ch <- poolEmptyAcquires
+	ch <- poolAcquireWait
+}
+
+func (p *poolCollector) Collect(ch chan<- prometheus.Metric) {
+
+	pool := p.pool.Load()
+	if pool == nil {
+		return
+	}
+
+	stat := pool.Stat()
+
+	ch <- prometheus.MustNewConstMetric(poolAcquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(poolIdleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(poolTotalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(poolMaxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(poolAcquires, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolEmptyAcquires, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolAcquireWait, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+}
@@ -3,13 +3,17 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"shifolink/api/models"
+	"shifolink/config"
 	"shifolink/storage"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -27,25 +31,54 @@ func (o *orderDrugRepo) Create(ctx context.Context, request models.CreateOrderDr
 
 	id := uuid.New()
 
+	tx, err := conn(ctx, o.pool).Begin(ctx)
+	if err != nil {
+		log.Println("error while starting transaction", err.Error())
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	if err = lockOpenOrder(ctx, tx, request.OrdersID); err != nil {
+		return "", err
+	}
+
+	// the drug price is copied to the line so later price changes do not touch the order
 	query := `insert into order_drug
 	 (id, 
 	  drug_id,
-	  orders_id) 
-	  values ($1, $2, $3)`
+	  orders_id,
+	  quantity,
+	  unit_price,
+	  line_total) 
+	  select $1, d.id, $3, $4, d.price, d.price * $4
+	   from drug d where d.id = $2 and d.deleted_at is null`
+
+	if err = checkPrescriptionRequired(ctx, tx, request.DrugID); err != nil {
+		return "", err
+	}
 
-	rowsAffected, err := o.pool.Exec(ctx, query,
+	rowsAffected, err := tx.Exec(ctx, query,
 		id,
 		request.DrugID,
 		request.OrdersID,
+		lineQuantity(request.Quantity),
 	)
+	if err != nil {
+		log.Println("error while inserting order drug ", err.Error())
+		return "", err
+	}
 
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
+	if rowsAffected.RowsAffected() == 0 {
+		return "", pgx.ErrNoRows
+	}
+
+	if err = recalcOrderTotals(ctx, tx, request.OrdersID); err != nil {
+		log.Println("error while calculating order totals ", err.Error())
 		return "", err
 	}
 
-	if err != nil {
-		log.Println("error while inserting order drug ", err.Error())
+	if err = tx.Commit(ctx); err != nil {
+		log.Println("error while committing order drug ", err.Error())
 		return "", err
 	}
 
@@ -63,16 +96,26 @@ func (o *orderDrugRepo) Get(ctx context.Context, request models.PrimaryKey) (mod
 	 id,
 	 drug_id,
 	 orders_id,
+	 quantity,
+	 unit_price::text,
+	 line_total::text,
+	 coalesce(prescription_item_id::text, ''),
+	 version,
 	 created_at,
 	 updated_at
 	 from order_drug where deleted_at is null and id = $1`
 
-	row := o.pool.QueryRow(ctx, query, request.ID)
+	row := conn(ctx, o.pool).QueryRow(ctx, query, request.ID)
 
 	err := row.Scan(
 		&orderDrug.ID,
 		&orderDrug.DrugID,
 		&orderDrug.OrdersID,
+		&orderDrug.Quantity,
+		&orderDrug.UnitPrice,
+		&orderDrug.LineTotal,
+		&orderDrug.PrescriptionItemID,
+		&orderDrug.Version,
 		&orderDrug.CreatedAt,
 		&updatedAt,
 	)
@@ -90,24 +133,36 @@ func (o *orderDrugRepo) Get(ctx context.Context, request models.PrimaryKey) (mod
 
 }
 
-func (o *orderDrugRepo) GetList(ctx context.Context, request models.GetListRequest) (models.OrderDrugsResponse, error) {
+// orderDrugSortColumns are columns the order drug list can be sorted by, besides created_at
+var orderDrugSortColumns = sortColumns{
+	"quantity":   "int",
+	"line_total": "numeric",
+}
+
+func (o *orderDrugRepo) GetList(ctx context.Context, request models.GetOrderDrugsListRequest) (models.OrderDrugsResponse, error) {
 
 	var (
 		updatedAt         = sql.NullTime{}
+		deletedAt         = sql.NullTime{}
 		orderDrugs        = []models.OrderDrug{}
 		count             = 0
 		query, countQuery string
-		page              = request.Page
-		offset            = (page - 1) * request.Limit
-		search            = request.Search
 	)
 
-	countQuery = `select count(1) from order_drug where deleted_at is null`
+	filter := newListFilter(request.GetListRequest)
+	if request.Search != "" {
+		filter.Filter("drug_id in (select id from drug where name ilike %s)", likePattern(request.Search))
+	}
+	filter.Equal("orders_id", request.OrdersID)
+	filter.Equal("drug_id", request.DrugID)
 
-	if search != "" {
-		countQuery += fmt.Sprintf(` and (drug_id ilike '%%%s%%' or orders_id ilike '%%%s%%')`, search, search)
+	if err := filter.Sort(request.GetListRequest, orderDrugSortColumns); err != nil {
+		return models.OrderDrugsResponse{}, err
 	}
-	if err := o.pool.QueryRow(ctx, countQuery).Scan(&count); err != nil {
+
+	countQuery = `select count(1) from order_drug` + filter.Where()
+
+	if err := conn(ctx, o.pool).QueryRow(ctx, countQuery, filter.Args()...).Scan(&count); err != nil {
 		fmt.Println("error is while selecting count", err.Error())
 		return models.OrderDrugsResponse{}, err
 	}
@@ -116,15 +171,19 @@ func (o *orderDrugRepo) GetList(ctx context.Context, request models.GetListReque
 	 id,
 	 drug_id,
 	 orders_id,
+	 quantity,
+	 unit_price::text,
+	 line_total::text,
+	 coalesce(prescription_item_id::text, ''),
+	 version,
 	 created_at, 
-	 updated_at from order_drug where deleted_at is null`
+	 updated_at,
+	 deleted_at from order_drug`
 
-	if search != "" {
-		query += fmt.Sprintf(` and (drug_id ilike '%%%s%%' or orders_id ilike '%%%s%%')`, search, search)
-	}
+	query += filter.Where()
 
-	query += ` LIMIT $1 OFFSET $2`
-	rows, err := o.pool.Query(ctx, query, request.Limit, offset)
+	pagination, args := filter.Page(request.Page, request.Limit)
+	rows, err := conn(ctx, o.pool).Query(ctx, query+pagination, args...)
 	if err != nil {
 		fmt.Println("error is while selecting order drug ", err.Error())
 		return models.OrderDrugsResponse{}, err
@@ -136,8 +195,14 @@ func (o *orderDrugRepo) GetList(ctx context.Context, request models.GetListReque
 			&orderDrug.ID,
 			&orderDrug.DrugID,
 			&orderDrug.OrdersID,
+			&orderDrug.Quantity,
+			&orderDrug.UnitPrice,
+			&orderDrug.LineTotal,
+			&orderDrug.PrescriptionItemID,
+			&orderDrug.Version,
 			&orderDrug.CreatedAt,
 			&updatedAt,
+			&deletedAt,
 		); err != nil {
 			fmt.Println("error is while scanning order drug data", err.Error())
 			return models.OrderDrugsResponse{}, err
@@ -147,6 +212,10 @@ func (o *orderDrugRepo) GetList(ctx context.Context, request models.GetListReque
 			orderDrug.UpdatedAt = updatedAt.Time
 		}
 
+		if deletedAt.Valid {
+			orderDrug.DeletedAt = deletedAt.Time
+		}
+
 		orderDrugs = append(orderDrugs, orderDrug)
 
 	}
@@ -159,26 +228,69 @@ func (o *orderDrugRepo) GetList(ctx context.Context, request models.GetListReque
 
 func (o *orderDrugRepo) Update(ctx context.Context, request models.UpdateOrderDrug) (string, error) {
 
+	oldOrdersID := ""
+
+	tx, err := conn(ctx, o.pool).Begin(ctx)
+	if err != nil {
+		log.Println("error while starting transaction", err.Error())
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	if err = tx.QueryRow(ctx, `select orders_id from order_drug where id = $1 and deleted_at is null for update`,
+		request.ID).Scan(&oldOrdersID); err != nil {
+		log.Println("error while selecting order drug for update", err.Error())
+		return "", err
+	}
+
+	// the line may be moved to another order, neither of them may be checked out
+	for _, ordersID := range []string{oldOrdersID, request.OrdersID} {
+		if err = lockOpenOrder(ctx, tx, ordersID); err != nil {
+			return "", err
+		}
+	}
+
+	if err = checkPrescriptionRequired(ctx, tx, request.DrugID); err != nil {
+		return "", err
+	}
+
 	query := `update order_drug set
-	drug_id = $1,
+	drug_id = d.id,
 	orders_id = $2,
-    updated_at = $3 
-	 where id = $4  
+	quantity = $3,
+	unit_price = d.price,
+	line_total = d.price * $3,
+    version = order_drug.version + 1,
+    updated_at = $4 
+	 from drug d
+	 where d.id = $1 and order_drug.id = $5 and ($6 = 0 or order_drug.version = $6)
    `
 
-	rowsAffected, err := o.pool.Exec(ctx, query,
+	rowsAffected, err := tx.Exec(ctx, query,
 		request.DrugID,
 		request.OrdersID,
+		lineQuantity(request.Quantity),
 		time.Now(),
-		request.ID)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
+		request.ID,
+		request.Version)
+	if err != nil {
+		log.Println("error while updating order drug data...", err.Error())
 		return "", err
 	}
 
-	if err != nil {
-		log.Println("error while updating order drug data...", err.Error())
+	if rowsAffected.RowsAffected() == 0 {
+		return "", missedUpdate(ctx, tx, "order_drug", request.ID, request.Version)
+	}
+
+	for _, ordersID := range []string{oldOrdersID, request.OrdersID} {
+		if err = recalcOrderTotals(ctx, tx, ordersID); err != nil {
+			log.Println("error while calculating order totals ", err.Error())
+			return "", err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Println("error while committing order drug ", err.Error())
 		return "", err
 	}
 
@@ -187,24 +299,194 @@ func (o *orderDrugRepo) Update(ctx context.Context, request models.UpdateOrderDr
 
 func (o *orderDrugRepo) Delete(ctx context.Context, id string) error {
 
+	ordersID := ""
+
+	tx, err := conn(ctx, o.pool).Begin(ctx)
+	if err != nil {
+		log.Println("error while starting transaction", err.Error())
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err = tx.QueryRow(ctx, `select orders_id from order_drug where id = $1 and deleted_at is null`,
+		id).Scan(&ordersID); err != nil {
+		log.Println("error while selecting order drug for delete", err.Error())
+		return err
+	}
+
+	// drugs taken by a checked out line go back to stock only when the whole order is cancelled
+	if err = lockOpenOrder(ctx, tx, ordersID); err != nil {
+		return err
+	}
+
 	query := `
 	update order_drug
 	 set deleted_at = $1
-	  where id = $2
+	  where id = $2 and deleted_at is null
 	`
 
-	rowsAffected, err := o.pool.Exec(ctx, query, time.Now(), id)
+	if _, err = tx.Exec(ctx, query, time.Now(), id); err != nil {
+		log.Println("error while deleting order_drug  by id", err.Error())
+		return err
+	}
+
+	if err = recalcOrderTotals(ctx, tx, ordersID); err != nil {
+		log.Println("error while calculating order totals ", err.Error())
+		return err
+	}
 
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
+	if err = tx.Commit(ctx); err != nil {
+		log.Println("error while committing order drug ", err.Error())
 		return err
 	}
 
+	return nil
+
+}
+
+// Restore brings the order line back and counts it into the totals of its order again
+func (o *orderDrugRepo) Restore(ctx context.Context, id string) error {
+
+	if err := restore(ctx, conn(ctx, o.pool), "order_drug", id); err != nil {
+		return err
+	}
+
+	ordersID := ""
+	if err := conn(ctx, o.pool).QueryRow(ctx, `select orders_id from order_drug where id = $1`, id).Scan(&ordersID); err != nil {
+		log.Println("error while getting order of order_drug", err.Error())
+		return err
+	}
+
+	if err := recalcOrderTotals(ctx, conn(ctx, o.pool), ordersID); err != nil {
+		log.Println("error while calculating order totals ", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (o *orderDrugRepo) GetByOrder(ctx context.Context, ordersID string) ([]models.OrderDrug, error) {
+
+	var (
+		updatedAt  = sql.NullTime{}
+		orderDrugs = []models.OrderDrug{}
+	)
+
+	query := `select 
+	 id,
+	 drug_id,
+	 orders_id,
+	 quantity,
+	 unit_price::text,
+	 line_total::text,
+	 coalesce(prescription_item_id::text, ''),
+	 version,
+	 created_at,
+	 updated_at
+	 from order_drug where deleted_at is null and orders_id = $1
+	 order by created_at`
+
+	rows, err := conn(ctx, o.pool).Query(ctx, query, ordersID)
 	if err != nil {
-		log.Println("error while deleting order_drug  by id", err.Error())
+		fmt.Println("error is while selecting order lines", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		orderDrug := models.OrderDrug{}
+		if err = rows.Scan(
+			&orderDrug.ID,
+			&orderDrug.DrugID,
+			&orderDrug.OrdersID,
+			&orderDrug.Quantity,
+			&orderDrug.UnitPrice,
+			&orderDrug.LineTotal,
+			&orderDrug.PrescriptionItemID,
+			&orderDrug.Version,
+			&orderDrug.CreatedAt,
+			&updatedAt,
+		); err != nil {
+			fmt.Println("error is while scanning order line", err.Error())
+			return nil, err
+		}
+
+		if updatedAt.Valid {
+			orderDrug.UpdatedAt = updatedAt.Time
+		}
+
+		orderDrugs = append(orderDrugs, orderDrug)
+	}
+
+	return orderDrugs, nil
+}
+
+// lockOpenOrder locks the order and returns storage.ErrOrderClosed unless its lines may still be changed.
+// Lines added after the checkout would take no stock, so a checked out order keeps the lines it was sold with
+func lockOpenOrder(ctx context.Context, tx pgx.Tx, ordersID string) error {
+
+	var (
+		status     string
+		checkedOut bool
+	)
+
+	if err := tx.QueryRow(ctx, `select status, drug_store_branch_id is not null from orders
+	 where id = $1 and deleted_at is null for update`, ordersID).Scan(&status, &checkedOut); err != nil {
+		log.Println("error while locking order of order drug", err.Error())
 		return err
 	}
 
+	if status != config.OrderPending || checkedOut {
+		return storage.ErrOrderClosed
+	}
+
 	return nil
+}
+
+// checkPrescriptionRequired returns storage.ErrPrescriptionRequired for a prescription-only drug, such drugs are
+// sold only by redeeming a prescription. An unknown drug is left to the caller
+func checkPrescriptionRequired(ctx context.Context, tx pgx.Tx, drugID string) error {
+
+	prescriptionRequired := false
+	if err := tx.QueryRow(ctx, `select prescription_required from drug where id = $1 and deleted_at is null`,
+		drugID).Scan(&prescriptionRequired); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		log.Println("error while selecting drug of order drug ", err.Error())
+		return err
+	}
+
+	if prescriptionRequired {
+		return storage.ErrPrescriptionRequired
+	}
+
+	return nil
+}
+
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// recalcOrderTotals sums the order lines again, the discount is kept
+func recalcOrderTotals(ctx context.Context, db execer, ordersID string) error {
+
+	query := `update orders set
+	 subtotal = t.subtotal,
+	 total = greatest(t.subtotal - orders.discount, 0),
+	 version = orders.version + 1,
+	 updated_at = now()
+	 from (select coalesce(sum(line_total), 0) as subtotal
+	        from order_drug where orders_id = $1 and deleted_at is null) t
+	 where orders.id = $1`
+
+	_, err := db.Exec(ctx, query, ordersID)
+
+	return err
+}
+
+// lineQuantity keeps old clients, which do not send quantity, working with one item per line
+func lineQuantity(quantity int) int {
+	if quantity == 0 {
+		return 1
+	}
 
+	return quantity
 }
@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"log"
 	"shifolink/api/models"
+	"shifolink/config"
+	"shifolink/pkg/money"
 	"shifolink/storage"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -33,22 +36,21 @@ func (o *ordersRepo) Create(ctx context.Context, request models.CreateOrders) (s
 	  customer_id) 
 	  values ($1, $2, $3)`
 
-	rowsAffected, err := o.pool.Exec(ctx, query,
+	rowsAffected, err := conn(ctx, o.pool).Exec(ctx, query,
 		id,
 		request.PharmacistID,
 		request.CustomerID,
 	)
 
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return "", err
-	}
-
 	if err != nil {
 		log.Println("error while inserting orders ", err.Error())
 		return "", err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return "", pgx.ErrNoRows
+	}
+
 	return id.String(), nil
 
 }
@@ -61,18 +63,46 @@ func (o *ordersRepo) Get(ctx context.Context, request models.PrimaryKey) (models
 
 	query := `select 
 	 id,
-	 pharmacist_id,
+	 coalesce(pharmacist_id::text, ''),
 	 customer_id,
+	 coalesce(drug_store_branch_id::text, ''),
+	 subtotal::text,
+	 discount::text,
+	 total::text,
+	 currency,
+	 status,
+	 coalesce(prescription_id::text, ''),
+	 confirmed_at,
+	 preparing_at,
+	 ready_for_pickup_at,
+	 delivered_at,
+	 cancelled_at,
+	 refunded_at,
+	 version,
 	 created_at,
 	 updated_at
 	 from orders where deleted_at is null and id = $1`
 
-	row := o.pool.QueryRow(ctx, query, request.ID)
+	row := conn(ctx, o.pool).QueryRow(ctx, query, request.ID)
 
 	err := row.Scan(
 		&orders.ID,
 		&orders.PharmacistID,
 		&orders.CustomerID,
+		&orders.DrugStoreBranchID,
+		&orders.Subtotal,
+		&orders.Discount,
+		&orders.Total,
+		&orders.Currency,
+		&orders.Status,
+		&orders.PrescriptionID,
+		&orders.ConfirmedAt,
+		&orders.PreparingAt,
+		&orders.ReadyForPickupAt,
+		&orders.DeliveredAt,
+		&orders.CancelledAt,
+		&orders.RefundedAt,
+		&orders.Version,
 		&orders.CreatedAt,
 		&updatedAt,
 	)
@@ -90,41 +120,72 @@ func (o *ordersRepo) Get(ctx context.Context, request models.PrimaryKey) (models
 
 }
 
-func (o *ordersRepo) GetList(ctx context.Context, request models.GetListRequest) (models.OrdersResponse, error) {
+// ordersSortColumns are columns the orders list can be sorted by, besides created_at
+var ordersSortColumns = sortColumns{
+	"total":  "numeric",
+	"status": "text",
+}
+
+func (o *ordersRepo) GetList(ctx context.Context, request models.GetOrdersListRequest) (models.OrdersResponse, error) {
 
 	var (
 		updatedAt         = sql.NullTime{}
+		deletedAt         = sql.NullTime{}
 		orders            = []models.Orders{}
 		count             = 0
 		query, countQuery string
-		page              = request.Page
-		offset            = (page - 1) * request.Limit
-		search            = request.Search
+		sortKey           = sql.NullString{}
 	)
 
-	countQuery = `select count(1) from orders where deleted_at is null`
+	filter := newListFilter(request.GetListRequest)
+	if request.Search != "" {
+		filter.Filter("customer_id in (select id from customer where first_name ilike %[1]s or last_name ilike %[1]s)", likePattern(request.Search))
+	}
+	filter.Equal("status", request.Status)
+	filter.Equal("customer_id", request.CustomerID)
+	filter.Equal("pharmacist_id", request.PharmacistID)
+	filter.Equal("drug_store_branch_id", request.DrugStoreBranchID)
+	if request.AccessPharmacistID != "" {
+		filter.Condition(fmt.Sprintf("(drug_store_branch_id = %s or pharmacist_id = %s)",
+			filter.Bind(request.AccessBranchID), filter.Bind(request.AccessPharmacistID)))
+	}
 
-	if search != "" {
-		countQuery += fmt.Sprintf(` and (pharmacist_id ilike '%%%s%%' or customer_id ilike '%%%s%%')`, search, search)
+	if err := filter.Sort(request.GetListRequest, ordersSortColumns); err != nil {
+		return models.OrdersResponse{}, err
 	}
-	if err := o.pool.QueryRow(ctx, countQuery).Scan(&count); err != nil {
+
+	countQuery = `select count(1) from orders` + filter.Where()
+
+	if err := conn(ctx, o.pool).QueryRow(ctx, countQuery, filter.Args()...).Scan(&count); err != nil {
 		fmt.Println("error is while selecting count", err.Error())
 		return models.OrdersResponse{}, err
 	}
 
 	query = `select 
 	 id,
-	 pharmacist_id,
+	 coalesce(pharmacist_id::text, ''),
 	 customer_id,
+	 coalesce(drug_store_branch_id::text, ''),
+	 subtotal::text,
+	 discount::text,
+	 total::text,
+	 currency,
+	 status,
+	 coalesce(prescription_id::text, ''),
+	 confirmed_at,
+	 preparing_at,
+	 ready_for_pickup_at,
+	 delivered_at,
+	 cancelled_at,
+	 refunded_at,
+	 version,
 	 created_at, 
-	 updated_at from orders where deleted_at is null`
+	 updated_at,
+	 deleted_at,
+	 ` + filter.SortKey() + ` from orders` + filter.Where()
 
-	if search != "" {
-		query += fmt.Sprintf(` and (pharmacist_id ilike '%%%s%%' or customer_id ilike '%%%s%%')`, search, search)
-	}
-
-	query += ` LIMIT $1 OFFSET $2`
-	rows, err := o.pool.Query(ctx, query, request.Limit, offset)
+	pagination, args := filter.Page(request.Page, request.Limit)
+	rows, err := conn(ctx, o.pool).Query(ctx, query+pagination, args...)
 	if err != nil {
 		fmt.Println("error is while selecting orders ", err.Error())
 		return models.OrdersResponse{}, err
@@ -136,8 +197,24 @@ func (o *ordersRepo) GetList(ctx context.Context, request models.GetListRequest)
 			&order.ID,
 			&order.PharmacistID,
 			&order.CustomerID,
+			&order.DrugStoreBranchID,
+			&order.Subtotal,
+			&order.Discount,
+			&order.Total,
+			&order.Currency,
+			&order.Status,
+			&order.PrescriptionID,
+			&order.ConfirmedAt,
+			&order.PreparingAt,
+			&order.ReadyForPickupAt,
+			&order.DeliveredAt,
+			&order.CancelledAt,
+			&order.RefundedAt,
+			&order.Version,
 			&order.CreatedAt,
 			&updatedAt,
+			&deletedAt,
+			&sortKey,
 		); err != nil {
 			fmt.Println("error is while scanning orders data", err.Error())
 			return models.OrdersResponse{}, err
@@ -147,38 +224,74 @@ func (o *ordersRepo) GetList(ctx context.Context, request models.GetListRequest)
 			order.UpdatedAt = updatedAt.Time
 		}
 
+		if deletedAt.Valid {
+			order.DeletedAt = deletedAt.Time
+		}
+
 		orders = append(orders, order)
 
 	}
 
-	return models.OrdersResponse{
+	response := models.OrdersResponse{
 		Orderss: orders,
 		Count:   count,
-	}, nil
+	}
+
+	if len(orders) > 0 {
+		response.NextCursor = filter.NextCursor(len(orders), request.Limit, sortKey.String, orders[len(orders)-1].ID)
+	}
+
+	return response, nil
 }
 
+// Update changes a pending order, once it moved on only its status changes
 func (o *ordersRepo) Update(ctx context.Context, request models.UpdateOrders) (string, error) {
 
+	status := ""
+
+	tx, err := conn(ctx, o.pool).Begin(ctx)
+	if err != nil {
+		log.Println("error while starting transaction", err.Error())
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	if err = tx.QueryRow(ctx, `select status from orders where id = $1 and deleted_at is null for update`,
+		request.ID).Scan(&status); err != nil {
+		log.Println("error while selecting orders for update", err.Error())
+		return "", err
+	}
+
+	if status != config.OrderPending {
+		return "", storage.ErrOrderNotPending
+	}
+
 	query := `update orders set
 	pharmacist_id = $1,
 	customer_id = $2,
+    version = version + 1,
     updated_at = $3 
-	 where id = $4  
+	 where id = $4 and deleted_at is null and ($5 = 0 or version = $5)
    `
 
-	rowsAffected, err := o.pool.Exec(ctx, query,
+	rowsAffected, err := tx.Exec(ctx, query,
 		request.PharmacistID,
 		request.CustomerID,
 		time.Now(),
-		request.ID)
+		request.ID,
+		request.Version)
 
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
+	if err != nil {
+		log.Println("error while updating orders data...", err.Error())
 		return "", err
 	}
 
-	if err != nil {
-		log.Println("error while updating orders data...", err.Error())
+	if rowsAffected.RowsAffected() == 0 {
+		return "", missedUpdate(ctx, tx, "orders", request.ID, request.Version)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Println("error while committing orders ", err.Error())
 		return "", err
 	}
 
@@ -188,23 +301,404 @@ func (o *ordersRepo) Update(ctx context.Context, request models.UpdateOrders) (s
 
 func (o *ordersRepo) Delete(ctx context.Context, id string) error {
 
+	tx, err := conn(ctx, o.pool).Begin(ctx)
+	if err != nil {
+		log.Println("error while starting transaction", err.Error())
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		checkedOut bool
+		status     string
+	)
+
+	if err = tx.QueryRow(ctx, `select drug_store_branch_id is not null, status from orders
+	 where id = $1 and deleted_at is null for update`, id).Scan(&checkedOut, &status); err != nil {
+		log.Println("error while locking orders", err.Error())
+		return err
+	}
+
+	// drugs of an open checked out order are still taken from lots, it is cancelled first to give them back
+	if checkedOut && status != config.OrderCancelled && status != config.OrderDelivered && status != config.OrderRefunded {
+		return storage.ErrOrderOpen
+	}
+
 	query := `
 	update orders
 	 set deleted_at = $1
-	  where id = $2
+	  where id = $2 and deleted_at is null
 	`
 
-	rowsAffected, err := o.pool.Exec(ctx, query, time.Now(), id)
+	if _, err = tx.Exec(ctx, query, time.Now(), id); err != nil {
+		log.Println("error while deleting orders  by id", err.Error())
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Checkout creates the order with its lines in one transaction. Drug rows are locked in id order so parallel
+// checkouts of the same drugs wait for each other instead of deadlocking, and nothing is written unless every
+// line can be sold, otherwise storage.CheckoutError reports the rejected lines
+func (o *ordersRepo) Checkout(ctx context.Context, request models.CheckoutOrder) (string, error) {
+
+	type stock struct {
+		drug                 catalogueDrug
+		count                int
+		price                string
+		drugStoreBranchID    string
+		prescriptionRequired bool
+	}
+
+	var (
+		id      = uuid.New()
+		drugIDs = make([]string, 0, len(request.Items))
+		stocks  = make(map[string]stock, len(request.Items))
+		lines   = []models.CheckoutLineError{}
+	)
+
+	for _, item := range request.Items {
+		drugIDs = append(drugIDs, item.DrugID)
+	}
+
+	tx, err := conn(ctx, o.pool).Begin(ctx)
+	if err != nil {
+		log.Println("error while starting transaction", err.Error())
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	// only unexpired lots can be sold
+	query := `select
+	 id,
+	 (select coalesce(sum(l.quantity), 0) from drug_lot l
+	   where l.drug_id = drug.id and l.deleted_at is null
+	    and (l.expiry_date is null or l.expiry_date >= current_date)),
+	 price::text,
+	 coalesce(drug_store_branch_id::text, ''),
+	 drug_catalogue_id,
+	 name,
+	 prescription_required
+	 from drug where deleted_at is null and id::text = any($1)
+	 order by id for update`
+
+	rows, err := tx.Query(ctx, query, drugIDs)
+	if err != nil {
+		log.Println("error while locking drugs", err.Error())
+		return "", err
+	}
+
+	for rows.Next() {
+		var (
+			drugID string
+			s      stock
+		)
+
+		if err = rows.Scan(&drugID, &s.count, &s.price, &s.drugStoreBranchID, &s.drug.id, &s.drug.name, &s.prescriptionRequired); err != nil {
+			rows.Close()
+			log.Println("error while scanning drug stock", err.Error())
+			return "", err
+		}
+
+		stocks[drugID] = s
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		log.Println("error while reading drug stock", err.Error())
+		return "", err
+	}
+
+	drugs := make(map[string]catalogueDrug, len(stocks))
+	for drugID, s := range stocks {
+		drugs[drugID] = s.drug
+	}
+
+	prescriptionErrors, err := checkPrescriptionItems(ctx, tx, request, drugs)
+	if err != nil {
+		log.Println("error while checking prescription", err.Error())
+		return "", err
+	}
+
+	for _, item := range request.Items {
+		s, ok := stocks[item.DrugID]
+
+		switch {
+		case !ok:
+			lines = append(lines, models.CheckoutLineError{
+				DrugID:   item.DrugID,
+				Quantity: item.Quantity,
+				Error:    "drug not found",
+			})
+
+		case s.drugStoreBranchID != request.DrugStoreBranchID:
+			lines = append(lines, models.CheckoutLineError{
+				DrugID:   item.DrugID,
+				Quantity: item.Quantity,
+				Error:    "drug is not sold in this drug store branch",
+			})
+
+		case prescriptionErrors[item.DrugID] != "":
+			lines = append(lines, models.CheckoutLineError{
+				DrugID:   item.DrugID,
+				Quantity: item.Quantity,
+				Error:    prescriptionErrors[item.DrugID],
+			})
+
+		case s.prescriptionRequired && item.PrescriptionItemID == "":
+			lines = append(lines, models.CheckoutLineError{
+				DrugID:   item.DrugID,
+				Quantity: item.Quantity,
+				Error:    "drug is sold only by prescription",
+			})
+
+		case s.count < item.Quantity:
+			lines = append(lines, models.CheckoutLineError{
+				DrugID:    item.DrugID,
+				Quantity:  item.Quantity,
+				Available: s.count,
+				Error:     "not enough drugs in stock",
+			})
+
+		// totals were computed from the price read before the lock
+		case !samePrice(s.price, item.UnitPrice):
+			lines = append(lines, models.CheckoutLineError{
+				DrugID:    item.DrugID,
+				Quantity:  item.Quantity,
+				Available: s.count,
+				Error:     "drug price has changed, review the order",
+			})
+		}
+	}
+
+	if len(lines) > 0 {
+		return "", storage.CheckoutError{Lines: lines}
+	}
+
+	query = `insert into orders
+	 (id,
+	  pharmacist_id,
+	  customer_id,
+	  drug_store_branch_id,
+	  subtotal,
+	  discount,
+	  total,
+	  currency,
+	  prescription_id)
+	  values ($1, $2, $3, $4, $5::numeric, $6::numeric, $7::numeric, $8, $9)`
+
+	if _, err = tx.Exec(ctx, query,
+		id,
+		nullIfEmpty(request.PharmacistID),
+		request.CustomerID,
+		request.DrugStoreBranchID,
+		request.Subtotal,
+		request.Discount,
+		request.Total,
+		request.Currency,
+		nullIfEmpty(request.PrescriptionID),
+	); err != nil {
+		log.Println("error while inserting orders ", err.Error())
+		return "", err
+	}
+
+	for _, item := range request.Items {
+		orderDrugID := uuid.New()
+
+		query = `insert into order_drug
+		 (id,
+		  drug_id,
+		  orders_id,
+		  quantity,
+		  unit_price,
+		  line_total,
+		  prescription_item_id)
+		  values ($1, $2, $3, $4, $5::numeric, $6::numeric, $7)`
+
+		if _, err = tx.Exec(ctx, query,
+			orderDrugID,
+			item.DrugID,
+			id,
+			item.Quantity,
+			item.UnitPrice,
+			item.LineTotal,
+			nullIfEmpty(item.PrescriptionItemID),
+		); err != nil {
+			log.Println("error while inserting order drug ", err.Error())
+			return "", err
+		}
+
+		if item.PrescriptionItemID != "" {
+			query = `update prescription_item set
+			 dispensed_quantity = dispensed_quantity + $2,
+			 redeemed_count = ceil((dispensed_quantity + $2)::numeric / quantity),
+			 updated_at = now()
+			 where id = $1`
+
+			if _, err = tx.Exec(ctx, query, item.PrescriptionItemID, item.Quantity); err != nil {
+				log.Println("error while redeeming prescription item ", err.Error())
+				return "", err
+			}
+		}
+
+		if err = allocateLots(ctx, tx, item.DrugID, orderDrugID.String(), item.Quantity); err != nil {
+			log.Println("error while taking drugs from lots ", err.Error())
+			return "", err
+		}
+	}
+
+	if err = syncDrugCount(ctx, tx, drugIDs...); err != nil {
+		log.Println("error while updating drug counts ", err.Error())
+		return "", err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Println("error while committing order ", err.Error())
+		return "", err
+	}
+
+	return id.String(), nil
+}
+
+func samePrice(a, b string) bool {
+	x, err := money.Parse(a)
+	if err != nil {
+		return false
+	}
+
+	y, err := money.Parse(b)
+	if err != nil {
+		return false
+	}
+
+	return x == y
+}
+
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+
+	return s
+}
+
+// orderStatusTimes maps a status to the column keeping the time the order got it
+var orderStatusTimes = map[string]string{
+	config.OrderConfirmed:      "confirmed_at",
+	config.OrderPreparing:      "preparing_at",
+	config.OrderReadyForPickup: "ready_for_pickup_at",
+	config.OrderDelivered:      "delivered_at",
+	config.OrderCancelled:      "cancelled_at",
+	config.OrderRefunded:       "refunded_at",
+}
+
+func (o *ordersRepo) UpdateStatus(ctx context.Context, request models.UpdateOrderStatus) error {
 
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
+	column, ok := orderStatusTimes[request.ToStatus]
+	if !ok {
+		return fmt.Errorf("unknown order status %q", request.ToStatus)
+	}
+
+	tx, err := conn(ctx, o.pool).Begin(ctx)
+	if err != nil {
+		log.Println("error while starting transaction", err.Error())
 		return err
 	}
+	defer tx.Rollback(ctx)
+
+	// orders built line by line never took their drugs from lots, only checked out orders can be confirmed
+	if request.ToStatus == config.OrderConfirmed {
+		checkedOut := false
+		if err = tx.QueryRow(ctx, `select drug_store_branch_id is not null from orders
+		 where id = $1 and deleted_at is null for update`, request.ID).Scan(&checkedOut); err != nil {
+			log.Println("error while locking order", err.Error())
+			return err
+		}
+
+		if !checkedOut {
+			return storage.ErrOrderNotCheckedOut
+		}
+	}
+
+	query := fmt.Sprintf(`update orders set
+	 status = $1,
+	 %s = now(),
+	 version = version + 1,
+	 updated_at = now()
+	  where id = $2 and status = $3 and deleted_at is null`, column)
 
+	rowsAffected, err := tx.Exec(ctx, query, request.ToStatus, request.ID, request.FromStatus)
 	if err != nil {
-		log.Println("error while deleting orders  by id", err.Error())
+		log.Println("error while updating order status", err.Error())
+		return err
+	}
+
+	if rowsAffected.RowsAffected() == 0 {
+		return storage.ErrStatusChanged
+	}
+
+	if request.RestoreStock {
+		drugIDs := []string{}
+
+		// only drugs taken from lots at checkout go back, lines of an order which was never checked out took nothing
+		rows, err := tx.Query(ctx, `select distinct l.drug_id::text from order_drug_lot a
+		 join drug_lot l on l.id = a.drug_lot_id
+		 join order_drug od on od.id = a.order_drug_id
+		 where od.orders_id = $1`, request.ID)
+		if err != nil {
+			log.Println("error while selecting order drugs", err.Error())
+			return err
+		}
+
+		for rows.Next() {
+			drugID := ""
+			if err = rows.Scan(&drugID); err != nil {
+				rows.Close()
+				log.Println("error while scanning order drug", err.Error())
+				return err
+			}
+			drugIDs = append(drugIDs, drugID)
+		}
+		rows.Close()
+
+		if err = lockDrugs(ctx, tx, drugIDs...); err != nil {
+			log.Println("error while locking order drugs", err.Error())
+			return err
+		}
+
+		if err = restoreLots(ctx, tx, request.ID); err != nil {
+			log.Println("error while restoring drug lots", err.Error())
+			return err
+		}
+
+		if err = syncDrugCount(ctx, tx, drugIDs...); err != nil {
+			log.Println("error while updating drug counts", err.Error())
+			return err
+		}
+
+		// a cancelled redemption gives the sold quantity back to the prescription
+		query = `update prescription_item set
+		 dispensed_quantity = prescription_item.dispensed_quantity - od.quantity,
+		 redeemed_count = ceil((prescription_item.dispensed_quantity - od.quantity)::numeric / prescription_item.quantity),
+		 updated_at = now()
+		 from order_drug od
+		 where od.orders_id = $1 and od.prescription_item_id = prescription_item.id`
+
+		if _, err = tx.Exec(ctx, query, request.ID); err != nil {
+			log.Println("error while restoring prescription refills", err.Error())
+			return err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Println("error while committing order status", err.Error())
 		return err
 	}
 
 	return nil
 }
+
+func (o *ordersRepo) Restore(ctx context.Context, id string) error {
+	return restore(ctx, conn(ctx, o.pool), "orders", id)
+}
@@ -6,11 +6,12 @@ import (
 	"fmt"
 	"log"
 	"shifolink/api/models"
-	"shifolink/pkg/check"
+	"shifolink/pkg/security"
 	"shifolink/storage"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -28,6 +29,12 @@ func (p *pharmacistRepo) Create(ctx context.Context, request models.CreatePharma
 
 	id := uuid.New()
 
+	hashedPassword, err := security.HashPassword(request.Password)
+	if err != nil {
+		log.Println("error while hashing pharmacist password", err.Error())
+		return "", err
+	}
+
 	query := `insert into pharmacist (
 		id, 
 		drug_store_branch_id, 
@@ -41,30 +48,29 @@ func (p *pharmacistRepo) Create(ctx context.Context, request models.CreatePharma
 		age, 
 		address) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
 
-	rowsAffected, err := p.pool.Exec(ctx, query,
+	rowsAffected, err := conn(ctx, p.pool).Exec(ctx, query,
 		id,
 		request.DrugStoreBranchID,
 		request.FirstName,
 		request.LastName,
 		request.Email,
-		request.Password,
+		hashedPassword,
 		request.Phone,
 		request.Gender,
 		request.BirthDate,
-		check.CalculateAge(request.BirthDate),
+		request.Age,
 		request.Address,
 	)
 
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return "", err
-	}
-
 	if err != nil {
 		log.Println("error while inserting pharmacist", err.Error())
 		return "", err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return "", pgx.ErrNoRows
+	}
+
 	return id.String(), nil
 
 }
@@ -81,17 +87,17 @@ func (p *pharmacistRepo) Get(ctx context.Context, request models.PrimaryKey) (mo
 	 first_name, 
 	 last_name, 
 	 email, 
-	 password, 
 	 phone, 
 	 gender, 
 	 birth_date::text, 
 	 age, 
 	 address, 
+	 version,
 	 created_at, 
 	 updated_at 
 	 from pharmacist where deleted_at is null and id = $1`
 
-	row := p.pool.QueryRow(ctx, query, request.ID)
+	row := conn(ctx, p.pool).QueryRow(ctx, query, request.ID)
 
 	err := row.Scan(
 		&pharmacist.ID,
@@ -99,12 +105,12 @@ func (p *pharmacistRepo) Get(ctx context.Context, request models.PrimaryKey) (mo
 		&pharmacist.FirstName,
 		&pharmacist.LastName,
 		&pharmacist.Email,
-		&pharmacist.Password,
 		&pharmacist.Phone,
 		&pharmacist.Gender,
 		&pharmacist.BirthDate,
 		&pharmacist.Age,
 		&pharmacist.Address,
+		&pharmacist.Version,
 		&pharmacist.CreatedAt,
 		&updatedAt,
 	)
@@ -122,24 +128,32 @@ func (p *pharmacistRepo) Get(ctx context.Context, request models.PrimaryKey) (mo
 
 }
 
-func (p *pharmacistRepo) GetList(ctx context.Context, request models.GetListRequest) (models.PharmacistsResponse, error) {
+// pharmacistSortColumns are columns the pharmacist list can be sorted by, besides created_at
+var pharmacistSortColumns = sortColumns{
+	"first_name": "text",
+	"last_name":  "text",
+}
+
+func (p *pharmacistRepo) GetList(ctx context.Context, request models.GetPharmacistsListRequest) (models.PharmacistsResponse, error) {
 
 	var (
 		updatedAt         = sql.NullTime{}
+		deletedAt         = sql.NullTime{}
 		pharmacists       = []models.Pharmacist{}
 		count             = 0
 		query, countQuery string
-		page              = request.Page
-		offset            = (page - 1) * request.Limit
-		search            = request.Search
 	)
 
-	countQuery = `select count(1) from pharmacist where deleted_at is null`
+	filter := newListFilter(request.GetListRequest, "first_name", "last_name")
+	filter.Equal("drug_store_branch_id", request.DrugStoreBranchID)
 
-	if search != "" {
-		countQuery += fmt.Sprintf(` and (first_name ilike '%%%s%%' or last_name ilike '%%%s%%')`, search, search)
+	if err := filter.Sort(request.GetListRequest, pharmacistSortColumns); err != nil {
+		return models.PharmacistsResponse{}, err
 	}
-	if err := p.pool.QueryRow(ctx, countQuery).Scan(&count); err != nil {
+
+	countQuery = `select count(1) from pharmacist` + filter.Where()
+
+	if err := conn(ctx, p.pool).QueryRow(ctx, countQuery, filter.Args()...).Scan(&count); err != nil {
 		fmt.Println("error is while selecting count", err.Error())
 		return models.PharmacistsResponse{}, err
 	}
@@ -150,21 +164,20 @@ func (p *pharmacistRepo) GetList(ctx context.Context, request models.GetListRequ
 	 first_name, 
 	 last_name, 
 	 email, 
-	 password, 
 	 phone, 
 	 gender, 
 	 birth_date::text, 
 	 age, 
 	 address, 
+	 version,
 	 created_at, 
-	 updated_at from pharmacist where deleted_at is null`
+	 updated_at,
+	 deleted_at from pharmacist`
 
-	if search != "" {
-		query += fmt.Sprintf(` and (first_name ilike '%%%s%%' or last_name ilike '%%%s%%')`, search, search)
-	}
+	query += filter.Where()
 
-	query += ` LIMIT $1 OFFSET $2`
-	rows, err := p.pool.Query(ctx, query, request.Limit, offset)
+	pagination, args := filter.Page(request.Page, request.Limit)
+	rows, err := conn(ctx, p.pool).Query(ctx, query+pagination, args...)
 	if err != nil {
 		fmt.Println("error is while selecting pharmacist", err.Error())
 		return models.PharmacistsResponse{}, err
@@ -178,14 +191,15 @@ func (p *pharmacistRepo) GetList(ctx context.Context, request models.GetListRequ
 			&pharmacist.FirstName,
 			&pharmacist.LastName,
 			&pharmacist.Email,
-			&pharmacist.Password,
 			&pharmacist.Phone,
 			&pharmacist.Gender,
 			&pharmacist.BirthDate,
 			&pharmacist.Age,
 			&pharmacist.Address,
+			&pharmacist.Version,
 			&pharmacist.CreatedAt,
 			&updatedAt,
+			&deletedAt,
 		); err != nil {
 			fmt.Println("error is while scanning pharmacist data", err.Error())
 			return models.PharmacistsResponse{}, err
@@ -195,6 +209,10 @@ func (p *pharmacistRepo) GetList(ctx context.Context, request models.GetListRequ
 			pharmacist.UpdatedAt = updatedAt.Time
 		}
 
+		if deletedAt.Valid {
+			pharmacist.DeletedAt = deletedAt.Time
+		}
+
 		pharmacists = append(pharmacists, pharmacist)
 
 	}
@@ -214,11 +232,12 @@ func (p *pharmacistRepo) Update(ctx context.Context, request models.UpdatePharma
 	email = $4,
 	phone = $5,
     address = $6,
+	version = version + 1,
 	updated_at = $7
-   where id = $8
+   where id = $8 and deleted_at is null and ($9 = 0 or version = $9)
    `
 
-	rowsAffected, err := p.pool.Exec(ctx, query,
+	rowsAffected, err := conn(ctx, p.pool).Exec(ctx, query,
 		request.DrugStoreBranchID,
 		request.FirstName,
 		request.LastName,
@@ -226,18 +245,18 @@ func (p *pharmacistRepo) Update(ctx context.Context, request models.UpdatePharma
 		request.Phone,
 		request.Address,
 		time.Now(),
-		request.ID)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return "", err
-	}
+		request.ID,
+		request.Version)
 
 	if err != nil {
 		log.Println("error while updating pharmacist data...", err.Error())
 		return "", err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return "", missedUpdate(ctx, conn(ctx, p.pool), "pharmacist", request.ID, request.Version)
+	}
+
 	return request.ID, nil
 
 }
@@ -247,21 +266,20 @@ func (p *pharmacistRepo) Delete(ctx context.Context, id string) error {
 	query := `
 	update pharmacist
 	 set deleted_at = $1
-	  where id = $2
+	  where id = $2 and deleted_at is null
 	`
 
-	rowsAffected, err := p.pool.Exec(ctx, query, time.Now(), id)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return err
-	}
+	rowsAffected, err := conn(ctx, p.pool).Exec(ctx, query, time.Now(), id)
 
 	if err != nil {
 		log.Println("error while deleting pharmacist by id", err.Error())
 		return err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
 	return nil
 }
 
@@ -270,9 +288,9 @@ func (p *pharmacistRepo) GetPassword(ctx context.Context, id string) (string, er
 
 	query := `
 		select password from pharmacist 
-						where id = $1`
+						where id = $1 and deleted_at is null`
 
-	if err := p.pool.QueryRow(ctx, query, id).Scan(&password); err != nil {
+	if err := conn(ctx, p.pool).QueryRow(ctx, query, id).Scan(&password); err != nil {
 		fmt.Println("Error while scanning password from pharmacist", err.Error())
 		return "", err
 	}
@@ -282,22 +300,43 @@ func (p *pharmacistRepo) GetPassword(ctx context.Context, id string) (string, er
 
 func (p *pharmacistRepo) UpdatePassword(ctx context.Context, request models.UpdatePharmacistPassword) error {
 
+	hashedPassword, err := security.HashPassword(request.NewPassword)
+	if err != nil {
+		log.Println("error while hashing pharmacist password", err.Error())
+		return err
+	}
+
 	query := `
 		update pharmacist 
 				set password = $1, updated_at = now()
-					where id = $2`
+					where id = $2 and deleted_at is null`
 
-	rowsAffected, err := p.pool.Exec(ctx, query, request.NewPassword, request.ID)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return err
-	}
+	rowsAffected, err := conn(ctx, p.pool).Exec(ctx, query, hashedPassword, request.ID)
 
 	if err != nil {
 		fmt.Println("error while updating password for pharmacist", err.Error())
 		return err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
 	return nil
 }
+
+func (p *pharmacistRepo) GetByLogin(ctx context.Context, login string) (models.Pharmacist, error) {
+	id, err := loginID(ctx, conn(ctx, p.pool), "pharmacist", login)
+	if err != nil {
+		fmt.Println("error while selecting pharmacist by login", err.Error())
+		return models.Pharmacist{}, err
+	}
+
+	return p.Get(ctx, models.PrimaryKey{
+		ID: id,
+	})
+}
+
+func (p *pharmacistRepo) Restore(ctx context.Context, id string) error {
+	return restore(ctx, conn(ctx, p.pool), "pharmacist", id)
+}
@@ -2,25 +2,33 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"shifolink/config"
+	"shifolink/migrations"
+	"shifolink/pkg/pubsub"
 	"shifolink/storage"
 	"strings"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database"          //database is needed for migration
 	_ "github.com/golang-migrate/migrate/v4/database/postgres" //postgres is used for database
-	_ "github.com/golang-migrate/migrate/v4/source/file"       //file is needed for migration url
 
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/lib/pq"
 )
 
 type Store struct {
-	pool *pgxpool.Pool
+	pool   *pgxpool.Pool
+	cfg    config.Config
+	broker *pubsub.Broker
+	// migrationVersion is the version of the last migration embedded from migrations/postgres
+	migrationVersion uint
 }
 
-func New(ctx context.Context, cfg config.Config) (storage.IStorage, error) {
+func New(ctx context.Context, cfg config.Config, broker *pubsub.Broker) (storage.IStorage, error) {
 	url := fmt.Sprintf(
 		`postgres://%s:%s@%s:%s/%s?sslmode=disable`,
 		cfg.PostgresUser,
@@ -44,8 +52,13 @@ func New(ctx context.Context, cfg config.Config) (storage.IStorage, error) {
 		return nil, err
 	}
 
+	if err = observePool(pool); err != nil {
+		fmt.Println("error while registering pool metrics", err.Error())
+		return nil, err
+	}
+
 	// migration
-	m, err := migrate.New("file://migrations/postgres/", url)
+	m, err := newMigrate(url)
 	if err != nil {
 		fmt.Println("error while migrating", err.Error())
 		return nil, err
@@ -72,8 +85,17 @@ func New(ctx context.Context, cfg config.Config) (storage.IStorage, error) {
 		}
 	}
 
+	migrationVersion, err := lastMigrationVersion()
+	if err != nil {
+		fmt.Println("error while reading migrations", err.Error())
+		return nil, err
+	}
+
 	return Store{
-		pool: pool,
+		pool:             pool,
+		cfg:              cfg,
+		broker:           broker,
+		migrationVersion: migrationVersion,
 	}, nil
 
 }
@@ -82,6 +104,75 @@ func (s Store) CloseDB() {
 	s.pool.Close()
 }
 
+func (s Store) Ping(ctx context.Context) error {
+	return s.pool.Ping(ctx)
+}
+
+func (s Store) CheckMigrations(ctx context.Context) error {
+
+	var (
+		version uint
+		dirty   bool
+	)
+
+	if err := s.pool.QueryRow(ctx, `select version, dirty from schema_migrations`).Scan(&version, &dirty); err != nil {
+		fmt.Println("error while selecting migration version", err.Error())
+		return err
+	}
+
+	if dirty {
+		return fmt.Errorf("migration %d did not finish", version)
+	}
+
+	if version != s.migrationVersion {
+		return fmt.Errorf("database is at migration %d, expected %d", version, s.migrationVersion)
+	}
+
+	return nil
+}
+
+// newMigrate migrates the database of the url with the migrations embedded in the binary
+func newMigrate(url string) (*migrate.Migrate, error) {
+
+	driver, err := iofs.New(migrations.Postgres, "postgres")
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.NewWithSourceInstance("iofs", driver, url)
+}
+
+// lastMigrationVersion returns the version of the newest embedded migration
+func lastMigrationVersion() (uint, error) {
+
+	driver, err := iofs.New(migrations.Postgres, "postgres")
+	if err != nil {
+		return 0, err
+	}
+	defer driver.Close()
+
+	version, err := driver.First()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		next, err := driver.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		version = next
+	}
+}
+
+func (s Store) AuditLog() storage.IAuditLogRepo {
+	return NewAuditLogRepo(s.pool)
+}
+
 func (s Store) Author() storage.IAuthorRepo {
 	return NewAuthorRepo(s.pool)
 }
@@ -110,10 +201,18 @@ func (s Store) Doctor() storage.IDoctorRepo {
 	return NewDoctorRepo(s.pool)
 }
 
+func (s Store) DoctorSchedule() storage.IDoctorScheduleRepo {
+	return NewDoctorScheduleRepo(s.pool)
+}
+
 func (s Store) DrugStoreBranch() storage.IDrugStoreBranchRepo {
 	return NewDrugStoreBranchRepo(s.pool)
 }
 
+func (s Store) DrugCatalogue() storage.IDrugCatalogueRepo {
+	return NewDrugCatalogueRepo(s.pool)
+}
+
 func (s Store) DrugStore() storage.IDrugStoreRepo {
 	return NewDrugStoreRepo(s.pool)
 }
@@ -122,10 +221,18 @@ func (s Store) Drug() storage.IDrugRepo {
 	return NewDrugRepo(s.pool)
 }
 
+func (s Store) DrugLot() storage.IDrugLotRepo {
+	return NewDrugLotRepo(s.pool)
+}
+
 func (s Store) Journal() storage.IJournalRepo {
 	return NewJournalRepo(s.pool)
 }
 
+func (s Store) JournalCategory() storage.IJournalCategoryRepo {
+	return NewJournalCategoryRepo(s.pool)
+}
+
 func (s Store) OrderDrug() storage.IOrderDrugRepo {
 	return NewOrderDrugRepo(s.pool)
 }
@@ -138,10 +245,18 @@ func (s Store) Pharmacist() storage.IPharmacistRepo {
 	return NewPharmacistRepo(s.pool)
 }
 
+func (s Store) Prescription() storage.IPrescriptionRepo {
+	return NewPrescriptionRepo(s.pool)
+}
+
 func (s Store) Queue() storage.IQueueRepo {
-	return NewQueueRepo(s.pool)
+	return NewQueueRepo(s.pool, s.cfg.QueueNumberFormat, s.broker)
 }
 
 func (s Store) SuperAdmin() storage.ISuperAdminRepo {
 	return NewSuperAdminRepo(s.pool)
 }
+
+func (s Store) Trash() storage.ITrashRepo {
+	return NewTrashRepo(s.pool)
+}
@@ -0,0 +1,377 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"shifolink/api/models"
+	"shifolink/storage"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type prescriptionRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewPrescriptionRepo(pool *pgxpool.Pool) storage.IPrescriptionRepo {
+	return &prescriptionRepo{
+		pool: pool,
+	}
+}
+
+const prescriptionColumns = `
+	 id,
+	 code,
+	 queue_id,
+	 doctor_id,
+	 customer_id,
+	 note,
+	 to_char(valid_until, 'YYYY-MM-DD'),
+	 created_at,
+	 updated_at`
+
+func scanPrescription(row pgx.Row) (models.Prescription, error) {
+
+	var updatedAt = sql.NullTime{}
+
+	prescription := models.Prescription{}
+
+	if err := row.Scan(
+		&prescription.ID,
+		&prescription.Code,
+		&prescription.QueueID,
+		&prescription.DoctorID,
+		&prescription.CustomerID,
+		&prescription.Note,
+		&prescription.ValidUntil,
+		&prescription.CreatedAt,
+		&updatedAt,
+	); err != nil {
+		return models.Prescription{}, err
+	}
+
+	if updatedAt.Valid {
+		prescription.UpdatedAt = updatedAt.Time
+	}
+
+	return prescription, nil
+}
+
+func (p *prescriptionRepo) Create(ctx context.Context, request models.CreatePrescription) (string, error) {
+
+	id := uuid.New()
+
+	tx, err := conn(ctx, p.pool).Begin(ctx)
+	if err != nil {
+		log.Println("error while starting transaction", err.Error())
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `insert into prescription
+	 (id,
+	  code,
+	  queue_id,
+	  doctor_id,
+	  customer_id,
+	  note,
+	  valid_until)
+	  values ($1, $2, $3, $4, $5, $6, $7::date)`
+
+	if _, err = tx.Exec(ctx, query,
+		id,
+		request.Code,
+		request.QueueID,
+		request.DoctorID,
+		request.CustomerID,
+		request.Note,
+		request.ValidUntil,
+	); err != nil {
+		log.Println("error while inserting prescription", err.Error())
+		return "", err
+	}
+
+	for _, item := range request.Items {
+		query = `insert into prescription_item
+		 (id,
+		  prescription_id,
+		  drug_catalogue_id,
+		  drug_name,
+		  dosage,
+		  duration_days,
+		  quantity,
+		  refills)
+		  values ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+		if _, err = tx.Exec(ctx, query,
+			uuid.New(),
+			id,
+			item.DrugCatalogueID,
+			item.DrugName,
+			item.Dosage,
+			item.DurationDays,
+			item.Quantity,
+			item.Refills,
+		); err != nil {
+			log.Println("error while inserting prescription item", err.Error())
+			return "", err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Println("error while committing prescription", err.Error())
+		return "", err
+	}
+
+	return id.String(), nil
+}
+
+func (p *prescriptionRepo) Get(ctx context.Context, request models.PrimaryKey) (models.Prescription, error) {
+
+	query := `select ` + prescriptionColumns + `
+	 from prescription where deleted_at is null and id = $1`
+
+	return p.getWithItems(ctx, query, request.ID)
+}
+
+func (p *prescriptionRepo) GetByCode(ctx context.Context, code string) (models.Prescription, error) {
+
+	query := `select ` + prescriptionColumns + `
+	 from prescription where deleted_at is null and code = $1`
+
+	return p.getWithItems(ctx, query, strings.ToUpper(code))
+}
+
+func (p *prescriptionRepo) GetByCustomer(ctx context.Context, customerID string) (models.PrescriptionsResponse, error) {
+
+	prescriptions := []models.Prescription{}
+
+	query := `select ` + prescriptionColumns + `
+	 from prescription where deleted_at is null and customer_id = $1
+	 order by created_at desc`
+
+	rows, err := conn(ctx, p.pool).Query(ctx, query, customerID)
+	if err != nil {
+		fmt.Println("error is while selecting prescriptions", err.Error())
+		return models.PrescriptionsResponse{}, err
+	}
+
+	for rows.Next() {
+		prescription, err := scanPrescription(rows)
+		if err != nil {
+			rows.Close()
+			fmt.Println("error is while scanning prescription data", err.Error())
+			return models.PrescriptionsResponse{}, err
+		}
+
+		prescriptions = append(prescriptions, prescription)
+	}
+	rows.Close()
+
+	for i := range prescriptions {
+		if prescriptions[i].Items, err = p.getItems(ctx, prescriptions[i].ID); err != nil {
+			fmt.Println("error is while selecting prescription items", err.Error())
+			return models.PrescriptionsResponse{}, err
+		}
+	}
+
+	return models.PrescriptionsResponse{
+		Prescriptions: prescriptions,
+		Count:         len(prescriptions),
+	}, nil
+}
+
+func (p *prescriptionRepo) Delete(ctx context.Context, id string) error {
+
+	query := `
+	update prescription
+	 set deleted_at = $1
+	  where id = $2 and deleted_at is null
+	`
+
+	rowsAffected, err := conn(ctx, p.pool).Exec(ctx, query, time.Now(), id)
+	if err != nil {
+		log.Println("error while deleting prescription by id", err.Error())
+		return err
+	}
+
+	if rowsAffected.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+func (p *prescriptionRepo) getWithItems(ctx context.Context, query string, arg string) (models.Prescription, error) {
+
+	prescription, err := scanPrescription(conn(ctx, p.pool).QueryRow(ctx, query, arg))
+	if err != nil {
+		log.Println("error while selecting prescription", err.Error())
+		return models.Prescription{}, err
+	}
+
+	if prescription.Items, err = p.getItems(ctx, prescription.ID); err != nil {
+		log.Println("error while selecting prescription items", err.Error())
+		return models.Prescription{}, err
+	}
+
+	return prescription, nil
+}
+
+func (p *prescriptionRepo) getItems(ctx context.Context, prescriptionID string) ([]models.PrescriptionItem, error) {
+
+	items := []models.PrescriptionItem{}
+
+	query := `select
+	 id,
+	 prescription_id,
+	 coalesce(drug_catalogue_id::text, ''),
+	 drug_name,
+	 dosage,
+	 duration_days,
+	 quantity,
+	 refills,
+	 redeemed_count,
+	 dispensed_quantity
+	 from prescription_item where prescription_id = $1
+	 order by created_at`
+
+	rows, err := conn(ctx, p.pool).Query(ctx, query, prescriptionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.PrescriptionItem{}
+		if err = rows.Scan(
+			&item.ID,
+			&item.PrescriptionID,
+			&item.DrugCatalogueID,
+			&item.DrugName,
+			&item.Dosage,
+			&item.DurationDays,
+			&item.Quantity,
+			&item.Refills,
+			&item.RedeemedCount,
+			&item.DispensedQuantity,
+		); err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// catalogueDrug is the catalogue drug a drug of a branch sells
+type catalogueDrug struct {
+	id   string
+	name string
+}
+
+// checkPrescriptionItems locks prescription items the checkout redeems and returns why a line can not be
+// redeemed, keyed by drug id. Dispensed quantities are counted under this lock so a fill can not be used twice.
+// A line sells at most one fill, the part of a fill left by a smaller sale stays for the next one. Drugs match
+// an item by the catalogue drug, items written before the catalogue by the drug name
+func checkPrescriptionItems(ctx context.Context, tx pgx.Tx, request models.CheckoutOrder, drugs map[string]catalogueDrug) (map[string]string, error) {
+
+	type prescribed struct {
+		drug       catalogueDrug
+		quantity   int
+		refills    int
+		dispensed  int
+		customerID string
+		valid      bool
+	}
+
+	var (
+		itemIDs  = []string{}
+		items    = map[string]prescribed{}
+		problems = map[string]string{}
+	)
+
+	for _, item := range request.Items {
+		if item.PrescriptionItemID != "" {
+			itemIDs = append(itemIDs, item.PrescriptionItemID)
+		}
+	}
+
+	if len(itemIDs) == 0 {
+		return problems, nil
+	}
+
+	query := `select
+	 pi.id,
+	 coalesce(pi.drug_catalogue_id::text, ''),
+	 pi.drug_name,
+	 pi.quantity,
+	 pi.refills,
+	 pi.dispensed_quantity,
+	 p.customer_id,
+	 p.valid_until >= current_date
+	 from prescription_item pi
+	 join prescription p on p.id = pi.prescription_id
+	 where pi.id::text = any($1) and p.id::text = $2 and p.deleted_at is null
+	 order by pi.id for update of pi`
+
+	rows, err := tx.Query(ctx, query, itemIDs, request.PrescriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		var (
+			id   string
+			item prescribed
+		)
+
+		if err = rows.Scan(&id, &item.drug.id, &item.drug.name, &item.quantity, &item.refills, &item.dispensed,
+			&item.customerID, &item.valid); err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		items[id] = item
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, line := range request.Items {
+		if line.PrescriptionItemID == "" {
+			continue
+		}
+
+		item, ok := items[line.PrescriptionItemID]
+
+		switch {
+		case !ok:
+			problems[line.DrugID] = "prescription item not found"
+		case item.customerID != request.CustomerID:
+			problems[line.DrugID] = "prescription belongs to another customer"
+		case !item.valid:
+			problems[line.DrugID] = "prescription is expired"
+		case item.dispensed >= item.quantity*(item.refills+1):
+			problems[line.DrugID] = "prescription item has no refills left"
+		case line.Quantity > item.quantity:
+			problems[line.DrugID] = fmt.Sprintf("only %d can be sold for the prescription", item.quantity)
+		case item.dispensed+line.Quantity > item.quantity*(item.refills+1):
+			problems[line.DrugID] = fmt.Sprintf("only %d is left on the prescription", item.quantity*(item.refills+1)-item.dispensed)
+		case item.drug.id != "" && drugs[line.DrugID].id != item.drug.id,
+			item.drug.id == "" && !strings.EqualFold(strings.TrimSpace(drugs[line.DrugID].name), strings.TrimSpace(item.drug.name)):
+			problems[line.DrugID] = "drug does not match the prescription"
+		}
+	}
+
+	return problems, nil
+}
@@ -3,23 +3,32 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"shifolink/api/models"
+	"shifolink/config"
+	"shifolink/pkg/pubsub"
+	"shifolink/pkg/slot"
 	"shifolink/storage"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type queueRepo struct {
-	pool *pgxpool.Pool
+	pool         *pgxpool.Pool
+	numberFormat string
+	broker       *pubsub.Broker
 }
 
-func NewQueueRepo(pool *pgxpool.Pool) storage.IQueueRepo {
+func NewQueueRepo(pool *pgxpool.Pool, numberFormat string, broker *pubsub.Broker) storage.IQueueRepo {
 	return &queueRepo{
-		pool: pool,
+		pool:         pool,
+		numberFormat: numberFormat,
+		broker:       broker,
 	}
 }
 
@@ -27,30 +36,53 @@ func (q *queueRepo) Create(ctx context.Context, request models.CreateQueue) (str
 
 	id := uuid.New()
 
+	tx, err := conn(ctx, q.pool).Begin(ctx)
+	if err != nil {
+		log.Println("error while starting transaction", err.Error())
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	s, err := reserveSlot(ctx, tx, request.DoctorID, request.StartTime, "")
+	if err != nil {
+		log.Println("error while reserving slot for queue ", err.Error())
+		return "", err
+	}
+
+	queueNumber, err := q.nextQueueNumber(ctx, tx, request.DoctorID, s.Start)
+	if err != nil {
+		log.Println("error while generating queue number ", err.Error())
+		return "", err
+	}
+
 	query := `insert into queue
 	 (id, 
 	  customer_id,
 	  doctor_id,
-	  queue_time) 
-	  values ($1, $2, $3, $4)`
+	  queue_number,
+	  start_time,
+	  end_time) 
+	  values ($1, $2, $3, $4, $5, $6)`
 
-	rowsAffected, err := q.pool.Exec(ctx, query,
+	if _, err = tx.Exec(ctx, query,
 		id,
 		request.CustomerID,
 		request.DoctorID,
-		request.QueueTime,
-	)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
+		queueNumber,
+		s.Start,
+		s.End,
+	); err != nil {
+		log.Println("error while inserting queue ", err.Error())
 		return "", err
 	}
 
-	if err != nil {
-		log.Println("error while inserting queue ", err.Error())
+	if err = tx.Commit(ctx); err != nil {
+		log.Println("error while committing queue ", err.Error())
 		return "", err
 	}
 
+	q.publish(ctx, id.String(), config.BoardCreated)
+
 	return id.String(), nil
 
 }
@@ -66,19 +98,25 @@ func (q *queueRepo) Get(ctx context.Context, request models.PrimaryKey) (models.
 	 customer_id,
 	 doctor_id,
 	 queue_number,
-	 queue_time,
+	 coalesce(to_char(start_time, 'YYYY-MM-DD HH24:MI'), ''),
+	 coalesce(to_char(end_time, 'YYYY-MM-DD HH24:MI'), ''),
+	 status,
+	 version,
 	 created_at,
 	 updated_at
 	 from queue where deleted_at is null and id = $1`
 
-	row := q.pool.QueryRow(ctx, query, request.ID)
+	row := conn(ctx, q.pool).QueryRow(ctx, query, request.ID)
 
 	err := row.Scan(
 		&queue.ID,
 		&queue.CustomerID,
 		&queue.DoctorID,
 		&queue.QueueNumber,
-		&queue.QueueTime,
+		&queue.StartTime,
+		&queue.EndTime,
+		&queue.Status,
+		&queue.Version,
 		&queue.CreatedAt,
 		&updatedAt,
 	)
@@ -96,24 +134,38 @@ func (q *queueRepo) Get(ctx context.Context, request models.PrimaryKey) (models.
 
 }
 
-func (q *queueRepo) GetList(ctx context.Context, request models.GetListRequest) (models.QueuesResponse, error) {
+// queueSortColumns are columns the queue list can be sorted by, besides created_at
+var queueSortColumns = sortColumns{
+	"start_time":   "timestamp",
+	"queue_number": "text",
+	"status":       "text",
+}
+
+func (q *queueRepo) GetList(ctx context.Context, request models.GetQueuesListRequest) (models.QueuesResponse, error) {
 
 	var (
 		updatedAt         = sql.NullTime{}
+		deletedAt         = sql.NullTime{}
 		queues            = []models.Queue{}
 		count             = 0
 		query, countQuery string
-		page              = request.Page
-		offset            = (page - 1) * request.Limit
-		search            = request.Search
+		sortKey           = sql.NullString{}
 	)
 
-	countQuery = `select count(1) from queue where deleted_at is null`
+	filter := newListFilter(request.GetListRequest, "queue_number")
+	filter.Equal("doctor_id", request.DoctorID)
+	filter.Equal("customer_id", request.CustomerID)
+	filter.Equal("status", request.Status)
+	filter.Filter("doctor_id in (select id from doctor where doctor_type_id in "+
+		"(select id from doctor_type where clinic_branch_id = %s))", request.ClinicBranchID)
 
-	if search != "" {
-		countQuery += fmt.Sprintf(` and (queue_number ilike '%%%s%%')`, search)
+	if err := filter.Sort(request.GetListRequest, queueSortColumns); err != nil {
+		return models.QueuesResponse{}, err
 	}
-	if err := q.pool.QueryRow(ctx, countQuery).Scan(&count); err != nil {
+
+	countQuery = `select count(1) from queue` + filter.Where()
+
+	if err := conn(ctx, q.pool).QueryRow(ctx, countQuery, filter.Args()...).Scan(&count); err != nil {
 		fmt.Println("error is while selecting count", err.Error())
 		return models.QueuesResponse{}, err
 	}
@@ -123,16 +175,19 @@ func (q *queueRepo) GetList(ctx context.Context, request models.GetListRequest)
 	customer_id,
 	doctor_id,
 	queue_number,
-	queue_time,
+	coalesce(to_char(start_time, 'YYYY-MM-DD HH24:MI'), ''),
+	coalesce(to_char(end_time, 'YYYY-MM-DD HH24:MI'), ''),
+	status,
+	version,
 	created_at,
-	updated_at from queue where deleted_at is null`
+	updated_at,
+	deleted_at,
+	` + filter.SortKey() + ` from queue`
 
-	if search != "" {
-		query += fmt.Sprintf(`  and (queue_number ilike '%%%s%%')`, search)
-	}
+	query += filter.Where()
 
-	query += ` LIMIT $1 OFFSET $2`
-	rows, err := q.pool.Query(ctx, query, request.Limit, offset)
+	pagination, args := filter.Page(request.Page, request.Limit)
+	rows, err := conn(ctx, q.pool).Query(ctx, query+pagination, args...)
 	if err != nil {
 		fmt.Println("error is while selecting queue ", err.Error())
 		return models.QueuesResponse{}, err
@@ -145,9 +200,14 @@ func (q *queueRepo) GetList(ctx context.Context, request models.GetListRequest)
 			&queue.CustomerID,
 			&queue.DoctorID,
 			&queue.QueueNumber,
-			&queue.QueueTime,
+			&queue.StartTime,
+			&queue.EndTime,
+			&queue.Status,
+			&queue.Version,
 			&queue.CreatedAt,
-			&updatedAt); err != nil {
+			&updatedAt,
+			&deletedAt,
+			&sortKey); err != nil {
 			fmt.Println("error is while scanning queues data", err.Error())
 			return models.QueuesResponse{}, err
 		}
@@ -156,43 +216,110 @@ func (q *queueRepo) GetList(ctx context.Context, request models.GetListRequest)
 			queue.UpdatedAt = updatedAt.Time
 		}
 
+		if deletedAt.Valid {
+			queue.DeletedAt = deletedAt.Time
+		}
+
 		queues = append(queues, queue)
 
 	}
 
-	return models.QueuesResponse{
+	response := models.QueuesResponse{
 		Queues: queues,
 		Count:  count,
-	}, nil
+	}
+
+	if len(queues) > 0 {
+		response.NextCursor = filter.NextCursor(len(queues), request.Limit, sortKey.String, queues[len(queues)-1].ID)
+	}
+
+	return response, nil
 }
 
 func (q *queueRepo) Update(ctx context.Context, request models.UpdateQueue) (string, error) {
 
+	var (
+		oldDoctorID  string
+		oldStartTime sql.NullTime
+		queueNumber  string
+		status       string
+	)
+
+	tx, err := conn(ctx, q.pool).Begin(ctx)
+	if err != nil {
+		log.Println("error while starting transaction", err.Error())
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	if err = tx.QueryRow(ctx, `select doctor_id, start_time, queue_number, status from queue where id = $1 and deleted_at is null for update`,
+		request.ID).Scan(&oldDoctorID, &oldStartTime, &queueNumber, &status); err != nil {
+		log.Println("error while selecting queue for update", err.Error())
+		return "", err
+	}
+
+	// a visit which began or ended keeps its doctor and time, only its status moves on
+	if status != config.QueueBooked {
+		return "", storage.ErrQueueNotBooked
+	}
+
+	// the queue may be moved to another day or to a doctor of another branch, the old board has to drop it
+	oldItem, oldBranchID, err := getQueueBoardItem(ctx, tx, request.ID)
+	if err != nil {
+		log.Println("error while selecting queue board item", err.Error())
+		return "", err
+	}
+
+	s, err := reserveSlot(ctx, tx, request.DoctorID, request.StartTime, request.ID)
+	if err != nil {
+		log.Println("error while reserving slot for queue ", err.Error())
+		return "", err
+	}
+
+	// a queue moved to another doctor or day takes the next number of that doctor's day
+	if oldDoctorID != request.DoctorID || !oldStartTime.Valid || !sameDay(oldStartTime.Time, s.Start) {
+		if queueNumber, err = q.nextQueueNumber(ctx, tx, request.DoctorID, s.Start); err != nil {
+			log.Println("error while generating queue number ", err.Error())
+			return "", err
+		}
+	}
+
 	query := `update queue set
 	customer_id = $1,
 	doctor_id = $2,
-	queue_time = $3,
-    updated_at = $4
-	 where id = $5  
+	queue_number = $3,
+	start_time = $4,
+	end_time = $5,
+    version = version + 1,
+    updated_at = $6
+	 where id = $7 and deleted_at is null and ($8 = 0 or version = $8)
    `
 
-	rowsAffected, err := q.pool.Exec(ctx, query,
+	rowsAffected, err := tx.Exec(ctx, query,
 		request.CustomerID,
 		request.DoctorID,
-		request.QueueTime,
+		queueNumber,
+		s.Start,
+		s.End,
 		time.Now(),
-		request.ID)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
+		request.ID,
+		request.Version)
+	if err != nil {
+		log.Println("error while updating queue data...", err.Error())
 		return "", err
 	}
 
-	if err != nil {
-		log.Println("error while updating queue data...", err.Error())
+	if rowsAffected.RowsAffected() == 0 {
+		return "", missedUpdate(ctx, tx, "queue", request.ID, request.Version)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Println("error while committing queue ", err.Error())
 		return "", err
 	}
 
+	q.publish(ctx, request.ID, config.BoardUpdated, queueBoardEntry{item: oldItem, branchID: oldBranchID})
+
 	return request.ID, nil
 
 }
@@ -202,21 +329,312 @@ func (q *queueRepo) Delete(ctx context.Context, id string) error {
 	query := `
 	update queue
 	 set deleted_at = $1
-	  where id = $2
+	  where id = $2 and deleted_at is null
 	`
 
-	rowsAffected, err := q.pool.Exec(ctx, query, time.Now(), id)
+	rowsAffected, err := conn(ctx, q.pool).Exec(ctx, query, time.Now(), id)
 
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
+	if err != nil {
+		log.Println("error while deleting queue  by id", err.Error())
 		return err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	q.publish(ctx, id, config.BoardDeleted)
+
+	return nil
+
+}
+
+func (q *queueRepo) GetBookedSlots(ctx context.Context, doctorID, date string) ([]models.Slot, error) {
+
+	slots := []models.Slot{}
+
+	query := `select
+	 to_char(start_time, 'YYYY-MM-DD HH24:MI'),
+	 to_char(end_time, 'YYYY-MM-DD HH24:MI')
+	 from queue where deleted_at is null and status <> 'cancelled' and doctor_id = $1
+	  and start_time >= $2::date and start_time < $2::date + 1
+	  order by start_time`
+
+	rows, err := conn(ctx, q.pool).Query(ctx, query, doctorID, date)
+	if err != nil {
+		fmt.Println("error is while selecting booked slots", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		s := models.Slot{}
+		if err = rows.Scan(&s.StartTime, &s.EndTime); err != nil {
+			fmt.Println("error is while scanning booked slot", err.Error())
+			return nil, err
+		}
+
+		slots = append(slots, s)
+	}
+
+	return slots, nil
+}
+
+func (q *queueRepo) UpdateStatus(ctx context.Context, request models.UpdateQueueStatus) error {
+
+	tx, err := conn(ctx, q.pool).Begin(ctx)
 	if err != nil {
-		log.Println("error while deleting queue  by id", err.Error())
+		log.Println("error while starting transaction", err.Error())
 		return err
 	}
+	defer tx.Rollback(ctx)
+
+	// the doctor row is locked first, two consultations starting at once would both see the doctor empty
+	if request.DoctorStatus != "" {
+		doctorStatus := ""
+
+		if err = tx.QueryRow(ctx, `select status from doctor where id = (select doctor_id from queue where id = $1) for update`,
+			request.ID).Scan(&doctorStatus); err != nil {
+			log.Println("error while selecting doctor for update", err.Error())
+			return err
+		}
+
+		if request.ToStatus == config.QueueInConsultation && doctorStatus == config.DoctorBusy {
+			return storage.ErrDoctorBusy
+		}
+	}
+
+	query := `update queue set
+	 status = $1,
+	 version = version + 1,
+	 updated_at = now()
+	  where id = $2 and status = $3 and deleted_at is null`
+
+	rowsAffected, err := tx.Exec(ctx, query, request.ToStatus, request.ID, request.FromStatus)
+	if err != nil {
+		log.Println("error while updating queue status", err.Error())
+		return err
+	}
+
+	if rowsAffected.RowsAffected() == 0 {
+		return storage.ErrStatusChanged
+	}
+
+	if request.DoctorStatus != "" {
+		query = `update doctor set
+		 status = $1,
+		 version = version + 1,
+		 updated_at = now()
+		  where id = (select doctor_id from queue where id = $2)`
+
+		if _, err = tx.Exec(ctx, query, request.DoctorStatus, request.ID); err != nil {
+			log.Println("error while updating doctor status", err.Error())
+			return err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Println("error while committing queue status", err.Error())
+		return err
+	}
+
+	q.publish(ctx, request.ID, config.BoardUpdated)
 
 	return nil
+}
+
+func (q *queueRepo) GetBoard(ctx context.Context, clinicBranchID, date string) ([]models.QueueBoardItem, error) {
+
+	items := []models.QueueBoardItem{}
+
+	query := queueBoardItemQuery + ` where q.deleted_at is null and dt.clinic_branch_id = $1
+	  and q.start_time >= $2::date and q.start_time < $2::date + 1
+	  order by q.start_time, q.queue_number`
+
+	rows, err := conn(ctx, q.pool).Query(ctx, query, clinicBranchID, date)
+	if err != nil {
+		fmt.Println("error is while selecting queue board", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, _, err := scanQueueBoardItem(rows)
+		if err != nil {
+			fmt.Println("error is while scanning queue board item", err.Error())
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// publish sends the queue to the live board of its clinic branch. Boards of staleBranchIDs, the queue
+// belonged to before, get it as deleted. Events wait for the commit of the change and its errors are only logged
+// queueBoardEntry is a queue as the board of its clinic branch showed it before a change
+type queueBoardEntry struct {
+	item     models.QueueBoardItem
+	branchID string
+}
+
+// publish sends the queue to the board of its branch. A previous entry of another branch or day gets a
+// deleted event, boards are kept per branch and day and the new one would never reach the old board
+func (q *queueRepo) publish(ctx context.Context, id, eventType string, previous ...queueBoardEntry) {
+
+	item, branchID, err := getQueueBoardItem(ctx, conn(ctx, q.pool), id)
+	if err != nil {
+		log.Println("error while selecting queue board item", err.Error())
+		return
+	}
+
+	afterCommit(ctx, func() {
+		q.broker.Publish(branchID, models.QueueBoardEvent{
+			Type:  eventType,
+			Items: []models.QueueBoardItem{item},
+		})
+
+		for _, entry := range previous {
+			if entry.branchID == branchID && boardDay(entry.item.StartTime) == boardDay(item.StartTime) {
+				continue
+			}
+
+			q.broker.Publish(entry.branchID, models.QueueBoardEvent{
+				Type:  config.BoardDeleted,
+				Items: []models.QueueBoardItem{entry.item},
+			})
+		}
+	})
+}
+
+// boardDay returns the YYYY-MM-DD day of the start time of a board item
+func boardDay(startTime string) string {
+	if len(startTime) < len(slot.DateLayout) {
+		return startTime
+	}
+
+	return startTime[:len(slot.DateLayout)]
+}
+
+const queueBoardItemQuery = `select
+	 q.id,
+	 q.queue_number,
+	 q.doctor_id,
+	 d.first_name || ' ' || d.last_name,
+	 coalesce(to_char(q.start_time, 'YYYY-MM-DD HH24:MI'), ''),
+	 coalesce(to_char(q.end_time, 'YYYY-MM-DD HH24:MI'), ''),
+	 q.status,
+	 coalesce(dt.clinic_branch_id::text, '')
+	 from queue q
+	 join doctor d on d.id = q.doctor_id
+	 join doctor_type dt on dt.id = d.doctor_type_id`
+
+// getQueueBoardItem returns the queue even if it is deleted, with the clinic branch its doctor works in
+func getQueueBoardItem(ctx context.Context, db rowQuerier, id string) (models.QueueBoardItem, string, error) {
+	return scanQueueBoardItem(db.QueryRow(ctx, queueBoardItemQuery+` where q.id = $1`, id))
+}
+
+func scanQueueBoardItem(row pgx.Row) (models.QueueBoardItem, string, error) {
+
+	item := models.QueueBoardItem{}
+	branchID := ""
 
+	err := row.Scan(
+		&item.QueueID,
+		&item.QueueNumber,
+		&item.DoctorID,
+		&item.DoctorName,
+		&item.StartTime,
+		&item.EndTime,
+		&item.Status,
+		&branchID,
+	)
+
+	return item, branchID, err
+}
+
+// reserveSlot locks the doctor row so bookings of the same doctor are serialized, then makes sure
+// startTime is a slot of the doctor's schedule which does not overlap any other queue of the doctor
+func reserveSlot(ctx context.Context, tx pgx.Tx, doctorID, startTime, queueID string) (slot.Slot, error) {
+
+	start, err := time.Parse(slot.DateTimeLayout, startTime)
+	if err != nil {
+		return slot.Slot{}, fmt.Errorf("%w: start_time should be in %q format", storage.ErrSlotNotAvailable, slot.DateTimeLayout)
+	}
+
+	if start.Before(slot.Now()) {
+		return slot.Slot{}, fmt.Errorf("%w: start_time is in the past", storage.ErrSlotNotAvailable)
+	}
+
+	lockedID := ""
+	if err = tx.QueryRow(ctx, `select id from doctor where id = $1 and deleted_at is null for update`, doctorID).Scan(&lockedID); err != nil {
+		return slot.Slot{}, err
+	}
+
+	schedule, err := getDoctorScheduleByWeekday(ctx, tx, doctorID, int(start.Weekday()))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return slot.Slot{}, fmt.Errorf("%w: doctor does not work on this day", storage.ErrSlotNotAvailable)
+		}
+		return slot.Slot{}, err
+	}
+
+	s, ok, err := slot.Find(schedule, start)
+	if err != nil {
+		return slot.Slot{}, err
+	}
+
+	if !ok {
+		return slot.Slot{}, storage.ErrSlotNotAvailable
+	}
+
+	taken := 0
+	query := `select count(1) from queue
+	 where deleted_at is null and status <> 'cancelled' and doctor_id = $1 and id::text <> $2
+	  and start_time < $4 and end_time > $3`
+
+	if err = tx.QueryRow(ctx, query, doctorID, queueID, s.Start, s.End).Scan(&taken); err != nil {
+		return slot.Slot{}, err
+	}
+
+	if taken > 0 {
+		return slot.Slot{}, fmt.Errorf("%w: slot is already booked", storage.ErrSlotNotAvailable)
+	}
+
+	return s, nil
+}
+
+// nextQueueNumber takes the next number from the doctor's counter of the day. The upsert locks the
+// counter row until the transaction ends, so parallel bookings never get the same number
+func (q *queueRepo) nextQueueNumber(ctx context.Context, tx pgx.Tx, doctorID string, day time.Time) (string, error) {
+
+	number := 0
+
+	query := `insert into queue_counter (doctor_id, queue_date, last_number)
+	 values ($1, $2::date, 1)
+	 on conflict (doctor_id, queue_date)
+	 do update set last_number = queue_counter.last_number + 1
+	 returning last_number`
+
+	if err := tx.QueryRow(ctx, query, doctorID, day.Format(slot.DateLayout)).Scan(&number); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(q.numberFormat, number), nil
+}
+
+func sameDay(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
+
+func (q *queueRepo) Restore(ctx context.Context, id string) error {
+
+	if err := restore(ctx, conn(ctx, q.pool), "queue", id); err != nil {
+		return err
+	}
+
+	q.publish(ctx, id, config.BoardCreated)
+
+	return nil
 }
@@ -0,0 +1,286 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"shifolink/api/models"
+	"shifolink/config"
+	"shifolink/pkg/pubsub"
+	"shifolink/pkg/slot"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// testPool connects to the database given in TEST_POSTGRES_URL and migrates it, the test is skipped without it
+func testPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	url := os.Getenv("TEST_POSTGRES_URL")
+	if url == "" {
+		t.Skip("TEST_POSTGRES_URL is not set")
+	}
+
+	m, err := newMigrate(url)
+	if err != nil {
+		t.Fatalf("error while migrating: %v", err)
+	}
+
+	if err = m.Up(); err != nil && err != migrate.ErrNoChange {
+		t.Fatalf("error while migrating: %v", err)
+	}
+
+	pool, err := pgxpool.New(context.Background(), url)
+	if err != nil {
+		t.Fatalf("error while connecting to db: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+// testDoctor creates a doctor working the whole weekday of the day and a customer, both are removed after the test
+func testDoctor(t *testing.T, pool *pgxpool.Pool, day time.Time) (doctorID, customerID string) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	var (
+		clinicID       = uuid.NewString()
+		clinicBranchID = uuid.NewString()
+		doctorTypeID   = uuid.NewString()
+	)
+
+	doctorID, customerID = uuid.NewString(), uuid.NewString()
+
+	queries := []struct {
+		query string
+		args  []any
+	}{
+		{`insert into clinic (id, name, description) values ($1, 'test clinic', '')`, []any{clinicID}},
+		{`insert into clinic_branch (id, clinic_id, address, phone, working_time) values ($1, $2, '', '', '')`,
+			[]any{clinicBranchID, clinicID}},
+		{`insert into doctor_type (id, name, description, clinic_branch_id) values ($1, 'test', '', $2)`,
+			[]any{doctorTypeID, clinicBranchID}},
+		{`insert into doctor (id, doctor_type_id, first_name, last_name, email, password, phone, birth_date, address, working_time)
+		  values ($1, $2, 'test', 'doctor', '', '', '', '1990-01-01', '', '')`, []any{doctorID, doctorTypeID}},
+		{`insert into doctor_schedule (id, doctor_id, weekday, start_time, end_time, slot_duration)
+		  values ($1, $2, $3, '08:00', '20:00', 10)`, []any{uuid.NewString(), doctorID, int(day.Weekday())}},
+		{`insert into customer (id, first_name, last_name, email, password, phone, birth_date, address)
+		  values ($1, 'test', 'customer', '', '', '', '1990-01-01', '')`, []any{customerID}},
+	}
+
+	for _, q := range queries {
+		if _, err := pool.Exec(ctx, q.query, q.args...); err != nil {
+			t.Fatalf("error while creating test data: %v", err)
+		}
+	}
+
+	t.Cleanup(func() {
+		for _, q := range []string{
+			`delete from queue where doctor_id = $1`,
+			`delete from queue_counter where doctor_id = $1`,
+			`delete from doctor_schedule where doctor_id = $1`,
+			`delete from doctor where id = $1`,
+		} {
+			if _, err := pool.Exec(ctx, q, doctorID); err != nil {
+				t.Errorf("error while removing test data: %v", err)
+			}
+		}
+
+		for _, q := range []struct {
+			query string
+			id    string
+		}{
+			{`delete from doctor_type where id = $1`, doctorTypeID},
+			{`delete from clinic_branch where id = $1`, clinicBranchID},
+			{`delete from clinic where id = $1`, clinicID},
+			{`delete from customer where id = $1`, customerID},
+		} {
+			if _, err := pool.Exec(ctx, q.query, q.id); err != nil {
+				t.Errorf("error while removing test data: %v", err)
+			}
+		}
+	})
+
+	return doctorID, customerID
+}
+
+func TestQueueCreateParallelNumbers(t *testing.T) {
+
+	const bookings = 20
+
+	var (
+		pool  = testPool(t)
+		day   = slot.Now().AddDate(0, 0, 1)
+		repo  = NewQueueRepo(pool, "%03d", pubsub.New())
+		ctx   = context.Background()
+		wg    sync.WaitGroup
+		errCh = make(chan error, bookings)
+	)
+
+	doctorID, customerID := testDoctor(t, pool, day)
+
+	start := time.Date(day.Year(), day.Month(), day.Day(), 8, 0, 0, 0, time.UTC)
+
+	for i := 0; i < bookings; i++ {
+		wg.Add(1)
+
+		go func(startTime time.Time) {
+			defer wg.Done()
+
+			_, err := repo.Create(ctx, models.CreateQueue{
+				CustomerID: customerID,
+				DoctorID:   doctorID,
+				StartTime:  startTime.Format(slot.DateTimeLayout),
+			})
+			errCh <- err
+		}(start.Add(time.Duration(i) * 10 * time.Minute))
+	}
+
+	wg.Wait()
+	close(errCh)
+
+	for err := range errCh {
+		if err != nil {
+			t.Fatalf("error while creating queue: %v", err)
+		}
+	}
+
+	rows, err := pool.Query(ctx, `select queue_number from queue where doctor_id = $1 order by queue_number`, doctorID)
+	if err != nil {
+		t.Fatalf("error while selecting queue numbers: %v", err)
+	}
+	defer rows.Close()
+
+	numbers := []string{}
+	for rows.Next() {
+		number := ""
+		if err = rows.Scan(&number); err != nil {
+			t.Fatalf("error while scanning queue number: %v", err)
+		}
+		numbers = append(numbers, number)
+	}
+
+	expected := make([]string, 0, bookings)
+	for i := 1; i <= bookings; i++ {
+		expected = append(expected, fmt.Sprintf("%03d", i))
+	}
+
+	if strings.Join(numbers, ",") != strings.Join(expected, ",") {
+		t.Fatalf("queue numbers are %v, expected %v", numbers, expected)
+	}
+}
+
+func TestQueueListCursorWithNullStartTime(t *testing.T) {
+
+	var (
+		pool = testPool(t)
+		day  = slot.Now().AddDate(0, 0, 1)
+		repo = NewQueueRepo(pool, "%03d", pubsub.New())
+		ctx  = context.Background()
+	)
+
+	doctorID, customerID := testDoctor(t, pool, day)
+
+	// queues booked before start_time was added have none
+	start := time.Date(day.Year(), day.Month(), day.Day(), 9, 0, 0, 0, time.UTC)
+	startTimes := []any{nil, nil, start, nil, start.Add(time.Hour)}
+	for i, startTime := range startTimes {
+		if _, err := pool.Exec(ctx, `insert into queue (id, customer_id, doctor_id, queue_number, queue_time, start_time)
+		 values ($1, $2, $3, $4, '', $5)`, uuid.NewString(), customerID, doctorID, fmt.Sprintf("%03d", i+1), startTime); err != nil {
+			t.Fatalf("error while creating queue: %v", err)
+		}
+	}
+
+	for _, order := range []string{"asc", "desc"} {
+		seen := map[string]bool{}
+		cursor := ""
+
+		for page := 0; page < len(startTimes); page++ {
+			response, err := repo.GetList(ctx, models.GetQueuesListRequest{
+				GetListRequest: models.GetListRequest{Limit: 2, SortBy: "start_time", Order: order, Cursor: cursor},
+				DoctorID:       doctorID,
+			})
+			if err != nil {
+				t.Fatalf("error while listing queues by start_time %s: %v", order, err)
+			}
+
+			for _, queue := range response.Queues {
+				if seen[queue.ID] {
+					t.Fatalf("queue %s is listed twice by start_time %s", queue.ID, order)
+				}
+				seen[queue.ID] = true
+			}
+
+			if cursor = response.NextCursor; cursor == "" {
+				break
+			}
+		}
+
+		if len(seen) != len(startTimes) {
+			t.Fatalf("%d of %d queues are listed by start_time %s", len(seen), len(startTimes), order)
+		}
+	}
+}
+
+func TestQueueUpdateToAnotherDayLeavesOldBoard(t *testing.T) {
+
+	var (
+		pool   = testPool(t)
+		day    = slot.Now().AddDate(0, 0, 1)
+		broker = pubsub.New()
+		repo   = NewQueueRepo(pool, "%03d", broker)
+		ctx    = context.Background()
+	)
+
+	doctorID, customerID := testDoctor(t, pool, day)
+
+	clinicBranchID := ""
+	if err := pool.QueryRow(ctx, `select dt.clinic_branch_id from doctor d join doctor_type dt on dt.id = d.doctor_type_id
+	 where d.id = $1`, doctorID).Scan(&clinicBranchID); err != nil {
+		t.Fatalf("error while selecting clinic branch: %v", err)
+	}
+
+	start := time.Date(day.Year(), day.Month(), day.Day(), 9, 0, 0, 0, time.UTC)
+
+	id, err := repo.Create(ctx, models.CreateQueue{
+		CustomerID: customerID,
+		DoctorID:   doctorID,
+		StartTime:  start.Format(slot.DateTimeLayout),
+	})
+	if err != nil {
+		t.Fatalf("error while creating queue: %v", err)
+	}
+
+	messages, unsubscribe := broker.Subscribe(clinicBranchID)
+	defer unsubscribe()
+
+	// the doctor works the same weekday of the next week
+	if _, err = repo.Update(ctx, models.UpdateQueue{
+		ID:         id,
+		CustomerID: customerID,
+		DoctorID:   doctorID,
+		StartTime:  start.AddDate(0, 0, 7).Format(slot.DateTimeLayout),
+	}); err != nil {
+		t.Fatalf("error while updating queue: %v", err)
+	}
+
+	deleted := false
+	for len(messages) > 0 {
+		event := (<-messages).Data.(models.QueueBoardEvent)
+		if event.Type == config.BoardDeleted && strings.HasPrefix(event.Items[0].StartTime, start.Format(slot.DateLayout)) {
+			deleted = true
+		}
+	}
+
+	if !deleted {
+		t.Fatalf("the board of %s got no deleted event for the moved queue", start.Format(slot.DateLayout))
+	}
+}
@@ -6,11 +6,12 @@ import (
 	"fmt"
 	"log"
 	"shifolink/api/models"
-	"shifolink/pkg/check"
+	"shifolink/pkg/security"
 	"shifolink/storage"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -28,6 +29,12 @@ func (s *superAdminRepo) Create(ctx context.Context, request models.CreateSuperA
 
 	id := uuid.New()
 
+	hashedPassword, err := security.HashPassword(request.Password)
+	if err != nil {
+		log.Println("error while hashing super admin password", err.Error())
+		return "", err
+	}
+
 	query := `insert into super_admin (
 		id, 
 		clinic_id,
@@ -43,7 +50,7 @@ func (s *superAdminRepo) Create(ctx context.Context, request models.CreateSuperA
 		age, 
 		address) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
 
-	rowsAffected, err := s.pool.Exec(ctx, query,
+	rowsAffected, err := conn(ctx, s.pool).Exec(ctx, query,
 		id,
 		request.ClinicID,
 		request.DrugStoreID,
@@ -51,24 +58,23 @@ func (s *superAdminRepo) Create(ctx context.Context, request models.CreateSuperA
 		request.FirstName,
 		request.LastName,
 		request.Email,
-		request.Password,
+		hashedPassword,
 		request.Phone,
 		request.Gender,
 		request.BirthDate,
-		check.CalculateAge(request.BirthDate),
+		request.Age,
 		request.Address,
 	)
 
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return "", err
-	}
-
 	if err != nil {
 		log.Println("error while inserting super_admin", err.Error())
 		return "", err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return "", pgx.ErrNoRows
+	}
+
 	return id.String(), nil
 }
 
@@ -86,17 +92,17 @@ func (s *superAdminRepo) Get(ctx context.Context, request models.PrimaryKey) (mo
 	 first_name, 
 	 last_name, 
 	 email, 
-	 password, 
 	 phone, 
 	 gender, 
 	 birth_date::text, 
 	 age, 
 	 address, 
+	 version,
 	 created_at, 
 	 updated_at 
 	 from super_admin where deleted_at is null and id = $1`
 
-	row := s.pool.QueryRow(ctx, query, request.ID)
+	row := conn(ctx, s.pool).QueryRow(ctx, query, request.ID)
 
 	err := row.Scan(
 		&superAdmin.ID,
@@ -106,12 +112,12 @@ func (s *superAdminRepo) Get(ctx context.Context, request models.PrimaryKey) (mo
 		&superAdmin.FirstName,
 		&superAdmin.LastName,
 		&superAdmin.Email,
-		&superAdmin.Password,
 		&superAdmin.Phone,
 		&superAdmin.Gender,
 		&superAdmin.BirthDate,
 		&superAdmin.Age,
 		&superAdmin.Address,
+		&superAdmin.Version,
 		&superAdmin.CreatedAt,
 		&updatedAt,
 	)
@@ -129,24 +135,31 @@ func (s *superAdminRepo) Get(ctx context.Context, request models.PrimaryKey) (mo
 
 }
 
+// superAdminSortColumns are columns the super admin list can be sorted by, besides created_at
+var superAdminSortColumns = sortColumns{
+	"first_name": "text",
+	"last_name":  "text",
+}
+
 func (s *superAdminRepo) GetList(ctx context.Context, request models.GetListRequest) (models.SuperAdminsResponse, error) {
 
 	var (
 		updatedAt         = sql.NullTime{}
+		deletedAt         = sql.NullTime{}
 		superAdmins       = []models.SuperAdmin{}
 		count             = 0
 		query, countQuery string
-		page              = request.Page
-		offset            = (page - 1) * request.Limit
-		search            = request.Search
 	)
 
-	countQuery = `select count(1) from super_admin where deleted_at is null`
+	filter := newListFilter(request, "first_name", "last_name")
 
-	if search != "" {
-		countQuery += fmt.Sprintf(` and (first_name ilike '%%%s%%' or last_name ilike '%%%s%%')`, search, search)
+	if err := filter.Sort(request, superAdminSortColumns); err != nil {
+		return models.SuperAdminsResponse{}, err
 	}
-	if err := s.pool.QueryRow(ctx, countQuery).Scan(&count); err != nil {
+
+	countQuery = `select count(1) from super_admin` + filter.Where()
+
+	if err := conn(ctx, s.pool).QueryRow(ctx, countQuery, filter.Args()...).Scan(&count); err != nil {
 		fmt.Println("error is while selecting count", err.Error())
 		return models.SuperAdminsResponse{}, err
 	}
@@ -159,21 +172,20 @@ func (s *superAdminRepo) GetList(ctx context.Context, request models.GetListRequ
 	 first_name, 
 	 last_name, 
 	 email, 
-	 password, 
 	 phone, 
 	 gender, 
 	 birth_date::text, 
 	 age, 
 	 address, 
+	 version,
 	 created_at, 
-	 updated_at from super_admin where deleted_at is null`
+	 updated_at,
+	 deleted_at from super_admin`
 
-	if search != "" {
-		query += fmt.Sprintf(` and (first_name ilike '%%%s%%' or last_name ilike '%%%s%%')`, search, search)
-	}
+	query += filter.Where()
 
-	query += ` LIMIT $1 OFFSET $2`
-	rows, err := s.pool.Query(ctx, query, request.Limit, offset)
+	pagination, args := filter.Page(request.Page, request.Limit)
+	rows, err := conn(ctx, s.pool).Query(ctx, query+pagination, args...)
 	if err != nil {
 		fmt.Println("error is while selecting super admin", err.Error())
 		return models.SuperAdminsResponse{}, err
@@ -189,14 +201,15 @@ func (s *superAdminRepo) GetList(ctx context.Context, request models.GetListRequ
 			&superAdmin.FirstName,
 			&superAdmin.LastName,
 			&superAdmin.Email,
-			&superAdmin.Password,
 			&superAdmin.Phone,
 			&superAdmin.Gender,
 			&superAdmin.BirthDate,
 			&superAdmin.Age,
 			&superAdmin.Address,
+			&superAdmin.Version,
 			&superAdmin.CreatedAt,
 			&updatedAt,
+			&deletedAt,
 		); err != nil {
 			fmt.Println("error is while scanning super admin data", err.Error())
 			return models.SuperAdminsResponse{}, err
@@ -206,6 +219,10 @@ func (s *superAdminRepo) GetList(ctx context.Context, request models.GetListRequ
 			superAdmin.UpdatedAt = updatedAt.Time
 		}
 
+		if deletedAt.Valid {
+			superAdmin.DeletedAt = deletedAt.Time
+		}
+
 		superAdmins = append(superAdmins, superAdmin)
 
 	}
@@ -227,11 +244,12 @@ func (s *superAdminRepo) Update(ctx context.Context, request models.UpdateSuperA
 	email = $6,
 	phone = $7,
     address = $8,
+	version = version + 1,
 	updated_at = $9
-   where id = $10
+   where id = $10 and deleted_at is null and ($11 = 0 or version = $11)
    `
 
-	rowsAffected, err := s.pool.Exec(ctx, query,
+	rowsAffected, err := conn(ctx, s.pool).Exec(ctx, query,
 		request.ClinicID,
 		request.DrugStoreID,
 		request.AuthorID,
@@ -241,18 +259,18 @@ func (s *superAdminRepo) Update(ctx context.Context, request models.UpdateSuperA
 		request.Phone,
 		request.Address,
 		time.Now(),
-		request.ID)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return "", err
-	}
+		request.ID,
+		request.Version)
 
 	if err != nil {
 		log.Println("error while updating super admin data...", err.Error())
 		return "", err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return "", missedUpdate(ctx, conn(ctx, s.pool), "super_admin", request.ID, request.Version)
+	}
+
 	return request.ID, nil
 
 }
@@ -262,21 +280,20 @@ func (s *superAdminRepo) Delete(ctx context.Context, id string) error {
 	query := `
 	update super_admin
 	 set deleted_at = $1
-	  where id = $2
+	  where id = $2 and deleted_at is null
 	`
 
-	rowsAffected, err := s.pool.Exec(ctx, query, time.Now(), id)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return err
-	}
+	rowsAffected, err := conn(ctx, s.pool).Exec(ctx, query, time.Now(), id)
 
 	if err != nil {
 		log.Println("error while deleting super admin by id", err.Error())
 		return err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
 	return nil
 
 }
@@ -286,9 +303,9 @@ func (s *superAdminRepo) GetPassword(ctx context.Context, id string) (string, er
 
 	query := `
 		select password from super_admin 
-						where id = $1`
+						where id = $1 and deleted_at is null`
 
-	if err := s.pool.QueryRow(ctx, query, id).Scan(&password); err != nil {
+	if err := conn(ctx, s.pool).QueryRow(ctx, query, id).Scan(&password); err != nil {
 		fmt.Println("Error while scanning password from super_admin", err.Error())
 		return "", err
 	}
@@ -298,22 +315,43 @@ func (s *superAdminRepo) GetPassword(ctx context.Context, id string) (string, er
 
 func (s *superAdminRepo) UpdatePassword(ctx context.Context, request models.UpdateSuperAdminPassword) error {
 
+	hashedPassword, err := security.HashPassword(request.NewPassword)
+	if err != nil {
+		log.Println("error while hashing super admin password", err.Error())
+		return err
+	}
+
 	query := `
 		update super_admin 
 				set password = $1, updated_at = now()
-					where id = $2`
+					where id = $2 and deleted_at is null`
 
-	rowsAffected, err := s.pool.Exec(ctx, query, request.NewPassword, request.ID)
-
-	if r := rowsAffected.RowsAffected(); r == 0 {
-		log.Println("error is while rows affected ", err.Error())
-		return err
-	}
+	rowsAffected, err := conn(ctx, s.pool).Exec(ctx, query, hashedPassword, request.ID)
 
 	if err != nil {
 		fmt.Println("error while updating password for super_admin", err.Error())
 		return err
 	}
 
+	if rowsAffected.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
 	return nil
 }
+
+func (s *superAdminRepo) GetByLogin(ctx context.Context, login string) (models.SuperAdmin, error) {
+	id, err := loginID(ctx, conn(ctx, s.pool), "super_admin", login)
+	if err != nil {
+		fmt.Println("error while selecting super admin by login", err.Error())
+		return models.SuperAdmin{}, err
+	}
+
+	return s.Get(ctx, models.PrimaryKey{
+		ID: id,
+	})
+}
+
+func (s *superAdminRepo) Restore(ctx context.Context, id string) error {
+	return restore(ctx, conn(ctx, s.pool), "super_admin", id)
+}
@@ -0,0 +1,281 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log"
+	"shifolink/pkg/errs"
+	"shifolink/storage"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// childTable is a table whose rows belong to a row of another table through the column
+type childTable struct {
+	table  string
+	column string
+}
+
+// cascadeChildren are soft deleted together with their parent, with the same deleted_at, so restoring
+// the parent brings back exactly the rows its delete took with it
+var cascadeChildren = map[string][]childTable{
+	"clinic":        {{table: "clinic_branch", column: "clinic_id"}},
+	"clinic_branch": {{table: "doctor_type", column: "clinic_branch_id"}},
+	"doctor_type":   {{table: "doctor", column: "doctor_type_id"}},
+	// a drug left the catalogue is sold by no branch
+	"drug_catalogue": {{table: "drug", column: "drug_catalogue_id"}},
+}
+
+// purgeTables are hard deleted in this order, rows of a table are purged before the rows they reference
+var purgeTables = []string{
+	"order_drug",
+	"orders",
+	"prescription",
+	"queue",
+	"doctor_schedule",
+	"drug_lot",
+	"drug",
+	"drug_catalogue",
+	"pharmacist",
+	"journal",
+	"journal_category",
+	"clinic_admin",
+	"doctor",
+	"doctor_type",
+	"clinic_branch",
+	"super_admin",
+	"clinic",
+	"drug_store_branch",
+	"drug_store",
+	"customer",
+	"author",
+}
+
+type trashRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewTrashRepo(pool *pgxpool.Pool) storage.ITrashRepo {
+	return &trashRepo{
+		pool: pool,
+	}
+}
+
+// Purge hard deletes rows which were soft deleted before the time and returns the ids of the removed rows
+// by table. A row which is still referenced by a live row, like a drug of a kept order, is left for later
+func (t *trashRepo) Purge(ctx context.Context, deletedBefore time.Time) (map[string][]string, error) {
+
+	purged := map[string][]string{}
+
+	for _, table := range purgeTables {
+		rows, err := conn(ctx, t.pool).Query(ctx, `select id from `+table+` where deleted_at < $1`, deletedBefore)
+		if err != nil {
+			log.Println("error while selecting rows to purge from", table, err.Error())
+			return purged, err
+		}
+
+		ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			log.Println("error while scanning rows to purge from", table, err.Error())
+			return purged, err
+		}
+
+		for _, id := range ids {
+			// every row is deleted in its own transaction, a referenced row fails alone and does not stop the others
+			if err = purgeRow(ctx, conn(ctx, t.pool), table, id); err != nil {
+				pgErr := &pgconn.PgError{}
+				if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+					continue
+				}
+
+				log.Println("error while purging row of", table, err.Error())
+				return purged, err
+			}
+
+			purged[table] = append(purged[table], id)
+		}
+	}
+
+	return purged, nil
+}
+
+func purgeRow(ctx context.Context, db dbtx, table, id string) error {
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Exec(ctx, `delete from `+table+` where id = $1`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// softDelete sets deleted_at of the row and of the rows belonging to it in one transaction and returns
+// the rows taken with it
+func softDelete(ctx context.Context, db dbtx, table, id string) ([]storage.ChildRecord, error) {
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		log.Println("error while starting transaction", err.Error())
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	deletedAt := time.Now()
+
+	rowsAffected, err := tx.Exec(ctx, `update `+table+` set deleted_at = $1 where id = $2 and deleted_at is null`, deletedAt, id)
+	if err != nil {
+		log.Println("error while deleting", table, "by id", err.Error())
+		return nil, err
+	}
+
+	if rowsAffected.RowsAffected() == 0 {
+		return nil, pgx.ErrNoRows
+	}
+
+	children := []storage.ChildRecord{}
+
+	if err = cascadeDelete(ctx, tx, table, []string{id}, deletedAt, &children); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return children, nil
+}
+
+func cascadeDelete(ctx context.Context, tx pgx.Tx, table string, ids []string, deletedAt time.Time, children *[]storage.ChildRecord) error {
+
+	for _, child := range cascadeChildren[table] {
+		rows, err := tx.Query(ctx, `update `+child.table+` set deleted_at = $1
+		 where `+child.column+` = any($2::uuid[]) and deleted_at is null returning id`, deletedAt, ids)
+		if err != nil {
+			log.Println("error while deleting", child.table, "of", table, err.Error())
+			return err
+		}
+
+		childIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			log.Println("error while scanning deleted", child.table, err.Error())
+			return err
+		}
+
+		if len(childIDs) == 0 {
+			continue
+		}
+
+		for _, childID := range childIDs {
+			*children = append(*children, storage.ChildRecord{Table: child.table, ID: childID})
+		}
+
+		if err = cascadeDelete(ctx, tx, child.table, childIDs, deletedAt, children); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restore clears deleted_at of the row and of the rows its delete took with it. A row whose parent
+// is still deleted can not be restored, the parent has to be restored first
+func restore(ctx context.Context, db dbtx, table, id string) error {
+	_, err := restoreWithChildren(ctx, db, table, id)
+	return err
+}
+
+// restoreWithChildren is restore for tables with cascadeChildren, it returns the rows brought back with the row
+func restoreWithChildren(ctx context.Context, db dbtx, table, id string) ([]storage.ChildRecord, error) {
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		log.Println("error while starting transaction", err.Error())
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var deletedAt time.Time
+
+	if err = tx.QueryRow(ctx, `select deleted_at from `+table+` where id = $1 and deleted_at is not null for update`,
+		id).Scan(&deletedAt); err != nil {
+		log.Println("error while getting deleted", table, err.Error())
+		return nil, err
+	}
+
+	for parent, children := range cascadeChildren {
+		for _, child := range children {
+			if child.table != table {
+				continue
+			}
+
+			parentDeleted := false
+			if err = tx.QueryRow(ctx, `select exists (select 1 from `+parent+` where deleted_at is not null
+			 and id = (select `+child.column+` from `+table+` where id = $1))`, id).Scan(&parentDeleted); err != nil {
+				log.Println("error while checking parent of", table, err.Error())
+				return nil, err
+			}
+
+			if parentDeleted {
+				return nil, errs.Conflict(strings.ReplaceAll(parent, "_", " ") + " of the record is deleted, restore it first")
+			}
+		}
+	}
+
+	if _, err = tx.Exec(ctx, `update `+table+` set deleted_at = null, updated_at = now() where id = $1`, id); err != nil {
+		log.Println("error while restoring", table, err.Error())
+		return nil, err
+	}
+
+	children := []storage.ChildRecord{}
+
+	if err = cascadeRestore(ctx, tx, table, []string{id}, deletedAt, &children); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return children, nil
+}
+
+func cascadeRestore(ctx context.Context, tx pgx.Tx, table string, ids []string, deletedAt time.Time, children *[]storage.ChildRecord) error {
+
+	for _, child := range cascadeChildren[table] {
+		rows, err := tx.Query(ctx, `update `+child.table+` set deleted_at = null, updated_at = now()
+		 where `+child.column+` = any($1::uuid[]) and deleted_at = $2 returning id`, ids, deletedAt)
+		if err != nil {
+			log.Println("error while restoring", child.table, "of", table, err.Error())
+			return err
+		}
+
+		childIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			log.Println("error while scanning restored", child.table, err.Error())
+			return err
+		}
+
+		if len(childIDs) == 0 {
+			continue
+		}
+
+		for _, childID := range childIDs {
+			*children = append(*children, storage.ChildRecord{Table: child.table, ID: childID})
+		}
+
+		if err = cascadeRestore(ctx, tx, child.table, childIDs, deletedAt, children); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
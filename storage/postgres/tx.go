@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// dbtx runs the queries of repos, it is the pool or the transaction started by Store.Transaction
+type dbtx interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type txKey struct{}
+
+// txState is the transaction of the context with the work waiting for its commit
+type txState struct {
+	tx          pgx.Tx
+	afterCommit []func()
+}
+
+// conn returns the transaction of the context, or the pool outside of one. Repos which begin their own
+// transaction inside get a savepoint, so their rollback leaves the outer work alone
+func conn(ctx context.Context, pool *pgxpool.Pool) dbtx {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx
+	}
+
+	return pool
+}
+
+// afterCommit runs fn once the transaction of the context is committed and never when it is rolled back,
+// outside of a transaction fn runs right away
+func afterCommit(ctx context.Context, fn func()) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.afterCommit = append(state.afterCommit, fn)
+		return
+	}
+
+	fn()
+}
+
+// Transaction runs fn in one transaction, every repo called with the context fn gets joins it. Nothing of fn
+// is saved when it returns an error. Nested calls run in a savepoint of the outer transaction
+func (s Store) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+
+	tx, err := conn(ctx, s.pool).Begin(ctx)
+	if err != nil {
+		log.Println("error while starting transaction", err.Error())
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	state := &txState{tx: tx}
+
+	if err = fn(context.WithValue(ctx, txKey{}, state)); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Println("error while committing transaction", err.Error())
+		return err
+	}
+
+	// a nested transaction hands its work to the outer one, which runs it after the real commit
+	for _, fn := range state.afterCommit {
+		afterCommit(ctx, fn)
+	}
+
+	return nil
+}
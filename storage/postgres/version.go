@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"context"
+	"log"
+	"shifolink/storage"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// missedUpdate tells why an update of the row changed nothing. ErrVersionChanged is returned when the row
+// was changed after the client read the given version, pgx.ErrNoRows otherwise
+func missedUpdate(ctx context.Context, db querier, table, id string, version int) error {
+
+	current := 0
+
+	if err := db.QueryRow(ctx, `select version from `+table+` where id = $1 and deleted_at is null`, id).Scan(&current); err != nil {
+		log.Println("error while selecting version of", table, err.Error())
+		return err
+	}
+
+	if version != 0 && version != current {
+		return storage.ErrVersionChanged
+	}
+
+	return pgx.ErrNoRows
+}
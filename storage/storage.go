@@ -2,11 +2,66 @@ package storage
 
 import (
 	"context"
+	"fmt"
 	"shifolink/api/models"
+	"shifolink/pkg/errs"
+	"time"
 )
 
+var (
+	ErrSlotNotAvailable = errs.Conflict("requested time does not fit a free slot of the doctor")
+	ErrStatusChanged    = errs.Conflict("status was changed by another request, try again")
+	ErrOutOfStock       = errs.Conflict("some order lines can not be sold")
+	// ErrInvalidListRequest is returned for unknown sort columns and broken cursors of list requests
+	ErrInvalidListRequest = errs.Validation("list request is not valid")
+	// ErrPrescriptionRequired is returned when a prescription-only drug is added to an order outside of a redemption
+	ErrPrescriptionRequired = errs.Conflict("drug is sold only by prescription")
+	// ErrOrderClosed is returned when lines of an order are changed after its checkout or once it left pending
+	ErrOrderClosed = errs.Conflict("lines can be changed only while the order is pending and not checked out")
+	// ErrOrderNotPending is returned when an order is edited after it left pending, its status moves it on from there
+	ErrOrderNotPending = errs.Conflict("order can be changed only while it is pending")
+	// ErrOrderNotCheckedOut is returned when an order is confirmed before its checkout took the drugs from lots
+	ErrOrderNotCheckedOut = errs.Conflict("order can be confirmed only after its checkout")
+	// ErrOrderOpen is returned when a checked out order is deleted while it still holds drugs of lots
+	ErrOrderOpen = errs.Conflict("checked out order can be deleted only once it is cancelled, delivered or refunded")
+	// ErrQueueNotBooked is returned when a queue is edited after the visit began or ended
+	ErrQueueNotBooked = errs.Conflict("queue can be changed only while it is booked")
+	// ErrDoctorBusy is returned when a consultation starts while the doctor is still in another one
+	ErrDoctorBusy = errs.Conflict("doctor is busy with another patient")
+	// ErrAmbiguousLogin is returned when a login is the email or phone of more than one user of the role
+	ErrAmbiguousLogin = errs.Conflict("login matches more than one user")
+	// ErrVersionChanged is returned when the record was changed after the version given in If-Match
+	ErrVersionChanged = errs.PreconditionFailed("record was changed by another request, get it again and retry")
+)
+
+// ChildRecord is a row deleted or restored together with the row it belongs to
+type ChildRecord struct {
+	Table string
+	ID    string
+}
+
+// CheckoutError lists the order lines which made the checkout roll back, it matches ErrOutOfStock
+type CheckoutError struct {
+	Lines []models.CheckoutLineError
+}
+
+func (e CheckoutError) Error() string {
+	return fmt.Sprintf("%s: %d line(s) rejected", ErrOutOfStock.Error(), len(e.Lines))
+}
+
+func (e CheckoutError) Unwrap() error {
+	return ErrOutOfStock
+}
+
 type IStorage interface {
 	CloseDB()
+	// Ping checks the database answers
+	Ping(ctx context.Context) error
+	// CheckMigrations checks the database schema is at the version of the migrations the service was built with
+	CheckMigrations(ctx context.Context) error
+	// Transaction runs fn in one transaction, repos called with the context given to fn take part in it
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+	AuditLog() IAuditLogRepo
 	Author() IAuthorRepo
 	ClinicAdmin() IClinicAdminRepo
 	ClinicBranch() IClinicBranchRepo
@@ -14,15 +69,26 @@ type IStorage interface {
 	Customer() ICustomerRepo
 	DoctorType() IDoctorTypeRepo
 	Doctor() IDoctorRepo
+	DoctorSchedule() IDoctorScheduleRepo
 	DrugStoreBranch() IDrugStoreBranchRepo
 	DrugStore() IDrugStoreRepo
 	Drug() IDrugRepo
+	DrugCatalogue() IDrugCatalogueRepo
+	DrugLot() IDrugLotRepo
 	Journal() IJournalRepo
+	JournalCategory() IJournalCategoryRepo
 	OrderDrug() IOrderDrugRepo
 	Orders() IOrdersRepo
 	Pharmacist() IPharmacistRepo
+	Prescription() IPrescriptionRepo
 	Queue() IQueueRepo
 	SuperAdmin() ISuperAdminRepo
+	Trash() ITrashRepo
+}
+
+type IAuditLogRepo interface {
+	Create(context.Context, models.CreateAuditLog) error
+	GetList(context.Context, models.GetAuditLogsListRequest) (models.AuditLogsResponse, error)
 }
 
 type IAuthorRepo interface {
@@ -31,26 +97,31 @@ type IAuthorRepo interface {
 	GetList(context.Context, models.GetListRequest) (models.AuthorsResponse, error)
 	Update(context.Context, models.UpdateAuthor) (string, error)
 	Delete(context.Context, string) error
+	Restore(context.Context, string) error
 	UpdatePassword(context.Context, models.UpdateAuthorPassword) error
 	GetPassword(context.Context, string) (string, error)
+	GetByLogin(context.Context, string) (models.Author, error)
 }
 
 type IClinicAdminRepo interface {
 	Create(context.Context, models.CreateClinicAdmin) (string, error)
 	Get(context.Context, models.PrimaryKey) (models.ClinicAdmin, error)
-	GetList(context.Context, models.GetListRequest) (models.ClinicAdminsResponse, error)
+	GetList(context.Context, models.GetClinicAdminsListRequest) (models.ClinicAdminsResponse, error)
 	Update(context.Context, models.UpdateClinicAdmin) (string, error)
 	Delete(context.Context, string) error
+	Restore(context.Context, string) error
 	UpdatePassword(context.Context, models.UpdateClinicAdminPassword) error
 	GetPassword(context.Context, string) (string, error)
+	GetByLogin(context.Context, string) (models.ClinicAdmin, error)
 }
 
 type IClinicBranchRepo interface {
 	Create(context.Context, models.CreateClinicBranch) (string, error)
 	Get(context.Context, models.PrimaryKey) (models.ClinicBranch, error)
-	GetList(context.Context, models.GetListRequest) (models.ClinicBranchsResponse, error)
+	GetList(context.Context, models.GetClinicBranchsListRequest) (models.ClinicBranchsResponse, error)
 	Update(context.Context, models.UpdateClinicBranch) (string, error)
-	Delete(context.Context, string) error
+	Delete(context.Context, string) ([]ChildRecord, error)
+	Restore(context.Context, string) ([]ChildRecord, error)
 }
 
 type IClinicRepo interface {
@@ -58,7 +129,8 @@ type IClinicRepo interface {
 	Get(context.Context, models.PrimaryKey) (models.Clinic, error)
 	GetList(context.Context, models.GetListRequest) (models.ClinicsResponse, error)
 	Update(context.Context, models.UpdateClinic) (string, error)
-	Delete(context.Context, string) error
+	Delete(context.Context, string) ([]ChildRecord, error)
+	Restore(context.Context, string) ([]ChildRecord, error)
 }
 
 type ICustomerRepo interface {
@@ -67,34 +139,49 @@ type ICustomerRepo interface {
 	GetList(context.Context, models.GetListRequest) (models.CustomersResponse, error)
 	Update(context.Context, models.UpdateCustomer) (string, error)
 	Delete(context.Context, string) error
+	Restore(context.Context, string) error
 	UpdatePassword(context.Context, models.UpdateCustomerPassword) error
 	GetPassword(context.Context, string) (string, error)
+	GetByLogin(context.Context, string) (models.Customer, error)
 }
 
 type IDoctorTypeRepo interface {
 	Create(context.Context, models.CreateDoctorType) (string, error)
 	Get(context.Context, models.PrimaryKey) (models.DoctorType, error)
-	GetList(context.Context, models.GetListRequest) (models.DoctorTypesResponse, error)
+	GetList(context.Context, models.GetDoctorTypesListRequest) (models.DoctorTypesResponse, error)
 	Update(context.Context, models.UpdateDoctorType) (string, error)
-	Delete(context.Context, string) error
+	Delete(context.Context, string) ([]ChildRecord, error)
+	Restore(context.Context, string) ([]ChildRecord, error)
 }
 
 type IDoctorRepo interface {
 	Create(context.Context, models.CreateDoctor) (string, error)
 	Get(context.Context, models.PrimaryKey) (models.Doctor, error)
-	GetList(context.Context, models.GetListRequest) (models.DoctorsResponse, error)
+	GetList(context.Context, models.GetDoctorsListRequest) (models.DoctorsResponse, error)
 	Update(context.Context, models.UpdateDoctor) (string, error)
 	Delete(context.Context, string) error
+	Restore(context.Context, string) error
 	UpdatePassword(context.Context, models.UpdateDoctorPassword) error
 	GetPassword(context.Context, string) (string, error)
+	GetByLogin(context.Context, string) (models.Doctor, error)
+}
+
+type IDoctorScheduleRepo interface {
+	Create(context.Context, models.CreateDoctorSchedule) (string, error)
+	Get(context.Context, models.PrimaryKey) (models.DoctorSchedule, error)
+	GetByDoctor(context.Context, string) (models.DoctorSchedulesResponse, error)
+	GetByWeekday(context.Context, string, int) (models.DoctorSchedule, error)
+	Update(context.Context, models.UpdateDoctorSchedule) (string, error)
+	Delete(context.Context, string) error
 }
 
 type IDrugStoreBranchRepo interface {
 	Create(context.Context, models.CreateDrugStoreBranch) (string, error)
 	Get(context.Context, models.PrimaryKey) (models.DrugStoreBranch, error)
-	GetList(context.Context, models.GetListRequest) (models.DrugStoreBranchsResponse, error)
+	GetList(context.Context, models.GetDrugStoreBranchsListRequest) (models.DrugStoreBranchsResponse, error)
 	Update(context.Context, models.UpdateDrugStoreBranch) (string, error)
 	Delete(context.Context, string) error
+	Restore(context.Context, string) error
 }
 
 type IDrugStoreRepo interface {
@@ -103,56 +190,113 @@ type IDrugStoreRepo interface {
 	GetList(context.Context, models.GetListRequest) (models.DrugStoresResponse, error)
 	Update(context.Context, models.UpdateDrugStore) (string, error)
 	Delete(context.Context, string) error
+	Restore(context.Context, string) error
 }
 
 type IDrugRepo interface {
 	Create(context.Context, models.CreateDrug) (string, error)
 	Get(context.Context, models.PrimaryKey) (models.Drug, error)
-	GetList(context.Context, models.GetListRequest) (models.DrugsResponse, error)
+	GetList(context.Context, models.GetDrugsListRequest) (models.DrugsResponse, error)
 	Update(context.Context, models.UpdateDrug) (string, error)
 	Delete(context.Context, string) error
+	Restore(context.Context, string) error
+}
+
+type IDrugCatalogueRepo interface {
+	Create(context.Context, models.CreateDrugCatalogue) (string, error)
+	Get(context.Context, models.PrimaryKey) (models.DrugCatalogue, error)
+	GetList(context.Context, models.GetListRequest) (models.DrugCataloguesResponse, error)
+	Update(context.Context, models.UpdateDrugCatalogue) (string, error)
+	Delete(context.Context, string) ([]ChildRecord, error)
+	Restore(context.Context, string) ([]ChildRecord, error)
+}
+
+type IDrugLotRepo interface {
+	Create(context.Context, models.CreateDrugLot) (string, error)
+	Get(context.Context, models.PrimaryKey) (models.DrugLot, error)
+	GetByDrug(ctx context.Context, drugID string) (models.DrugLotsResponse, error)
+	Update(context.Context, models.UpdateDrugLot) (string, error)
+	Delete(context.Context, string) error
+	GetExpiring(context.Context, models.ExpiringLotsRequest) (models.ExpiringLotsResponse, error)
+	GetOrderMoves(ctx context.Context, ordersID string) ([]models.DrugLotMove, error)
 }
 
 type IJournalRepo interface {
 	Create(context.Context, models.CreateJournal) (string, error)
 	Get(context.Context, models.PrimaryKey) (models.Journal, error)
-	GetList(context.Context, models.GetListRequest) (models.JournalsResponse, error)
+	GetList(context.Context, models.GetJournalsListRequest) (models.JournalsResponse, error)
+	Search(context.Context, models.SearchJournalsRequest) (models.JournalSearchResponse, error)
 	Update(context.Context, models.UpdateJournal) (string, error)
+	GetBySlug(context.Context, string) (models.Journal, error)
+	UpdateStatus(context.Context, models.UpdateJournalStatus) error
+	GetRevisions(context.Context, string) (models.JournalRevisionsResponse, error)
+	GetRevision(ctx context.Context, journalID string, version int) (models.JournalRevision, error)
+	Classify(context.Context, models.ClassifyJournal) error
+	GetTags(context.Context) (models.JournalTagsResponse, error)
 	Delete(context.Context, string) error
+	Restore(context.Context, string) error
+}
+
+type IJournalCategoryRepo interface {
+	Create(context.Context, models.CreateJournalCategory) (string, error)
+	Get(context.Context, models.PrimaryKey) (models.JournalCategory, error)
+	GetList(context.Context, models.GetListRequest) (models.JournalCategoriesResponse, error)
+	Update(context.Context, models.UpdateJournalCategory) (string, error)
+	Delete(context.Context, string) error
+	Restore(context.Context, string) error
 }
 
 type IOrderDrugRepo interface {
 	Create(context.Context, models.CreateOrderDrug) (string, error)
 	Get(context.Context, models.PrimaryKey) (models.OrderDrug, error)
-	GetList(context.Context, models.GetListRequest) (models.OrderDrugsResponse, error)
+	GetList(context.Context, models.GetOrderDrugsListRequest) (models.OrderDrugsResponse, error)
 	Update(context.Context, models.UpdateOrderDrug) (string, error)
 	Delete(context.Context, string) error
+	Restore(context.Context, string) error
+	GetByOrder(ctx context.Context, ordersID string) ([]models.OrderDrug, error)
 }
 
 type IOrdersRepo interface {
 	Create(context.Context, models.CreateOrders) (string, error)
 	Get(context.Context, models.PrimaryKey) (models.Orders, error)
-	GetList(context.Context, models.GetListRequest) (models.OrdersResponse, error)
+	GetList(context.Context, models.GetOrdersListRequest) (models.OrdersResponse, error)
 	Update(context.Context, models.UpdateOrders) (string, error)
 	Delete(context.Context, string) error
+	Restore(context.Context, string) error
+	Checkout(context.Context, models.CheckoutOrder) (string, error)
+	UpdateStatus(context.Context, models.UpdateOrderStatus) error
 }
 
 type IPharmacistRepo interface {
 	Create(context.Context, models.CreatePharmacist) (string, error)
 	Get(context.Context, models.PrimaryKey) (models.Pharmacist, error)
-	GetList(context.Context, models.GetListRequest) (models.PharmacistsResponse, error)
+	GetList(context.Context, models.GetPharmacistsListRequest) (models.PharmacistsResponse, error)
 	Update(context.Context, models.UpdatePharmacist) (string, error)
 	Delete(context.Context, string) error
+	Restore(context.Context, string) error
 	UpdatePassword(context.Context, models.UpdatePharmacistPassword) error
 	GetPassword(context.Context, string) (string, error)
+	GetByLogin(context.Context, string) (models.Pharmacist, error)
+}
+
+type IPrescriptionRepo interface {
+	Create(context.Context, models.CreatePrescription) (string, error)
+	Get(context.Context, models.PrimaryKey) (models.Prescription, error)
+	GetByCode(ctx context.Context, code string) (models.Prescription, error)
+	GetByCustomer(ctx context.Context, customerID string) (models.PrescriptionsResponse, error)
+	Delete(context.Context, string) error
 }
 
 type IQueueRepo interface {
 	Create(context.Context, models.CreateQueue) (string, error)
 	Get(context.Context, models.PrimaryKey) (models.Queue, error)
-	GetList(context.Context, models.GetListRequest) (models.QueuesResponse, error)
+	GetList(context.Context, models.GetQueuesListRequest) (models.QueuesResponse, error)
 	Update(context.Context, models.UpdateQueue) (string, error)
 	Delete(context.Context, string) error
+	Restore(context.Context, string) error
+	GetBookedSlots(ctx context.Context, doctorID, date string) ([]models.Slot, error)
+	UpdateStatus(context.Context, models.UpdateQueueStatus) error
+	GetBoard(ctx context.Context, clinicBranchID, date string) ([]models.QueueBoardItem, error)
 }
 
 type ISuperAdminRepo interface {
@@ -161,6 +305,13 @@ type ISuperAdminRepo interface {
 	GetList(context.Context, models.GetListRequest) (models.SuperAdminsResponse, error)
 	Update(context.Context, models.UpdateSuperAdmin) (string, error)
 	Delete(context.Context, string) error
+	Restore(context.Context, string) error
 	UpdatePassword(context.Context, models.UpdateSuperAdminPassword) error
 	GetPassword(context.Context, string) (string, error)
+	GetByLogin(context.Context, string) (models.SuperAdmin, error)
+}
+
+// ITrashRepo removes soft deleted records for good
+type ITrashRepo interface {
+	Purge(ctx context.Context, deletedBefore time.Time) (map[string][]string, error)
 }